@@ -0,0 +1,347 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/api"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/pom"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// sha1Pattern 40位十六进制的SHA-1校验和
+var sha1Pattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// runSearch 执行search命令
+func runSearch(env *commandEnv, args []string) error {
+	core := env.flags.String("core", "", "搜索的Solr核心，为gav时按版本返回结果")
+	limit := env.flags.Int("limit", 20, "返回的最大结果数")
+	start := env.flags.Int("start", 0, "结果的起始偏移量")
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	searchRequest := request.NewSearchRequest().
+		SetQuery(request.NewQuery().SetCustomQuery(positional[0])).
+		SetStart(*start).
+		SetLimit(*limit)
+
+	if *core == "gav" {
+		searchRequest.SetCore(*core)
+		var result response.Response[*response.Version]
+		if err := env.client().SearchRequest(env.ctx, searchRequest, &result); err != nil {
+			return err
+		}
+		docs := responseDocs(result.ResponseBody)
+		return env.write(docs, versionTable(docs))
+	}
+
+	if *core != "" {
+		searchRequest.SetCore(*core)
+	}
+	var result response.Response[*response.Artifact]
+	if err := env.client().SearchRequest(env.ctx, searchRequest, &result); err != nil {
+		return err
+	}
+	docs := responseDocs(result.ResponseBody)
+	return env.write(docs, artifactTable(docs))
+}
+
+// responseDocs 返回响应中的结果列表，响应体为空时返回空列表
+func responseDocs[Doc any](body *response.ResponseBody[Doc]) []Doc {
+	if body == nil {
+		return []Doc{}
+	}
+	return orEmpty(body.Docs)
+}
+
+// orEmpty 将nil切片替换为空切片，使JSON输出为[]而不是null
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// runVersions 执行versions命令
+func runVersions(env *commandEnv, args []string) error {
+	limit := env.flags.Int("limit", 0, "返回的最大版本数，0表示全部")
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+	groupId, artifactId, _, err := parseCoordinate(positional[0], false)
+	if err != nil {
+		return err
+	}
+
+	versions, err := env.client().ListVersions(env.ctx, groupId, artifactId, *limit)
+	if err != nil {
+		return err
+	}
+	versions = orEmpty(versions)
+	return env.write(versions, versionTable(versions))
+}
+
+// runLatest 执行latest命令
+func runLatest(env *commandEnv, args []string) error {
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+	groupId, artifactId, _, err := parseCoordinate(positional[0], false)
+	if err != nil {
+		return err
+	}
+
+	latest, err := env.client().GetLatestVersion(env.ctx, groupId, artifactId)
+	if err != nil {
+		return err
+	}
+	return env.write(latest, versionTable([]*response.Version{latest}))
+}
+
+// runSha1 执行sha1命令
+func runSha1(env *commandEnv, args []string) error {
+	limit := env.flags.Int("limit", 20, "返回的最大结果数")
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	checksum := positional[0]
+	if !sha1Pattern.MatchString(checksum) {
+		checksum, err = fileSha1(checksum)
+		if err != nil {
+			return fmt.Errorf("参数既不是SHA-1校验和也不是可读取的文件: %w", err)
+		}
+	}
+
+	versions, err := env.client().SearchBySha1(env.ctx, strings.ToLower(checksum), *limit)
+	if err != nil {
+		return err
+	}
+	versions = orEmpty(versions)
+	return env.write(versions, versionTable(versions))
+}
+
+// fileSha1 计算文件的SHA-1校验和
+func fileSha1(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha1.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// runClass 执行class命令
+func runClass(env *commandEnv, args []string) error {
+	limit := env.flags.Int("limit", 20, "返回的最大结果数")
+	fqcn := env.flags.Bool("fqcn", false, "按全限定类名搜索，类名中包含\".\"时自动启用")
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	className := positional[0]
+	var versions []*response.Version
+	if *fqcn || strings.Contains(className, ".") {
+		versions, err = env.client().SearchByFullyQualifiedClassName(env.ctx, className, *limit)
+	} else {
+		versions, err = env.client().SearchByClassName(env.ctx, className, *limit)
+	}
+	if err != nil {
+		return err
+	}
+	versions = orEmpty(versions)
+	return env.write(versions, versionTable(versions))
+}
+
+// downloadedFile download命令输出的单个文件信息
+type downloadedFile struct {
+	Type  string `json:"type"`
+	Path  string `json:"path"`
+	Size  int    `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// runDownload 执行download命令
+func runDownload(env *commandEnv, args []string) error {
+	dir := env.flags.String("dir", ".", "保存文件的目录，文件按Maven仓库目录结构存放")
+	extra := env.flags.String("classifiers", "", "额外下载的JAR分类器，多个用逗号分隔，如\"tests,javadoc\"")
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+	groupId, artifactId, version, err := parseCoordinate(positional[0], true)
+	if err != nil {
+		return err
+	}
+
+	var extraFiles []api.ArtifactFile
+	for _, classifier := range splitList(*extra) {
+		extraFiles = append(extraFiles, api.ArtifactFile{Type: strings.ToUpper(classifier), Extension: api.JAR, Classifier: classifier})
+	}
+
+	client := env.client()
+	bundle, err := client.DownloadCompleteBundle(env.ctx, groupId, artifactId, version, extraFiles...)
+	if err != nil {
+		return err
+	}
+	if err := client.SaveBundle(bundle, *dir); err != nil {
+		return fmt.Errorf("保存文件失败: %w", err)
+	}
+
+	standard := []struct {
+		name string
+		file api.ArtifactFile
+		data []byte
+	}{
+		{"POM", api.PomFile, bundle.Pom},
+		{"JAR", api.JarFile, bundle.Jar},
+		{"SOURCES", api.SourcesFile, bundle.Sources},
+		{"JAVADOC", api.JavadocFile, bundle.Javadoc},
+		{"TESTS", api.TestsFile, bundle.Tests},
+	}
+	var files []*downloadedFile
+	for _, s := range standard {
+		files = append(files, bundleFileInfo(bundle, *dir, s.name, s.file.Extension, s.file.Classifier, s.data))
+	}
+	for _, file := range extraFiles {
+		files = append(files, bundleFileInfo(bundle, *dir, file.Type, file.Extension, file.Classifier, bundle.OtherFiles[file.Type]))
+	}
+
+	t := &table{header: []string{"type", "path", "size", "status"}}
+	for _, file := range files {
+		status := "saved"
+		if file.Error != "" {
+			status = file.Error
+		}
+		t.add(file.Type, file.Path, fmt.Sprint(file.Size), status)
+	}
+	return env.write(files, t)
+}
+
+// bundleFileInfo 生成制品包中单个文件的输出信息
+func bundleFileInfo(bundle *api.ArtifactBundle, dir, fileType, extension, classifier string, data []byte) *downloadedFile {
+	path := api.BuildArtifactPath(bundle.GroupId, bundle.ArtifactId, bundle.Version, extension, classifier)
+	file := &downloadedFile{Type: fileType, Path: filepath.Join(dir, filepath.FromSlash(path)), Size: len(data)}
+	if err := bundle.Errors[fileType]; err != nil {
+		file.Error = err.Error()
+	}
+	return file
+}
+
+// runLicenses 执行licenses命令
+func runLicenses(env *commandEnv, args []string) error {
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+	groupId, artifactId, version, err := parseCoordinate(positional[0], true)
+	if err != nil {
+		return err
+	}
+
+	licenses, err := env.client().GetComponentLicenses(env.ctx, groupId, artifactId, version)
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"license", "spdx", "category", "url"}}
+	for _, license := range licenses {
+		t.add(license.Name, license.Type, license.Category, license.URL)
+	}
+	return env.write(orEmpty(licenses), t)
+}
+
+// dependency deps命令输出的单个依赖
+type dependency struct {
+	GroupId    string `json:"groupId"`
+	ArtifactId string `json:"artifactId"`
+	Version    string `json:"version"`
+	Type       string `json:"type,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
+	Scope      string `json:"scope,omitempty"`
+	Optional   bool   `json:"optional,omitempty"`
+	Depth      int    `json:"depth"`
+}
+
+// runDeps 执行deps命令
+func runDeps(env *commandEnv, args []string) error {
+	scopes := env.flags.String("scopes", "compile,runtime", "要解析的作用域，多个用逗号分隔")
+	optional := env.flags.Bool("optional", false, "是否包含可选依赖")
+	depth := env.flags.Int("depth", 0, "最大解析深度，0表示不限制")
+	tree := env.flags.Bool("tree", false, "以依赖树的形式输出（table格式下类似mvn dependency:tree，json格式下输出完整的依赖图）")
+	positional, err := env.parse(args, 1)
+	if err != nil {
+		return err
+	}
+	groupId, artifactId, version, err := parseCoordinate(positional[0], true)
+	if err != nil {
+		return err
+	}
+
+	graph, err := env.client().ResolveDependencyGraph(env.ctx, groupId, artifactId, version, &pom.GraphOptions{
+		Scopes:          splitList(*scopes),
+		IncludeOptional: *optional,
+		MaxDepth:        *depth,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *tree {
+		switch *env.output {
+		case formatTable:
+			_, err := fmt.Fprint(env.stdout, graph.String())
+			return err
+		case formatJSON:
+			return env.write(graph, nil)
+		}
+	}
+
+	nodes := graph.Classpath(splitList(*scopes)...)
+
+	dependencies := make([]*dependency, 0, len(nodes))
+	t := &table{header: []string{"groupId", "artifactId", "version", "scope", "depth"}}
+	for _, node := range nodes {
+		dependencies = append(dependencies, &dependency{
+			GroupId:    node.GroupId,
+			ArtifactId: node.ArtifactId,
+			Version:    node.Version,
+			Type:       node.Type,
+			Classifier: node.Classifier,
+			Scope:      node.Scope,
+			Optional:   node.Optional,
+			Depth:      node.Depth,
+		})
+		t.add(node.GroupId, node.ArtifactId, node.Version, node.Scope, fmt.Sprint(node.Depth))
+	}
+	return env.write(dependencies, t)
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
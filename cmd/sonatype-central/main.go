@@ -0,0 +1,227 @@
+// sonatype-central 是基于SDK的命令行工具，用于在终端中查询Maven中央仓库
+//
+// 用法:
+//
+//	sonatype-central <命令> [参数] [选项]
+//
+// 支持的命令:
+//
+//	search    使用原始Solr查询语句搜索，如 sonatype-central search 'g:org.apache.commons AND a:commons-*'
+//	versions  列出制品的所有版本，如 sonatype-central versions org.apache.commons:commons-lang3
+//	latest    获取制品的最新正式版本
+//	sha1      根据SHA-1校验和（或本地文件）查找制品
+//	class     根据类名或全限定类名查找制品
+//	download  下载制品的POM、JAR、源码、文档和测试包
+//	licenses  查看制品声明的许可证
+//	deps      解析制品的传递依赖
+//
+// 所有命令都支持 -o table|json|csv 指定输出格式，以及 -base-url、-repo-url、-timeout 配置客户端。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/api"
+)
+
+// exitUsage 参数错误时的退出码
+const exitUsage = 2
+
+// errUsage 参数错误，已经向标准错误输出了用法说明
+var errUsage = errors.New("usage error")
+
+// command 一个子命令
+type command struct {
+	usage       string
+	description string
+	run         func(env *commandEnv, args []string) error
+}
+
+// commands 所有子命令，键为命令名
+var commands = map[string]*command{
+	"search":   {"search <查询语句>", "使用原始Solr查询语句搜索制品", runSearch},
+	"versions": {"versions <groupId:artifactId>", "列出制品的所有版本", runVersions},
+	"latest":   {"latest <groupId:artifactId>", "获取制品的最新正式版本", runLatest},
+	"sha1":     {"sha1 <SHA-1|文件路径>", "根据SHA-1校验和查找制品，传入文件时自动计算校验和", runSha1},
+	"class":    {"class <类名|全限定类名>", "根据类名查找包含该类的制品", runClass},
+	"download": {"download <groupId:artifactId:version>", "下载制品的完整包并按仓库目录结构保存", runDownload},
+	"licenses": {"licenses <groupId:artifactId:version>", "查看制品声明的许可证", runLicenses},
+	"deps":     {"deps <groupId:artifactId:version>", "解析制品的传递依赖", runDeps},
+}
+
+// commandEnv 子命令的执行环境
+type commandEnv struct {
+	ctx    context.Context
+	name   string
+	stdout io.Writer
+	stderr io.Writer
+	flags  *flag.FlagSet
+
+	output  *string
+	baseURL *string
+	repoURL *string
+	timeout *time.Duration
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 执行命令行，返回进程退出码
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+		printUsage(stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "未知命令: %s\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	env := newCommandEnv(ctx, args[0], cmd, stdout, stderr)
+	if err := cmd.run(env, args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			return exitUsage
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(stderr, "错误: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printUsage 输出命令列表
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "用法: sonatype-central <命令> [参数] [选项]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "命令:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s%s\n", name, commands[name].description)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "使用 \"sonatype-central <命令> -h\" 查看命令的选项")
+}
+
+// newCommandEnv 创建子命令的执行环境并注册所有命令共有的选项
+func newCommandEnv(ctx context.Context, name string, cmd *command, stdout, stderr io.Writer) *commandEnv {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "用法: sonatype-central %s [选项]\n\n%s\n\n选项:\n", cmd.usage, cmd.description)
+		flags.PrintDefaults()
+	}
+
+	return &commandEnv{
+		ctx:     ctx,
+		name:    name,
+		stdout:  stdout,
+		stderr:  stderr,
+		flags:   flags,
+		output:  flags.String("o", formatTable, "输出格式: table、json或csv"),
+		baseURL: flags.String("base-url", "", "搜索API的基础URL，默认为https://search.maven.org"),
+		repoURL: flags.String("repo-url", "", "下载文件使用的仓库URL，默认为https://repo1.maven.org/maven2"),
+		timeout: flags.Duration("timeout", 30*time.Second, "单次HTTP请求的超时时间"),
+	}
+}
+
+// parse 解析选项并返回位置参数
+//
+// 选项可以出现在位置参数的前后，如"versions org.slf4j:slf4j-api -o json"。
+// 位置参数的数量不等于want时输出用法说明并返回errUsage。
+func (e *commandEnv) parse(args []string, want int) ([]string, error) {
+	var positional []string
+	for {
+		if err := e.flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = e.flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != want {
+		e.flags.Usage()
+		return nil, errUsage
+	}
+	switch *e.output {
+	case formatTable, formatJSON, formatCSV:
+	default:
+		fmt.Fprintf(e.stderr, "不支持的输出格式: %s\n", *e.output)
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// client 按选项创建SDK客户端
+func (e *commandEnv) client() *api.Client {
+	options := []api.ClientOption{
+		api.WithHTTPClient(&http.Client{Timeout: *e.timeout}),
+	}
+	if *e.baseURL != "" {
+		options = append(options, api.WithBaseURL(strings.TrimSuffix(*e.baseURL, "/")))
+	}
+	if *e.repoURL != "" {
+		options = append(options, api.WithRepoBaseURL(strings.TrimSuffix(*e.repoURL, "/")))
+	}
+	return api.NewClient(options...)
+}
+
+// write 按-o选项输出结果
+func (e *commandEnv) write(value interface{}, t *table) error {
+	return writeOutput(e.stdout, *e.output, value, t)
+}
+
+// parseCoordinate 解析groupId:artifactId[:version]形式的坐标
+//
+// 参数:
+//   - coordinate: 坐标字符串
+//   - withVersion: 是否要求包含版本号
+func parseCoordinate(coordinate string, withVersion bool) (groupId, artifactId, version string, err error) {
+	parts := strings.Split(coordinate, ":")
+	want, format := 2, "groupId:artifactId"
+	if withVersion {
+		want, format = 3, "groupId:artifactId:version"
+	}
+	if len(parts) != want {
+		return "", "", "", fmt.Errorf("无效的坐标 %q，格式应为%s", coordinate, format)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return "", "", "", fmt.Errorf("无效的坐标 %q，格式应为%s", coordinate, format)
+		}
+	}
+	if withVersion {
+		version = parts[2]
+	}
+	return parts[0], parts[1], version, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const versionsJson = `{"response":{"numFound":2,"start":0,"docs":[
+	{"id":"org.example:demo:1.1","g":"org.example","a":"demo","v":"1.1","p":"jar","timestamp":1700000000000},
+	{"id":"org.example:demo:1.0","g":"org.example","a":"demo","v":"1.0","p":"jar","timestamp":1600000000000}
+]}}`
+
+const demoPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>demo</artifactId>
+  <version>1.1</version>
+  <licenses>
+    <license>
+      <name>The Apache Software License, Version 2.0</name>
+      <url>http://www.apache.org/licenses/LICENSE-2.0.txt</url>
+    </license>
+  </licenses>
+</project>`
+
+// newTestServer 创建模拟搜索API和仓库的测试服务器
+func newTestServer(t *testing.T) *httptest.Server {
+	files := map[string]string{
+		"org/example/demo/1.1/demo-1.1.pom": demoPom,
+		"org/example/demo/1.1/demo-1.1.jar": "jar-content",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/solrsearch/select" {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(r.URL.Query().Get("q"), "demo") {
+				_, _ = w.Write([]byte(versionsJson))
+			} else {
+				_, _ = w.Write([]byte(`{"response":{"numFound":0,"start":0,"docs":[]}}`))
+			}
+			return
+		}
+		content, ok := files[strings.TrimPrefix(r.URL.Path, "/maven2/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runCommand 执行命令行并返回退出码和输出
+func runCommand(server *httptest.Server, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	args = append(args, "-base-url", server.URL, "-repo-url", server.URL+"/maven2")
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestOutputFormats 测试表格、JSON和CSV输出
+func TestOutputFormats(t *testing.T) {
+	server := newTestServer(t)
+
+	code, out, _ := runCommand(server, "versions", "org.example:demo")
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "GROUPID"))
+	assert.Contains(t, lines[1], "1.1")
+	assert.Contains(t, lines[1], "2023-11-14")
+
+	code, out, _ = runCommand(server, "versions", "-o", "json", "org.example:demo")
+	assert.Equal(t, 0, code)
+	var versions []map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(out), &versions))
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "1.0", versions[1]["v"])
+
+	code, out, _ = runCommand(server, "latest", "org.example:demo", "-o", "csv")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "groupId,artifactId,version,packaging,updated\norg.example,demo,1.1,jar,2023-11-14\n", out)
+
+	code, out, _ = runCommand(server, "search", "-o", "json", "a:nothing")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "[]\n", out)
+}
+
+// TestCommands 测试需要下载文件的命令
+func TestCommands(t *testing.T) {
+	server := newTestServer(t)
+
+	code, out, _ := runCommand(server, "licenses", "org.example:demo:1.1", "-o", "csv")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Apache-2.0")
+
+	dir := t.TempDir()
+	code, out, _ = runCommand(server, "download", "org.example:demo:1.1", "-dir", dir, "-o", "json")
+	assert.Equal(t, 0, code)
+	var files []*downloadedFile
+	assert.NoError(t, json.Unmarshal([]byte(out), &files))
+	assert.Len(t, files, 5)
+	assert.Equal(t, "JAR", files[1].Type)
+	assert.Empty(t, files[1].Error)
+	assert.NotEmpty(t, files[2].Error, "SOURCES不存在")
+
+	data, err := os.ReadFile(filepath.Join(dir, "org", "example", "demo", "1.1", "demo-1.1.jar"))
+	assert.NoError(t, err)
+	assert.Equal(t, "jar-content", string(data))
+}
+
+// TestUsageErrors 测试参数错误
+func TestUsageErrors(t *testing.T) {
+	server := newTestServer(t)
+
+	code, _, stderr := runCommand(server, "unknown")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "未知命令")
+
+	code, _, _ = runCommand(server, "versions")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runCommand(server, "versions", "org.example:demo", "-o", "xml")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, stderr = runCommand(server, "licenses", "org.example:demo")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "groupId:artifactId:version")
+
+	code, _, stderr = runCommand(server, "sha1", "not-a-file")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "SHA-1")
+}
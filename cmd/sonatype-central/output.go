@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// 支持的输出格式
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// table 表格和CSV输出使用的数据
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// add 追加一行
+func (t *table) add(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// writeOutput 按格式输出结果
+//
+// JSON格式直接输出value，保留SDK返回的全部字段；表格和CSV格式输出t中挑选的列。
+func writeOutput(w io.Writer, format string, value interface{}, t *table) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(t.header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(t.rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	default:
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		header := make([]string, len(t.header))
+		for i, h := range t.header {
+			header[i] = strings.ToUpper(h)
+		}
+		fmt.Fprintln(writer, strings.Join(header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	}
+}
+
+// versionTable 将版本列表转换为表格
+func versionTable(versions []*response.Version) *table {
+	t := &table{header: []string{"groupId", "artifactId", "version", "packaging", "updated"}}
+	for _, v := range versions {
+		t.add(v.GroupId, v.ArtifactId, v.Version, v.Packaging, formatTimestamp(v.Timestamp))
+	}
+	return t
+}
+
+// artifactTable 将制品列表转换为表格
+func artifactTable(artifacts []*response.Artifact) *table {
+	t := &table{header: []string{"groupId", "artifactId", "latestVersion", "packaging", "versions", "updated"}}
+	for _, a := range artifacts {
+		t.add(a.GroupId, a.ArtifactId, a.LatestVersion, a.Packaging, fmt.Sprint(a.VersionCount), formatTimestamp(a.Timestamp))
+	}
+	return t
+}
+
+// formatTimestamp 将毫秒时间戳格式化为UTC日期，时间戳为0时返回空字符串
+func formatTimestamp(ms int64) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
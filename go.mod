@@ -3,17 +3,20 @@ module github.com/scagogogo/sonatype-central-sdk
 go 1.18
 
 require (
+	github.com/ProtonMail/go-crypto v1.1.6
 	github.com/crawler-go-go-go/go-requests v0.0.0-20230525030146-0f17843cff2c
 	github.com/golang-infrastructure/go-iterator v0.0.0-20230524171120-56988a9b127c
 	github.com/golang-infrastructure/go-queue v0.0.0-20221128180429-701892f44bcc
-	github.com/patrickmn/go-cache v2.1.0+incompatible
 	github.com/stretchr/testify v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/cloudflare/circl v1.3.7 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang-infrastructure/go-heap v0.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 
+	"github.com/scagogogo/sonatype-central-sdk/pkg/pom"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
 )
@@ -46,28 +47,7 @@ import (
 //	    fmt.Printf("%s:%s:%s\n", artifact.GroupId, artifact.ArtifactId, artifact.Version)
 //	}
 func (c *Client) AdvancedSearch(ctx context.Context, options *request.AdvancedSearchOptions, limit int) ([]*response.Artifact, error) {
-	query := request.NewQuery()
-
-	// 设置搜索参数
-	if options.GroupId != "" {
-		query.SetGroupId(options.GroupId)
-	}
-
-	if options.ArtifactId != "" {
-		query.SetArtifactId(options.ArtifactId)
-	}
-
-	if options.Version != "" {
-		query.SetVersion(options.Version)
-	}
-
-	if options.Packaging != "" {
-		query.SetPackaging(options.Packaging)
-	}
-
-	if options.Classifier != "" {
-		query.SetClassifier(options.Classifier)
-	}
+	query := options.ToQuery()
 
 	// 创建搜索请求
 	searchReq := request.NewSearchRequest().SetQuery(query).SetLimit(limit)
@@ -122,28 +102,7 @@ func (c *Client) AdvancedSearch(ctx context.Context, options *request.AdvancedSe
 //	    }
 //	}
 func (c *Client) AdvancedSearchIterator(ctx context.Context, options *request.AdvancedSearchOptions) *SearchIterator[*response.Artifact] {
-	query := request.NewQuery()
-
-	// 设置搜索参数
-	if options.GroupId != "" {
-		query.SetGroupId(options.GroupId)
-	}
-
-	if options.ArtifactId != "" {
-		query.SetArtifactId(options.ArtifactId)
-	}
-
-	if options.Version != "" {
-		query.SetVersion(options.Version)
-	}
-
-	if options.Packaging != "" {
-		query.SetPackaging(options.Packaging)
-	}
-
-	if options.Classifier != "" {
-		query.SetClassifier(options.Classifier)
-	}
+	query := options.ToQuery()
 
 	// 创建搜索请求
 	searchReq := request.NewSearchRequest().SetQuery(query)
@@ -302,7 +261,8 @@ func (c *Client) SearchByLicense(ctx context.Context, license string, limit int)
 //
 // 该方法用于获取指定制品的详细元数据信息。它首先根据提供的坐标查找制品，
 // 然后获取其基本信息。如果提供了版本参数，还会尝试下载并解析POM文件以获取
-// 更详细的元数据。这对于需要分析制品依赖、许可证或其他详细信息的场景非常有用。
+// 更详细的元数据。POM会经过父POM合并、属性替换和BOM导入，依赖项、许可证、开发者
+// 和项目信息都来自有效模型。这对于需要分析制品依赖、许可证或其他详细信息的场景非常有用。
 //
 // 参数:
 //   - ctx: 请求上下文，用于控制超时和取消
@@ -338,6 +298,12 @@ func (c *Client) SearchByLicense(ctx context.Context, license string, limit int)
 //	    fmt.Println("POM内容长度:", len(metadata.PomContent))
 //	}
 func (c *Client) GetArtifactMetadata(ctx context.Context, groupId, artifactId, version string) (*response.ArtifactMetadata, error) {
+	return c.getArtifactMetadata(ctx, pom.NewResolver(c), groupId, artifactId, version)
+}
+
+// getArtifactMetadata 与GetArtifactMetadata相同，但使用调用方提供的POM解析器，
+// 以便与后续的依赖解析共享已下载的父POM和BOM
+func (c *Client) getArtifactMetadata(ctx context.Context, resolver *pom.Resolver, groupId, artifactId, version string) (*response.ArtifactMetadata, error) {
 	// 使用GAV坐标查询
 	query := request.NewQuery().
 		SetGroupId(groupId).
@@ -374,9 +340,16 @@ func (c *Client) GetArtifactMetadata(ctx context.Context, groupId, artifactId, v
 		// 下载POM文件
 		pomData, err := c.DownloadPom(ctx, groupId, artifactId, version)
 		if err == nil {
-			// 解析POM文件
 			metadata.PomContent = string(pomData)
-			// TODO: 解析POM获取更多元数据
+
+			// 解析POM文件，优先构建合并了父POM和BOM的有效模型，
+			// 父POM无法获取时退化为只使用POM自身的信息
+			if project, parseErr := pom.Parse(pomData); parseErr == nil {
+				if effective, resolveErr := resolver.ResolveProject(ctx, project); resolveErr == nil {
+					project = effective
+				}
+				applyPomToMetadata(metadata, project)
+			}
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/scagogogo/sonatype-central-sdk/pkg/pom"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
 )
@@ -442,16 +443,31 @@ type ArtifactDependencyInfo struct {
 	// 直接依赖项
 	DirectDependencies []*response.Dependency `json:"directDependencies"`
 
-	// 传递依赖项（依赖的依赖）
+	// 传递依赖项（依赖的依赖），按Maven的就近原则和作用域传播规则解析得到
 	TransitiveDependencies []*response.Dependency `json:"transitiveDependencies"`
 
 	// 可选依赖项
 	OptionalDependencies []*response.Dependency `json:"optionalDependencies"`
+
+	// 测试作用域的直接依赖项
+	TestDependencies []*response.Dependency `json:"testDependencies"`
+
+	// provided和system作用域的直接依赖项
+	ProvidedDependencies []*response.Dependency `json:"providedDependencies"`
+
+	// 依赖管理中声明的版本约束（已合并父POM并导入BOM）
+	ManagedDependencies []*response.Dependency `json:"managedDependencies"`
+
+	// 解析传递依赖失败的原因，为nil时TransitiveDependencies是完整的
+	TransitiveError error `json:"-"`
 }
 
 // GetArtifactDependencies 获取制品的依赖关系
 //
-// 该方法用于获取指定Maven制品的完整依赖关系信息，包括直接依赖项、传递依赖项和可选依赖项。
+// 该方法用于获取指定Maven制品的完整依赖关系信息，包括直接依赖项、传递依赖项和可选依赖项，
+// 测试和provided作用域的直接依赖会被单独归类。传递依赖通过ResolveDependencyGraph解析，
+// 只包含compile和runtime作用域中最终参与类路径的制品。元数据和依赖图共用同一个POM解析器，
+// 父POM和BOM只会下载一次。传递依赖解析失败时仍然返回直接依赖，失败原因记录在TransitiveError中。
 // 依赖关系分析对于项目依赖管理、冲突检测、安全审计和兼容性评估至关重要。通过此方法，开发者
 // 可以全面了解制品的依赖结构，有助于做出更明智的集成决策。
 //
@@ -494,8 +510,9 @@ type ArtifactDependencyInfo struct {
 //	    fmt.Printf("  %d. %s:%s:%s\n", i+1, dep.GroupId, dep.ArtifactId, dep.Version)
 //	}
 func (c *Client) GetArtifactDependencies(ctx context.Context, groupId, artifactId, version string) (*ArtifactDependencyInfo, error) {
-	// 获取制品元数据
-	metadata, err := c.GetArtifactMetadata(ctx, groupId, artifactId, version)
+	// 获取制品元数据，解析器在元数据和依赖图之间共享
+	resolver := pom.NewResolver(c)
+	metadata, err := c.getArtifactMetadata(ctx, resolver, groupId, artifactId, version)
 	if err != nil {
 		return nil, err
 	}
@@ -505,16 +522,45 @@ func (c *Client) GetArtifactDependencies(ctx context.Context, groupId, artifactI
 		DirectDependencies:     make([]*response.Dependency, 0),
 		OptionalDependencies:   make([]*response.Dependency, 0),
 		TransitiveDependencies: make([]*response.Dependency, 0),
+		TestDependencies:       make([]*response.Dependency, 0),
+		ProvidedDependencies:   make([]*response.Dependency, 0),
+		ManagedDependencies:    make([]*response.Dependency, 0),
 	}
+	depInfo.ManagedDependencies = append(depInfo.ManagedDependencies, metadata.ManagedDependencies...)
 
-	// 处理依赖项
+	// 处理直接声明的依赖项
 	for _, dep := range metadata.Dependencies {
-		if dep.Optional {
+		switch {
+		case dep.Optional:
 			depInfo.OptionalDependencies = append(depInfo.OptionalDependencies, dep)
-		} else if dep.Scope == "compile" || dep.Scope == "runtime" {
+		case dep.Scope == pom.ScopeTest:
+			depInfo.TestDependencies = append(depInfo.TestDependencies, dep)
+		case dep.Scope == pom.ScopeProvided || dep.Scope == pom.ScopeSystem:
+			depInfo.ProvidedDependencies = append(depInfo.ProvidedDependencies, dep)
+		default:
 			depInfo.DirectDependencies = append(depInfo.DirectDependencies, dep)
-		} else {
-			depInfo.TransitiveDependencies = append(depInfo.TransitiveDependencies, dep)
+		}
+	}
+
+	// 解析传递依赖
+	if len(depInfo.DirectDependencies) > 0 {
+		graph, err := resolver.ResolveGraph(ctx, groupId, artifactId, version, nil)
+		if err != nil {
+			depInfo.TransitiveError = err
+			return depInfo, nil
+		}
+		for _, node := range graph.Classpath() {
+			if node.Depth < 2 {
+				continue
+			}
+			depInfo.TransitiveDependencies = append(depInfo.TransitiveDependencies, &response.Dependency{
+				GroupId:    node.GroupId,
+				ArtifactId: node.ArtifactId,
+				Version:    node.Version,
+				Scope:      node.Scope,
+				Type:       node.Type,
+				Classifier: node.Classifier,
+			})
 		}
 	}
 
@@ -783,10 +829,10 @@ func (c *Client) CompareArtifacts(ctx context.Context, groupId1, artifactId1, gr
 //	}
 func (c *Client) SearchArtifactsByDateRange(ctx context.Context, startDate, endDate string, limit int) ([]*response.Artifact, error) {
 	// 构建日期范围查询
-	dateQuery := fmt.Sprintf("timestamp:[%s TO %s]", startDate, endDate)
+	dateQuery := request.Range(request.FieldTimestamp, startDate, endDate)
 
 	// 创建搜索请求
-	query := request.NewQuery().SetCustomQuery(dateQuery)
+	query := request.NewQuery().SetExpr(dateQuery)
 	search := request.NewSearchRequest().
 		SetQuery(query).
 		SetSort("timestamp", false). // 按时间戳降序排序
@@ -878,7 +924,10 @@ func (c *Client) SuggestSimilarArtifacts(ctx context.Context, groupId, artifactI
 	}
 
 	// 步骤3: 构建搜索查询，排除自身
-	query := fmt.Sprintf("NOT (g:%s AND a:%s)", groupId, artifactId)
+	query := request.Not(request.And(
+		request.Field(request.FieldGroupId, groupId),
+		request.Field(request.FieldArtifactId, artifactId),
+	))
 
 	// 添加关键词，限制最多使用5个关键词
 	keywordLimit := 5
@@ -886,14 +935,15 @@ func (c *Client) SuggestSimilarArtifacts(ctx context.Context, groupId, artifactI
 		keywords = keywords[:keywordLimit]
 	}
 
-	if len(keywords) > 0 {
-		keywordQuery := strings.Join(keywords, " OR ")
-		query = fmt.Sprintf("(%s) AND (%s)", query, keywordQuery)
+	keywordQueries := make([]request.Expr, 0, len(keywords))
+	for _, keyword := range keywords {
+		keywordQueries = append(keywordQueries, request.Text(keyword))
 	}
+	query = request.And(query, request.Or(keywordQueries...))
 
 	// 步骤4: 执行搜索
 	search := request.NewSearchRequest().
-		SetQuery(request.NewQuery().SetCustomQuery(query)).
+		SetQuery(request.NewQuery().SetExpr(query)).
 		SetLimit(limit)
 
 	result, err := SearchRequestJsonDoc[*response.Artifact](c, ctx, search)
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Credentials 访问私有仓库使用的认证信息
+//
+// 字段说明:
+//   - Username、Password: HTTP Basic认证的用户名和密码
+//   - Token: Bearer令牌，不为空时优先于用户名和密码
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// authorization 返回Authorization请求头的值，没有可用的认证信息时返回空字符串
+func (c Credentials) authorization() string {
+	switch {
+	case c.Token != "":
+		return "Bearer " + c.Token
+	case c.Username != "" || c.Password != "":
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password))
+	}
+	return ""
+}
+
+// WithCredentials 为指定主机设置认证信息
+//
+// 认证信息只会发送给匹配的主机：host不带端口时匹配该主机的任意端口，带端口时只匹配该端口，
+// 也可以直接传入仓库URL（如"https://nexus.example.com/repository/maven-public"），此时使用其中的主机和端口。
+// 认证在传输层按每个请求的目标主机添加，因此重定向到其他主机（如对象存储）时不会泄露认证信息，
+// 重定向回同一主机时会重新带上。请求已经带有Authorization头时保持不变。
+// 多次调用可以为不同主机设置不同的认证信息，同一主机以最后一次设置为准。
+//
+// 参数:
+//   - host: 主机名、主机名:端口或仓库URL
+//   - credentials: 认证信息
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+//
+// 使用示例:
+//
+//	client := api.NewClient(
+//	    api.WithRepoBaseURL("https://nexus.example.com/repository/maven-public"),
+//	    api.WithCredentials("nexus.example.com", api.Credentials{
+//	        Username: "deployer",
+//	        Password: os.Getenv("NEXUS_PASSWORD"),
+//	    }),
+//	)
+func WithCredentials(host string, credentials Credentials) ClientOption {
+	return func(c *Client) {
+		key := credentialHost(host)
+		if key == "" {
+			return
+		}
+		if c.credentials == nil {
+			c.credentials = make(map[string]Credentials)
+		}
+		c.credentials[key] = credentials
+	}
+}
+
+// WithBasicAuth 为指定主机设置HTTP Basic认证，是WithCredentials的便捷形式
+//
+// 参数:
+//   - host: 主机名、主机名:端口或仓库URL
+//   - username: 用户名
+//   - password: 密码
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+func WithBasicAuth(host, username, password string) ClientOption {
+	return WithCredentials(host, Credentials{Username: username, Password: password})
+}
+
+// WithBearerToken 为指定主机设置Bearer令牌认证，是WithCredentials的便捷形式
+//
+// 参数:
+//   - host: 主机名、主机名:端口或仓库URL
+//   - token: 访问令牌，如Artifactory的访问令牌
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+func WithBearerToken(host, token string) ClientOption {
+	return WithCredentials(host, Credentials{Token: token})
+}
+
+// credentialHost 将主机名、主机名:端口或URL规范化为认证信息的键
+//
+// 主机名转为小写；传入URL时总是带上端口，省略的端口按协议的默认端口补全。
+func credentialHost(host string) string {
+	host = strings.TrimSpace(host)
+	if strings.Contains(host, "://") {
+		u, err := url.Parse(host)
+		if err != nil {
+			return ""
+		}
+		hostname, port := urlHostPort(u)
+		if port == "" {
+			return hostname
+		}
+		return hostname + ":" + port
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "/"))
+}
+
+// urlHostPort 返回URL中小写的主机名（IPv6地址带方括号）和端口，省略的端口按协议的默认端口补全
+func urlHostPort(u *url.URL) (string, string) {
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	return host, port
+}
+
+// authTransport 按请求的目标主机添加认证头的传输层
+type authTransport struct {
+	credentials map[string]Credentials
+	next        http.RoundTripper
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		if credentials, ok := t.lookup(req.URL); ok {
+			if authorization := credentials.authorization(); authorization != "" {
+				// RoundTripper不能修改传入的请求
+				req = req.Clone(req.Context())
+				req.Header.Set("Authorization", authorization)
+			}
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// lookup 查找目标地址的认证信息，先匹配主机名和端口，再匹配不带端口的主机名
+func (t *authTransport) lookup(u *url.URL) (Credentials, bool) {
+	host, port := urlHostPort(u)
+	if credentials, ok := t.credentials[host+":"+port]; ok && port != "" {
+		return credentials, true
+	}
+	credentials, ok := t.credentials[host]
+	return credentials, ok
+}
+
+// applyCredentials 在HTTP客户端的传输层外包装认证
+func (c *Client) applyCredentials() {
+	if len(c.credentials) == 0 {
+		return
+	}
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = &authTransport{credentials: c.credentials, next: next}
+	c.httpClient = &httpClient
+}
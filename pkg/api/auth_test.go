@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithCredentials 测试认证信息只发送给匹配的主机，重定向到其他主机时不泄露
+func TestWithCredentials(t *testing.T) {
+	var storageAuth string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storageAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("jar-content"))
+	}))
+	defer storage.Close()
+
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("deployer:s3cret"))
+	nexus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, ".jar"):
+			http.Redirect(w, r, storage.URL+"/blob/1", http.StatusFound)
+		case strings.HasSuffix(r.URL.Path, "old.pom"):
+			http.Redirect(w, r, "/maven2/new.pom", http.StatusMovedPermanently)
+		default:
+			_, _ = w.Write([]byte("<project/>"))
+		}
+	}))
+	defer nexus.Close()
+
+	ctx := context.Background()
+	client := NewClient(
+		WithRepoBaseURL(nexus.URL+"/maven2"),
+		WithBasicAuth(nexus.URL, "deployer", "s3cret"),
+		WithBearerToken("other.example.com", "token"),
+		WithMaxRetries(0),
+	)
+
+	data, err := client.Download(ctx, "org/example/demo/1.0/demo-1.0.jar")
+	assert.NoError(t, err)
+	assert.Equal(t, "jar-content", string(data))
+	assert.Empty(t, storageAuth, "重定向到其他主机时不应发送认证信息")
+
+	data, err = client.Download(ctx, "old.pom")
+	assert.NoError(t, err, "重定向回同一主机时应重新发送认证信息")
+	assert.Equal(t, "<project/>", string(data))
+
+	_, err = NewClient(WithRepoBaseURL(nexus.URL+"/maven2"), WithMaxRetries(0)).Download(ctx, "a.pom")
+	assert.Error(t, err)
+}
+
+// TestAuthTransportLookup 测试认证信息的主机匹配
+func TestAuthTransportLookup(t *testing.T) {
+	client := NewClient(
+		WithBearerToken("Nexus.Example.com", "any-port"),
+		WithBasicAuth("nexus.example.com:8443", "u", "p"),
+		WithBasicAuth("https://secure.example.com:443/repo", "s", "p"),
+	)
+	transport := client.httpClient.Transport.(*authTransport)
+
+	lookup := func(rawURL string) string {
+		req, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+		credentials, _ := transport.lookup(req.URL)
+		return credentials.authorization()
+	}
+	assert.Equal(t, "Bearer any-port", lookup("https://nexus.example.com/repo"))
+	assert.Equal(t, "Basic dTpw", lookup("https://NEXUS.example.com:8443/repo"))
+	assert.Equal(t, "Basic czpw", lookup("https://secure.example.com/repo"))
+	assert.Equal(t, "", lookup("https://nexus.example.com.evil.org/repo"))
+	assert.Equal(t, "", lookup("https://secure.example.com:8443/repo"))
+}
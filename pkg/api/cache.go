@@ -5,6 +5,27 @@ import (
 	"time"
 )
 
+// Cache 缓存后端接口
+//
+// 客户端通过该接口存取缓存的下载内容和API响应。SDK内置了两种实现:
+//   - MemoryCache: 进程内的内存缓存，NewClient默认为每个客户端创建一个独立的实例
+//   - FileCache: 基于文件系统的持久化缓存，进程重启后仍然有效，支持容量上限和LRU淘汰
+//
+// 也可以实现该接口接入Redis等外部存储，并通过WithCacheBackend注入到客户端中。
+// 实现必须是并发安全的。
+//
+// 方法说明:
+//   - Get: 获取缓存内容，不存在或已过期时第二个返回值为false
+//   - Set: 写入缓存内容，ttl小于或等于0表示永不过期
+//   - Delete: 删除指定键的缓存内容，键不存在时不返回错误
+//   - Clear: 清空所有缓存内容
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+	Clear() error
+}
+
 // cacheItem 缓存条目
 //
 // 表示存储在内存缓存中的单个数据项。每个缓存项包含实际数据内容和过期时间。
@@ -12,51 +33,101 @@ import (
 //
 // 字段说明:
 //   - data: 缓存的二进制数据内容，通常是API响应的正文
-//   - expiration: 过期时间点，表示该缓存项在何时应被视为无效
+//   - expiration: 过期时间点，零值表示永不过期
 type cacheItem struct {
 	data       []byte
 	expiration time.Time
 }
 
-// memCache 内存缓存
+// MemoryCache 内存缓存
 //
 // 提供简单的内存中键值存储，用于缓存下载的文件和API响应，以减少网络请求。
 // 该实现使用标准的Go sync.RWMutex来保证并发安全，允许多个goroutine同时
 // 读取缓存，但写入操作会阻塞所有其他访问。
 //
 // 缓存使用惰性过期检查策略，即只有在尝试访问某个缓存项时才检查它是否过期，
-// 而不是主动清理过期项。这种方法简化了实现，但可能导致过期项在内存中长期存在，
-// 直到被再次访问或整个缓存被清除。
+// 而不是主动清理过期项。过期项会在被访问时删除。
 //
-// 字段说明:
-//   - entries: 存储缓存项的映射，键为缓存标识符，值为包含数据和过期时间的结构
-//   - mutex: 读写锁，用于保证并发安全
-type memCache struct {
+// 注意事项:
+//   - 缓存仅在内存中，应用程序重启后会丢失，需要持久化时请使用FileCache
+//   - 多个Client需要共享缓存时，可以将同一个MemoryCache实例通过WithCacheBackend传给它们
+type MemoryCache struct {
 	entries map[string]cacheItem
 	mutex   sync.RWMutex
 }
 
-// 创建全局缓存实例
+// NewMemoryCache 创建一个空的内存缓存
 //
-// globalCache是包级别的单例缓存实例，被整个SDK共享使用。这种设计允许
-// 不同的Client实例共享同一个缓存，从而最大化缓存命中率和资源复用。
+// 返回:
+//   - *MemoryCache: 内存缓存实例
 //
-// 全局缓存主要用于存储下载的文件内容、搜索结果和其他API响应，避免重复请求
-// 相同的资源。缓存内容的生命周期由各个Client实例的cacheTTLSeconds参数控制。
+// 使用示例:
 //
-// 注意事项:
-//   - 缓存仅在内存中，应用程序重启后会丢失
-//   - 在使用大量数据或长时间运行的应用中，应定期调用ClearCache()以释放内存
-//   - 缓存是进程内的，不在多个应用实例间共享
-var globalCache = &memCache{
-	entries: make(map[string]cacheItem),
+//	shared := api.NewMemoryCache()
+//	client1 := api.NewClient(api.WithCacheBackend(shared))
+//	client2 := api.NewClient(api.WithCacheBackend(shared))
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]cacheItem),
+	}
 }
 
-// ClearCache 清除客户端的内存缓存
+// Get 从内存缓存中获取内容
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mutex.RLock()
+	entry, exists := m.entries[key]
+	m.mutex.RUnlock()
+	if !exists {
+		return nil, false
+	}
+
+	// 检查是否过期
+	if !entry.expiration.IsZero() && time.Now().After(entry.expiration) {
+		m.mutex.Lock()
+		if current, ok := m.entries[key]; ok && current.expiration.Equal(entry.expiration) {
+			delete(m.entries, key)
+		}
+		m.mutex.Unlock()
+		return nil, false
+	}
+
+	return entry.data, true
+}
+
+// Set 添加内容到内存缓存
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) error {
+	item := cacheItem{data: value}
+	if ttl > 0 {
+		item.expiration = time.Now().Add(ttl)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.entries[key] = item
+	return nil
+}
+
+// Delete 从内存缓存中删除内容
+func (m *MemoryCache) Delete(key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// Clear 清空内存缓存
+func (m *MemoryCache) Clear() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.entries = make(map[string]cacheItem)
+	return nil
+}
+
+// ClearCache 清除客户端的缓存
 //
-// 该方法用于立即清空全局内存缓存中的所有条目。这在需要强制刷新缓存或释放内存时非常有用，
+// 该方法用于立即清空客户端缓存后端中的所有条目。这在需要强制刷新缓存或释放空间时非常有用，
 // 例如当怀疑缓存数据已过时或在测试环境中需要确保每次请求都获取最新数据时。
-// 清除操作使用互斥锁保证并发安全。
+// 如果客户端通过WithCacheBackend使用了共享的缓存后端，其他共享该后端的客户端也会受到影响。
 //
 // 使用示例:
 //
@@ -69,17 +140,12 @@ var globalCache = &memCache{
 //
 //	// 后续请求将获取新的数据而不是使用缓存
 func (c *Client) ClearCache() {
-	globalCache.mutex.Lock()
-	defer globalCache.mutex.Unlock()
-
-	// 重置缓存
-	globalCache.entries = make(map[string]cacheItem)
+	if c.cache != nil {
+		_ = c.cache.Clear()
+	}
 }
 
-// 从缓存中获取内容
-//
-// 该方法用于从全局内存缓存中检索数据。它会先检查指定键的数据是否存在，然后验证数据是否已过期。
-// 如果数据存在且未过期，则返回缓存的内容；否则表示缓存未命中。整个过程使用读锁保证并发安全。
+// getFromCache 从客户端的缓存后端中获取内容
 //
 // 参数:
 //   - key: 缓存的键，通常由请求路径或资源标识符构成
@@ -87,56 +153,31 @@ func (c *Client) ClearCache() {
 // 返回:
 //   - []byte: 缓存的数据内容，当缓存命中且未过期时返回
 //   - bool: 如果缓存命中且未过期返回true，否则返回false
-func getFromCache(key string) ([]byte, bool) {
-	globalCache.mutex.RLock()
-	defer globalCache.mutex.RUnlock()
-
-	entry, exists := globalCache.entries[key]
-	if !exists {
-		return nil, false
-	}
-
-	// 检查是否过期
-	if time.Now().After(entry.expiration) {
+func (c *Client) getFromCache(key string) ([]byte, bool) {
+	if c.cache == nil {
 		return nil, false
 	}
-
-	return entry.data, true
+	return c.cache.Get(key)
 }
 
-// 添加内容到缓存
+// addToCache 添加内容到客户端的缓存后端
 //
-// 该方法用于将数据存储到全局内存缓存中，并设置其过期时间。如果指定的TTL小于或等于0，
-// 则不会进行缓存。缓存操作使用互斥锁保证并发安全。缓存项包括数据内容和基于当前时间
-// 计算的过期时间点。
+// 如果客户端的TTL小于或等于0，则不会进行缓存。写入缓存失败（如磁盘已满）不会影响
+// 下载本身，因此错误会被忽略。
 //
 // 参数:
 //   - key: 缓存的键，用于后续检索数据
 //   - data: 要缓存的数据内容
-//   - ttlSeconds: 生存时间(秒)，指定数据在缓存中保留的时长
-//
-// 实现细节:
-//   - 数据以byte数组形式存储，适合缓存二进制内容如下载的文件
-//   - 过期机制基于时间戳比较，而非定时删除
-//   - 在访问时才检查过期，没有主动清理机制
-func addToCache(key string, data []byte, ttlSeconds int) {
-	if ttlSeconds <= 0 {
+func (c *Client) addToCache(key string, data []byte) {
+	if c.cache == nil || c.cacheTTLSeconds <= 0 {
 		return
 	}
-
-	globalCache.mutex.Lock()
-	defer globalCache.mutex.Unlock()
-
-	// 添加带过期时间的条目
-	globalCache.entries[key] = cacheItem{
-		data:       data,
-		expiration: time.Now().Add(time.Duration(ttlSeconds) * time.Second),
-	}
+	_ = c.cache.Set(key, data, time.Duration(c.cacheTTLSeconds)*time.Second)
 }
 
 // IsCacheEnabled 判断客户端是否启用了缓存
 //
-// 该方法返回当前客户端的缓存状态。当缓存启用时，客户端会尝试从内存缓存获取响应，
+// 该方法返回当前客户端的缓存状态。当缓存启用时，客户端会尝试从缓存后端获取响应，
 // 减少网络请求；当缓存禁用时，每次请求都会直接访问网络。
 //
 // 返回:
@@ -175,7 +216,7 @@ func (c *Client) GetCacheTTL() int {
 
 // SetCacheTTL 设置缓存条目的生存时间(TTL)
 //
-// 该方法用于调整缓存条目在内存中保留的时间长度（以秒为单位）。较短的TTL会使缓存更快过期，
+// 该方法用于调整缓存条目在缓存中保留的时间长度（以秒为单位）。较短的TTL会使缓存更快过期，
 // 更频繁地从网络获取最新数据；较长的TTL可以减少网络请求，但可能导致使用过时的数据。
 // 该设置仅影响新添加的缓存项，不会改变已存在缓存项的过期时间。
 //
@@ -197,7 +238,7 @@ func (c *Client) SetCacheTTL(seconds int) {
 
 // EnableCache 启用客户端缓存
 //
-// 该方法用于启用客户端的内存缓存功能。启用缓存后，客户端会尝试从缓存中获取之前
+// 该方法用于启用客户端的缓存功能。启用缓存后，客户端会尝试从缓存中获取之前
 // 请求过的数据，从而减少网络请求，提高性能和响应速度。对于频繁访问相同资源的场景，
 // 启用缓存可以显著减少API请求次数和网络带宽消耗。
 //
@@ -218,7 +259,7 @@ func (c *Client) EnableCache() {
 
 // DisableCache 禁用客户端缓存
 //
-// 该方法用于禁用客户端的内存缓存功能。禁用缓存后，每次请求都会直接访问网络获取最新数据，
+// 该方法用于禁用客户端的缓存功能。禁用缓存后，每次请求都会直接访问网络获取最新数据，
 // 而不会使用任何缓存的响应。这在需要确保始终获取最新数据的场景下非常有用，例如在开发或测试环境中，
 // 或者当数据变化频繁且实时性要求高的应用场景。
 //
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"log"
+	"strings"
+)
+
+// ChecksumPolicy 下载文件时的校验和验证策略
+//
+// 与Maven的checksumPolicy含义一致:
+//   - ChecksumPolicyIgnore: 不验证校验和（默认）
+//   - ChecksumPolicyWarn: 验证校验和，不匹配或缺少校验文件时只发出警告，仍返回下载内容
+//   - ChecksumPolicyFail: 验证校验和，不匹配或缺少校验文件时返回错误
+type ChecksumPolicy int
+
+const (
+	// ChecksumPolicyIgnore 不验证校验和
+	ChecksumPolicyIgnore ChecksumPolicy = iota
+
+	// ChecksumPolicyWarn 校验失败时发出警告
+	ChecksumPolicyWarn
+
+	// ChecksumPolicyFail 校验失败时返回错误
+	ChecksumPolicyFail
+)
+
+// String 返回策略名称，与Maven settings.xml中的取值一致
+func (p ChecksumPolicy) String() string {
+	switch p {
+	case ChecksumPolicyWarn:
+		return "warn"
+	case ChecksumPolicyFail:
+		return "fail"
+	default:
+		return "ignore"
+	}
+}
+
+// ErrChecksumUnavailable 远程仓库中没有可用的校验文件
+var ErrChecksumUnavailable = errors.New("no remote checksum available")
+
+// ChecksumMismatchError 下载内容与远程校验文件不一致
+//
+// 字段说明:
+//   - Path: 文件在仓库中的相对路径
+//   - Algorithm: 校验算法，如"sha1"、"sha256"、"sha512"
+//   - Expected: 远程校验文件中的值
+//   - Actual: 根据下载内容计算得到的值
+//
+// 使用示例:
+//
+//	data, err := client.Download(ctx, jarPath)
+//	var mismatch *api.ChecksumMismatchError
+//	if errors.As(err, &mismatch) {
+//	    log.Fatalf("%s的%s校验失败: 期望%s，实际%s", mismatch.Path, mismatch.Algorithm, mismatch.Expected, mismatch.Actual)
+//	}
+type ChecksumMismatchError struct {
+	Path      string
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+// Error 实现error接口
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("校验和不匹配 %s (%s): 期望 %s，实际 %s", e.Path, e.Algorithm, e.Expected, e.Actual)
+}
+
+// ChecksumWarningHandler 在ChecksumPolicyWarn策略下接收校验警告的回调函数
+//
+// err为*ChecksumMismatchError，或包装了ErrChecksumUnavailable的错误。
+type ChecksumWarningHandler func(filePath string, err error)
+
+// checksumAlgorithms 验证时依次尝试的校验算法，与Maven一致优先使用sha1
+//
+// 几乎所有仓库都提供.sha1文件，而.sha256和.sha512通常不存在，先尝试sha1可以避免多余的404请求。
+var checksumAlgorithms = []string{"sha1", "sha256", "sha512"}
+
+// newChecksumHash 根据算法名称创建哈希对象
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "sha1":
+		return sha1.New(), nil
+	case "md5":
+		return md5.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("不支持的校验和类型: %s", algorithm)
+}
+
+// computeChecksum 计算数据的校验和并以十六进制字符串返回
+func computeChecksum(algorithm string, data []byte) (string, error) {
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checksumDigest 增量计算下载内容在checksumAlgorithms中各算法下的校验和
+//
+// checksumDigest实现了io.Writer，流式下载时与目标写入器组合使用，
+// 下载完成后即可与远程校验文件比对，无需将整个文件读入内存。
+type checksumDigest struct {
+	hashes map[string]hash.Hash
+}
+
+// newChecksumDigest 创建校验和计算器
+func newChecksumDigest() *checksumDigest {
+	digest := &checksumDigest{hashes: make(map[string]hash.Hash, len(checksumAlgorithms))}
+	for _, algorithm := range checksumAlgorithms {
+		h, _ := newChecksumHash(algorithm)
+		digest.hashes[algorithm] = h
+	}
+	return digest
+}
+
+// Write 将数据写入所有算法的哈希计算
+func (d *checksumDigest) Write(p []byte) (int, error) {
+	for _, h := range d.hashes {
+		h.Write(p)
+	}
+	return len(p), nil
+}
+
+// Reset 清空已经写入的数据，重新开始计算
+func (d *checksumDigest) Reset() {
+	for _, h := range d.hashes {
+		h.Reset()
+	}
+}
+
+// Sum 返回指定算法的十六进制校验和，不支持的算法返回空字符串
+func (d *checksumDigest) Sum(algorithm string) string {
+	h, ok := d.hashes[strings.ToLower(algorithm)]
+	if !ok {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// parseChecksumFile 从校验文件内容中提取校验值
+//
+// 校验文件通常只包含十六进制字符串，有时会在后面附带文件名（sha1sum格式）。
+func parseChecksumFile(data []byte) string {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// isSignatureOrChecksumFile 判断仓库路径是否为校验文件或签名文件，这类文件本身没有校验文件
+func isSignatureOrChecksumFile(filePath string) bool {
+	return isChecksumFile(filePath) || strings.HasSuffix(filePath, ".asc")
+}
+
+// verifyDownload 按照客户端的校验策略验证从远程仓库下载的内容
+//
+// 依次尝试获取.sha1、.sha256和.sha512校验文件，使用第一个存在的校验文件进行比对，不再请求其余的校验文件。
+// 在ChecksumPolicyWarn策略下，校验失败只会通知警告处理函数并返回nil。
+//
+// 参数:
+//   - ctx: 上下文对象
+//   - filePath: 文件在仓库中的相对路径
+//   - data: 下载得到的文件内容
+//
+// 返回:
+//   - error: ChecksumPolicyFail策略下校验失败时返回*ChecksumMismatchError或包装了ErrChecksumUnavailable的错误
+func (c *Client) verifyDownload(ctx context.Context, filePath string, data []byte) error {
+	if c.checksumPolicy == ChecksumPolicyIgnore || isSignatureOrChecksumFile(filePath) {
+		return nil
+	}
+	digest := newChecksumDigest()
+	_, _ = digest.Write(data)
+	return c.verifyDownloadDigest(ctx, filePath, digest)
+}
+
+// verifyDownloadDigest 与verifyDownload相同，但使用流式下载过程中增量计算的校验和
+func (c *Client) verifyDownloadDigest(ctx context.Context, filePath string, digest *checksumDigest) error {
+	if c.checksumPolicy == ChecksumPolicyIgnore || isSignatureOrChecksumFile(filePath) {
+		return nil
+	}
+
+	err := c.compareRemoteChecksum(ctx, filePath, digest)
+	if err == nil {
+		return nil
+	}
+
+	// 上下文取消等错误与校验结果无关，直接返回
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if c.checksumPolicy == ChecksumPolicyWarn {
+		c.warnChecksum(filePath, err)
+		return nil
+	}
+	return err
+}
+
+// compareRemoteChecksum 获取远程校验文件并与内容比对
+func (c *Client) compareRemoteChecksum(ctx context.Context, filePath string, digest *checksumDigest) error {
+	for _, algorithm := range checksumAlgorithms {
+		remote, err := c.fetchRemote(ctx, filePath+"."+algorithm)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		expected := parseChecksumFile(remote)
+		if expected == "" {
+			continue
+		}
+
+		actual := digest.Sum(algorithm)
+		if actual != expected {
+			return &ChecksumMismatchError{
+				Path:      filePath,
+				Algorithm: algorithm,
+				Expected:  expected,
+				Actual:    actual,
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrChecksumUnavailable, filePath)
+}
+
+// warnChecksum 在Warn策略下报告校验失败
+func (c *Client) warnChecksum(filePath string, err error) {
+	if c.checksumWarningHandler != nil {
+		c.checksumWarningHandler(filePath, err)
+		return
+	}
+	log.Printf("sonatype-central-sdk: 校验警告 %s: %v", filePath, err)
+}
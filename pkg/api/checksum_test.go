@@ -0,0 +1,143 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// newChecksumFixtures 创建带有校验文件的测试仓库内容
+func newChecksumFixtures() map[string]string {
+	good := BuildArtifactPath("org.example", "good", "1.0", JAR)
+	bad := BuildArtifactPath("org.example", "bad", "1.0", JAR)
+	unsigned := BuildArtifactPath("org.example", "unsigned", "1.0", JAR)
+
+	goodSha1, _ := computeChecksum("sha1", []byte("good-jar"))
+	badSha256, _ := computeChecksum("sha256", []byte("bad-jar"))
+
+	return map[string]string{
+		good:            "good-jar",
+		good + ".sha1":  goodSha1 + "  good-1.0.jar\n",
+		bad:             "bad-jar",
+		bad + ".sha1":   "0000000000000000000000000000000000000000",
+		bad + ".sha256": badSha256,
+		unsigned:        "unsigned-jar",
+		BuildArtifactPath("org.example", "bad", "1.0", POM): "<project/>",
+	}
+}
+
+// newChecksumServer 创建只提供fixtures中校验文件的测试服务器，不自动生成校验和
+func newChecksumServer(t *testing.T, fixtures map[string]string) *testserver.Server {
+	server := newTestServer(t, fixtures)
+	server.Corpus().SetGenerateChecksums(false)
+	return server
+}
+
+// TestChecksumPolicyFail 测试Fail策略下校验失败返回错误
+func TestChecksumPolicyFail(t *testing.T) {
+	server := newChecksumServer(t, newChecksumFixtures())
+	client := newTestClient(server.URL, WithChecksumPolicy(ChecksumPolicyFail))
+	ctx := context.Background()
+
+	data, err := client.DownloadJar(ctx, "org.example", "good", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "good-jar", string(data))
+
+	// 与Maven一致优先使用sha1，即使sha256是正确的
+	_, err = client.DownloadJar(ctx, "org.example", "bad", "1.0")
+	var mismatch *ChecksumMismatchError
+	assert.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "sha1", mismatch.Algorithm)
+	assert.Equal(t, BuildArtifactPath("org.example", "bad", "1.0", JAR), mismatch.Path)
+
+	_, err = client.DownloadJar(ctx, "org.example", "unsigned", "1.0")
+	assert.ErrorIs(t, err, ErrChecksumUnavailable)
+
+	var buf bytes.Buffer
+	err = client.DownloadToWriter(ctx, BuildArtifactPath("org.example", "bad", "1.0", JAR), &buf)
+	assert.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, 0, buf.Len())
+
+	localPath := filepath.Join(t.TempDir(), "bad.jar")
+	err = client.DownloadFile(ctx, BuildArtifactPath("org.example", "bad", "1.0", JAR), localPath)
+	assert.True(t, errors.As(err, &mismatch))
+	assert.NoFileExists(t, localPath)
+
+	bundle, err := client.DownloadCompleteBundle(ctx, "org.example", "bad", "1.0")
+	assert.Error(t, err)
+	assert.True(t, errors.As(bundle.Errors["JAR"], &mismatch))
+
+	// 校验文件本身不做校验
+	_, err = client.Download(ctx, BuildArtifactPath("org.example", "good", "1.0", JAR)+".sha1")
+	assert.NoError(t, err)
+}
+
+// TestChecksumAlgorithmOrder 测试找到sha1校验文件后不再请求其他算法的校验文件
+func TestChecksumAlgorithmOrder(t *testing.T) {
+	var requested []string
+	handler := newChecksumServer(t, newChecksumFixtures()).Config.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, path.Ext(r.URL.Path))
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, WithChecksumPolicy(ChecksumPolicyFail))
+	_, err := client.DownloadJar(context.Background(), "org.example", "good", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".jar", ".sha1"}, requested)
+
+	// 没有sha1时按顺序尝试更强的算法
+	requested = nil
+	_, err = client.DownloadJar(context.Background(), "org.example", "unsigned", "1.0")
+	assert.ErrorIs(t, err, ErrChecksumUnavailable)
+	assert.Equal(t, []string{".jar", ".sha1", ".sha256", ".sha512"}, requested)
+}
+
+// TestChecksumPolicyWarn 测试Warn策略下校验失败只报告警告
+func TestChecksumPolicyWarn(t *testing.T) {
+	server := newChecksumServer(t, newChecksumFixtures())
+
+	var warnings []error
+	client := newTestClient(server.URL,
+		WithChecksumPolicy(ChecksumPolicyWarn),
+		WithChecksumWarningHandler(func(filePath string, err error) {
+			warnings = append(warnings, err)
+		}),
+	)
+
+	data, err := client.DownloadJar(context.Background(), "org.example", "bad", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "bad-jar", string(data))
+	assert.Len(t, warnings, 1)
+
+	var mismatch *ChecksumMismatchError
+	assert.True(t, errors.As(warnings[0], &mismatch))
+	assert.Equal(t, "warn", ChecksumPolicyWarn.String())
+}
+
+// TestDownloadWithChecksumMismatch 测试DownloadWithChecksum返回类型化的校验错误
+func TestDownloadWithChecksumMismatch(t *testing.T) {
+	fixtures := newChecksumFixtures()
+	fixtures[BuildArtifactPath("org.example", "good", "1.0", JAR)+".md5"] = "ffffffffffffffffffffffffffffffff"
+	server := newChecksumServer(t, fixtures)
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	_, checksum, err := client.DownloadWithChecksum(ctx, BuildArtifactPath("org.example", "good", "1.0", JAR), "sha1")
+	assert.NoError(t, err)
+	assert.Len(t, checksum, 40)
+
+	_, _, err = client.DownloadWithChecksum(ctx, BuildArtifactPath("org.example", "good", "1.0", JAR), "MD5")
+	var mismatch *ChecksumMismatchError
+	assert.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "md5", mismatch.Algorithm)
+}
@@ -1,11 +1,8 @@
 package api
 
 import (
+	"bytes"
 	"context"
-	"crypto/md5"
-	"crypto/sha1"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -72,6 +69,12 @@ func (c *Client) Download(ctx context.Context, filePath string) ([]byte, error)
 // 并将下载的内容写入指定的本地路径。这使得获取Maven制品并存储到本地变得非常简单，
 // 无需手动处理文件的创建和写入操作。
 //
+// 文件内容以流式方式写入磁盘，不会整体读入内存，适合下载体积很大的发行包。
+// 下载过程中内容先写入localPath+".part"文件，完成并通过验证后才重命名为localPath；
+// 如果传输中断，.part文件会被保留，再次调用时通过HTTP Range请求从断点继续下载；
+// 远程文件在两次调用之间发生变化时（通过If-Range判断），会丢弃.part文件中的内容重新下载。
+// 通过WithProgress设置的回调会收到下载进度。流式下载的内容不会写入内存缓存。
+//
 // 参数:
 //   - ctx: 上下文对象，用于控制请求的超时和取消
 //   - filePath: 文件在Maven仓库中的相对路径
@@ -104,19 +107,34 @@ func (c *Client) Download(ctx context.Context, filePath string) ([]byte, error)
 //	    log.Fatalf("下载并保存POM文件失败: %v", err)
 //	}
 func (c *Client) DownloadFile(ctx context.Context, filePath, localPath string) error {
-	data, err := c.Download(ctx, filePath)
-	if err != nil {
-		return err
-	}
-
 	// 确保目录存在
 	dir := filepath.Dir(localPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
+	partPath := localPath + partFileSuffix
+
+	// 本地仓库或缓存中已有文件时直接复制
+	if src, ok := c.openCachedDownload(filePath); ok {
+		defer src.Close()
+		if err := copyToFile(partPath, src); err != nil {
+			return err
+		}
+		return os.Rename(partPath, localPath)
+	}
+
+	// 从远程仓库流式下载到.part文件，中断后再次调用会从断点继续
+	source, err := c.downloadToPartFile(ctx, filePath, partPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(partPath, localPath); err != nil {
+		return err
+	}
 
-	// 写入文件
-	return os.WriteFile(localPath, data, 0644)
+	// 下载成功后写回本地仓库，写入失败不影响本次下载结果
+	c.saveToLocalRepository(filePath, localPath, source.ID)
+	return nil
 }
 
 // DownloadToWriter 下载文件并直接写入到io.Writer接口
@@ -125,6 +143,9 @@ func (c *Client) DownloadFile(ctx context.Context, filePath, localPath string) e
 // 这使得API更加灵活，支持将下载内容直接写入HTTP响应、内存缓冲区、压缩流或其他自定义writer。
 // 当需要对下载内容进行进一步处理而不是直接保存文件时，这个方法特别有用。
 //
+// 内容以流式方式写入writer，不会整体读入内存。启用了校验和或签名验证、或者配置了本地仓库时，
+// 内容会先下载到临时文件，验证通过后再写入writer，保证writer不会收到未经验证的内容。
+//
 // 参数:
 //   - ctx: 上下文对象，用于控制请求的超时和取消
 //   - filePath: 文件在Maven仓库中的相对路径
@@ -166,12 +187,185 @@ func (c *Client) DownloadFile(ctx context.Context, filePath, localPath string) e
 //	//     }
 //	// }
 func (c *Client) DownloadToWriter(ctx context.Context, filePath string, writer io.Writer) error {
-	data, err := c.Download(ctx, filePath)
+	// 本地仓库或缓存中已有文件时直接复制
+	if src, ok := c.openCachedDownload(filePath); ok {
+		defer src.Close()
+		_, err := io.Copy(writer, src)
+		return err
+	}
+
+	// 不需要验证也不需要写回本地仓库时，直接流式写入writer
+	if !c.verifiesDownload(filePath) && !c.usesLocalRepositoryFor(filePath) {
+		_, err := c.streamRemote(ctx, filePath, &downloadTarget{w: writer}, c.progress)
+		return err
+	}
+
+	// 验证通过之前不能向writer写入任何内容，先下载到临时文件
+	tmp, err := os.CreateTemp("", "sonatype-central-*"+partFileSuffix)
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+	defer os.Remove(tmpPath + partValidatorSuffix)
+
+	source, err := c.downloadToPartFile(ctx, filePath, tmpPath)
+	if err != nil {
+		return err
+	}
+	c.saveToLocalRepository(filePath, tmpPath, source.ID)
+
+	file, err := os.Open(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(writer, file)
+	return err
+}
+
+// partFileSuffix 未下载完成的文件使用的后缀
+const partFileSuffix = ".part"
+
+// partValidatorSuffix 保存.part文件内容对应的ETag或Last-Modified的文件后缀，追加在.part文件名之后
+const partValidatorSuffix = ".validator"
+
+// downloadToPartFile 从远程仓库流式下载文件到partPath并按客户端策略验证
+//
+// partPath中已有的内容被视为上次中断时下载的前半部分，只请求剩余部分；
+// 首次响应的ETag或Last-Modified保存在partPath旁边，续传时作为If-Range发送，
+// 远程文件已经变化时服务器返回完整文件，已有的部分会被丢弃。
+// 下载中断时保留partPath以便下次继续；验证失败时删除partPath，避免损坏的内容被续传。
+// 成功时返回提供文件的远程仓库。
+func (c *Client) downloadToPartFile(ctx context.Context, filePath, partPath string) (MavenRepository, error) {
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return MavenRepository{}, err
+	}
+	validatorPath := partPath + partValidatorSuffix
+
+	// 已有的部分同样需要计入校验和
+	digest := newChecksumDigest()
+	target := &downloadTarget{w: io.MultiWriter(file, digest)}
+	target.reset = func(validator string) error {
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		digest.Reset()
+		if validator == "" {
+			if err := os.Remove(validatorPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		}
+		return os.WriteFile(validatorPath, []byte(validator), 0644)
+	}
+	if data, err := os.ReadFile(validatorPath); err == nil {
+		target.validator = string(data)
+	}
+
+	var source MavenRepository
+	target.offset, err = io.Copy(digest, file)
+	if err == nil {
+		source, err = c.streamRemote(ctx, filePath, target, c.progress)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
+		// 没有下载到任何内容时不保留空的.part文件
+		if target.offset == 0 {
+			os.Remove(partPath)
+			os.Remove(validatorPath)
+		}
+		return MavenRepository{}, err
+	}
+	os.Remove(validatorPath)
+
+	if err := c.verifyDownloadedFile(ctx, filePath, digest, partPath); err != nil {
+		os.Remove(partPath)
+		return MavenRepository{}, err
+	}
+	return source, nil
+}
+
+// verifyDownloadedFile 按客户端的校验和签名策略验证已下载到本地文件的内容
+func (c *Client) verifyDownloadedFile(ctx context.Context, filePath string, digest *checksumDigest, localPath string) error {
+	if err := c.verifyDownloadDigest(ctx, filePath, digest); err != nil {
 		return err
 	}
+	if !c.verifiesSignature(filePath) {
+		return nil
+	}
 
-	_, err = writer.Write(data)
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = c.verifyDownloadSignature(ctx, filePath, file)
+	return err
+}
+
+// verifiesDownload 判断下载该文件时是否需要进行校验和或签名验证
+func (c *Client) verifiesDownload(filePath string) bool {
+	return (c.checksumPolicy != ChecksumPolicyIgnore && !isSignatureOrChecksumFile(filePath)) || c.verifiesSignature(filePath)
+}
+
+// usesLocalRepositoryFor 判断该文件的下载是否经过本地仓库
+func (c *Client) usesLocalRepositoryFor(filePath string) bool {
+	return c.localRepository != nil && usesLocalRepository(filePath)
+}
+
+// openCachedDownload 从本地仓库或缓存中打开已有的文件，不存在时返回false
+func (c *Client) openCachedDownload(filePath string) (io.ReadCloser, bool) {
+	if c.usesLocalRepositoryFor(filePath) {
+		if file, err := c.localRepository.Open(filePath); err == nil {
+			return file, true
+		}
+	}
+	if c.cacheEnabled {
+		cacheKey, err := c.downloadCacheKey(filePath)
+		if err != nil {
+			return nil, false
+		}
+		if data, found := c.getFromCache(cacheKey); found {
+			return io.NopCloser(bytes.NewReader(data)), true
+		}
+	}
+	return nil, false
+}
+
+// saveToLocalRepository 将已下载的本地文件写回本地仓库，在_remote.repositories中记录为来自repositoryId，写入失败时忽略
+func (c *Client) saveToLocalRepository(filePath, localPath, repositoryId string) {
+	if !c.usesLocalRepositoryFor(filePath) {
+		return
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	_ = c.localRepository.WriteFrom(filePath, file, repositoryId)
+}
+
+// copyToFile 将src的内容写入localPath
+func copyToFile(localPath string, src io.Reader) error {
+	file, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, src)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(localPath)
+	}
 	return err
 }
 
@@ -650,7 +844,7 @@ func (c *Client) DownloadMultipleFiles(ctx context.Context, groupId, artifactId,
 // 返回:
 //   - []byte: 下载的文件内容
 //   - string: 计算出的校验和（十六进制字符串）
-//   - error: 如果下载失败或校验和不匹配，返回错误信息
+//   - error: 如果下载失败返回错误信息；校验和不匹配时返回*ChecksumMismatchError
 //
 // 例子:
 //
@@ -681,20 +875,14 @@ func (c *Client) DownloadWithChecksum(ctx context.Context, filePath string, chec
 	}
 
 	// 计算校验和
-	var checksum string
-	switch strings.ToLower(checksumType) {
-	case "sha1":
-		hash := sha1.Sum(data)
-		checksum = hex.EncodeToString(hash[:])
-	case "md5":
-		hash := md5.Sum(data)
-		checksum = hex.EncodeToString(hash[:])
-	case "sha256":
-		hash := sha256.Sum256(data)
-		checksum = hex.EncodeToString(hash[:])
-	default:
+	checksumType = strings.ToLower(checksumType)
+	if checksumType != "sha1" && checksumType != "md5" && checksumType != "sha256" {
 		return data, "", fmt.Errorf("不支持的校验和类型: %s", checksumType)
 	}
+	checksum, err := computeChecksum(checksumType, data)
+	if err != nil {
+		return data, "", err
+	}
 
 	// 下载对应的校验和文件进行验证
 	checksumFilePath := filePath + "." + checksumType
@@ -705,17 +893,15 @@ func (c *Client) DownloadWithChecksum(ctx context.Context, filePath string, chec
 		return data, checksum, nil
 	}
 
-	// 校验和文件通常只包含十六进制字符串
-	remoteChecksum := strings.TrimSpace(string(checksumFileData))
-
-	// 有时校验和文件可能包含文件名，需要提取第一段
-	if parts := strings.Fields(remoteChecksum); len(parts) > 0 {
-		remoteChecksum = parts[0]
-	}
-
-	// 比较校验和
+	// 比较校验和，校验和文件可能在校验值后附带文件名
+	remoteChecksum := parseChecksumFile(checksumFileData)
 	if checksum != remoteChecksum {
-		return data, checksum, fmt.Errorf("校验和不匹配: 计算得到 %s，远程值 %s", checksum, remoteChecksum)
+		return data, checksum, &ChecksumMismatchError{
+			Path:      filePath,
+			Algorithm: checksumType,
+			Expected:  remoteChecksum,
+			Actual:    checksum,
+		}
 	}
 
 	return data, checksum, nil
@@ -786,17 +972,25 @@ func (c *Client) DownloadAsync(ctx context.Context, filePath string) <-chan Asyn
 }
 
 // ArtifactBundle 表示一个制品包，包含所有相关文件
+//
+// OtherFiles和OtherFileTypes的键都是额外文件的Type，OtherFileTypes记录文件的扩展名和分类器，
+// 保存制品包时据此生成文件名；没有对应记录的额外文件按"<Type小写>"分类器的jar文件保存。
+//
+// Signatures仅在客户端通过WithSignatureVerification配置了公钥环时填充，
+// 键与Errors相同（如"POM"、"JAR"或额外文件的Type），记录每个成功下载文件的签名验证结果。
 type ArtifactBundle struct {
-	GroupId    string
-	ArtifactId string
-	Version    string
-	Pom        []byte
-	Jar        []byte
-	Sources    []byte
-	Javadoc    []byte
-	Tests      []byte
-	OtherFiles map[string][]byte
-	Errors     map[string]error
+	GroupId        string
+	ArtifactId     string
+	Version        string
+	Pom            []byte
+	Jar            []byte
+	Sources        []byte
+	Javadoc        []byte
+	Tests          []byte
+	OtherFiles     map[string][]byte
+	OtherFileTypes map[string]ArtifactFile
+	Errors         map[string]error
+	Signatures     map[string]*SignatureVerification
 }
 
 // DownloadCompleteBundle 下载制品的完整包，包括所有可用的相关文件
@@ -845,11 +1039,13 @@ type ArtifactBundle struct {
 func (c *Client) DownloadCompleteBundle(ctx context.Context, groupId, artifactId, version string, extraFiles ...ArtifactFile) (*ArtifactBundle, error) {
 	// 创建基本的bundle结构
 	bundle := &ArtifactBundle{
-		GroupId:    groupId,
-		ArtifactId: artifactId,
-		Version:    version,
-		OtherFiles: make(map[string][]byte),
-		Errors:     make(map[string]error),
+		GroupId:        groupId,
+		ArtifactId:     artifactId,
+		Version:        version,
+		OtherFiles:     make(map[string][]byte),
+		OtherFileTypes: make(map[string]ArtifactFile),
+		Errors:         make(map[string]error),
+		Signatures:     make(map[string]*SignatureVerification),
 	}
 
 	// 必要文件列表
@@ -876,7 +1072,8 @@ func (c *Client) DownloadCompleteBundle(ctx context.Context, groupId, artifactId
 			defer wg.Done()
 
 			path := BuildArtifactPath(groupId, artifactId, version, ft.Extension, ft.Classifier)
-			data, err := c.Download(ctx, path)
+			data, verification, err := c.downloadWithSignature(ctx, path)
+			verification = c.bundleSignature(ctx, path, data, verification, err)
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -889,6 +1086,9 @@ func (c *Client) DownloadCompleteBundle(ctx context.Context, groupId, artifactId
 			} else {
 				*target = data
 			}
+			if verification != nil {
+				bundle.Signatures[name] = verification
+			}
 		}(file.fileType, file.target, file.name)
 	}
 
@@ -899,7 +1099,8 @@ func (c *Client) DownloadCompleteBundle(ctx context.Context, groupId, artifactId
 			defer wg.Done()
 
 			path := BuildArtifactPath(groupId, artifactId, version, ft.Extension, ft.Classifier)
-			data, err := c.Download(ctx, path)
+			data, verification, err := c.downloadWithSignature(ctx, path)
+			verification = c.bundleSignature(ctx, path, data, verification, err)
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -908,6 +1109,10 @@ func (c *Client) DownloadCompleteBundle(ctx context.Context, groupId, artifactId
 				bundle.Errors[ft.Type] = err
 			} else {
 				bundle.OtherFiles[ft.Type] = data
+				bundle.OtherFileTypes[ft.Type] = ft
+			}
+			if verification != nil {
+				bundle.Signatures[ft.Type] = verification
 			}
 		}(extraFile)
 	}
@@ -922,11 +1127,38 @@ func (c *Client) DownloadCompleteBundle(ctx context.Context, groupId, artifactId
 	return bundle, nil
 }
 
+// bundleSignature 为制品包中的文件获取签名验证结果
+//
+// 下载时已经验证过签名的文件直接使用下载过程中的验证结果，不会重复获取.asc签名；
+// 只有从本地仓库或缓存读取、下载时未验证签名的文件才会重新验证。
+// 客户端没有配置公钥环、文件下载失败或获取签名时出错时返回nil。
+func (c *Client) bundleSignature(ctx context.Context, path string, data []byte, verification *SignatureVerification, downloadErr error) *SignatureVerification {
+	if c.signatureKeyring == nil {
+		return nil
+	}
+	if verification != nil {
+		return verification
+	}
+	if downloadErr != nil {
+		var sigErr *SignatureError
+		if errors.As(downloadErr, &sigErr) {
+			return sigErr.Verification
+		}
+		return nil
+	}
+	verification, err := c.VerifySignature(ctx, path, data, c.signatureKeyring)
+	if err != nil {
+		return nil
+	}
+	return verification
+}
+
 // SaveBundle 将制品包保存到本地目录
 //
 // 此方法将下载的制品包保存到本地文件系统，遵循Maven仓库的标准目录结构
 // （groupId/artifactId/version）。它会自动创建必要的目录结构，并仅保存成功
-// 下载的文件。
+// 下载的文件。如果需要同时生成校验文件和_remote.repositories，使保存的文件能被Maven
+// 直接复用，请使用SaveBundleToLocalRepository或LocalRepository.SaveBundle。
 //
 // 参数:
 //   - bundle: 要保存的制品包，通常是DownloadCompleteBundle方法的返回结果
@@ -958,49 +1190,47 @@ func (c *Client) DownloadCompleteBundle(ctx context.Context, groupId, artifactId
 //
 //	fmt.Println("制品包已成功保存到本地仓库")
 func (c *Client) SaveBundle(bundle *ArtifactBundle, baseDir string) error {
-	// 构建目标目录
-	targetDir := filepath.Join(baseDir,
-		strings.ReplaceAll(bundle.GroupId, ".", string(filepath.Separator)),
-		bundle.ArtifactId,
-		bundle.Version)
-
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return err
-	}
-
-	// 保存文件
-	filesToSave := []struct {
-		data     []byte
-		fileName string
-	}{
-		{bundle.Pom, fmt.Sprintf("%s-%s.pom", bundle.ArtifactId, bundle.Version)},
-		{bundle.Jar, fmt.Sprintf("%s-%s.jar", bundle.ArtifactId, bundle.Version)},
-		{bundle.Sources, fmt.Sprintf("%s-%s-sources.jar", bundle.ArtifactId, bundle.Version)},
-		{bundle.Javadoc, fmt.Sprintf("%s-%s-javadoc.jar", bundle.ArtifactId, bundle.Version)},
-		{bundle.Tests, fmt.Sprintf("%s-%s-tests.jar", bundle.ArtifactId, bundle.Version)},
-	}
-
-	for _, file := range filesToSave {
-		if file.data == nil || len(file.data) == 0 {
-			continue
+	for _, file := range bundleFiles(bundle) {
+		filePath := filepath.Join(baseDir, filepath.FromSlash(file.path))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
 		}
-
-		filePath := filepath.Join(targetDir, file.fileName)
 		if err := os.WriteFile(filePath, file.data, 0644); err != nil {
 			return err
 		}
 	}
 
-	// 保存其他文件
-	for fileType, data := range bundle.OtherFiles {
-		fileName := fmt.Sprintf("%s-%s-%s.%s",
-			bundle.ArtifactId, bundle.Version, strings.ToLower(fileType), "jar")
-		filePath := filepath.Join(targetDir, fileName)
+	return nil
+}
 
-		if err := os.WriteFile(filePath, data, 0644); err != nil {
-			return err
-		}
+// SaveBundleToLocalRepository 将制品包保存到客户端挂载的本地Maven仓库
+//
+// 保存时会同时写入.sha1/.md5校验文件并更新_remote.repositories，使Maven构建可以直接使用这些文件。
+// _remote.repositories中记录的来源是最近一次提供该GAV文件的远程仓库，没有记录时为仓库列表中的第一个仓库。
+//
+// 参数:
+//   - bundle: 要保存的制品包，通常是DownloadCompleteBundle方法的返回结果
+//
+// 返回:
+//   - error: 客户端没有通过WithLocalRepository挂载本地仓库，或写入文件失败时返回错误
+//
+// 例子:
+//
+//	repo, _ := api.DefaultLocalRepository()
+//	client := api.NewClient(api.WithLocalRepository(repo))
+//
+//	bundle, err := client.DownloadCompleteBundle(ctx, "org.apache.commons", "commons-lang3", "3.12.0")
+//	if err != nil {
+//	    log.Fatalf("下载制品包失败: %v", err)
+//	}
+//
+//	if err := client.SaveBundleToLocalRepository(bundle); err != nil {
+//	    log.Fatalf("保存制品包失败: %v", err)
+//	}
+func (c *Client) SaveBundleToLocalRepository(bundle *ArtifactBundle) error {
+	if c.localRepository == nil {
+		return errors.New("客户端没有配置本地仓库")
 	}
-
-	return nil
+	dir := fmt.Sprintf("%s/%s/%s", strings.ReplaceAll(bundle.GroupId, ".", "/"), bundle.ArtifactId, bundle.Version)
+	return c.localRepository.SaveBundle(bundle, c.sourceRepository(dir).ID)
 }
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -152,3 +159,175 @@ func TestSaveBundle(t *testing.T) {
 	_, err = os.Stat(pomPath)
 	assert.Nil(t, err, "POM文件应该存在于指定路径")
 }
+
+// newRangeServer 创建支持Range请求的离线测试服务器，并记录每次请求的Range头
+//
+// interrupt中的文件在第一次被请求时只返回前一半内容就断开连接。
+func newRangeServer(t *testing.T, files map[string]string, interrupt map[string]bool) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var ranges []string
+	interrupted := make(map[string]bool)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filePath := strings.TrimPrefix(r.URL.Path, "/maven2/")
+		content, ok := files[filePath]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		first := interrupt[filePath] && !interrupted[filePath]
+		interrupted[filePath] = true
+		mu.Unlock()
+
+		if first {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			_, _ = w.Write([]byte(content[:len(content)/2]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, path.Base(filePath), time.Time{}, strings.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &ranges
+}
+
+// TestDownloadFileResume 测试DownloadFile通过Range请求从.part文件续传
+func TestDownloadFileResume(t *testing.T) {
+	content := strings.Repeat("0123456789", 500)
+	zip := BuildArtifactPath("org.example", "dist", "1.0", "zip")
+	sha1Sum, _ := computeChecksum("sha1", []byte(content))
+	server, ranges := newRangeServer(t, map[string]string{zip: content, zip + ".sha1": sha1Sum}, nil)
+
+	var progress []int64
+	client := newTestClient(server.URL,
+		WithChecksumPolicy(ChecksumPolicyFail),
+		WithProgress(func(downloaded, total int64, fileName string) {
+			assert.Equal(t, int64(len(content)), total)
+			assert.Equal(t, "dist-1.0.zip", fileName)
+			progress = append(progress, downloaded)
+		}),
+	)
+
+	localPath := filepath.Join(t.TempDir(), "dist.zip")
+	assert.NoError(t, os.WriteFile(localPath+".part", []byte(content[:1000]), 0644))
+
+	err := client.DownloadFile(context.Background(), zip, localPath)
+	assert.NoError(t, err)
+	data, _ := os.ReadFile(localPath)
+	assert.Equal(t, content, string(data))
+	assert.NoFileExists(t, localPath+".part")
+	assert.Equal(t, "bytes=1000-", (*ranges)[0])
+	assert.Greater(t, progress[0], int64(1000))
+	assert.Equal(t, int64(len(content)), progress[len(progress)-1])
+
+	// .part文件已经完整时服务器返回416，视为下载完成
+	assert.NoError(t, os.WriteFile(localPath+".part", []byte(content), 0644))
+	assert.NoError(t, client.DownloadFile(context.Background(), zip, localPath))
+	data, _ = os.ReadFile(localPath)
+	assert.Equal(t, content, string(data))
+
+	// 续传的内容与已有部分拼接后校验失败时，删除.part文件
+	assert.NoError(t, os.WriteFile(localPath+".part", []byte(strings.Repeat("x", 1000)), 0644))
+	err = client.DownloadFile(context.Background(), zip, localPath)
+	var mismatch *ChecksumMismatchError
+	assert.True(t, errors.As(err, &mismatch))
+	assert.NoFileExists(t, localPath+".part")
+}
+
+// TestDownloadFileResumeWithoutRangeSupport 测试服务器忽略Range请求时跳过已有的部分
+func TestDownloadFileResumeWithoutRangeSupport(t *testing.T) {
+	content := strings.Repeat("abcdefghij", 100)
+	zip := BuildArtifactPath("org.example", "dist", "1.0", "zip")
+	server := newTestServer(t, map[string]string{zip: content})
+	client := newTestClient(server.URL)
+
+	localPath := filepath.Join(t.TempDir(), "dist.zip")
+	assert.NoError(t, os.WriteFile(localPath+".part", []byte(content[:300]), 0644))
+
+	assert.NoError(t, client.DownloadFile(context.Background(), zip, localPath))
+	data, _ := os.ReadFile(localPath)
+	assert.Equal(t, content, string(data))
+}
+
+// TestDownloadFileResumeChangedFile 测试续传时通过If-Range发现远程文件已经变化，从头重新下载
+func TestDownloadFileResumeChangedFile(t *testing.T) {
+	zip := BuildArtifactPath("org.example", "dist", "1.0", "zip")
+	var mu sync.Mutex
+	content, etag := strings.Repeat("0123456789", 400), `"v1"`
+	var requests []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		first := len(requests) == 0
+		requests = append(requests, r.Header.Clone())
+		current, currentEtag := content, etag
+		mu.Unlock()
+
+		w.Header().Set("ETag", currentEtag)
+		if first {
+			w.Header().Set("Content-Length", strconv.Itoa(len(current)))
+			_, _ = w.Write([]byte(current[:len(current)/2]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, path.Base(zip), time.Time{}, strings.NewReader(current))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newTestClient(server.URL)
+	localPath := filepath.Join(t.TempDir(), "dist.zip")
+	assert.Error(t, client.DownloadFile(context.Background(), zip, localPath))
+	validator, err := os.ReadFile(localPath + ".part" + partValidatorSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, `"v1"`, string(validator))
+
+	// 中断期间发布了新的内容，服务器忽略Range返回完整文件
+	mu.Lock()
+	content, etag = strings.Repeat("abcdefghij", 300), `"v2"`
+	mu.Unlock()
+	assert.NoError(t, client.DownloadFile(context.Background(), zip, localPath))
+	data, _ := os.ReadFile(localPath)
+	assert.Equal(t, strings.Repeat("abcdefghij", 300), string(data))
+	assert.Equal(t, "bytes=2000-", requests[1].Get("Range"))
+	assert.Equal(t, `"v1"`, requests[1].Get("If-Range"))
+	assert.NoFileExists(t, localPath+".part")
+	assert.NoFileExists(t, localPath+".part"+partValidatorSuffix)
+}
+
+// TestDownloadInterruptedTransfer 测试传输中断后的重试和跨调用续传
+func TestDownloadInterruptedTransfer(t *testing.T) {
+	content := strings.Repeat("0123456789", 400)
+	zip := BuildArtifactPath("org.example", "dist", "1.0", "zip")
+	ctx := context.Background()
+
+	// 允许重试时，同一次调用内从断点继续
+	server, ranges := newRangeServer(t, map[string]string{zip: content}, map[string]bool{zip: true})
+	client := newTestClient(server.URL, WithMaxRetries(2), WithRetryBackoff(1))
+	var buf bytes.Buffer
+	assert.NoError(t, client.DownloadToWriter(ctx, zip, &buf))
+	assert.Equal(t, content, buf.String())
+	assert.Equal(t, []string{"", "bytes=2000-"}, *ranges)
+
+	// 不允许重试时，中断的下载保留.part文件，下次调用继续
+	server, ranges = newRangeServer(t, map[string]string{zip: content}, map[string]bool{zip: true})
+	client = newTestClient(server.URL)
+	localPath := filepath.Join(t.TempDir(), "dist.zip")
+	assert.Error(t, client.DownloadFile(ctx, zip, localPath))
+	info, err := os.Stat(localPath + ".part")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2000), info.Size())
+
+	assert.NoError(t, client.DownloadFile(ctx, zip, localPath))
+	data, _ := os.ReadFile(localPath)
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, "bytes=2000-", (*ranges)[1])
+
+	// 不存在的文件不留下空的.part文件
+	missingPath := filepath.Join(t.TempDir(), "missing.zip")
+	err = client.DownloadFile(ctx, BuildArtifactPath("org.example", "missing", "1.0", "zip"), missingPath)
+	assert.True(t, isNotFoundError(err))
+	assert.NoFileExists(t, missingPath+".part")
+}
@@ -0,0 +1,389 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// fileCacheIndexName 缓存索引文件名
+	fileCacheIndexName = "index.json"
+
+	// fileCacheObjectsDir 存放缓存内容的子目录
+	fileCacheObjectsDir = "objects"
+
+	// fileCacheIndexDelay 索引变更后延迟写入磁盘的时间，期间的多次变更合并为一次写入
+	fileCacheIndexDelay = time.Second
+)
+
+// fileCacheEntry 文件缓存索引中的一个条目
+//
+// 字段说明:
+//   - Sha1: 缓存内容的SHA-1，同时也是内容文件的文件名
+//   - Size: 缓存内容的字节数
+//   - Expiration: 过期时间，nil表示永不过期
+//   - Accessed: 最近一次访问时间，用于LRU淘汰
+type fileCacheEntry struct {
+	Sha1       string     `json:"sha1"`
+	Size       int64      `json:"size"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+	Accessed   time.Time  `json:"accessed"`
+}
+
+// expired 判断条目在now时是否已经过期
+func (e *fileCacheEntry) expired(now time.Time) bool {
+	return e.Expiration != nil && now.After(*e.Expiration)
+}
+
+// fileCacheIndex 文件缓存索引的持久化格式
+type fileCacheIndex struct {
+	Entries map[string]*fileCacheEntry `json:"entries"`
+}
+
+// FileCache 基于文件系统的持久化缓存
+//
+// FileCache实现了Cache接口，缓存内容保存在磁盘上，进程重启后依然有效，适合在CI环境中
+// 跨构建复用已下载的JAR、POM等文件。缓存目录的结构如下:
+//
+//	<dir>/index.json                  缓存键到内容的索引
+//	<dir>/objects/ab/ab12...ef        以内容SHA-1命名的缓存内容
+//
+// 缓存内容按SHA-1进行内容寻址，不同键对应相同内容时（例如不同镜像地址下载到的同一个JAR）
+// 在磁盘上只保存一份。读取时会重新校验SHA-1，损坏的内容会被自动丢弃。
+//
+// 当缓存内容的总大小超过容量上限时，会按照最近访问时间淘汰最久未使用的条目（LRU）。
+// 访问时间只在内存中更新，并在下一次写入索引或调用Flush时持久化。
+//
+// 索引不会在每次写入时重写：变更后一秒内的所有变更合并为一次写入，
+// 因此程序退出前应调用Flush，否则最后一秒内写入的条目不会出现在索引中。
+//
+// 注意事项:
+//   - FileCache在单个进程内是并发安全的，但不支持多个进程同时写入同一个缓存目录
+//   - 过期条目在被访问或被淘汰时删除
+type FileCache struct {
+	dir      string
+	maxBytes int64
+
+	mutex     sync.Mutex
+	entries   map[string]*fileCacheEntry
+	refs      map[string]int
+	totalSize int64
+	dirty     bool
+	saveTimer *time.Timer
+}
+
+// NewFileCache 创建或打开一个文件系统缓存
+//
+// 如果目录中已经存在之前的缓存，会加载其索引并继续使用；索引损坏时会以空缓存重新开始，
+// 索引中引用但磁盘上已不存在的内容会被忽略。
+//
+// 参数:
+//   - dir: 缓存目录，不存在时会自动创建
+//   - maxBytes: 缓存内容的总大小上限（字节），小于或等于0表示不限制
+//
+// 返回:
+//   - *FileCache: 文件缓存实例
+//   - error: 如果无法创建缓存目录，返回错误
+//
+// 使用示例:
+//
+//	cache, err := api.NewFileCache(filepath.Join(os.Getenv("HOME"), ".cache", "sonatype-central"), 1<<30)
+//	if err != nil {
+//	    log.Fatalf("创建缓存失败: %v", err)
+//	}
+//	defer cache.Flush()
+//
+//	client := api.NewClient(
+//	    api.WithCacheBackend(cache),
+//	    api.WithCache(true, 30*24*3600),
+//	)
+func NewFileCache(dir string, maxBytes int64) (*FileCache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, fileCacheObjectsDir), 0755); err != nil {
+		return nil, fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+
+	f := &FileCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*fileCacheEntry),
+		refs:     make(map[string]int),
+	}
+	f.loadIndex()
+
+	return f, nil
+}
+
+// loadIndex 从磁盘加载缓存索引
+func (f *FileCache) loadIndex() {
+	data, err := os.ReadFile(filepath.Join(f.dir, fileCacheIndexName))
+	if err != nil {
+		return
+	}
+
+	var index fileCacheIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return
+	}
+
+	for key, entry := range index.Entries {
+		if entry == nil || len(entry.Sha1) < 2 {
+			continue
+		}
+		// 旧版本的索引用零值表示永不过期
+		if entry.Expiration != nil && entry.Expiration.IsZero() {
+			entry.Expiration = nil
+		}
+		if f.refs[entry.Sha1] == 0 {
+			info, err := os.Stat(f.objectPath(entry.Sha1))
+			if err != nil {
+				f.dirty = true
+				continue
+			}
+			entry.Size = info.Size()
+			f.totalSize += info.Size()
+		}
+		f.refs[entry.Sha1]++
+		f.entries[key] = entry
+	}
+}
+
+// objectPath 返回指定SHA-1内容文件的路径
+func (f *FileCache) objectPath(sum string) string {
+	return filepath.Join(f.dir, fileCacheObjectsDir, sum[:2], sum)
+}
+
+// Get 从文件缓存中获取内容
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entry, exists := f.entries[key]
+	if !exists {
+		return nil, false
+	}
+
+	if entry.expired(time.Now()) {
+		f.removeEntry(key)
+		f.scheduleSave()
+		return nil, false
+	}
+
+	data, err := os.ReadFile(f.objectPath(entry.Sha1))
+	if err != nil || sha1Hex(data) != entry.Sha1 {
+		f.removeEntry(key)
+		f.scheduleSave()
+		return nil, false
+	}
+
+	entry.Accessed = time.Now()
+	f.dirty = true
+	return data, true
+}
+
+// Set 将内容写入文件缓存
+//
+// 写入后如果缓存总大小超过上限，会淘汰最久未访问的条目。单个内容大于容量上限时不会被缓存，
+// 并返回错误。内容文件立即写入，索引延迟写入，写入索引的错误由Flush返回。
+func (f *FileCache) Set(key string, value []byte, ttl time.Duration) error {
+	size := int64(len(value))
+	if f.maxBytes > 0 && size > f.maxBytes {
+		return fmt.Errorf("缓存内容大小%d字节超过容量上限%d字节", size, f.maxBytes)
+	}
+
+	sum := sha1Hex(value)
+	now := time.Now()
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.refs[sum] == 0 {
+		if err := writeFileAtomic(f.objectPath(sum), value); err != nil {
+			return fmt.Errorf("写入缓存内容失败: %w", err)
+		}
+		f.totalSize += size
+	}
+
+	// 先增加新内容的引用计数，避免替换相同内容的条目时误删内容文件
+	f.refs[sum]++
+	f.removeEntry(key)
+
+	entry := &fileCacheEntry{Sha1: sum, Size: size, Accessed: now}
+	if ttl > 0 {
+		expiration := now.Add(ttl)
+		entry.Expiration = &expiration
+	}
+	f.entries[key] = entry
+	f.dirty = true
+
+	f.evict(key)
+	f.scheduleSave()
+	return nil
+}
+
+// Delete 从文件缓存中删除内容
+func (f *FileCache) Delete(key string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, exists := f.entries[key]; !exists {
+		return nil
+	}
+	f.removeEntry(key)
+	f.scheduleSave()
+	return nil
+}
+
+// Clear 清空文件缓存，删除所有缓存内容
+func (f *FileCache) Clear() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := os.RemoveAll(filepath.Join(f.dir, fileCacheObjectsDir)); err != nil {
+		return fmt.Errorf("清空缓存目录失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(f.dir, fileCacheObjectsDir), 0755); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+
+	f.entries = make(map[string]*fileCacheEntry)
+	f.refs = make(map[string]int)
+	f.totalSize = 0
+	f.dirty = true
+	return f.saveIndex()
+}
+
+// Flush 将内存中尚未写入的索引变更写入磁盘
+//
+// 索引变更会延迟写入，程序退出前应调用Flush。Get只在内存中更新访问时间，
+// 长时间运行的程序也可以定期调用Flush，使进程重启后的LRU顺序保持准确。
+func (f *FileCache) Flush() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.saveIndex()
+}
+
+// Size 返回缓存内容在磁盘上占用的总字节数（相同内容只计算一次）
+func (f *FileCache) Size() int64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.totalSize
+}
+
+// Len 返回缓存条目的数量
+func (f *FileCache) Len() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.entries)
+}
+
+// removeEntry 删除条目，内容不再被任何条目引用时一并删除内容文件
+func (f *FileCache) removeEntry(key string) {
+	entry, exists := f.entries[key]
+	if !exists {
+		return
+	}
+	delete(f.entries, key)
+	f.dirty = true
+
+	f.refs[entry.Sha1]--
+	if f.refs[entry.Sha1] <= 0 {
+		delete(f.refs, entry.Sha1)
+		_ = os.Remove(f.objectPath(entry.Sha1))
+		f.totalSize -= entry.Size
+	}
+}
+
+// evict 按照LRU策略淘汰条目直到总大小不超过上限，keep指定的条目不会被淘汰
+func (f *FileCache) evict(keep string) {
+	if f.maxBytes <= 0 || f.totalSize <= f.maxBytes {
+		return
+	}
+
+	keys := make([]string, 0, len(f.entries))
+	for key := range f.entries {
+		if key != keep {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return f.entries[keys[i]].Accessed.Before(f.entries[keys[j]].Accessed)
+	})
+
+	for _, key := range keys {
+		if f.totalSize <= f.maxBytes {
+			break
+		}
+		f.removeEntry(key)
+	}
+}
+
+// scheduleSave 在fileCacheIndexDelay之后写入索引，已经安排了写入时不重复安排
+func (f *FileCache) scheduleSave() {
+	if !f.dirty || f.saveTimer != nil {
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(fileCacheIndexDelay, func() {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		// 等待锁期间索引可能已经由Flush写入
+		if f.saveTimer == timer {
+			_ = f.saveIndex()
+		}
+	})
+	f.saveTimer = timer
+}
+
+// saveIndex 将索引写入磁盘，并取消尚未执行的延迟写入
+func (f *FileCache) saveIndex() error {
+	if f.saveTimer != nil {
+		f.saveTimer.Stop()
+		f.saveTimer = nil
+	}
+	if !f.dirty {
+		return nil
+	}
+	data, err := json.Marshal(&fileCacheIndex{Entries: f.entries})
+	if err != nil {
+		return fmt.Errorf("序列化缓存索引失败: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(f.dir, fileCacheIndexName), data); err != nil {
+		return fmt.Errorf("写入缓存索引失败: %w", err)
+	}
+	f.dirty = false
+	return nil
+}
+
+// writeFileAtomic 先写入临时文件再重命名，避免进程中断时留下不完整的文件
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// sha1Hex 计算数据的SHA-1并以十六进制字符串返回
+func sha1Hex(data []byte) string {
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFileCachePersistence 测试文件缓存在重新打开后仍然有效
+func TestFileCachePersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	cache, err := NewFileCache(dir, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, cache.Set("a", []byte("hello"), 0))
+	assert.NoError(t, cache.Set("b", []byte("hello"), time.Hour))
+	assert.NoError(t, cache.Set("c", []byte("world"), 0))
+
+	// 相同内容只保存一份
+	assert.Equal(t, int64(10), cache.Size())
+	assert.FileExists(t, filepath.Join(dir, fileCacheObjectsDir, "aa", sha1Hex([]byte("hello"))))
+
+	// 索引延迟写入，多次写入合并为一次
+	assert.NoFileExists(t, filepath.Join(dir, fileCacheIndexName))
+	assert.NoError(t, cache.Flush())
+	index, err := os.ReadFile(filepath.Join(dir, fileCacheIndexName))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(index), `"expiration"`), "永不过期的条目不写入过期时间")
+
+	reopened, err := NewFileCache(dir, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, reopened.Len())
+	assert.Equal(t, int64(10), reopened.Size())
+
+	data, ok := reopened.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, "hello", string(data))
+
+	// 删除一个键不影响共享相同内容的其他键
+	assert.NoError(t, reopened.Delete("a"))
+	data, ok = reopened.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, "hello", string(data))
+
+	assert.NoError(t, reopened.Clear())
+	_, ok = reopened.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), reopened.Size())
+}
+
+// TestFileCacheLRU 测试超过容量上限时淘汰最久未访问的条目
+func TestFileCacheLRU(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir(), 10)
+	assert.NoError(t, err)
+
+	assert.NoError(t, cache.Set("first", []byte("1111"), 0))
+	assert.NoError(t, cache.Set("second", []byte("2222"), 0))
+
+	// 访问first，使second成为最久未使用的条目
+	_, ok := cache.Get("first")
+	assert.True(t, ok)
+
+	assert.NoError(t, cache.Set("third", []byte("3333"), 0))
+	_, ok = cache.Get("second")
+	assert.False(t, ok)
+	_, ok = cache.Get("first")
+	assert.True(t, ok)
+	_, ok = cache.Get("third")
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), cache.Size())
+
+	// 超过容量上限的单个内容不会被缓存
+	assert.Error(t, cache.Set("huge", make([]byte, 11), 0))
+}
+
+// TestFileCacheExpirationAndCorruption 测试过期和损坏的缓存内容会被丢弃
+func TestFileCacheExpirationAndCorruption(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir, 0)
+	assert.NoError(t, err)
+
+	assert.NoError(t, cache.Set("expired", []byte("old"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	_, ok := cache.Get("expired")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+
+	assert.NoError(t, cache.Set("corrupt", []byte("content"), 0))
+	sum := sha1Hex([]byte("content"))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, fileCacheObjectsDir, sum[:2], sum), []byte("tampered"), 0644))
+	_, ok = cache.Get("corrupt")
+	assert.False(t, ok)
+
+	// 没有调用Flush时索引在延迟之后自动写入
+	assert.NoError(t, cache.Set("delayed", []byte("delayed"), 0))
+	assert.Eventually(t, func() bool {
+		reopened, err := NewFileCache(dir, 0)
+		return err == nil && reopened.Len() == 1
+	}, 3*fileCacheIndexDelay, 50*time.Millisecond)
+}
+
+// TestClientCacheBackend 测试客户端通过注入的缓存后端复用下载内容
+func TestClientCacheBackend(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("jar-content"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	cache, err := NewFileCache(dir, 0)
+	assert.NoError(t, err)
+	client := newTestClient(server.URL, WithCacheBackend(cache))
+	assert.True(t, client.IsCacheEnabled())
+
+	data, err := client.Download(ctx, "org/example/demo/1.0/demo-1.0.jar")
+	assert.NoError(t, err)
+	assert.Equal(t, "jar-content", string(data))
+
+	// 模拟进程重启: 新的客户端使用同一个缓存目录
+	assert.NoError(t, cache.Flush())
+	reopened, err := NewFileCache(dir, 0)
+	assert.NoError(t, err)
+	client = newTestClient(server.URL, WithCacheBackend(reopened))
+	data, err = client.Download(ctx, "org/example/demo/1.0/demo-1.0.jar")
+	assert.NoError(t, err)
+	assert.Equal(t, "jar-content", string(data))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// 默认的内存缓存不在客户端之间共享
+	client1 := newTestClient(server.URL, WithCache(true, 60))
+	client2 := newTestClient(server.URL, WithCache(true, 60))
+	_, err = client1.Download(ctx, "org/example/demo/1.0/demo-1.0.pom")
+	assert.NoError(t, err)
+	_, err = client2.Download(ctx, "org/example/demo/1.0/demo-1.0.pom")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
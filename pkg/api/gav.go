@@ -3,8 +3,6 @@ package api
 import (
 	"context"
 	"errors"
-	"fmt"
-	"net/url"
 
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
@@ -102,15 +100,15 @@ func (c *Client) ListGAVs(ctx context.Context, query string, limit int) ([]*resp
 //	    fmt.Printf("最新版本: %s\n", latestArtifact.LatestVersion)
 //	}
 func (c *Client) GetGAVInfo(ctx context.Context, groupId, artifactId, version string) (*response.Artifact, error) {
-	query := fmt.Sprintf("g:%s AND a:%s",
-		url.QueryEscape(groupId),
-		url.QueryEscape(artifactId))
-
+	expr := request.And(
+		request.Field(request.FieldGroupId, groupId),
+		request.Field(request.FieldArtifactId, artifactId),
+	)
 	if version != "" {
-		query = fmt.Sprintf("%s AND v:%s", query, url.QueryEscape(version))
+		expr = request.And(expr, request.Field(request.FieldVersion, version))
 	}
 
-	artifacts, err := c.ListGAVs(ctx, query, 1)
+	artifacts, err := c.ListGAVs(ctx, expr.String(), 1)
 	if err != nil {
 		return nil, err
 	}
@@ -228,9 +226,10 @@ func (c *Client) SearchGAVsWithSort(ctx context.Context, query string, sortField
 //	// 3. 检查是否包含目标制品的依赖
 func (c *Client) FindGAVDependencies(ctx context.Context, groupId1, artifactId1, groupId2, artifactId2 string, limit int) ([]*response.Artifact, error) {
 	// 构建查询语句，先仅搜索目标制品
-	query := fmt.Sprintf("g:%s AND a:%s",
-		url.QueryEscape(groupId1),
-		url.QueryEscape(artifactId1))
+	query := request.And(
+		request.Field(request.FieldGroupId, groupId1),
+		request.Field(request.FieldArtifactId, artifactId1),
+	).String()
 
 	// 获取制品的列表，然后手动检查每个制品的依赖关系
 	artifacts, err := c.ListGAVs(ctx, query, limit)
@@ -36,8 +36,7 @@ func (c *Client) IteratorByGroupId(ctx context.Context, groupId string) *SearchI
 // SearchByGroupPattern 根据模式（如前缀、关键词等）搜索组ID
 func (c *Client) SearchByGroupPattern(ctx context.Context, pattern string, limit int) ([]*response.GroupSearchResult, error) {
 	// 构建查询 - 注意这里使用了g开头的模糊匹配搜索
-	q := fmt.Sprintf("g:%s*", pattern)
-	query := request.NewQuery().SetCustomQuery(q)
+	query := request.NewQuery().SetExpr(request.Wildcard(request.FieldGroupId, pattern+"*"))
 	searchReq := request.NewSearchRequest().
 		SetQuery(query).
 		SetRows(limit)
@@ -263,8 +262,7 @@ func (c *Client) SearchSubgroups(ctx context.Context, parentGroupId string, limi
 	}
 
 	// 构建查询 - 搜索以parentGroupId开头的所有groupId
-	q := fmt.Sprintf("g:%s*", parentGroupId)
-	query := request.NewQuery().SetCustomQuery(q)
+	query := request.NewQuery().SetExpr(request.Wildcard(request.FieldGroupId, parentGroupId+"*"))
 	searchReq := request.NewSearchRequest().
 		SetQuery(query).
 		SetRows(limit)
@@ -1,16 +1,28 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net/http"
 	"net/url"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// 速率限制器使用的操作类型
+const (
+	operationTypeSearch   = "search"
+	operationTypeDownload = "download"
+	operationTypeDefault  = "default"
+)
+
 // executeWithRetry 执行HTTP请求并包含重试逻辑
 func (c *Client) executeWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
 	var resp *http.Response
@@ -114,6 +126,11 @@ func (c *Client) doRequest(ctx context.Context, method, targetUrl string, body i
 				return err
 			}
 
+			// 遵守速率限制
+			if err := c.waitForRateLimit(ctx, req.URL, requestOperationType(req.URL)); err != nil {
+				return err
+			}
+
 			// 执行请求
 			resp, reqErr := c.httpClient.Do(req)
 			if reqErr != nil {
@@ -174,36 +191,153 @@ func (c *Client) doRequest(ctx context.Context, method, targetUrl string, body i
 //   - 重试次数和退避策略由Client配置决定
 //   - 所有重试都失败后，返回最后一次尝试的错误
 //
+// 校验行为:
+//   - 通过WithChecksumPolicy启用校验后，从远程下载的内容会与远程的.sha512/.sha256/.sha1校验文件比对，
+//     校验失败的内容不会被写入缓存和本地仓库
+//   - 通过WithSignatureVerification启用签名验证后，还会获取.asc签名并使用公钥环验证
+//
 // 缓存行为:
+//   - 如果配置了本地仓库且本地存在对应文件，直接返回本地文件内容；下载成功后会写回本地仓库
 //   - 如果启用了缓存且缓存中存在对应的内容，直接返回缓存内容而不发起HTTP请求
 //   - 如果启用了缓存且成功下载文件，会将文件内容添加到缓存中，TTL由Client配置决定
 func (c *Client) downloadWithCache(ctx context.Context, filePath string) ([]byte, error) {
-	// 构建完整URL
-	targetUrl, err := url.JoinPath(c.repoBaseURL, filePath)
+	data, _, err := c.downloadWithSignature(ctx, filePath)
+	return data, err
+}
+
+// downloadWithSignature 与downloadWithCache相同，同时返回下载过程中产生的签名验证结果
+//
+// 只有从远程仓库下载且客户端需要验证该文件的签名时才有验证结果，从本地仓库或缓存读取时返回nil。
+// 签名验证失败导致下载被拒绝时，验证结果与错误一起返回。
+func (c *Client) downloadWithSignature(ctx context.Context, filePath string) ([]byte, *SignatureVerification, error) {
+	// 构建缓存键
+	cacheKey, err := c.downloadCacheKey(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("URL构建失败: %w", err)
+		return nil, nil, fmt.Errorf("URL构建失败: %w", err)
+	}
+
+	// 如果配置了本地仓库，优先从本地仓库读取
+	useLocalRepository := c.localRepository != nil && usesLocalRepository(filePath)
+	if useLocalRepository {
+		if data, err := c.localRepository.Read(filePath); err == nil {
+			return data, nil, nil
+		}
 	}
 
 	// 如果启用了缓存，尝试从缓存获取
 	if c.cacheEnabled {
-		cacheKey := "download:" + targetUrl
-		if data, found := getFromCache(cacheKey); found {
-			return data, nil
+		if data, found := c.getFromCache(cacheKey); found {
+			return data, nil, nil
 		}
 	}
 
-	// 创建请求
-	req, err := http.NewRequestWithContext(ctx, "GET", targetUrl, nil)
+	// 从远程仓库下载并按照校验策略验证内容
+	var buf bytes.Buffer
+	source, err := c.streamRemote(ctx, filePath, newBufferTarget(&buf), c.progress)
+	responseBody := buf.Bytes()
+	if err == nil {
+		err = c.verifyDownload(ctx, filePath, responseBody)
+	}
+	var verification *SignatureVerification
+	if err == nil {
+		verification, err = c.verifyDownloadSignature(ctx, filePath, bytes.NewReader(responseBody))
+	}
+
+	// 如果请求成功且启用了缓存，添加到缓存
+	if err == nil && c.cacheEnabled {
+		c.addToCache(cacheKey, responseBody)
+	}
+
+	// 下载成功后写回本地仓库，写入失败不影响本次下载结果
+	if err == nil && useLocalRepository {
+		_ = c.localRepository.Write(filePath, responseBody, source.ID)
+	}
+
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return nil, verification, err
 	}
+	return responseBody, verification, nil
+}
 
-	// 设置请求头
-	req.Header.Set("User-Agent", "sonatype-central-sdk/1.0")
+// fetchRemote 直接从远程仓库下载文件，不经过缓存、本地仓库和校验
+//
+// 该方法将streamRemote的结果读入内存，是校验文件和签名文件获取共用的底层实现。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - filePath: 文件在Maven仓库中的相对路径
+//
+// 返回:
+//   - []byte: 下载文件的二进制内容
+//   - error: URL构建、请求或读取失败，以及服务器返回错误状态码时返回错误
+func (c *Client) fetchRemote(ctx context.Context, filePath string) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := c.streamRemote(ctx, filePath, newBufferTarget(&buf), nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// downloadTarget 流式下载的写入目标及其断点续传状态
+//
+// offset为w中已有的字节数，validator为这些内容对应的ETag或Last-Modified，续传时作为If-Range发送。
+// 服务器返回完整文件时（已有内容对应的文件已经变化，或者服务器不支持Range），调用reset清空w并记录
+// 新的validator，然后从头写入；reset为nil表示w不能重写，只有validator相同时才跳过响应中已有的部分。
+type downloadTarget struct {
+	w         io.Writer
+	offset    int64
+	validator string
+	reset     func(validator string) error
+}
+
+// newBufferTarget 创建写入内存缓冲区的下载目标，服务器返回完整文件时清空缓冲区
+func newBufferTarget(buf *bytes.Buffer) *downloadTarget {
+	return &downloadTarget{
+		w: buf,
+		reset: func(string) error {
+			buf.Reset()
+			return nil
+		},
+	}
+}
+
+// responseValidator 返回响应中可以用于If-Range的validator
+//
+// If-Range只能使用强ETag，没有强ETag时使用Last-Modified，都没有时返回空字符串。
+func responseValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// streamFromRepository 从指定的远程仓库流式下载文件并写入target
+//
+// 该方法执行带重试和速率限制的GET请求，响应体直接复制到target.w中而不会整体读入内存。
+// 当target.offset大于0时表示w中已经有文件的前offset个字节（例如上次中断留下的.part文件），
+// 此时发送Range请求只获取剩余部分，并通过If-Range要求服务器在文件变化后返回完整文件；
+// 收到完整文件时从头重新写入，见downloadTarget。
+// 传输中途断开属于可重试的错误，重试时同样从已写入的位置继续。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - baseURL: 仓库的基础URL
+//   - filePath: 文件在Maven仓库中的相对路径
+//   - target: 写入下载内容的目标，下载过程中更新其offset和validator
+//   - progress: 进度回调，为nil时不报告进度
+//
+// 返回:
+//   - error: URL构建、请求或读取失败，以及服务器返回错误状态码时返回错误
+func (c *Client) streamFromRepository(ctx context.Context, baseURL, filePath string, target *downloadTarget, progress DownloadProgress) error {
+	targetUrl, err := url.JoinPath(baseURL, filePath)
+	if err != nil {
+		return fmt.Errorf("URL构建失败: %w", err)
+	}
+
+	fileName := path.Base(filePath)
 
 	// 使用RetryWithBackoff执行带重试的请求
-	var responseBody []byte
-	err = RetryWithBackoff(
+	return RetryWithBackoff(
 		ctx,
 		c.maxRetries,
 		c.retryBackoffMs,
@@ -215,6 +349,24 @@ func (c *Client) downloadWithCache(ctx context.Context, filePath string) ([]byte
 				return err
 			}
 
+			// 创建请求，已有内容时只请求剩余部分
+			req, err := http.NewRequestWithContext(ctx, "GET", targetUrl, nil)
+			if err != nil {
+				return fmt.Errorf("创建请求失败: %w", err)
+			}
+			req.Header.Set("User-Agent", "sonatype-central-sdk/1.0")
+			if target.offset > 0 {
+				req.Header.Set("Range", fmt.Sprintf("bytes=%d-", target.offset))
+				if target.validator != "" {
+					req.Header.Set("If-Range", target.validator)
+				}
+			}
+
+			// 遵守速率限制
+			if err := c.waitForRateLimit(ctx, req.URL, operationTypeDownload); err != nil {
+				return err
+			}
+
 			// 执行请求
 			resp, reqErr := c.httpClient.Do(req)
 			if reqErr != nil {
@@ -222,30 +374,178 @@ func (c *Client) downloadWithCache(ctx context.Context, filePath string) ([]byte
 			}
 			defer resp.Body.Close()
 
-			// 读取响应体
-			body, readErr := io.ReadAll(resp.Body)
-			if readErr != nil {
-				return readErr
+			total := resp.ContentLength
+			skip := int64(0)
+			switch {
+			case resp.StatusCode == http.StatusPartialContent:
+				start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+				if !ok || start != target.offset {
+					return fmt.Errorf("服务器返回了无效的Content-Range: %q", resp.Header.Get("Content-Range"))
+				}
+				total = size
+			case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && target.offset > 0:
+				// 已有内容就是完整文件
+				if _, size, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && size == target.offset {
+					if progress != nil {
+						progress(target.offset, target.offset, fileName)
+					}
+					return nil
+				}
+				body, _ := io.ReadAll(resp.Body)
+				return handleHttpError(resp.StatusCode, body)
+			case resp.StatusCode >= 400:
+				body, readErr := io.ReadAll(resp.Body)
+				if readErr != nil {
+					return readErr
+				}
+				return handleHttpError(resp.StatusCode, body)
+			default:
+				// 完整的文件：首次请求、文件已经变化或服务器不支持Range
+				validator := responseValidator(resp.Header)
+				switch {
+				case target.reset != nil:
+					if err := target.reset(validator); err != nil {
+						return err
+					}
+					target.offset = 0
+				case target.offset > 0 && (validator == "" || validator != target.validator):
+					return fmt.Errorf("文件%s在下载过程中发生了变化，无法续传", fileName)
+				default:
+					skip = target.offset
+				}
+				target.validator = validator
 			}
 
-			// 处理HTTP错误
-			if resp.StatusCode >= 400 {
-				return handleHttpError(resp.StatusCode, body)
+			if skip > 0 {
+				if _, err := io.CopyN(io.Discard, resp.Body, skip); err != nil {
+					return &interruptedTransferError{err: err}
+				}
 			}
 
-			// 成功，保存响应
-			responseBody = body
+			reader := &progressReader{
+				reader:     resp.Body,
+				downloaded: target.offset,
+				total:      total,
+				fileName:   fileName,
+				progress:   progress,
+			}
+			n, copyErr := io.Copy(target.w, reader)
+			target.offset += n
+			if copyErr != nil {
+				// 读取响应体失败说明传输中断，可以从断点重试；写入失败则直接返回
+				if reader.err != nil && errors.Is(copyErr, reader.err) {
+					return &interruptedTransferError{err: copyErr}
+				}
+				return copyErr
+			}
 			return nil
 		},
 	)
+}
 
-	// 如果请求成功且启用了缓存，添加到缓存
-	if err == nil && c.cacheEnabled {
-		cacheKey := "download:" + targetUrl
-		addToCache(cacheKey, responseBody, c.cacheTTLSeconds)
+// interruptedTransferError 响应体传输中途断开的错误，可以从已写入的位置重试
+type interruptedTransferError struct {
+	err error
+}
+
+func (e *interruptedTransferError) Error() string {
+	return fmt.Sprintf("下载传输中断: %v", e.err)
+}
+
+func (e *interruptedTransferError) Unwrap() error {
+	return e.err
+}
+
+// progressReader 在读取响应体的同时报告下载进度
+type progressReader struct {
+	reader     io.Reader
+	downloaded int64
+	total      int64
+	fileName   string
+	progress   DownloadProgress
+
+	// err 最近一次读取失败的错误，用于区分读取错误和写入错误
+	err error
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.downloaded += int64(n)
+	if n > 0 && r.progress != nil {
+		r.progress(r.downloaded, r.total, r.fileName)
 	}
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
 
-	return responseBody, err
+// parseContentRange 解析"bytes start-end/size"或"bytes */size"形式的Content-Range头
+//
+// 返回起始位置和文件总大小，总大小未知（"*"）时为-1。
+func parseContentRange(header string) (start, size int64, ok bool) {
+	header = strings.TrimSpace(header)
+	if !strings.HasPrefix(header, "bytes ") {
+		return 0, 0, false
+	}
+	spec := strings.TrimPrefix(header, "bytes ")
+	rangePart, sizePart, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	size = -1
+	if sizePart != "*" {
+		parsed, err := strconv.ParseInt(sizePart, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = parsed
+	}
+	if rangePart == "*" {
+		return 0, size, true
+	}
+
+	startPart, _, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
+
+// waitForRateLimit 在发送请求前按主机和操作类型进行限流
+//
+// 如果客户端没有配置速率限制器，该方法直接返回。否则以请求URL的主机名为键调用
+// RateLimiter.WaitForRateLimit，必要时阻塞直到可以发送请求或上下文被取消。
+//
+// 参数:
+//   - ctx: 上下文对象，用于在等待期间响应取消
+//   - targetUrl: 即将请求的URL
+//   - operationType: 操作类型，"search"、"download"或"default"
+//
+// 返回:
+//   - error: 如果等待被上下文取消，返回取消错误；否则返回nil
+func (c *Client) waitForRateLimit(ctx context.Context, targetUrl *url.URL, operationType string) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+	_, err := c.rateLimiter.WaitForRateLimit(ctx, targetUrl.Host, operationType)
+	return err
+}
+
+// requestOperationType 根据请求URL判断速率限制使用的操作类型
+//
+// 访问Solr搜索端点或Nexus搜索接口的请求视为"search"，其余API请求视为"default"。
+// 下载请求由downloadWithCache直接以"download"类型限流，不经过该方法。
+func requestOperationType(targetUrl *url.URL) string {
+	if strings.Contains(targetUrl.Path, "/solrsearch/") || strings.Contains(targetUrl.Path, nexusSearchPath) {
+		return operationTypeSearch
+	}
+	return operationTypeDefault
 }
 
 // isRetriableError 判断是否为可重试的错误
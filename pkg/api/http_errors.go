@@ -5,9 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
-	"github.com/patrickmn/go-cache"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
 )
 
@@ -55,33 +53,6 @@ var (
 	ErrServerError = errors.New("server error")
 )
 
-// cachedResponse 表示一个缓存的HTTP响应
-//
-// 该结构体用于在内存中临时存储HTTP响应的内容，包括状态码、响应体和头信息。
-// 通过缓存常用请求的响应，可以减少重复的网络请求，提高应用程序性能，
-// 特别是对于那些频繁请求但内容变化不大的API端点。
-//
-// 字段说明:
-//   - StatusCode: HTTP响应状态码
-//   - Body: 响应体的二进制内容
-//   - Headers: 响应头信息，可用于检查内容类型、日期等元数据
-type cachedResponse struct {
-	StatusCode int
-	Body       []byte
-	Headers    http.Header
-}
-
-// 内存缓存实例，全局共享，默认5分钟过期时间，10分钟清理一次
-//
-// 该缓存使用github.com/patrickmn/go-cache库实现，提供线程安全的内存缓存功能。
-// 缓存项默认在5分钟后过期，过期的项目会在每10分钟进行一次自动清理。
-// 这种机制确保了缓存的数据不会无限增长占用内存，同时保持了合理的新鲜度。
-//
-// 缓存键通常由请求URL组成，而值为cachedResponse结构体，包含完整的响应内容。
-// 此缓存主要用于减少对相同资源的重复请求，特别适合于搜索结果和元数据信息等
-// 短期内不太可能发生变化的数据。
-var memoryCache = cache.New(5*time.Minute, 10*time.Minute)
-
 // handleHttpError 根据HTTP状态码处理错误
 //
 // 这是SDK中错误处理的核心方法，用于将HTTP错误转换为对客户端友好的错误对象。
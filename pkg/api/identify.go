@@ -0,0 +1,394 @@
+package api
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// MatchConfidence 文件识别结果的可信度
+type MatchConfidence string
+
+const (
+	// MatchConfidenceExact 文件的SHA-1与中央仓库中的制品完全一致
+	MatchConfidenceExact MatchConfidence = "exact"
+	// MatchConfidenceHigh 文件中内嵌了唯一的pom.properties，坐标可信但内容可能被重新打包过
+	MatchConfidenceHigh MatchConfidence = "high"
+	// MatchConfidenceMedium 文件中内嵌了多个pom.properties（如shade打包的JAR），按文件名选出了最可能的一个
+	MatchConfidenceMedium MatchConfidence = "medium"
+	// MatchConfidenceLow 坐标由MANIFEST.MF和文件名推测得到
+	MatchConfidenceLow MatchConfidence = "low"
+	// MatchConfidenceNone 无法识别
+	MatchConfidenceNone MatchConfidence = "none"
+)
+
+// MatchSource 文件识别结果的来源
+type MatchSource string
+
+const (
+	MatchSourceCentral       MatchSource = "central"        // 按SHA-1在中央仓库中查到
+	MatchSourcePomProperties MatchSource = "pom.properties" // 文件内嵌的META-INF/maven/**/pom.properties
+	MatchSourceManifest      MatchSource = "manifest"       // 文件内嵌的META-INF/MANIFEST.MF
+)
+
+// identifyExtensions IdentifyFiles在目录中查找的文件扩展名
+var identifyExtensions = map[string]bool{".jar": true, ".war": true, ".aar": true}
+
+// fileVersionPattern 从文件名中拆分artifactId和版本号，如"commons-io-2.11.0"
+var fileVersionPattern = regexp.MustCompile(`^(.+?)-(\d[^-]*(?:-.*)?)$`)
+
+// IdentifiedFile 单个本地文件的识别结果
+type IdentifiedFile struct {
+	Path       string              `json:"path"`
+	Sha1       string              `json:"sha1,omitempty"`
+	Size       int64               `json:"size"`
+	GroupId    string              `json:"groupId,omitempty"`
+	ArtifactId string              `json:"artifactId,omitempty"`
+	Version    string              `json:"version,omitempty"`
+	Packaging  string              `json:"packaging,omitempty"`
+	Confidence MatchConfidence     `json:"confidence"`
+	Source     MatchSource         `json:"source,omitempty"`
+	Candidates []*response.Version `json:"candidates,omitempty"` // 存在多个可能的坐标时列出全部候选
+	Error      string              `json:"error,omitempty"`      // 读取文件或查询中央仓库时的错误
+}
+
+// Coordinate 返回groupId:artifactId:version坐标，无法识别时返回空字符串
+func (f *IdentifiedFile) Coordinate() string {
+	if f.GroupId == "" || f.ArtifactId == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%s", f.GroupId, f.ArtifactId, f.Version)
+}
+
+// FileInventory 一组本地文件的识别结果，Files按路径排序
+type FileInventory struct {
+	Files []*IdentifiedFile `json:"files"`
+}
+
+// Summary 按可信度统计文件数量
+func (inv *FileInventory) Summary() map[MatchConfidence]int {
+	summary := make(map[MatchConfidence]int)
+	for _, file := range inv.Files {
+		summary[file.Confidence]++
+	}
+	return summary
+}
+
+// IdentifyFiles 识别本地的JAR/WAR/AAR文件对应的Maven坐标
+//
+// 目录会被递归遍历，其中扩展名为.jar、.war、.aar的文件参与识别；直接传入的文件不检查扩展名。
+// 每个文件先计算SHA-1并在中央仓库中精确查找，内容相同的文件只查询一次，多个SHA-1合并为批量查询（见SearchBySha1Batch）。
+// 中央仓库中找不到（或查询失败）的文件，依次尝试读取文件中内嵌的
+// META-INF/maven/**/pom.properties和META-INF/MANIFEST.MF推测坐标，并用Confidence标明可信度。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - paths: 文件或目录路径
+//
+// 返回:
+//   - *FileInventory: 所有文件的识别结果，单个文件的错误记录在IdentifiedFile.Error中
+//   - error: 路径不存在或上下文被取消时返回错误
+//
+// 使用示例:
+//
+//	inventory, err := client.IdentifyFiles(ctx, []string{"./legacy-app/lib"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, file := range inventory.Files {
+//	    fmt.Printf("%s\t%s\t%s\n", file.Path, file.Coordinate(), file.Confidence)
+//	}
+func (c *Client) IdentifyFiles(ctx context.Context, paths []string) (*FileInventory, error) {
+	filePaths, err := collectIdentifyFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &FileInventory{Files: make([]*IdentifiedFile, 0, len(filePaths))}
+	hashes := make(map[string][]*IdentifiedFile)
+	for _, p := range filePaths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		file := &IdentifiedFile{Path: p, Confidence: MatchConfidenceNone}
+		file.Sha1, file.Size, err = fileSha1(p)
+		if err != nil {
+			file.Error = err.Error()
+		} else {
+			hashes[file.Sha1] = append(hashes[file.Sha1], file)
+		}
+		inventory.Files = append(inventory.Files, file)
+	}
+
+	matches, errs := c.lookupSha1s(ctx, hashes)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for hash, files := range hashes {
+		for _, file := range files {
+			if versions := matches[hash]; len(versions) > 0 {
+				file.applyCentralMatch(versions)
+				continue
+			}
+			if err := errs[hash]; err != nil {
+				file.Error = fmt.Sprintf("查询中央仓库失败: %v", err)
+			}
+			if err := file.identifyFromArchive(); err != nil && file.Error == "" {
+				file.Error = err.Error()
+			}
+		}
+	}
+
+	return inventory, nil
+}
+
+// collectIdentifyFiles 展开目录并返回排序去重后的文件列表
+func collectIdentifyFiles(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("读取路径失败: %w", err)
+		}
+		if !info.IsDir() {
+			if !seen[p] {
+				seen[p] = true
+				files = append(files, p)
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !identifyExtensions[strings.ToLower(filepath.Ext(file))] || seen[file] {
+				return nil
+			}
+			seen[file] = true
+			files = append(files, file)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("遍历目录失败: %w", err)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// fileSha1 计算文件的SHA-1校验和和大小
+func fileSha1(p string) (string, int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	hash := sha1.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// lookupSha1s 批量地在中央仓库中查询一组SHA-1
+func (c *Client) lookupSha1s(ctx context.Context, hashes map[string][]*IdentifiedFile) (map[string][]*response.Version, map[string]error) {
+	list := make([]string, 0, len(hashes))
+	for hash := range hashes {
+		list = append(list, hash)
+	}
+
+	matches, err := c.SearchBySha1Batch(ctx, list)
+	errs := make(map[string]error)
+	var batchErr *Sha1BatchError
+	switch {
+	case errors.As(err, &batchErr):
+		errs = batchErr.Errors
+	case err != nil:
+		for _, hash := range list {
+			errs[hash] = err
+		}
+	}
+	return matches, errs
+}
+
+// applyCentralMatch 使用中央仓库的查询结果填充识别结果
+//
+// 同一个文件可能以多个坐标发布（如重定位后的制品），此时优先选择与文件名一致的坐标。
+func (f *IdentifiedFile) applyCentralMatch(versions []*response.Version) {
+	best := versions[0]
+	name := strings.TrimSuffix(filepath.Base(f.Path), filepath.Ext(f.Path))
+	for _, v := range versions {
+		if name == v.ArtifactId+"-"+v.Version {
+			best = v
+			break
+		}
+	}
+
+	f.GroupId, f.ArtifactId, f.Version, f.Packaging = best.GroupId, best.ArtifactId, best.Version, best.Packaging
+	f.Confidence = MatchConfidenceExact
+	f.Source = MatchSourceCentral
+	if len(versions) > 1 {
+		f.Candidates = versions
+	}
+}
+
+// identifyFromArchive 读取文件中内嵌的Maven元数据推测坐标
+func (f *IdentifiedFile) identifyFromArchive() error {
+	archive, err := zip.OpenReader(f.Path)
+	if err != nil {
+		return fmt.Errorf("无法作为压缩包读取: %w", err)
+	}
+	defer archive.Close()
+
+	f.Packaging = strings.TrimPrefix(strings.ToLower(filepath.Ext(f.Path)), ".")
+
+	var properties []*response.Version
+	var manifest map[string]string
+	for _, entry := range archive.File {
+		switch {
+		case strings.HasPrefix(entry.Name, "META-INF/maven/") && path.Base(entry.Name) == "pom.properties":
+			values, err := readZipEntry(entry, parseProperties)
+			if err != nil || values["groupId"] == "" || values["artifactId"] == "" {
+				continue
+			}
+			properties = append(properties, &response.Version{
+				ID:         fmt.Sprintf("%s:%s:%s", values["groupId"], values["artifactId"], values["version"]),
+				GroupId:    values["groupId"],
+				ArtifactId: values["artifactId"],
+				Version:    values["version"],
+			})
+		case strings.EqualFold(entry.Name, "META-INF/MANIFEST.MF"):
+			manifest, _ = readZipEntry(entry, parseManifest)
+		}
+	}
+
+	name := strings.TrimSuffix(filepath.Base(f.Path), filepath.Ext(f.Path))
+	fileArtifactId, fileVersion := name, ""
+	if m := fileVersionPattern.FindStringSubmatch(name); m != nil {
+		fileArtifactId, fileVersion = m[1], m[2]
+	}
+
+	switch {
+	case len(properties) == 1:
+		f.applyProperties(properties[0], MatchConfidenceHigh)
+	case len(properties) > 1:
+		sort.Slice(properties, func(i, j int) bool { return properties[i].ID < properties[j].ID })
+		best := properties[0]
+		for _, p := range properties {
+			if p.ArtifactId == fileArtifactId {
+				best = p
+				break
+			}
+		}
+		f.applyProperties(best, MatchConfidenceMedium)
+		f.Candidates = properties
+	case manifest != nil:
+		f.applyManifest(manifest, fileArtifactId, fileVersion)
+	}
+	return nil
+}
+
+// applyProperties 使用pom.properties中的坐标填充识别结果
+func (f *IdentifiedFile) applyProperties(p *response.Version, confidence MatchConfidence) {
+	f.GroupId, f.ArtifactId, f.Version = p.GroupId, p.ArtifactId, p.Version
+	f.Confidence = confidence
+	f.Source = MatchSourcePomProperties
+}
+
+// applyManifest 根据MANIFEST.MF和文件名推测坐标
+//
+// groupId取自Implementation-Vendor-Id，没有时尝试从Bundle-SymbolicName中去掉artifactId后缀得到；
+// 版本号依次取Implementation-Version、Bundle-Version和文件名中的版本号。
+func (f *IdentifiedFile) applyManifest(manifest map[string]string, fileArtifactId, fileVersion string) {
+	artifactId := fileArtifactId
+	groupId := manifest["Implementation-Vendor-Id"]
+	if groupId == "" {
+		symbolicName := strings.TrimSpace(strings.SplitN(manifest["Bundle-SymbolicName"], ";", 2)[0])
+		if strings.HasSuffix(symbolicName, "."+artifactId) {
+			groupId = strings.TrimSuffix(symbolicName, "."+artifactId)
+		}
+	}
+
+	version := fileVersion
+	for _, key := range []string{"Implementation-Version", "Bundle-Version"} {
+		if v := manifest[key]; v != "" {
+			version = v
+			break
+		}
+	}
+
+	if groupId == "" && version == "" {
+		return
+	}
+	f.GroupId, f.ArtifactId, f.Version = groupId, artifactId, version
+	f.Confidence = MatchConfidenceLow
+	f.Source = MatchSourceManifest
+}
+
+// readZipEntry 读取压缩包中的条目并解析
+func readZipEntry(entry *zip.File, parse func(io.Reader) (map[string]string, error)) (map[string]string, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return parse(rc)
+}
+
+// parseProperties 解析Java properties格式的内容，只支持pom.properties用到的简单形式
+func parseProperties(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		if i := strings.IndexAny(line, "=:"); i > 0 {
+			values[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return values, scanner.Err()
+}
+
+// parseManifest 解析MANIFEST.MF主段的属性，处理以空格开头的续行
+func parseManifest(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	var lastKey string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			// 空行之后是各个条目的段落，只需要主段
+			break
+		}
+		if line[0] == ' ' && lastKey != "" {
+			values[lastKey] += line[1:]
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			lastKey = strings.TrimSpace(key)
+			values[lastKey] = strings.TrimSpace(value)
+		}
+	}
+	return values, scanner.Err()
+}
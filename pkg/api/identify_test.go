@@ -0,0 +1,128 @@
+package api
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestJar 创建包含指定条目的JAR文件
+func writeTestJar(t *testing.T, path string, entries map[string]string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range entries {
+		entry, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = entry.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+}
+
+// TestIdentifyFiles 测试本地文件的识别
+func TestIdentifyFiles(t *testing.T) {
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib")
+
+	known := filepath.Join(lib, "commons-io-2.11.0.jar")
+	writeTestJar(t, known, map[string]string{"org/apache/commons/io/IOUtils.class": "class"})
+	data, err := os.ReadFile(known)
+	assert.NoError(t, err)
+	sum := sha1.Sum(data)
+	knownSha1 := hex.EncodeToString(sum[:])
+
+	// 内容相同的副本只查询一次
+	assert.NoError(t, os.WriteFile(filepath.Join(lib, "copy.jar"), data, 0644))
+
+	writeTestJar(t, filepath.Join(lib, "internal-tool.jar"), map[string]string{
+		"META-INF/maven/com.example/internal-tool/pom.properties": "#Generated by Maven\ngroupId=com.example\nartifactId=internal-tool\nversion=3.2.1\n",
+	})
+	writeTestJar(t, filepath.Join(lib, "app-all-1.0.war"), map[string]string{
+		"META-INF/maven/com.example/app-core/pom.properties": "groupId=com.example\nartifactId=app-core\nversion=1.0\n",
+		"META-INF/maven/com.example/app-all/pom.properties":  "groupId=com.example\nartifactId=app-all\nversion=1.0\n",
+	})
+	writeTestJar(t, filepath.Join(lib, "nested", "jsr305-1.3.9.jar"), map[string]string{
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\r\nBundle-SymbolicName: com.google.code.findbugs.js\r\n r305\r\nBundle-Version: 1.3.9\r\n\r\nName: javax/annotation/\r\nImplementation-Version: 9.9\r\n",
+	})
+	assert.NoError(t, os.WriteFile(filepath.Join(lib, "broken.aar"), []byte("not a zip"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(lib, "README.txt"), []byte("ignored"), 0644))
+
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		mu.Lock()
+		queries = append(queries, q)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(q, knownSha1) {
+			_, _ = w.Write([]byte(`{"response":{"numFound":2,"start":0,"docs":[
+				{"id":"org.apache:commons-io:2.11.0","g":"org.apache","a":"commons-io","v":"2.11.0","p":"jar","1":"` + knownSha1 + `"},
+				{"id":"commons-io:commons-io:2.11.0","g":"commons-io","a":"commons-io","v":"2.11.0","p":"jar","1":"` + knownSha1 + `"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"response":{"numFound":0,"start":0,"docs":[]}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	inventory, err := client.IdentifyFiles(context.Background(), []string{lib, known})
+	assert.NoError(t, err)
+	assert.Len(t, queries, 1, "所有SHA-1合并为一个批量查询")
+	assert.Equal(t, 5, strings.Count(queries[0], " OR ")+1, "内容相同的文件只查询一次")
+
+	byName := make(map[string]*IdentifiedFile)
+	for _, file := range inventory.Files {
+		byName[filepath.Base(file.Path)] = file
+	}
+	assert.Len(t, byName, 6, "README.txt不参与识别，重复传入的文件只识别一次")
+
+	file := byName["commons-io-2.11.0.jar"]
+	assert.Equal(t, knownSha1, file.Sha1)
+	assert.Equal(t, "org.apache:commons-io:2.11.0", file.Coordinate())
+	assert.Equal(t, MatchConfidenceExact, file.Confidence)
+	assert.Equal(t, MatchSourceCentral, file.Source)
+	assert.Len(t, file.Candidates, 2)
+	assert.Equal(t, MatchConfidenceExact, byName["copy.jar"].Confidence)
+
+	file = byName["internal-tool.jar"]
+	assert.Equal(t, "com.example:internal-tool:3.2.1", file.Coordinate())
+	assert.Equal(t, MatchConfidenceHigh, file.Confidence)
+	assert.Equal(t, MatchSourcePomProperties, file.Source)
+
+	file = byName["app-all-1.0.war"]
+	assert.Equal(t, "com.example:app-all:1.0", file.Coordinate())
+	assert.Equal(t, MatchConfidenceMedium, file.Confidence)
+	assert.Equal(t, "war", file.Packaging)
+	assert.Len(t, file.Candidates, 2)
+
+	file = byName["jsr305-1.3.9.jar"]
+	assert.Equal(t, "com.google.code.findbugs:jsr305:1.3.9", file.Coordinate())
+	assert.Equal(t, MatchConfidenceLow, file.Confidence)
+	assert.Equal(t, MatchSourceManifest, file.Source)
+
+	file = byName["broken.aar"]
+	assert.Equal(t, MatchConfidenceNone, file.Confidence)
+	assert.NotEmpty(t, file.Error)
+
+	summary := inventory.Summary()
+	assert.Equal(t, 2, summary[MatchConfidenceExact])
+	assert.Equal(t, 1, summary[MatchConfidenceNone])
+
+	_, err = client.IdentifyFiles(context.Background(), []string{filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
 	"strings"
 
+	"github.com/scagogogo/sonatype-central-sdk/pkg/license"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
 )
@@ -33,20 +33,25 @@ const (
 type LicenseCategory string
 
 const (
-	LicenseCategoryPermissive    LicenseCategory = "permissive"     // 宽松许可证，如MIT, Apache
-	LicenseCategoryCopyleft      LicenseCategory = "copyleft"       // 传染性许可证，如GPL
-	LicenseCategoryWeakCopyleft  LicenseCategory = "weak-copyleft"  // 弱传染性许可证，如LGPL
-	LicenseCategoryNonCommercial LicenseCategory = "non-commercial" // 非商业许可证
+	LicenseCategoryPermissive      = LicenseCategory(license.CategoryPermissive)      // 宽松许可证，如MIT, Apache
+	LicenseCategoryCopyleft        = LicenseCategory(license.CategoryCopyleft)        // 传染性许可证，如GPL
+	LicenseCategoryWeakCopyleft    = LicenseCategory(license.CategoryWeakCopyleft)    // 弱传染性许可证，如LGPL
+	LicenseCategoryNetworkCopyleft = LicenseCategory(license.CategoryNetworkCopyleft) // 网络传染性许可证，如AGPL
+	LicenseCategoryNonCommercial   = LicenseCategory(license.CategoryNonCommercial)   // 非商业许可证
+	LicenseCategoryUnknown         = LicenseCategory(license.CategoryUnknown)         // 无法识别的许可证
 )
 
 // GetComponentLicenses 获取一个组件的许可证信息
+//
+// 许可证名称会通过license.Normalize规范化为SPDX表达式并填入Type字段，无法识别时Type保持原始名称，
+// Category为"unknown"。搜索结果中没有许可证信息时，会改为从组件的有效POM中读取<licenses>声明。
 func (c *Client) GetComponentLicenses(ctx context.Context, groupID, artifactID, version string) ([]response.LicenseInfo, error) {
-	// 构建请求URL
-	q := fmt.Sprintf("g:%s+AND+a:%s+AND+v:%s",
-		url.QueryEscape(groupID), url.QueryEscape(artifactID), url.QueryEscape(version))
-
 	// 创建查询
-	query := request.NewQuery().SetCustomQuery(q)
+	query := request.NewQuery().SetExpr(request.And(
+		request.Field(request.FieldGroupId, groupID),
+		request.Field(request.FieldArtifactId, artifactID),
+		request.Field(request.FieldVersion, version),
+	))
 	searchReq := request.NewSearchRequest().SetQuery(query)
 
 	// 执行查询
@@ -72,8 +77,21 @@ func (c *Client) GetComponentLicenses(ctx context.Context, groupID, artifactID,
 					}
 
 					// 解析许可证信息
-					licenses = append(licenses, parseLicense(licStr))
+					licenses = append(licenses, parseLicense(licStr, ""))
+				}
+			}
+		}
+	}
+
+	// 搜索结果中没有许可证信息时，从POM的<licenses>中读取
+	if len(licenses) == 0 {
+		project, err := c.ResolvePom(ctx, groupID, artifactID, version)
+		if err == nil {
+			for _, lic := range project.Licenses {
+				if lic.Name == "" && lic.URL == "" {
+					continue
 				}
+				licenses = append(licenses, parseLicense(strings.TrimSpace(lic.Name), strings.TrimSpace(lic.URL)))
 			}
 		}
 	}
@@ -84,8 +102,7 @@ func (c *Client) GetComponentLicenses(ctx context.Context, groupID, artifactID,
 // SearchByLicenseType 搜索使用特定许可证类型的组件
 func (c *Client) SearchByLicenseType(ctx context.Context, licenseType LicenseType, limit int) ([]response.ArtifactRef, error) {
 	// 构建查询请求
-	q := fmt.Sprintf("l:%s", url.QueryEscape(string(licenseType)))
-	query := request.NewQuery().SetCustomQuery(q)
+	query := request.NewQuery().SetExpr(request.Field(request.FieldClassifier, string(licenseType)))
 	searchReq := request.NewSearchRequest().
 		SetQuery(query).
 		SetRows(limit)
@@ -149,7 +166,7 @@ func (c *Client) FindLicenseConflicts(ctx context.Context, artifacts []response.
 	}
 
 	// 检查许可证冲突
-	conflicts := findConflicts(foundLicenses)
+	conflicts := c.findConflicts(foundLicenses)
 
 	return &response.LicenseSummary{
 		TotalArtifacts:       len(artifacts),
@@ -197,53 +214,48 @@ func (c *Client) GetPopularLicenses(ctx context.Context, limit int) (map[string]
 	return licenses, nil
 }
 
-// 解析许可证字符串为LicenseInfo
-func parseLicense(licenseStr string) response.LicenseInfo {
-	// 简单实现，实际应用中可能需要更复杂的解析逻辑
-	licenseType := LicenseType(licenseStr)
-	licenseCategory := determineLicenseCategory(licenseType)
-
+// 解析许可证名称和URL为LicenseInfo
+func parseLicense(name, licenseURL string) response.LicenseInfo {
 	info := response.LicenseInfo{
-		Name:     licenseStr,
-		Type:     string(licenseType),
-		Category: string(licenseCategory),
-		URL:      fmt.Sprintf("https://opensource.org/licenses/%s", licenseType),
+		Name:     name,
+		Type:     name,
+		Category: string(LicenseCategoryUnknown),
+		URL:      licenseURL,
+	}
+	if info.Name == "" {
+		info.Name = licenseURL
+		info.Type = licenseURL
 	}
 
+	expr, ok := license.Normalize(name, licenseURL)
+	if !ok {
+		return info
+	}
+
+	info.Type = expr
+	info.Category = string(determineLicenseCategory(LicenseType(expr)))
+	if lic, ok := license.Lookup(expr); ok {
+		if info.URL == "" {
+			info.URL = lic.URL()
+		}
+		info.Description = lic.Name
+	}
 	return info
 }
 
-// 确定许可证类别
+// 确定许可证类别，licenseType可以是SPDX表达式，无法识别时返回LicenseCategoryUnknown
 func determineLicenseCategory(licenseType LicenseType) LicenseCategory {
-	licenseStr := string(licenseType)
-
-	// 根据许可证类型确定类别
-	switch {
-	case strings.Contains(licenseStr, "GPL"):
-		return LicenseCategoryCopyleft
-	case strings.Contains(licenseStr, "LGPL"):
-		return LicenseCategoryWeakCopyleft
-	case strings.Contains(licenseStr, "MIT") ||
-		strings.Contains(licenseStr, "Apache") ||
-		strings.Contains(licenseStr, "BSD"):
-		return LicenseCategoryPermissive
-	default:
-		// 默认为宽松许可
-		return LicenseCategoryPermissive
+	expr, ok := license.Normalize(string(licenseType), "")
+	if !ok {
+		return LicenseCategoryUnknown
 	}
+	return LicenseCategory(license.CategoryOf(expr))
 }
 
 // 查找许可证之间的冲突
-func findConflicts(licenses map[response.ArtifactRef][]response.LicenseInfo) []response.LicenseConflict {
+func (c *Client) findConflicts(licenses map[response.ArtifactRef][]response.LicenseInfo) []response.LicenseConflict {
 	var conflicts []response.LicenseConflict
 
-	// 定义不兼容的许可证组合
-	incompatiblePairs := map[string]string{
-		string(LicenseTypeGPLv2) + "_" + string(LicenseTypeApache2): "GPL-2.0不兼容Apache-2.0",
-		string(LicenseTypeGPLv3) + "_" + string(LicenseTypeCDDL):    "GPL-3.0不兼容CDDL-1.0",
-		// 可以添加更多不兼容的许可证组合
-	}
-
 	// 检查所有许可证组合
 	checkedPairs := make(map[string]bool)
 
@@ -270,27 +282,12 @@ func findConflicts(licenses map[response.ArtifactRef][]response.LicenseInfo) []r
 					checkedPairs[pairKey2] = true
 
 					// 检查是否有冲突
-					if reason, hasConflict := incompatiblePairs[pairKey1]; hasConflict {
+					if compatible, reason := c.checkLicensePair(license1.Type, license2.Type); !compatible {
 						conflicts = append(conflicts, response.LicenseConflict{
 							License1: license1.Type,
 							License2: license2.Type,
 							Reason:   reason,
 						})
-					} else if reason, hasConflict := incompatiblePairs[pairKey2]; hasConflict {
-						conflicts = append(conflicts, response.LicenseConflict{
-							License1: license2.Type,
-							License2: license1.Type,
-							Reason:   reason,
-						})
-					}
-
-					// 检查GPL和非GPL许可证的冲突
-					if isGPL(license1.Type) && !isGPL(license2.Type) && !isCompatibleWithGPL(license2.Type) {
-						conflicts = append(conflicts, response.LicenseConflict{
-							License1: license1.Type,
-							License2: license2.Type,
-							Reason:   fmt.Sprintf("%s不兼容%s", license1.Type, license2.Type),
-						})
 					}
 				}
 			}
@@ -300,81 +297,148 @@ func findConflicts(licenses map[response.ArtifactRef][]response.LicenseInfo) []r
 	return conflicts
 }
 
-// 检查是否是GPL许可证
-func isGPL(licenseStr string) bool {
-	return strings.HasPrefix(licenseStr, "GPL")
-}
+// checkLicensePair 判断两个许可证能否出现在同一个作品中
+//
+// 兼容性矩阵是有方向的，只要其中一个许可证的组件能用在以另一个许可证发布的作品中就视为兼容。
+// 任意一个许可证无法识别时视为兼容，但在原因中提示不确定。
+func (c *Client) checkLicensePair(license1, license2 string) (bool, string) {
+	matrix := c.getLicenseMatrix()
+	expr1 := normalizeLicenseExpression(license1)
+	expr2 := normalizeLicenseExpression(license2)
 
-// 检查许可证是否与GPL兼容
-func isCompatibleWithGPL(licenseStr string) bool {
-	// 以下许可证通常与GPL兼容
-	compatibleLicenses := map[string]bool{
-		string(LicenseTypeMIT):       true,
-		string(LicenseTypeBSD2):      true,
-		string(LicenseTypeBSD3):      true,
-		string(LicenseTypeLGPLv2):    true,
-		string(LicenseTypeLGPLv3):    true,
-		string(LicenseTypeUnlicense): true,
+	forward := matrix.Check(expr1, expr2)
+	if forward.Compatible() {
+		return true, forward.Reason
 	}
-
-	return compatibleLicenses[licenseStr]
+	backward := matrix.Check(expr2, expr1)
+	if backward.Compatible() {
+		return true, backward.Reason
+	}
+	if forward.Verdict == license.VerdictUnknown || backward.Verdict == license.VerdictUnknown {
+		return true, "未检测到明确的不兼容性，但请咨询法律专家"
+	}
+	return false, fmt.Sprintf("%s不兼容%s: %s", license1, license2, forward.Reason)
 }
 
-// CheckLicenseCompatibility 检查两个许可证是否兼容
-func (c *Client) CheckLicenseCompatibility(license1, license2 string) (bool, string, error) {
-	// 相同许可证总是兼容的
-	if license1 == license2 {
-		return true, "相同的许可证总是兼容的", nil
+// normalizeLicenseExpression 将许可证名称规范化为SPDX表达式，无法识别时原样返回
+func normalizeLicenseExpression(name string) string {
+	if expr, ok := license.Normalize(name, ""); ok {
+		return expr
 	}
+	return name
+}
 
-	// 定义不兼容的许可证组合
-	incompatiblePairs := map[string]string{
-		string(LicenseTypeGPLv2) + "_" + string(LicenseTypeApache2): "GPL-2.0不兼容Apache-2.0",
-		string(LicenseTypeGPLv3) + "_" + string(LicenseTypeCDDL):    "GPL-3.0不兼容CDDL-1.0",
-		// 这里可以添加更多不兼容的许可证组合
+// getLicenseMatrix 获取客户端使用的许可证兼容性矩阵
+func (c *Client) getLicenseMatrix() *license.Matrix {
+	if c.licenseMatrix != nil {
+		return c.licenseMatrix
 	}
+	return license.DefaultMatrix()
+}
 
-	// 检查是否存在已知的不兼容性
-	pairKey1 := license1 + "_" + license2
-	pairKey2 := license2 + "_" + license1
+// CheckLicenseCompatibility 检查两个许可证是否兼容
+//
+// 两个许可证会先规范化为SPDX表达式（可以传入POM中的自由书写名称或"Apache-2.0 OR MIT"这样的表达式），
+// 再按客户端的许可证兼容性矩阵判断（见WithLicenseMatrix）。只要其中一个许可证的组件能用在
+// 以另一个许可证发布的作品中就视为兼容；需要判断组件能否用在特定对外发布许可证下时，
+// 请使用CheckOutboundLicense。
+func (c *Client) CheckLicenseCompatibility(license1, license2 string) (bool, string, error) {
+	compatible, reason := c.checkLicensePair(license1, license2)
+	return compatible, reason, nil
+}
 
-	if reason, hasConflict := incompatiblePairs[pairKey1]; hasConflict {
-		return false, reason, nil
+// CheckOutboundLicense 检查一组组件能否用在以指定许可证对外发布的作品中
+//
+// 每个组件的许可证通过GetComponentLicenses获取并规范化为SPDX表达式，组件声明了多个许可证时
+// 视为可以任选其一（与SBOM中的处理一致），然后按客户端的许可证兼容性矩阵判断。
+// 无法获取许可证信息或许可证无法识别的组件结论为"unknown"。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - artifacts: 要检查的组件列表
+//   - outbound: 作品对外发布的许可证，可以是SPDX表达式，闭源发布时使用license.ProprietaryID
+//
+// 返回:
+//   - *response.OutboundLicenseReport: 每个组件的兼容性结论
+//   - error: 上下文被取消时返回错误
+//
+// 使用示例:
+//
+//	report, err := client.CheckOutboundLicense(ctx, artifacts, license.ProprietaryID)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, component := range report.Components {
+//	    if component.Verdict == string(license.VerdictIncompatible) {
+//	        fmt.Printf("%s:%s:%s (%s): %s\n", component.GroupId, component.ArtifactId, component.Version,
+//	            component.License, component.Reason)
+//	    }
+//	}
+func (c *Client) CheckOutboundLicense(ctx context.Context, artifacts []response.ArtifactRef, outbound string) (*response.OutboundLicenseReport, error) {
+	matrix := c.getLicenseMatrix()
+	outbound = normalizeLicenseExpression(outbound)
+
+	report := &response.OutboundLicenseReport{
+		OutboundLicense: outbound,
+		TotalComponents: len(artifacts),
+		Components:      make([]response.ComponentLicenseCompliance, 0, len(artifacts)),
 	}
 
-	if reason, hasConflict := incompatiblePairs[pairKey2]; hasConflict {
-		return false, reason, nil
-	}
+	for _, artifact := range artifacts {
+		compliance := response.ComponentLicenseCompliance{
+			GroupId:    artifact.GroupId,
+			ArtifactId: artifact.ArtifactId,
+			Version:    artifact.Version,
+		}
 
-	// 检查GPL和非GPL许可证的冲突
-	if isGPL(license1) && !isGPL(license2) && !isCompatibleWithGPL(license2) {
-		return false, fmt.Sprintf("%s不兼容%s", license1, license2), nil
-	}
+		licenses, err := c.GetComponentLicenses(ctx, artifact.GroupId, artifact.ArtifactId, artifact.Version)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 
-	if isGPL(license2) && !isGPL(license1) && !isCompatibleWithGPL(license1) {
-		return false, fmt.Sprintf("%s不兼容%s", license2, license1), nil
-	}
+		switch {
+		case err != nil:
+			compliance.Verdict = string(license.VerdictUnknown)
+			compliance.Reason = err.Error()
+		case len(licenses) == 0:
+			compliance.Verdict = string(license.VerdictUnknown)
+			compliance.Reason = "组件没有声明许可证"
+		default:
+			compliance.License = joinLicenseExpressions(licenses)
+			result := matrix.Check(compliance.License, outbound)
+			compliance.Verdict = string(result.Verdict)
+			compliance.Reason = result.Reason
+		}
 
-	// 宽松许可证与其他许可证兼容
-	if isPermissiveLicense(license1) || isPermissiveLicense(license2) {
-		return true, "宽松许可证通常兼容其他许可证", nil
+		switch compliance.Verdict {
+		case string(license.VerdictCompatible):
+			report.CompatibleCount++
+		case string(license.VerdictIncompatible):
+			report.IncompatibleCount++
+		default:
+			report.UnknownCount++
+		}
+		report.Components = append(report.Components, compliance)
 	}
 
-	// 默认为兼容，但提示不确定
-	return true, "未检测到明确的不兼容性，但请咨询法律专家", nil
+	return report, nil
 }
 
-// 检查是否是宽松许可证
-func isPermissiveLicense(licenseStr string) bool {
-	permissiveLicenses := map[string]bool{
-		string(LicenseTypeMIT):       true,
-		string(LicenseTypeBSD2):      true,
-		string(LicenseTypeBSD3):      true,
-		string(LicenseTypeApache2):   true,
-		string(LicenseTypeUnlicense): true,
+// joinLicenseExpressions 将组件声明的多个许可证用OR连接为一个表达式
+func joinLicenseExpressions(licenses []response.LicenseInfo) string {
+	terms := make([]string, 0, len(licenses))
+	for i, lic := range licenses {
+		term := lic.Type
+		if expr, err := license.Parse(term); err != nil {
+			// 无法识别的许可证名称转换为LicenseRef，使表达式仍然可以解析；
+			// 名称中没有可用字符时（如"许可证"）按位置使用LicenseRef-unknown-<n>
+			term = license.LicenseRef(term, i+1)
+		} else if _, ok := expr.(*license.Simple); !ok && len(licenses) > 1 {
+			term = "(" + term + ")"
+		}
+		terms = append(terms, term)
 	}
-
-	return permissiveLicenses[licenseStr]
+	return strings.Join(terms, " OR ")
 }
 
 // GenerateLicenseReport 为一组组件生成许可证报告
@@ -387,12 +451,13 @@ func (c *Client) GenerateLicenseReport(ctx context.Context, artifacts []response
 	// 计算合规风险
 	var highRiskCount, mediumRiskCount, lowRiskCount int
 	for _, conflict := range summary.PotentialConflicts {
-		// 简单的风险分析，可以根据需要扩展
-		if strings.Contains(conflict.License1, "GPL") || strings.Contains(conflict.License2, "GPL") {
+		// 按冲突双方中限制最多的许可证类别评估风险
+		switch conflictRiskCategory(conflict) {
+		case LicenseCategoryCopyleft, LicenseCategoryNetworkCopyleft, LicenseCategoryNonCommercial:
 			highRiskCount++
-		} else if strings.Contains(conflict.License1, "LGPL") || strings.Contains(conflict.License2, "LGPL") {
+		case LicenseCategoryWeakCopyleft:
 			mediumRiskCount++
-		} else {
+		default:
 			lowRiskCount++
 		}
 	}
@@ -448,6 +513,24 @@ func (c *Client) GenerateLicenseReport(ctx context.Context, artifacts []response
 	return report, nil
 }
 
+// conflictRiskCategory 返回许可证冲突双方中限制最多的类别，无法识别的许可证不参与比较
+func conflictRiskCategory(conflict response.LicenseConflict) LicenseCategory {
+	category := LicenseCategoryPermissive
+	for _, name := range []string{conflict.License1, conflict.License2} {
+		switch c := determineLicenseCategory(LicenseType(name)); c {
+		case LicenseCategoryNetworkCopyleft, LicenseCategoryNonCommercial:
+			return c
+		case LicenseCategoryCopyleft:
+			category = c
+		case LicenseCategoryWeakCopyleft:
+			if category == LicenseCategoryPermissive {
+				category = c
+			}
+		}
+	}
+	return category
+}
+
 // FilterByLicenseType 根据许可证类型过滤组件
 func (c *Client) FilterByLicenseType(ctx context.Context, artifacts []response.ArtifactRef, allowedTypes []string) ([]response.ArtifactRef, []response.ArtifactRef, error) {
 	if len(artifacts) == 0 {
@@ -507,11 +590,13 @@ func generateRecommendations(summary *response.LicenseSummary) []string {
 	hasGPL := false
 	hasLGPL := false
 	for licType := range summary.LicenseDistribution {
-		if strings.Contains(licType, "GPL-") && !strings.Contains(licType, "LGPL-") {
-			hasGPL = true
-		}
-		if strings.Contains(licType, "LGPL-") {
-			hasLGPL = true
+		for _, lic := range licenseIDs(licType) {
+			if strings.HasPrefix(lic, "GPL-") || strings.HasPrefix(lic, "AGPL-") {
+				hasGPL = true
+			}
+			if strings.HasPrefix(lic, "LGPL-") {
+				hasLGPL = true
+			}
 		}
 	}
 
@@ -529,3 +614,16 @@ func generateRecommendations(summary *response.LicenseSummary) []string {
 
 	return recommendations
 }
+
+// licenseIDs 返回许可证表达式中的全部许可证标识符，无法解析时返回原始字符串
+func licenseIDs(expression string) []string {
+	expr, err := license.Parse(expression)
+	if err != nil {
+		return []string{expression}
+	}
+	var ids []string
+	for _, lic := range expr.Licenses() {
+		ids = append(ids, lic.ID)
+	}
+	return ids
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/scagogogo/sonatype-central-sdk/pkg/license"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
 )
 
@@ -182,3 +183,58 @@ func TestGenerateLicenseReport(t *testing.T) {
 		}
 	}
 }
+
+// TestDetermineLicenseCategory 测试许可证类别判断不再依赖子串匹配
+func TestDetermineLicenseCategory(t *testing.T) {
+	assert.Equal(t, LicenseCategoryWeakCopyleft, determineLicenseCategory("LGPL-2.1"))
+	assert.Equal(t, LicenseCategoryCopyleft, determineLicenseCategory("GPL-3.0"))
+	assert.Equal(t, LicenseCategoryPermissive, determineLicenseCategory("The Apache Software License, Version 2.0"))
+	assert.Equal(t, LicenseCategoryUnknown, determineLicenseCategory("Some Custom License"))
+
+	info := parseLicense("GNU Lesser General Public License, Version 2.1", "")
+	assert.Equal(t, "LGPL-2.1-only", info.Type)
+	assert.Equal(t, string(LicenseCategoryWeakCopyleft), info.Category)
+}
+
+// TestJoinLicenseExpressions 测试多个许可证合并为表达式，无法识别的名称转换为LicenseRef
+func TestJoinLicenseExpressions(t *testing.T) {
+	expression := joinLicenseExpressions([]response.LicenseInfo{
+		{Type: "Apache-2.0"},
+		{Type: "MIT OR BSD-3-Clause"},
+		{Type: "Company License v1"},
+		{Type: "许可证"},
+		{Type: ""},
+	})
+	assert.Equal(t, "Apache-2.0 OR (MIT OR BSD-3-Clause) OR LicenseRef-Company-License-v1 OR LicenseRef-unknown-4 OR LicenseRef-unknown-5", expression)
+	_, err := license.Parse(expression)
+	assert.NoError(t, err)
+}
+
+// TestCheckOutboundLicense 测试按对外发布许可证检查组件，许可证从POM中读取
+func TestCheckOutboundLicense(t *testing.T) {
+	server := newTestServer(t, pomFixtures, pomFixtureDoc)
+	client := newTestClient(server.URL)
+
+	artifacts := []response.ArtifactRef{
+		{GroupId: "org.example", ArtifactId: "example-core", Version: "1.0.0"},
+		{GroupId: "com.google.guava", ArtifactId: "guava", Version: "31.1-jre"},
+	}
+
+	report, err := client.CheckOutboundLicense(context.Background(), artifacts, "GPL-2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "GPL-2.0-only", report.OutboundLicense)
+	assert.Len(t, report.Components, 2)
+
+	// example-core从父POM继承Apache-2.0，不能用在GPL-2.0-only的作品中
+	assert.Equal(t, "Apache-2.0", report.Components[0].License)
+	assert.Equal(t, "incompatible", report.Components[0].Verdict)
+	assert.Equal(t, 1, report.IncompatibleCount)
+
+	// guava的POM没有声明许可证
+	assert.Equal(t, "unknown", report.Components[1].Verdict)
+	assert.Equal(t, 1, report.UnknownCount)
+
+	report, err = client.CheckOutboundLicense(context.Background(), artifacts[:1], "Apache-2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.CompatibleCount)
+}
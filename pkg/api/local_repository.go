@@ -0,0 +1,405 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// remoteRepositoriesFileName Maven Resolver记录文件来源仓库的文件名
+	remoteRepositoriesFileName = "_remote.repositories"
+
+	// defaultRemoteRepositoryId 写入_remote.repositories时使用的默认仓库ID，与Maven中央仓库的ID一致
+	defaultRemoteRepositoryId = "central"
+)
+
+// ErrNotInLocalRepository 本地仓库中不存在请求的文件
+var ErrNotInLocalRepository = errors.New("file not found in local repository")
+
+// LocalRepository 本地Maven仓库
+//
+// LocalRepository读写标准的Maven本地仓库目录布局（默认为~/.m2/repository），文件路径与
+// BuildArtifactPath生成的仓库相对路径一致。通过WithLocalRepository挂载到客户端后，
+// Download系列方法会优先从本地仓库读取文件，本地不存在时再从远程仓库下载并写回本地仓库，
+// 同时生成.sha1/.md5校验文件并更新_remote.repositories，使Maven构建可以直接复用这些文件。
+//
+// 以下文件不会经过本地仓库:
+//   - maven-metadata*.xml: 仓库元数据会随发布变化，Maven在本地以不同的文件名保存
+//   - SNAPSHOT版本目录中的文件: 快照版本内容会变化，需要始终从远程获取
+//
+// 使用示例:
+//
+//	repo, err := api.DefaultLocalRepository()
+//	if err != nil {
+//	    log.Fatalf("定位本地仓库失败: %v", err)
+//	}
+//
+//	client := api.NewClient(api.WithLocalRepository(repo))
+//
+//	// 第一次调用从远程下载并写入~/.m2/repository，之后直接读取本地文件
+//	jar, err := client.DownloadJar(ctx, "org.apache.commons", "commons-lang3", "3.12.0")
+type LocalRepository struct {
+	root string
+
+	// remoteMutex 保护_remote.repositories文件的读-改-写过程
+	remoteMutex sync.Mutex
+}
+
+// NewLocalRepository 创建指向指定目录的本地仓库
+//
+// 参数:
+//   - root: 本地仓库根目录，如"/home/user/.m2/repository"，目录不存在时会在首次写入时创建
+//
+// 返回:
+//   - *LocalRepository: 本地仓库实例
+func NewLocalRepository(root string) *LocalRepository {
+	return &LocalRepository{root: root}
+}
+
+// DefaultLocalRepository 返回当前用户默认的本地Maven仓库(~/.m2/repository)
+//
+// 返回:
+//   - *LocalRepository: 本地仓库实例
+//   - error: 无法确定用户主目录时返回错误
+func DefaultLocalRepository() (*LocalRepository, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("无法确定用户主目录: %w", err)
+	}
+	return NewLocalRepository(filepath.Join(home, ".m2", "repository")), nil
+}
+
+// Root 返回本地仓库根目录
+func (r *LocalRepository) Root() string {
+	return r.root
+}
+
+// Path 返回仓库相对路径对应的本地文件路径
+//
+// 参数:
+//   - filePath: 仓库相对路径，如"org/apache/commons/commons-lang3/3.12.0/commons-lang3-3.12.0.jar"
+//
+// 返回:
+//   - string: 本地文件系统路径
+//   - error: 路径试图跳出仓库根目录时返回错误
+func (r *LocalRepository) Path(filePath string) (string, error) {
+	normalized := strings.Trim(strings.ReplaceAll(filePath, "\\", "/"), "/")
+	if normalized == "" {
+		return "", fmt.Errorf("无效的仓库路径: %s", filePath)
+	}
+	for _, segment := range strings.Split(normalized, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("无效的仓库路径: %s", filePath)
+		}
+	}
+	return filepath.Join(r.root, filepath.FromSlash(normalized)), nil
+}
+
+// ArtifactPath 返回制品文件在本地仓库中的路径
+//
+// 路径规则与BuildArtifactPath一致，例如ArtifactPath("junit", "junit", "4.13.2", "jar")
+// 返回"<root>/junit/junit/4.13.2/junit-4.13.2.jar"。
+func (r *LocalRepository) ArtifactPath(groupId, artifactId, version, extension string, classifier ...string) string {
+	localPath, _ := r.Path(BuildArtifactPath(groupId, artifactId, version, extension, classifier...))
+	return localPath
+}
+
+// Has 判断本地仓库中是否存在指定文件
+func (r *LocalRepository) Has(filePath string) bool {
+	localPath, err := r.Path(filePath)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(localPath)
+	return err == nil && !info.IsDir()
+}
+
+// Read 从本地仓库读取文件
+//
+// 参数:
+//   - filePath: 仓库相对路径
+//
+// 返回:
+//   - []byte: 文件内容
+//   - error: 文件不存在时返回包装了ErrNotInLocalRepository的错误
+func (r *LocalRepository) Read(filePath string) ([]byte, error) {
+	localPath, err := r.Path(filePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotInLocalRepository, filePath)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// Write 将文件写入本地仓库
+//
+// 除写入文件本身外，还会像Maven一样在同一目录下写入.sha1和.md5校验文件，
+// 并在_remote.repositories中记录文件来源的仓库ID。写入校验文件本身时不会生成额外的文件。
+//
+// 参数:
+//   - filePath: 仓库相对路径
+//   - data: 文件内容
+//   - repositoryId: 文件来源的远程仓库ID，如"central"；为空时不更新_remote.repositories
+//
+// 返回:
+//   - error: 创建目录或写入文件失败时返回错误
+func (r *LocalRepository) Write(filePath string, data []byte, repositoryId string) error {
+	return r.WriteFrom(filePath, bytes.NewReader(data), repositoryId)
+}
+
+// WriteFrom 与Write相同，但从src中流式读取文件内容，适用于不宜整体读入内存的大文件
+//
+// 文件先写入同一目录下的临时文件，完整写入后再重命名，读取src失败时不会留下不完整的文件。
+//
+// 参数:
+//   - filePath: 仓库相对路径
+//   - src: 文件内容
+//   - repositoryId: 文件来源的远程仓库ID；为空时不更新_remote.repositories
+//
+// 返回:
+//   - error: 读取内容、创建目录或写入文件失败时返回错误
+func (r *LocalRepository) WriteFrom(filePath string, src io.Reader, repositoryId string) error {
+	localPath, err := r.Path(filePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("写入本地仓库失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(localPath), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("写入本地仓库失败: %w", err)
+	}
+	sha1Hash := sha1.New()
+	md5Hash := md5.New()
+	_, err = io.Copy(io.MultiWriter(tmp, sha1Hash, md5Hash), src)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), localPath)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("写入本地仓库失败: %w", err)
+	}
+
+	if isChecksumFile(filePath) {
+		return nil
+	}
+
+	if err := writeFileAtomic(localPath+".sha1", []byte(hex.EncodeToString(sha1Hash.Sum(nil)))); err != nil {
+		return fmt.Errorf("写入校验文件失败: %w", err)
+	}
+	if err := writeFileAtomic(localPath+".md5", []byte(hex.EncodeToString(md5Hash.Sum(nil)))); err != nil {
+		return fmt.Errorf("写入校验文件失败: %w", err)
+	}
+
+	if repositoryId == "" {
+		return nil
+	}
+	return r.recordRemoteRepository(localPath, repositoryId)
+}
+
+// Open 打开本地仓库中的文件以便流式读取，调用方负责关闭
+//
+// 参数:
+//   - filePath: 仓库相对路径
+//
+// 返回:
+//   - *os.File: 打开的文件
+//   - error: 文件不存在时返回包装了ErrNotInLocalRepository的错误
+func (r *LocalRepository) Open(filePath string) (*os.File, error) {
+	localPath, err := r.Path(filePath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotInLocalRepository, filePath)
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// SaveBundle 将制品包保存到本地仓库
+//
+// 与Client.SaveBundle不同，该方法会同时写入校验文件和_remote.repositories，
+// 保存后的文件可以直接被Maven构建使用。
+//
+// 参数:
+//   - bundle: 要保存的制品包，通常是DownloadCompleteBundle方法的返回结果
+//   - repositoryId: 制品来源的远程仓库ID，为空时使用"central"
+//
+// 返回:
+//   - error: 写入任一文件失败时返回错误
+func (r *LocalRepository) SaveBundle(bundle *ArtifactBundle, repositoryId string) error {
+	if repositoryId == "" {
+		repositoryId = defaultRemoteRepositoryId
+	}
+	for _, file := range bundleFiles(bundle) {
+		if err := r.Write(file.path, file.data, repositoryId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoteRepositories 返回_remote.repositories中记录的文件来源
+//
+// 参数:
+//   - groupId、artifactId、version: 制品坐标
+//
+// 返回:
+//   - map[string][]string: 文件名到来源仓库ID列表的映射，文件不存在时返回空映射
+func (r *LocalRepository) RemoteRepositories(groupId, artifactId, version string) map[string][]string {
+	dir := filepath.Dir(r.ArtifactPath(groupId, artifactId, version, POM))
+	data, err := os.ReadFile(filepath.Join(dir, remoteRepositoriesFileName))
+	if err != nil {
+		return map[string][]string{}
+	}
+	return parseRemoteRepositories(data)
+}
+
+// recordRemoteRepository 在_remote.repositories中记录文件的来源仓库
+func (r *LocalRepository) recordRemoteRepository(localPath, repositoryId string) error {
+	r.remoteMutex.Lock()
+	defer r.remoteMutex.Unlock()
+
+	trackingPath := filepath.Join(filepath.Dir(localPath), remoteRepositoriesFileName)
+	entries := map[string][]string{}
+	if data, err := os.ReadFile(trackingPath); err == nil {
+		entries = parseRemoteRepositories(data)
+	}
+
+	fileName := filepath.Base(localPath)
+	for _, id := range entries[fileName] {
+		if id == repositoryId {
+			return nil
+		}
+	}
+	entries[fileName] = append(entries[fileName], repositoryId)
+
+	fileNames := make([]string, 0, len(entries))
+	for name := range entries {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	var buf bytes.Buffer
+	buf.WriteString("#NOTE: This is a Maven Resolver internal implementation file, its format can be changed without prior notice.\n")
+	buf.WriteString("#" + time.Now().Format("Mon Jan 02 15:04:05 MST 2006") + "\n")
+	for _, name := range fileNames {
+		for _, id := range entries[name] {
+			fmt.Fprintf(&buf, "%s>%s=\n", name, id)
+		}
+	}
+
+	if err := writeFileAtomic(trackingPath, buf.Bytes()); err != nil {
+		return fmt.Errorf("写入%s失败: %w", remoteRepositoriesFileName, err)
+	}
+	return nil
+}
+
+// parseRemoteRepositories 解析_remote.repositories文件内容
+//
+// 文件中每一行的格式为"<文件名>><仓库ID>="，以#开头的行是注释。
+func parseRemoteRepositories(data []byte) map[string][]string {
+	entries := map[string][]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSuffix(line, "=")
+		index := strings.LastIndex(line, ">")
+		if index <= 0 {
+			continue
+		}
+		name := line[:index]
+		entries[name] = append(entries[name], line[index+1:])
+	}
+	return entries
+}
+
+// isChecksumFile 判断仓库路径是否为校验文件
+func isChecksumFile(filePath string) bool {
+	for _, ext := range []string{".sha1", ".md5", ".sha256", ".sha512"} {
+		if strings.HasSuffix(filePath, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesLocalRepository 判断仓库路径是否应该经过本地仓库
+func usesLocalRepository(filePath string) bool {
+	if strings.HasPrefix(path.Base(filePath), "maven-metadata") {
+		return false
+	}
+	return !strings.Contains(filePath, "-SNAPSHOT/")
+}
+
+// bundleFile 制品包中的一个待保存文件
+type bundleFile struct {
+	path string
+	data []byte
+}
+
+// bundleFiles 列出制品包中所有成功下载的文件及其仓库相对路径
+func bundleFiles(bundle *ArtifactBundle) []bundleFile {
+	files := []bundleFile{
+		{BuildArtifactPath(bundle.GroupId, bundle.ArtifactId, bundle.Version, PomFile.Extension, PomFile.Classifier), bundle.Pom},
+		{BuildArtifactPath(bundle.GroupId, bundle.ArtifactId, bundle.Version, JarFile.Extension, JarFile.Classifier), bundle.Jar},
+		{BuildArtifactPath(bundle.GroupId, bundle.ArtifactId, bundle.Version, SourcesFile.Extension, SourcesFile.Classifier), bundle.Sources},
+		{BuildArtifactPath(bundle.GroupId, bundle.ArtifactId, bundle.Version, JavadocFile.Extension, JavadocFile.Classifier), bundle.Javadoc},
+		{BuildArtifactPath(bundle.GroupId, bundle.ArtifactId, bundle.Version, TestsFile.Extension, TestsFile.Classifier), bundle.Tests},
+	}
+
+	otherTypes := make([]string, 0, len(bundle.OtherFiles))
+	for fileType := range bundle.OtherFiles {
+		otherTypes = append(otherTypes, fileType)
+	}
+	sort.Strings(otherTypes)
+	for _, fileType := range otherTypes {
+		file, ok := bundle.OtherFileTypes[fileType]
+		if !ok {
+			file = ArtifactFile{Type: fileType, Extension: JAR, Classifier: strings.ToLower(fileType)}
+		}
+		files = append(files, bundleFile{
+			BuildArtifactPath(bundle.GroupId, bundle.ArtifactId, bundle.Version, file.Extension, file.Classifier),
+			bundle.OtherFiles[fileType],
+		})
+	}
+
+	result := files[:0]
+	for _, file := range files {
+		if len(file.data) > 0 {
+			result = append(result, file)
+		}
+	}
+	return result
+}
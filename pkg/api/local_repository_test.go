@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLocalRepositoryWrite 测试写入本地仓库时生成校验文件和_remote.repositories
+func TestLocalRepositoryWrite(t *testing.T) {
+	repo := NewLocalRepository(t.TempDir())
+	jarPath := BuildArtifactPath("org.example", "demo", "1.0", JAR)
+	pomPath := BuildArtifactPath("org.example", "demo", "1.0", POM)
+
+	assert.False(t, repo.Has(jarPath))
+	_, err := repo.Read(jarPath)
+	assert.ErrorIs(t, err, ErrNotInLocalRepository)
+
+	assert.NoError(t, repo.Write(jarPath, []byte("jar"), "central"))
+	assert.NoError(t, repo.Write(pomPath, []byte("pom"), "central"))
+	assert.NoError(t, repo.Write(pomPath, []byte("pom"), "central"))
+
+	localJar := repo.ArtifactPath("org.example", "demo", "1.0", JAR)
+	assert.Equal(t, filepath.Join(repo.Root(), "org", "example", "demo", "1.0", "demo-1.0.jar"), localJar)
+
+	sha1Data, err := os.ReadFile(localJar + ".sha1")
+	assert.NoError(t, err)
+	assert.Equal(t, sha1Hex([]byte("jar")), string(sha1Data))
+	assert.FileExists(t, localJar+".md5")
+
+	remotes := repo.RemoteRepositories("org.example", "demo", "1.0")
+	assert.Equal(t, map[string][]string{
+		"demo-1.0.jar": {"central"},
+		"demo-1.0.pom": {"central"},
+	}, remotes)
+
+	_, err = repo.Path("../outside.jar")
+	assert.Error(t, err)
+}
+
+// TestClientLocalRepository 测试下载优先读取本地仓库并在未命中时写回
+func TestClientLocalRepository(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("remote:" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	repo := NewLocalRepository(t.TempDir())
+	client := newTestClient(server.URL, WithLocalRepository(repo))
+	ctx := context.Background()
+
+	// 本地已有的文件（例如mvn install安装的）直接读取
+	installed := BuildArtifactPath("org.example", "installed", "1.0", JAR)
+	assert.NoError(t, repo.Write(installed, []byte("local"), ""))
+	data, err := client.Download(ctx, installed)
+	assert.NoError(t, err)
+	assert.Equal(t, "local", string(data))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+
+	// 本地不存在时从远程下载并写回
+	data, err = client.DownloadJar(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.True(t, repo.Has(BuildArtifactPath("org.example", "demo", "1.0", JAR)))
+	assert.True(t, repo.Has(BuildArtifactPath("org.example", "demo", "1.0", JAR)+".sha1"))
+	assert.Equal(t, []string{"central"}, repo.RemoteRepositories("org.example", "demo", "1.0")["demo-1.0.jar"])
+
+	again, err := client.DownloadJar(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, data, again)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// 仓库元数据和快照版本不经过本地仓库
+	_, err = client.Download(ctx, "org/example/demo/maven-metadata.xml")
+	assert.NoError(t, err)
+	_, err = client.Download(ctx, "org/example/demo/maven-metadata.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.False(t, repo.Has("org/example/demo/maven-metadata.xml"))
+
+	// 制品包保存到本地仓库
+	bundle := &ArtifactBundle{
+		GroupId:    "org.example",
+		ArtifactId: "bundle",
+		Version:    "2.0",
+		Pom:        []byte("pom"),
+		Jar:        []byte("jar"),
+		OtherFiles: map[string][]byte{"EXAMPLES": []byte("examples")},
+	}
+	assert.NoError(t, client.SaveBundleToLocalRepository(bundle))
+	assert.True(t, repo.Has("org/example/bundle/2.0/bundle-2.0-examples.jar"))
+	assert.Len(t, repo.RemoteRepositories("org.example", "bundle", "2.0"), 3)
+
+	assert.Error(t, newTestClient(server.URL).SaveBundleToLocalRepository(bundle))
+}
+
+// TestLocalRepositorySaveBundleOtherFiles 测试额外文件按自身的扩展名和分类器保存
+func TestLocalRepositorySaveBundleOtherFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("remote:" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	bundle, err := newTestClient(server.URL).DownloadCompleteBundle(context.Background(), "org.example", "dist", "1.0",
+		ArtifactFile{Type: "DIST", Extension: "zip", Classifier: "bin"})
+	assert.NoError(t, err)
+	assert.Equal(t, ArtifactFile{Type: "DIST", Extension: "zip", Classifier: "bin"}, bundle.OtherFileTypes["DIST"])
+
+	repo := NewLocalRepository(t.TempDir())
+	assert.NoError(t, repo.SaveBundle(bundle, "internal"))
+
+	zipPath := "org/example/dist/1.0/dist-1.0-bin.zip"
+	data, err := repo.Read(zipPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "remote:/maven2/"+zipPath, string(data))
+	sha1Data, err := repo.Read(zipPath + ".sha1")
+	assert.NoError(t, err)
+	assert.Equal(t, sha1Hex(data), string(sha1Data))
+	assert.False(t, repo.Has("org/example/dist/1.0/dist-1.0-dist.jar"))
+
+	remotes := repo.RemoteRepositories("org.example", "dist", "1.0")
+	assert.Equal(t, []string{"internal"}, remotes["dist-1.0-bin.zip"])
+	assert.NotContains(t, remotes, "dist-1.0-dist.jar")
+}
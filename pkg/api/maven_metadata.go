@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	mavenversion "github.com/scagogogo/sonatype-central-sdk/pkg/version"
+)
+
+// mavenMetadataFileName 仓库中元数据文件的名称
+const mavenMetadataFileName = "maven-metadata.xml"
+
+// ParseMavenMetadata 解析maven-metadata.xml的内容
+//
+// 参数:
+//   - data: maven-metadata.xml文件的原始内容
+//
+// 返回:
+//   - *response.MavenMetadata: 解析后的元数据
+//   - error: 内容不是合法的元数据XML时返回错误
+func ParseMavenMetadata(data []byte) (*response.MavenMetadata, error) {
+	var metadata response.MavenMetadata
+	if err := xml.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse maven-metadata.xml: %w", err)
+	}
+	return &metadata, nil
+}
+
+// GetMavenMetadata 从仓库下载并解析制品级的maven-metadata.xml
+//
+// 与依赖Solr搜索索引的ListVersions不同，该方法直接读取仓库（repoBaseURL）中的元数据文件，
+// 新发布的版本无需等待索引更新即可看到，也不受搜索结果行数的限制。
+// 返回的元数据包含latest、release、完整的版本列表以及最后更新时间。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 制品的组ID，如"org.apache.commons"
+//   - artifactId: 制品的制品ID，如"commons-lang3"
+//
+// 返回:
+//   - *response.MavenMetadata: 解析后的元数据
+//   - error: 下载或解析失败时返回错误，元数据不存在时返回404对应的APIError
+//
+// 使用示例:
+//
+//	client := api.NewClient()
+//	ctx := context.Background()
+//
+//	metadata, err := client.GetMavenMetadata(ctx, "org.apache.commons", "commons-lang3")
+//	if err != nil {
+//	    log.Fatalf("获取元数据失败: %v", err)
+//	}
+//
+//	fmt.Printf("最新正式版本: %s\n", metadata.Versioning.Release)
+//	fmt.Printf("共有 %d 个版本，最后更新于 %s\n",
+//	    len(metadata.Versioning.Versions), metadata.LastUpdatedTime().Format(time.RFC3339))
+func (c *Client) GetMavenMetadata(ctx context.Context, groupId, artifactId string) (*response.MavenMetadata, error) {
+	filePath := fmt.Sprintf("%s/%s/%s", strings.ReplaceAll(groupId, ".", "/"), artifactId, mavenMetadataFileName)
+	return c.downloadMavenMetadata(ctx, filePath)
+}
+
+// GetSnapshotMetadata 从仓库下载并解析快照版本目录下的maven-metadata.xml
+//
+// 快照版本在仓库中以带时间戳的文件名存储（如demo-1.0-20230115.103000-3.jar），
+// 该方法返回的元数据记录了最新快照的时间戳、构建号以及每个文件对应的实际版本，
+// 可以配合MavenMetadata.SnapshotVersion或ResolveSnapshotArtifactPath定位具体文件。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 制品的组ID
+//   - artifactId: 制品的制品ID
+//   - version: 快照版本，如"1.0-SNAPSHOT"
+//
+// 返回:
+//   - *response.MavenMetadata: 解析后的快照元数据
+//   - error: 版本不是快照版本、下载或解析失败时返回错误
+//
+// 使用示例:
+//
+//	metadata, err := client.GetSnapshotMetadata(ctx, "org.example", "demo", "1.0-SNAPSHOT")
+//	if err != nil {
+//	    log.Fatalf("获取快照元数据失败: %v", err)
+//	}
+//	fmt.Printf("最新快照JAR版本: %s\n", metadata.SnapshotVersion("jar", ""))
+func (c *Client) GetSnapshotMetadata(ctx context.Context, groupId, artifactId, version string) (*response.MavenMetadata, error) {
+	if !strings.HasSuffix(version, "-SNAPSHOT") {
+		return nil, fmt.Errorf("%s is not a snapshot version", version)
+	}
+	filePath := fmt.Sprintf("%s/%s/%s/%s", strings.ReplaceAll(groupId, ".", "/"), artifactId, version, mavenMetadataFileName)
+	return c.downloadMavenMetadata(ctx, filePath)
+}
+
+// ResolveSnapshotArtifactPath 将快照版本的制品解析为仓库中带时间戳的文件路径
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 制品的组ID
+//   - artifactId: 制品的制品ID
+//   - version: 快照版本，如"1.0-SNAPSHOT"
+//   - extension: 文件扩展名，如"jar"、"pom"
+//   - classifier: 可选的分类器，如"sources"
+//
+// 返回:
+//   - string: 可直接传给Download的相对路径
+//   - error: 元数据获取失败或其中没有对应文件时返回错误
+func (c *Client) ResolveSnapshotArtifactPath(ctx context.Context, groupId, artifactId, version, extension string, classifier ...string) (string, error) {
+	metadata, err := c.GetSnapshotMetadata(ctx, groupId, artifactId, version)
+	if err != nil {
+		return "", err
+	}
+
+	cls := ""
+	if len(classifier) > 0 {
+		cls = classifier[0]
+	}
+	resolved := metadata.SnapshotVersion(extension, cls)
+	if resolved == "" {
+		return "", fmt.Errorf("%w: no %s file for %s:%s:%s in snapshot metadata", ErrNotFound, extension, groupId, artifactId, version)
+	}
+
+	fileName := artifactId + "-" + resolved
+	if cls != "" {
+		fileName += "-" + cls
+	}
+	return fmt.Sprintf("%s/%s/%s/%s.%s", strings.ReplaceAll(groupId, ".", "/"), artifactId, version, fileName, extension), nil
+}
+
+// ListVersionsFromMetadata 基于仓库元数据列出制品的全部版本
+//
+// 返回值与ListVersions相同，便于调用方在搜索索引和仓库元数据两种数据源之间切换。
+// 结果按Maven版本规则从高到低排序；元数据中没有每个版本的发布时间，
+// 因此只有与lastUpdated对应的latest版本会填充Timestamp（毫秒）。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 制品的组ID
+//   - artifactId: 制品的制品ID
+//
+// 返回:
+//   - []*response.Version: 版本列表，最高版本在前
+//   - error: 元数据获取或解析失败时返回错误
+//
+// 使用示例:
+//
+//	// 搜索索引可能滞后于仓库，需要最新数据时改用仓库元数据
+//	versions, err := client.ListVersionsFromMetadata(ctx, "org.apache.commons", "commons-lang3")
+//	if err != nil {
+//	    log.Fatalf("获取版本失败: %v", err)
+//	}
+//	for _, v := range versions {
+//	    fmt.Println(v.Version)
+//	}
+func (c *Client) ListVersionsFromMetadata(ctx context.Context, groupId, artifactId string) ([]*response.Version, error) {
+	metadata, err := c.GetMavenMetadata(ctx, groupId, artifactId)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Versioning == nil {
+		return []*response.Version{}, nil
+	}
+
+	var lastUpdated int64
+	if t := metadata.LastUpdatedTime(); !t.IsZero() {
+		lastUpdated = t.UnixMilli()
+	}
+
+	versions := make([]*response.Version, 0, len(metadata.Versioning.Versions))
+	for _, v := range metadata.Versioning.Versions {
+		item := &response.Version{
+			ID:         fmt.Sprintf("%s:%s:%s", groupId, artifactId, v),
+			GroupId:    groupId,
+			ArtifactId: artifactId,
+			Version:    v,
+		}
+		if v == metadata.Versioning.Latest {
+			item.Timestamp = lastUpdated
+		}
+		versions = append(versions, item)
+	}
+	sortVersionsDescending(versions)
+	return versions, nil
+}
+
+// GetLatestReleaseFromMetadata 基于仓库元数据获取最新的正式版本号
+//
+// 优先使用元数据中的release字段；该字段缺失时（部分旧制品如此），
+// 从版本列表中按Maven版本规则选出最高的非快照版本。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 制品的组ID
+//   - artifactId: 制品的制品ID
+//
+// 返回:
+//   - string: 最新正式版本号
+//   - error: 元数据获取失败或没有正式版本时返回错误
+func (c *Client) GetLatestReleaseFromMetadata(ctx context.Context, groupId, artifactId string) (string, error) {
+	metadata, err := c.GetMavenMetadata(ctx, groupId, artifactId)
+	if err != nil {
+		return "", err
+	}
+	if metadata.Versioning == nil {
+		return "", fmt.Errorf("no versions found for %s:%s", groupId, artifactId)
+	}
+	if metadata.Versioning.Release != "" {
+		return metadata.Versioning.Release, nil
+	}
+
+	releases := make([]string, 0, len(metadata.Versioning.Versions))
+	for _, v := range metadata.Versioning.Versions {
+		if !mavenversion.Parse(v).IsSnapshot() {
+			releases = append(releases, v)
+		}
+	}
+	if len(releases) == 0 {
+		return "", fmt.Errorf("no release versions found for %s:%s", groupId, artifactId)
+	}
+	return mavenversion.Max(releases), nil
+}
+
+// downloadMavenMetadata 下载并解析指定路径的元数据文件
+func (c *Client) downloadMavenMetadata(ctx context.Context, filePath string) (*response.MavenMetadata, error) {
+	data, err := c.Download(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMavenMetadata(data)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testArtifactMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>org.example</groupId>
+  <artifactId>demo</artifactId>
+  <versioning>
+    <latest>1.10-SNAPSHOT</latest>
+    <release>1.9</release>
+    <versions>
+      <version>1.2</version>
+      <version>1.9</version>
+      <version>1.10-SNAPSHOT</version>
+      <version>1.10-beta-1</version>
+    </versions>
+    <lastUpdated>20230115103000</lastUpdated>
+  </versioning>
+</metadata>`
+
+const testSnapshotMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>org.example</groupId>
+  <artifactId>demo</artifactId>
+  <version>1.10-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20230115.103000</timestamp>
+      <buildNumber>3</buildNumber>
+    </snapshot>
+    <lastUpdated>20230115103000</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>1.10-20230115.103000-3</value>
+        <updated>20230115103000</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <classifier>sources</classifier>
+        <extension>jar</extension>
+        <value>1.10-20230115.102000-2</value>
+        <updated>20230115102000</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>`
+
+// TestGetMavenMetadata 测试从仓库读取制品级元数据
+func TestGetMavenMetadata(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"org/example/demo/maven-metadata.xml": testArtifactMetadata,
+		"org/example/old/maven-metadata.xml":  `<metadata><versioning><versions><version>2.0</version><version>2.1-SNAPSHOT</version><version>10.0</version></versions></versioning></metadata>`,
+	})
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	metadata, err := client.GetMavenMetadata(ctx, "org.example", "demo")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.9", metadata.Versioning.Release)
+	assert.Equal(t, "1.10-SNAPSHOT", metadata.Versioning.Latest)
+	assert.Equal(t, []string{"1.2", "1.9", "1.10-SNAPSHOT", "1.10-beta-1"}, metadata.Versioning.Versions)
+	assert.Equal(t, time.Date(2023, 1, 15, 10, 30, 0, 0, time.UTC), metadata.LastUpdatedTime())
+
+	versions, err := client.ListVersionsFromMetadata(ctx, "org.example", "demo")
+	assert.NoError(t, err)
+	assert.Len(t, versions, 4)
+	assert.Equal(t, "1.10-SNAPSHOT", versions[0].Version)
+	assert.Equal(t, "1.10-beta-1", versions[1].Version)
+	assert.Equal(t, "org.example:demo:1.2", versions[3].ID)
+	assert.NotZero(t, versions[0].Timestamp)
+
+	release, err := client.GetLatestReleaseFromMetadata(ctx, "org.example", "demo")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.9", release)
+
+	// 没有release字段时按版本规则选出最高正式版本
+	release, err = client.GetLatestReleaseFromMetadata(ctx, "org.example", "old")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0", release)
+
+	_, err = client.GetMavenMetadata(ctx, "org.example", "missing")
+	assert.True(t, isNotFoundError(err))
+
+	_, err = ParseMavenMetadata([]byte("not xml"))
+	assert.Error(t, err)
+}
+
+// TestGetSnapshotMetadata 测试快照版本元数据的解析和文件路径解析
+func TestGetSnapshotMetadata(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"org/example/demo/1.10-SNAPSHOT/maven-metadata.xml": testSnapshotMetadata,
+		"org/example/legacy/1.0-SNAPSHOT/maven-metadata.xml": `<metadata><version>1.0-SNAPSHOT</version><versioning>` +
+			`<snapshot><timestamp>20200101.000000</timestamp><buildNumber>7</buildNumber></snapshot></versioning></metadata>`,
+	})
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	metadata, err := client.GetSnapshotMetadata(ctx, "org.example", "demo", "1.10-SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, metadata.Versioning.Snapshot.BuildNumber)
+	assert.Len(t, metadata.Versioning.SnapshotVersions, 2)
+	assert.Equal(t, "1.10-20230115.102000-2", metadata.SnapshotVersion("jar", "sources"))
+
+	path, err := client.ResolveSnapshotArtifactPath(ctx, "org.example", "demo", "1.10-SNAPSHOT", JAR)
+	assert.NoError(t, err)
+	assert.Equal(t, "org/example/demo/1.10-SNAPSHOT/demo-1.10-20230115.103000-3.jar", path)
+
+	path, err = client.ResolveSnapshotArtifactPath(ctx, "org.example", "demo", "1.10-SNAPSHOT", JAR, "sources")
+	assert.NoError(t, err)
+	assert.Equal(t, "org/example/demo/1.10-SNAPSHOT/demo-1.10-20230115.102000-2-sources.jar", path)
+
+	_, err = client.ResolveSnapshotArtifactPath(ctx, "org.example", "demo", "1.10-SNAPSHOT", POM)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// 旧格式元数据只有snapshot节点
+	path, err = client.ResolveSnapshotArtifactPath(ctx, "org.example", "legacy", "1.0-SNAPSHOT", POM)
+	assert.NoError(t, err)
+	assert.Equal(t, "org/example/legacy/1.0-SNAPSHOT/legacy-1.0-20200101.000000-7.pom", path)
+
+	_, err = client.GetSnapshotMetadata(ctx, "org.example", "demo", "1.9")
+	assert.Error(t, err)
+}
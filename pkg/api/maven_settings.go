@@ -0,0 +1,294 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MavenSettings Maven settings.xml中与仓库访问相关的配置
+//
+// 字段说明:
+//   - LocalRepository: 本地仓库路径，未配置时为空
+//   - Servers: 服务器认证信息，加密的密码已经解密
+//   - Mirrors: 镜像配置
+//   - Repositories: 激活的profile中声明的仓库（包括插件仓库）
+type MavenSettings struct {
+	LocalRepository string
+	Servers         []MavenServer
+	Mirrors         []MavenMirror
+	Repositories    []MavenRepository
+}
+
+// MavenServer settings.xml中的一个<server>
+//
+// 字段说明:
+//   - ID: 服务器ID，与镜像或仓库的ID对应
+//   - Username、Password: 用户名和密码
+//   - Headers: <configuration><httpHeaders>中配置的请求头，如Authorization: Bearer ...
+type MavenServer struct {
+	ID       string
+	Username string
+	Password string
+	Headers  map[string]string
+}
+
+// Credentials 返回服务器的认证信息，httpHeaders中配置了Bearer令牌时使用令牌
+func (s MavenServer) Credentials() Credentials {
+	for name, value := range s.Headers {
+		if strings.EqualFold(name, "Authorization") && strings.HasPrefix(strings.ToLower(value), "bearer ") {
+			return Credentials{Token: strings.TrimSpace(value[len("bearer "):])}
+		}
+	}
+	return Credentials{Username: s.Username, Password: s.Password}
+}
+
+// MavenMirror settings.xml中的一个<mirror>
+type MavenMirror struct {
+	ID       string
+	Name     string
+	URL      string
+	MirrorOf string
+}
+
+// MavenRepository settings.xml的profile中声明的一个仓库
+type MavenRepository struct {
+	ID   string
+	Name string
+	URL  string
+}
+
+// mavenSettingsXML settings.xml的XML结构
+type mavenSettingsXML struct {
+	LocalRepository string `xml:"localRepository"`
+	Servers         []struct {
+		ID            string `xml:"id"`
+		Username      string `xml:"username"`
+		Password      string `xml:"password"`
+		Configuration struct {
+			HTTPHeaders []struct {
+				Name  string `xml:"name"`
+				Value string `xml:"value"`
+			} `xml:"httpHeaders>property"`
+		} `xml:"configuration"`
+	} `xml:"servers>server"`
+	Mirrors []struct {
+		ID       string `xml:"id"`
+		Name     string `xml:"name"`
+		URL      string `xml:"url"`
+		MirrorOf string `xml:"mirrorOf"`
+	} `xml:"mirrors>mirror"`
+	Profiles []struct {
+		ID         string `xml:"id"`
+		Activation struct {
+			ActiveByDefault bool `xml:"activeByDefault"`
+		} `xml:"activation"`
+		Repositories       []mavenRepositoryXML `xml:"repositories>repository"`
+		PluginRepositories []mavenRepositoryXML `xml:"pluginRepositories>pluginRepository"`
+	} `xml:"profiles>profile"`
+	ActiveProfiles []string `xml:"activeProfiles>activeProfile"`
+}
+
+// mavenRepositoryXML settings.xml中<repository>的XML结构
+type mavenRepositoryXML struct {
+	ID   string `xml:"id"`
+	Name string `xml:"name"`
+	URL  string `xml:"url"`
+}
+
+// mavenPropertyPattern settings.xml中的${env.NAME}和${user.home}属性引用
+var mavenPropertyPattern = regexp.MustCompile(`\$\{(env\.[A-Za-z0-9_.]+|user\.home)\}`)
+
+// LoadMavenSettings 读取Maven的settings.xml
+//
+// 支持${env.NAME}和${user.home}属性引用。加密的密码（形如{...}）使用settings-security.xml中的
+// 主密码解密，解密算法与Maven 3的plexus-cipher一致；只有存在加密的密码时才会读取securityPath。
+// 只有通过<activeProfiles>或activeByDefault激活的profile中的仓库会被读取。
+//
+// 参数:
+//   - settingsPath: settings.xml路径
+//   - securityPath: settings-security.xml路径，为空时使用~/.m2/settings-security.xml
+//
+// 返回:
+//   - *MavenSettings: 解析后的配置
+//   - error: 文件读取、解析或密码解密失败时返回错误
+//
+// 使用示例:
+//
+//	settings, err := api.LoadMavenSettings("/home/ci/.m2/settings.xml", "")
+//	if err != nil {
+//	    log.Fatalf("读取settings.xml失败: %v", err)
+//	}
+//	client := api.NewClient(
+//	    api.WithRepoBaseURL("https://nexus.example.com/repository/maven-public"),
+//	    api.WithMavenSettings(settings),
+//	)
+func LoadMavenSettings(settingsPath, securityPath string) (*MavenSettings, error) {
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return nil, fmt.Errorf("读取settings.xml失败: %w", err)
+	}
+	var raw mavenSettingsXML
+	if err := xml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("解析settings.xml失败: %w", err)
+	}
+
+	// 属性引用在XML解析之后替换，环境变量中的<、&等字符不会破坏XML结构
+	home, _ := os.UserHomeDir()
+	interpolate := func(s string) string {
+		return strings.TrimSpace(mavenPropertyPattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := match[2 : len(match)-1]
+			if name == "user.home" {
+				return home
+			}
+			return os.Getenv(strings.TrimPrefix(name, "env."))
+		}))
+	}
+
+	if securityPath == "" && home != "" {
+		securityPath = filepath.Join(home, ".m2", "settings-security.xml")
+	}
+	var master *string
+	decrypt := func(serverID, value string) (string, error) {
+		if !isEncryptedMavenPassword(value) {
+			return unescapeMavenPassword(value), nil
+		}
+		if master == nil {
+			m, err := loadMavenMasterPassword(securityPath)
+			if err != nil {
+				return "", fmt.Errorf("服务器%s的密码已加密: %w", serverID, err)
+			}
+			master = &m
+		}
+		plain, err := decryptMavenPassword(value, *master)
+		if err != nil {
+			return "", fmt.Errorf("解密服务器%s的密码失败: %w", serverID, err)
+		}
+		return plain, nil
+	}
+
+	settings := &MavenSettings{LocalRepository: interpolate(raw.LocalRepository)}
+	for _, s := range raw.Servers {
+		server := MavenServer{ID: interpolate(s.ID), Username: interpolate(s.Username)}
+		if server.Password, err = decrypt(server.ID, interpolate(s.Password)); err != nil {
+			return nil, err
+		}
+		for _, header := range s.Configuration.HTTPHeaders {
+			if server.Headers == nil {
+				server.Headers = make(map[string]string)
+			}
+			server.Headers[interpolate(header.Name)] = interpolate(header.Value)
+		}
+		settings.Servers = append(settings.Servers, server)
+	}
+	for _, m := range raw.Mirrors {
+		settings.Mirrors = append(settings.Mirrors, MavenMirror{
+			ID:       interpolate(m.ID),
+			Name:     interpolate(m.Name),
+			URL:      interpolate(m.URL),
+			MirrorOf: interpolate(m.MirrorOf),
+		})
+	}
+
+	active := make(map[string]bool)
+	for _, id := range raw.ActiveProfiles {
+		active[interpolate(id)] = true
+	}
+	for _, profile := range raw.Profiles {
+		if !active[interpolate(profile.ID)] && !profile.Activation.ActiveByDefault {
+			continue
+		}
+		for _, r := range append(profile.Repositories, profile.PluginRepositories...) {
+			settings.Repositories = append(settings.Repositories, MavenRepository{
+				ID:   interpolate(r.ID),
+				Name: interpolate(r.Name),
+				URL:  interpolate(r.URL),
+			})
+		}
+	}
+	return settings, nil
+}
+
+// DefaultMavenSettings 读取当前用户的~/.m2/settings.xml和~/.m2/settings-security.xml
+//
+// 返回:
+//   - *MavenSettings: 解析后的配置
+//   - error: 无法确定用户主目录、文件不存在或解析失败时返回错误
+func DefaultMavenSettings() (*MavenSettings, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("无法确定用户主目录: %w", err)
+	}
+	m2 := filepath.Join(home, ".m2")
+	return LoadMavenSettings(filepath.Join(m2, "settings.xml"), filepath.Join(m2, "settings-security.xml"))
+}
+
+// Server 返回指定ID的服务器配置
+func (s *MavenSettings) Server(id string) (MavenServer, bool) {
+	for _, server := range s.Servers {
+		if server.ID == id {
+			return server, true
+		}
+	}
+	return MavenServer{}, false
+}
+
+// HostCredentials 返回按主机划分的认证信息
+//
+// settings.xml中的<server>按ID与镜像和仓库关联，该方法根据同ID的镜像或仓库的URL确定认证信息对应的主机。
+// 返回的键与WithCredentials的host参数格式相同。
+func (s *MavenSettings) HostCredentials() map[string]Credentials {
+	result := make(map[string]Credentials)
+	add := func(id, repoURL string) {
+		server, ok := s.Server(id)
+		if !ok || repoURL == "" {
+			return
+		}
+		if host := credentialHost(repoURL); host != "" {
+			result[host] = server.Credentials()
+		}
+	}
+	// 仓库先于镜像处理，同一主机以镜像的认证信息为准
+	for _, repo := range s.Repositories {
+		add(repo.ID, repo.URL)
+	}
+	for _, mirror := range s.Mirrors {
+		add(mirror.ID, mirror.URL)
+	}
+	return result
+}
+
+// WithMavenSettings 导入settings.xml中的认证信息
+//
+// 根据MavenSettings.HostCredentials为镜像和仓库所在的主机设置认证信息，效果与对每个主机调用WithCredentials相同。
+// 与WithCredentials同时使用时，后应用的选项覆盖先应用的选项中同一主机的设置。
+//
+// 参数:
+//   - settings: 通过LoadMavenSettings或DefaultMavenSettings读取的配置，为nil时不做任何修改
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+//
+// 使用示例:
+//
+//	settings, err := api.DefaultMavenSettings()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client := api.NewClient(
+//	    api.WithRepoBaseURL("https://artifactory.example.com/artifactory/libs-release"),
+//	    api.WithMavenSettings(settings),
+//	)
+func WithMavenSettings(settings *MavenSettings) ClientOption {
+	return func(c *Client) {
+		if settings == nil {
+			return
+		}
+		for host, credentials := range settings.HostCredentials() {
+			WithCredentials(host, credentials)(c)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// mavenMasterPasswordKey 加密settings-security.xml中主密码使用的固定口令
+	mavenMasterPasswordKey = "settings.security"
+
+	// mavenCipherSaltSize plexus-cipher加密数据中盐的长度
+	mavenCipherSaltSize = 8
+
+	// mavenMaxRelocations settings-security.xml中<relocation>的最大跳转次数
+	mavenMaxRelocations = 5
+)
+
+// mavenSettingsSecurityXML settings-security.xml的XML结构
+type mavenSettingsSecurityXML struct {
+	Master     string `xml:"master"`
+	Relocation string `xml:"relocation"`
+}
+
+// loadMavenMasterPassword 读取并解密settings-security.xml中的主密码，支持<relocation>指向的其他文件
+func loadMavenMasterPassword(path string) (string, error) {
+	for i := 0; i <= mavenMaxRelocations; i++ {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("读取settings-security.xml失败: %w", err)
+		}
+		var security mavenSettingsSecurityXML
+		if err := xml.Unmarshal(data, &security); err != nil {
+			return "", fmt.Errorf("解析%s失败: %w", path, err)
+		}
+
+		if master := strings.TrimSpace(security.Master); master != "" {
+			plain, err := decryptMavenPassword(master, mavenMasterPasswordKey)
+			if err != nil {
+				return "", fmt.Errorf("解密主密码失败: %w", err)
+			}
+			return plain, nil
+		}
+		if security.Relocation == "" {
+			return "", fmt.Errorf("%s中没有配置主密码", path)
+		}
+		path = strings.TrimSpace(security.Relocation)
+	}
+	return "", errors.New("settings-security.xml的relocation跳转次数过多")
+}
+
+// mavenEncryptedPayload 返回加密字符串中花括号内的部分
+//
+// 加密的密码形如"{base64}"，花括号前后可以有说明文字，被反斜杠转义的花括号不作为边界。
+func mavenEncryptedPayload(value string) (string, bool) {
+	start := -1
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+			continue
+		}
+		switch {
+		case value[i] == '{' && start < 0:
+			start = i
+		case value[i] == '}' && start >= 0:
+			return value[start+1 : i], true
+		}
+	}
+	return "", false
+}
+
+// isEncryptedMavenPassword 判断settings.xml中的值是否是加密的密码
+func isEncryptedMavenPassword(value string) bool {
+	_, ok := mavenEncryptedPayload(value)
+	return ok
+}
+
+// unescapeMavenPassword 去掉未加密密码中花括号的转义
+func unescapeMavenPassword(value string) string {
+	return strings.NewReplacer(`\{`, "{", `\}`, "}").Replace(value)
+}
+
+// decryptMavenPassword 按plexus-cipher的格式解密密码
+//
+// 解码后的数据依次为8字节盐、1字节填充长度、密文和随机填充。AES-128-CBC的密钥和IV
+// 分别是SHA-256(口令+盐)的前16字节和后16字节。
+func decryptMavenPassword(value, password string) (string, error) {
+	payload, ok := mavenEncryptedPayload(value)
+	if !ok {
+		return "", errors.New("不是加密的密码")
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("密文不是有效的Base64: %w", err)
+	}
+	if len(data) < mavenCipherSaltSize+1 {
+		return "", errors.New("密文长度不足")
+	}
+
+	salt := data[:mavenCipherSaltSize]
+	padLen := int(data[mavenCipherSaltSize])
+	end := len(data) - padLen
+	if end < mavenCipherSaltSize+1 {
+		return "", errors.New("密文填充长度无效")
+	}
+	encrypted := data[mavenCipherSaltSize+1 : end]
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return "", errors.New("密文长度无效")
+	}
+
+	keyAndIV := sha256.Sum256(append([]byte(password), salt...))
+	block, err := aes.NewCipher(keyAndIV[:16])
+	if err != nil {
+		return "", err
+	}
+	plain := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, keyAndIV[16:]).CryptBlocks(plain, encrypted)
+
+	// 去掉PKCS5填充
+	n := int(plain[len(plain)-1])
+	if n == 0 || n > aes.BlockSize || !bytes.Equal(plain[len(plain)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return "", errors.New("密码错误或密文已损坏")
+	}
+	return string(plain[:len(plain)-n]), nil
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// encryptMavenPassword 按plexus-cipher的格式加密密码，用于生成测试数据
+func encryptMavenPassword(plain, password string) string {
+	salt := []byte("12345678")
+	keyAndIV := sha256.Sum256(append([]byte(password), salt...))
+	block, _ := aes.NewCipher(keyAndIV[:16])
+
+	n := aes.BlockSize - len(plain)%aes.BlockSize
+	data := []byte(plain)
+	for i := 0; i < n; i++ {
+		data = append(data, byte(n))
+	}
+	cipher.NewCBCEncrypter(block, keyAndIV[16:]).CryptBlocks(data, data)
+
+	padLen := 16 - (len(salt)+len(data)+1)%16
+	all := append(append(append(salt, byte(padLen)), data...), make([]byte, padLen)...)
+	return "{" + base64.StdEncoding.EncodeToString(all) + "}"
+}
+
+// TestLoadMavenSettings 测试读取settings.xml并解密密码
+func TestLoadMavenSettings(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CI_TOKEN", "env-token")
+	// 环境变量中的XML特殊字符按原样替换，不会改变settings.xml的结构
+	t.Setenv("CI_USER", "reader</username><password>injected&amp;")
+
+	master := "master-P@ss"
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	writeFile("relocated-security.xml", `<settingsSecurity><master>`+encryptMavenPassword(master, "settings.security")+`</master></settingsSecurity>`)
+	securityPath := writeFile("settings-security.xml", `<settingsSecurity><relocation>`+filepath.Join(dir, "relocated-security.xml")+`</relocation></settingsSecurity>`)
+	settingsPath := writeFile("settings.xml", `<?xml version="1.0"?>
+<settings xmlns="http://maven.apache.org/SETTINGS/1.0.0">
+  <localRepository>/var/m2</localRepository>
+  <servers>
+    <server>
+      <id>nexus</id>
+      <username>deployer</username>
+      <password>encrypted `+encryptMavenPassword("nexus-secret", master)+` by mvn -ep</password>
+    </server>
+    <server>
+      <id>artifactory</id>
+      <configuration>
+        <httpHeaders>
+          <property><name>Authorization</name><value>Bearer ${env.CI_TOKEN}</value></property>
+        </httpHeaders>
+      </configuration>
+    </server>
+    <server>
+      <id>plain</id>
+      <username>${env.CI_USER}</username>
+      <password>\{not-encrypted\}</password>
+    </server>
+  </servers>
+  <mirrors>
+    <mirror><id>nexus</id><url>https://nexus.example.com:8443/repository/public</url><mirrorOf>*,!artifactory</mirrorOf></mirror>
+  </mirrors>
+  <profiles>
+    <profile>
+      <id>company</id>
+      <repositories>
+        <repository><id>artifactory</id><url>https://artifactory.example.com/artifactory/libs</url></repository>
+      </repositories>
+    </profile>
+    <profile>
+      <id>inactive</id>
+      <repositories>
+        <repository><id>plain</id><url>https://plain.example.com/maven2</url></repository>
+      </repositories>
+    </profile>
+  </profiles>
+  <activeProfiles><activeProfile>company</activeProfile></activeProfiles>
+</settings>`)
+
+	settings, err := LoadMavenSettings(settingsPath, securityPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/m2", settings.LocalRepository)
+	assert.Len(t, settings.Servers, 3)
+	assert.Equal(t, "nexus-secret", settings.Servers[0].Password)
+	assert.Equal(t, "{not-encrypted}", settings.Servers[2].Password)
+	assert.Equal(t, "reader</username><password>injected&amp;", settings.Servers[2].Username)
+	assert.Equal(t, []MavenMirror{{ID: "nexus", URL: "https://nexus.example.com:8443/repository/public", MirrorOf: "*,!artifactory"}}, settings.Mirrors)
+	assert.Equal(t, []MavenRepository{{ID: "artifactory", URL: "https://artifactory.example.com/artifactory/libs"}}, settings.Repositories)
+
+	assert.Equal(t, map[string]Credentials{
+		"nexus.example.com:8443":      {Username: "deployer", Password: "nexus-secret"},
+		"artifactory.example.com:443": {Token: "env-token"},
+	}, settings.HostCredentials())
+
+	client := NewClient(WithMavenSettings(settings), WithMavenSettings(nil))
+	assert.Len(t, client.credentials, 2)
+
+	// 存在加密的密码但无法获取主密码
+	_, err = LoadMavenSettings(settingsPath, filepath.Join(dir, "missing.xml"))
+	assert.Error(t, err)
+
+	// 主密码错误
+	writeFile("wrong-security.xml", `<settingsSecurity><master>`+encryptMavenPassword("wrong", "settings.security")+`</master></settingsSecurity>`)
+	_, err = LoadMavenSettings(settingsPath, filepath.Join(dir, "wrong-security.xml"))
+	assert.Error(t, err)
+}
+
+// TestDecryptMavenPassword 测试plexus-cipher格式的解密
+func TestDecryptMavenPassword(t *testing.T) {
+	for _, plain := range []string{"", "a", "exactly16bytes!!", "密码 with unicode"} {
+		decrypted, err := decryptMavenPassword(encryptMavenPassword(plain, "master"), "master")
+		assert.NoError(t, err, plain)
+		assert.Equal(t, plain, decrypted)
+	}
+
+	for _, value := range []string{"plain", "{not base64!}", "{AAAA}", "\\{escaped}"} {
+		_, err := decryptMavenPassword(value, "master")
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,412 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 常用的Maven中央仓库区域镜像
+const (
+	// AliyunMirrorURL 阿里云Maven镜像
+	AliyunMirrorURL = "https://maven.aliyun.com/repository/public"
+
+	// HuaweiMirrorURL 华为云Maven镜像
+	HuaweiMirrorURL = "https://repo.huaweicloud.com/repository/maven"
+
+	// TencentMirrorURL 腾讯云Maven镜像
+	TencentMirrorURL = "https://mirrors.cloud.tencent.com/nexus/repository/maven-public"
+)
+
+// MirrorSelectorConfig 镜像选择器的配置
+//
+// 字段说明:
+//   - ProbePath: 探测时发送HEAD请求的文件路径，应该是所有镜像上都存在的制品
+//   - Interval: 后台重新探测的间隔
+//   - Timeout: 单次探测的超时时间，超时视为不可用
+//   - HTTPClient: 探测使用的HTTP客户端，为nil时使用第一个挂载该选择器的Client的HTTP客户端
+//     （包括其代理和认证设置），尚未挂载时使用默认的HTTP客户端
+type MirrorSelectorConfig struct {
+	ProbePath  string
+	Interval   time.Duration
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+// DefaultMirrorSelectorConfig 默认的镜像选择器配置
+//
+// 默认配置值:
+//   - ProbePath: junit 4.13.2的POM，体积小且所有中央仓库镜像都会有
+//   - Interval: 5分钟
+//   - Timeout: 5秒
+var DefaultMirrorSelectorConfig = MirrorSelectorConfig{
+	ProbePath: "junit/junit/4.13.2/junit-4.13.2.pom",
+	Interval:  5 * time.Minute,
+	Timeout:   5 * time.Second,
+}
+
+// MirrorStatus 一个候选镜像的探测状态
+//
+// 字段说明:
+//   - URL: 镜像的基础URL
+//   - Healthy: 最近一次探测或下载是否成功
+//   - Probed: 是否已经探测过
+//   - Latency: 探测延迟的平滑值，每次探测后取与上次结果的平均
+//   - LastChecked: 最近一次探测的时间
+//   - LastError: 最近一次失败的原因，成功后清空
+//   - ConsecutiveFailures: 连续失败的次数
+type MirrorStatus struct {
+	URL                 string
+	Healthy             bool
+	Probed              bool
+	Latency             time.Duration
+	LastChecked         time.Time
+	LastError           error
+	ConsecutiveFailures int
+}
+
+// MirrorSelector 根据延迟和可用性选择最佳镜像
+//
+// MirrorSelector对每个候选镜像发送HEAD请求探测延迟，并按照以下顺序排列镜像:
+// 可用的镜像按延迟从低到高、尚未探测的镜像按传入顺序、不可用的镜像按连续失败次数从少到多。
+// 调用Start后会在后台按配置的间隔持续重新探测，直到调用Stop。
+//
+// 通过WithMirrorSelector挂载到客户端后，客户端仓库列表中的Maven中央仓库（ID为"central"的仓库）
+// 会被替换为按当前排名排列的候选镜像，下载时优先使用最佳镜像，失败时自动依次尝试其他镜像。
+// 下载过程中遇到的网络错误和5xx错误会立即把该镜像标记为不可用，成功的下载会恢复其可用状态，
+// 因此即使不启动后台探测，排名也会随实际下载结果调整。MirrorSelector是并发安全的，可以在多个客户端之间共享。
+//
+// 使用示例:
+//
+//	selector := api.NewMirrorSelector(
+//	    "https://repo1.maven.org/maven2",
+//	    api.AliyunMirrorURL,
+//	    api.HuaweiMirrorURL,
+//	    api.TencentMirrorURL,
+//	)
+//	selector.Start()
+//	defer selector.Stop()
+//
+//	client := api.NewClient(api.WithMirrorSelector(selector))
+//	jar, err := client.DownloadJar(ctx, "com.google.guava", "guava", "33.0.0-jre")
+type MirrorSelector struct {
+	config MirrorSelectorConfig
+
+	mu         sync.RWMutex
+	candidates []string
+	statuses   map[string]*MirrorStatus
+	httpClient *http.Client
+
+	// 后台探测的停止信号，为nil表示未启动
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewMirrorSelector 使用默认配置创建镜像选择器
+//
+// 参数:
+//   - candidates: 候选镜像的基础URL，如"https://maven.aliyun.com/repository/public"
+//
+// 返回:
+//   - *MirrorSelector: 镜像选择器，创建后尚未探测
+func NewMirrorSelector(candidates ...string) *MirrorSelector {
+	return NewMirrorSelectorWithConfig(DefaultMirrorSelectorConfig, candidates...)
+}
+
+// NewMirrorSelectorWithConfig 使用指定配置创建镜像选择器
+//
+// 配置中为零值的字段使用DefaultMirrorSelectorConfig中的值。
+//
+// 参数:
+//   - config: 选择器配置
+//   - candidates: 候选镜像的基础URL，重复的地址会被忽略
+//
+// 返回:
+//   - *MirrorSelector: 镜像选择器，创建后尚未探测
+//
+// 使用示例:
+//
+//	selector := api.NewMirrorSelectorWithConfig(api.MirrorSelectorConfig{
+//	    ProbePath: "org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.pom",
+//	    Interval:  time.Minute,
+//	}, api.AliyunMirrorURL, "https://repo1.maven.org/maven2")
+func NewMirrorSelectorWithConfig(config MirrorSelectorConfig, candidates ...string) *MirrorSelector {
+	if config.ProbePath == "" {
+		config.ProbePath = DefaultMirrorSelectorConfig.ProbePath
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultMirrorSelectorConfig.Interval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultMirrorSelectorConfig.Timeout
+	}
+
+	s := &MirrorSelector{
+		config:     config,
+		statuses:   make(map[string]*MirrorStatus),
+		httpClient: config.HTTPClient,
+	}
+	for _, candidate := range candidates {
+		if _, ok := s.statuses[candidate]; ok || candidate == "" {
+			continue
+		}
+		s.candidates = append(s.candidates, candidate)
+		s.statuses[candidate] = &MirrorStatus{URL: candidate}
+	}
+	return s
+}
+
+// Probe 立即并发探测所有候选镜像并更新排名
+//
+// 参数:
+//   - ctx: 上下文对象，取消时尚未完成的探测记为失败
+func (s *MirrorSelector) Probe(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, candidate := range s.candidates {
+		wg.Add(1)
+		go func(candidate string) {
+			defer wg.Done()
+			latency, err := s.probe(ctx, candidate)
+			s.recordProbe(candidate, latency, err)
+		}(candidate)
+	}
+	wg.Wait()
+}
+
+// probe 对一个镜像发送HEAD请求，返回延迟
+func (s *MirrorSelector) probe(ctx context.Context, candidate string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	probeURL, err := url.JoinPath(candidate, s.config.ProbePath)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, probeURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "sonatype-central-sdk/1.0")
+
+	s.mu.RLock()
+	httpClient := s.httpClient
+	s.mu.RUnlock()
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return 0, fmt.Errorf("探测%s返回状态码%d", probeURL, resp.StatusCode)
+	}
+	return time.Since(start), nil
+}
+
+// recordProbe 记录一次探测结果
+func (s *MirrorSelector) recordProbe(candidate string, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.statuses[candidate]
+	status.LastChecked = time.Now()
+	if err != nil {
+		status.markFailed(err)
+		return
+	}
+	if status.Probed && status.Latency > 0 {
+		latency = (status.Latency + latency) / 2
+	}
+	status.Probed = true
+	status.Healthy = true
+	status.Latency = latency
+	status.LastError = nil
+	status.ConsecutiveFailures = 0
+}
+
+// markFailed 将镜像标记为不可用
+func (st *MirrorStatus) markFailed(err error) {
+	st.Probed = true
+	st.Healthy = false
+	st.LastError = err
+	st.ConsecutiveFailures++
+}
+
+// reportDownload 根据实际下载结果更新镜像状态
+//
+// 404表示镜像上没有该文件，不影响镜像的可用性；其他错误（网络错误、5xx等）把镜像标记为不可用，
+// 成功的下载恢复镜像的可用状态。
+func (s *MirrorSelector) reportDownload(candidate string, err error) {
+	if errors.Is(err, context.Canceled) || (err != nil && isNotFoundError(err)) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.statuses[candidate]
+	if !ok {
+		return
+	}
+	if err != nil {
+		status.markFailed(err)
+		return
+	}
+	if !status.Healthy {
+		status.Healthy = true
+		status.LastError = nil
+		status.ConsecutiveFailures = 0
+	}
+}
+
+// Ranked 返回按当前排名排列的候选镜像URL
+func (s *MirrorSelector) Ranked() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ranked := append([]string(nil), s.candidates...)
+	rank := func(st *MirrorStatus) int {
+		switch {
+		case st.Probed && st.Healthy:
+			return 0
+		case !st.Probed:
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := s.statuses[ranked[i]], s.statuses[ranked[j]]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		switch rank(a) {
+		case 0:
+			return a.Latency < b.Latency
+		case 2:
+			return a.ConsecutiveFailures < b.ConsecutiveFailures
+		}
+		return false
+	})
+	return ranked
+}
+
+// Best 返回当前最佳的可用镜像
+//
+// 返回:
+//   - string: 最佳镜像的基础URL
+//   - bool: 没有已确认可用的镜像时返回false
+func (s *MirrorSelector) Best() (string, bool) {
+	ranked := s.Ranked()
+	if len(ranked) == 0 {
+		return "", false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status := s.statuses[ranked[0]]
+	return ranked[0], status.Probed && status.Healthy
+}
+
+// Statuses 返回按当前排名排列的所有候选镜像状态
+func (s *MirrorSelector) Statuses() []MirrorStatus {
+	ranked := s.Ranked()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	statuses := make([]MirrorStatus, 0, len(ranked))
+	for _, candidate := range ranked {
+		statuses = append(statuses, *s.statuses[candidate])
+	}
+	return statuses
+}
+
+// Start 启动后台探测：立即探测一次，之后按配置的间隔重复，重复调用不会启动多个后台任务
+func (s *MirrorSelector) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	s.stop, s.done = stop, done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+		for {
+			s.Probe(ctx)
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止后台探测并等待正在进行的探测结束，未启动时直接返回
+func (s *MirrorSelector) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// attach 挂载到客户端时调用，没有配置HTTP客户端时使用该客户端的HTTP客户端进行探测
+func (s *MirrorSelector) attach(httpClient *http.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.httpClient == nil {
+		s.httpClient = httpClient
+	}
+}
+
+// WithMirrorSelector 使用镜像选择器选择Maven中央仓库的镜像
+//
+// 设置后，客户端仓库列表中ID为"central"的仓库（未设置WithRepositories时即WithRepoBaseURL设置的默认仓库）
+// 会在每次下载时被替换为选择器按当前排名排列的候选镜像：优先使用最佳镜像，
+// 失败时依次尝试其他镜像，所有镜像都失败时返回*RepositoryChainError。
+// 被WithMirrors重定向到其他镜像的中央仓库不受影响。下载结果会反馈给选择器以调整排名。
+// 缓存的键仍然基于中央仓库的地址，切换镜像不会使缓存失效。
+//
+// 参数:
+//   - selector: 镜像选择器，传入nil表示不使用
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+//
+// 使用示例:
+//
+//	selector := api.NewMirrorSelector(api.AliyunMirrorURL, api.HuaweiMirrorURL, "https://repo1.maven.org/maven2")
+//	selector.Start()
+//	defer selector.Stop()
+//
+//	client := api.NewClient(api.WithMirrorSelector(selector))
+func WithMirrorSelector(selector *MirrorSelector) ClientOption {
+	return func(c *Client) {
+		c.mirrorSelector = selector
+	}
+}
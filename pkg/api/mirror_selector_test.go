@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMirrorSelector 测试按延迟和可用性给镜像排序
+func TestMirrorSelector(t *testing.T) {
+	probePath := "org/example/probe/1.0/probe-1.0.pom"
+	fast, _ := countingServer(t, newTestServer(t, map[string]string{probePath: "<project/>"}).Config.Handler)
+	slowFixture := newTestServer(t, map[string]string{probePath: "<project/>"}).Config.Handler
+	slow, _ := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		slowFixture.ServeHTTP(w, r)
+	}))
+	broken, _ := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	missing, _ := countingServer(t, newTestServer(t, nil).Config.Handler)
+
+	candidates := []string{broken.URL + "/maven2", missing.URL + "/maven2", slow.URL + "/maven2", fast.URL + "/maven2"}
+	selector := NewMirrorSelectorWithConfig(MirrorSelectorConfig{ProbePath: probePath, Interval: 20 * time.Millisecond}, candidates...)
+
+	// 未探测时按传入顺序排列，没有确认可用的镜像
+	assert.Equal(t, candidates, selector.Ranked())
+	_, ok := selector.Best()
+	assert.False(t, ok)
+
+	selector.Probe(context.Background())
+	ranked := selector.Ranked()
+	assert.Equal(t, []string{fast.URL + "/maven2", slow.URL + "/maven2"}, ranked[:2])
+	assert.ElementsMatch(t, []string{broken.URL + "/maven2", missing.URL + "/maven2"}, ranked[2:])
+	best, ok := selector.Best()
+	assert.True(t, ok)
+	assert.Equal(t, fast.URL+"/maven2", best)
+
+	statuses := selector.Statuses()
+	assert.True(t, statuses[0].Healthy)
+	assert.Greater(t, statuses[1].Latency, statuses[0].Latency)
+	for _, status := range statuses[2:] {
+		assert.False(t, status.Healthy)
+		assert.Error(t, status.LastError)
+		assert.Equal(t, 1, status.ConsecutiveFailures)
+	}
+
+	// 后台探测持续更新状态，重复调用Start和Stop是安全的
+	checked := statuses[0].LastChecked
+	selector.Start()
+	selector.Start()
+	assert.Eventually(t, func() bool {
+		return selector.Statuses()[0].LastChecked.After(checked)
+	}, time.Second, 10*time.Millisecond)
+	selector.Stop()
+	selector.Stop()
+}
+
+// TestClientWithMirrorSelector 测试下载使用最佳镜像并在失败时切换
+func TestClientWithMirrorSelector(t *testing.T) {
+	probePath := "org/example/probe/1.0/probe-1.0.pom"
+	dir := "org/example/demo/1.0/"
+	files := map[string]string{
+		probePath:                 "<project/>",
+		dir + "demo-1.0.jar":      "demo",
+		dir + "demo-1.0.jar.sha1": sha1Hex([]byte("demo")),
+		dir + "demo-1.0.pom":      "<project/>",
+		dir + "demo-1.0.pom.sha1": sha1Hex([]byte("<project/>")),
+	}
+
+	// fast的探测正常，但下载JAR时返回502，下载校验文件时返回503
+	fastFixture := newChecksumServer(t, files).Config.Handler
+	fast, fastCount := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, ".jar"):
+			w.WriteHeader(http.StatusBadGateway)
+		case strings.HasSuffix(r.URL.Path, ".sha1"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fastFixture.ServeHTTP(w, r)
+		}
+	}))
+	slowFixture := newChecksumServer(t, files).Config.Handler
+	slow, slowCount := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			time.Sleep(50 * time.Millisecond)
+		}
+		slowFixture.ServeHTTP(w, r)
+	}))
+
+	selector := NewMirrorSelectorWithConfig(MirrorSelectorConfig{ProbePath: probePath}, slow.URL+"/maven2", fast.URL+"/maven2")
+	selector.Probe(context.Background())
+	best, _ := selector.Best()
+	assert.Equal(t, fast.URL+"/maven2", best)
+
+	client := NewClient(WithMirrorSelector(selector), WithMaxRetries(0), WithChecksumPolicy(ChecksumPolicyFail))
+	data, err := client.DownloadJar(context.Background(), "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "demo", string(data))
+	assert.Greater(t, atomic.LoadInt32(slowCount), int32(1))
+
+	// 下载失败的镜像被标记为不可用，之后优先使用slow；单个仓库时不记录GAV的来源
+	best, ok := selector.Best()
+	assert.True(t, ok)
+	assert.Equal(t, slow.URL+"/maven2", best)
+	_, ok = client.RepositoryFor("org.example", "demo", "1.0")
+	assert.False(t, ok)
+
+	// fast恢复后同一GAV的文件重新优先使用fast，校验文件失败时切换到slow
+	selector.Probe(context.Background())
+	best, _ = selector.Best()
+	assert.Equal(t, fast.URL+"/maven2", best)
+	before := atomic.LoadInt32(fastCount)
+	data, err = client.DownloadPom(context.Background(), "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "<project/>", string(data))
+	assert.Greater(t, atomic.LoadInt32(fastCount), before)
+	best, _ = selector.Best()
+	assert.Equal(t, slow.URL+"/maven2", best)
+
+	// 所有镜像都不存在的文件返回资源不存在，不影响镜像的可用性
+	_, err = client.DownloadPom(context.Background(), "org.example", "absent", "1.0")
+	assert.Error(t, err)
+	assert.True(t, isNotFoundError(err))
+	best, ok = selector.Best()
+	assert.True(t, ok)
+	assert.Equal(t, slow.URL+"/maven2", best)
+}
+
+// TestMirrorSelectorRepositoryChain 测试多个仓库时按ID记住来源仓库，镜像顺序仍然由选择器决定
+func TestMirrorSelectorRepositoryChain(t *testing.T) {
+	dir := "org/example/demo/1.0/"
+	files := map[string]string{
+		dir + "demo-1.0.jar": "demo",
+		dir + "demo-1.0.pom": "<project/>",
+	}
+	nexus, _ := countingServer(t, newTestServer(t, nil).Config.Handler)
+	first, firstCount := countingServer(t, newTestServer(t, files).Config.Handler)
+	second, secondCount := countingServer(t, newTestServer(t, files).Config.Handler)
+
+	selector := NewMirrorSelector(first.URL+"/maven2", second.URL+"/maven2")
+	client := NewClient(
+		WithRepositories(MavenRepository{ID: "nexus", URL: nexus.URL + "/maven2"}, MavenCentral),
+		WithMirrorSelector(selector),
+		WithMaxRetries(0),
+	)
+	ctx := context.Background()
+
+	_, err := client.DownloadJar(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(firstCount))
+	repo, ok := client.RepositoryFor("org.example", "demo", "1.0")
+	assert.True(t, ok)
+	assert.Equal(t, MavenCentral, repo)
+
+	// first变为不可用后，同一GAV的文件直接从当前最佳的second下载
+	selector.reportDownload(first.URL+"/maven2", ErrServerError)
+	_, err = client.DownloadPom(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(firstCount))
+	assert.Equal(t, int32(1), atomic.LoadInt32(secondCount))
+}
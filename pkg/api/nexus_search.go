@@ -0,0 +1,770 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	mavenversion "github.com/scagogogo/sonatype-central-sdk/pkg/version"
+)
+
+const (
+	// nexusSearchPath Nexus 3搜索组件的接口
+	nexusSearchPath = "/service/rest/v1/search"
+
+	// nexusSearchAssetsPath Nexus 3搜索文件的接口，按SHA-1或分类器查询时使用
+	nexusSearchAssetsPath = "/service/rest/v1/search/assets"
+
+	// nexusMaxQueries 一个搜索请求最多拆分成的Nexus查询数
+	nexusMaxQueries = 256
+
+	// nexusMaxCachedQueries 最多记住多少个查询的续页令牌或全部结果
+	nexusMaxCachedQueries = 128
+
+	// nexusResultTTL 读取了全部结果的查询在最后一次使用后保留的时间
+	nexusResultTTL = time.Minute
+)
+
+// NexusSearchBackend Nexus Repository Manager 3的搜索后端
+//
+// 查询条件被转换为Nexus搜索接口的参数：g、a、v分别对应group、name、version，p对应maven.extension，
+// 1（SHA-1）和l（分类器）通过/search/assets按文件搜索，没有字段名的文本作为关键字（q），
+// id:"g:a:v"拆分为对应的坐标，timestamp范围在本地过滤。OR条件拆分为多个查询后合并结果（用于批量SHA-1查询），
+// NOT条件以及类名（c、fc）、标签等Nexus不支持的字段返回ErrUnsupportedSearch。
+//
+// 与search.maven.org一致，默认返回每个groupId:artifactId一条结果（latestVersion和versionCount根据所有版本计算）；
+// 设置了Core为"gav"或者查询中包含版本、SHA-1、分类器时，返回每个版本一条结果。
+//
+// Nexus使用续页令牌翻页且不返回结果总数。按版本查询且不需要在本地排序或过滤时，后端只请求到所需的页为止，
+// 并记住每页的续页令牌，SearchIterator等顺序翻页的场景不会重复请求前面的页；此时如果还有更多结果，
+// NumFound只是已知结果数加一，读完最后一页后才是准确的总数。其他情况下会读取全部结果后在本地分组、排序和分页，
+// NumFound是准确的；全部结果在最后一次使用后保留一分钟，顺序翻页时只读取一次。
+// 排序字段g、a、v交给Nexus排序，timestamp在本地排序。
+//
+// 请求通过客户端的HTTP客户端发出，因此重试、速率限制、代理和WithCredentials设置的认证同样生效。
+// NexusSearchBackend是并发安全的。
+type NexusSearchBackend struct {
+	baseURL    string
+	repository string
+
+	// 每个查询已知的续页令牌，键为查询URL，值为结果偏移量到令牌的映射
+	tokenMutex sync.Mutex
+	tokens     map[string]map[int]string
+
+	// 读取了全部结果的查询，键为所有查询URL
+	resultMutex sync.Mutex
+	results     map[string]*nexusResult
+}
+
+// nexusResult 一组查询的全部结果
+type nexusResult struct {
+	docs     []*nexusDoc
+	lastUsed time.Time
+}
+
+var _ SearchBackend = &NexusSearchBackend{}
+
+// NewNexusSearchBackend 创建Nexus Repository Manager 3的搜索后端
+//
+// 参数:
+//   - baseURL: Nexus的基础URL，如"https://nexus.example.com"，不包括/service/rest
+//   - repository: 只搜索指定的仓库（可以是group仓库），为空时搜索所有Maven仓库
+//
+// 返回:
+//   - *NexusSearchBackend: 搜索后端，通过WithSearchBackend设置到客户端
+//
+// 使用示例:
+//
+//	client := api.NewClient(
+//	    api.WithSearchBackend(api.NewNexusSearchBackend("https://nexus.example.com", "maven-releases")),
+//	    api.WithBearerToken("nexus.example.com", os.Getenv("NEXUS_TOKEN")),
+//	)
+//	artifacts, err := client.SearchByGroupId(ctx, "com.example.platform", 50)
+func NewNexusSearchBackend(baseURL, repository string) *NexusSearchBackend {
+	return &NexusSearchBackend{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		repository: repository,
+	}
+}
+
+// nexusPage Nexus搜索接口返回的一页结果
+type nexusPage[T any] struct {
+	Items             []T     `json:"items"`
+	ContinuationToken *string `json:"continuationToken"`
+}
+
+// nexusComponent Nexus搜索接口返回的组件
+type nexusComponent struct {
+	ID         string        `json:"id"`
+	Repository string        `json:"repository"`
+	Format     string        `json:"format"`
+	Group      string        `json:"group"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version"`
+	Assets     []*nexusAsset `json:"assets"`
+}
+
+// nexusAsset Nexus搜索接口返回的文件
+type nexusAsset struct {
+	DownloadURL  string            `json:"downloadUrl"`
+	Path         string            `json:"path"`
+	ID           string            `json:"id"`
+	Repository   string            `json:"repository"`
+	Format       string            `json:"format"`
+	Checksum     map[string]string `json:"checksum"`
+	LastModified string            `json:"lastModified"`
+	Maven2       *struct {
+		GroupId    string `json:"groupId"`
+		ArtifactId string `json:"artifactId"`
+		Version    string `json:"version"`
+		Extension  string `json:"extension"`
+		Classifier string `json:"classifier"`
+	} `json:"maven2"`
+}
+
+// nexusDoc 转换为Solr格式的搜索结果，字段名与response.Artifact和response.Version相同
+type nexusDoc struct {
+	ID            string   `json:"id"`
+	GroupId       string   `json:"g"`
+	ArtifactId    string   `json:"a"`
+	Version       string   `json:"v,omitempty"`
+	LatestVersion string   `json:"latestVersion,omitempty"`
+	VersionCount  int      `json:"versionCount,omitempty"`
+	RepositoryID  string   `json:"repositoryId,omitempty"`
+	Packaging     string   `json:"p,omitempty"`
+	Timestamp     int64    `json:"timestamp"`
+	Ec            []string `json:"ec"`
+	Sha1          string   `json:"1,omitempty"`
+}
+
+// nexusQuery 一次Nexus查询
+type nexusQuery struct {
+	params url.Values
+
+	// 是否通过/search/assets按文件搜索
+	assets bool
+
+	// 是否包含只能按版本匹配的条件
+	versionLevel bool
+
+	// 需要在本地过滤的时间范围
+	ranges []*request.RangeExpr
+}
+
+// Search 实现SearchBackend接口
+func (b *NexusSearchBackend) Search(ctx context.Context, client *Client, searchRequest *request.SearchRequest, result interface{}) error {
+	queries, versionLevel, err := b.plan(searchRequest)
+	if err != nil {
+		return err
+	}
+
+	start, limit := searchRequest.Start, searchRequest.Limit
+	if start < 0 {
+		start = 0
+	}
+	var docs []*nexusDoc
+	var numFound int
+	sortField := searchRequest.SortField
+	if versionLevel && limit > 0 && len(queries) == 1 && len(queries[0].ranges) == 0 &&
+		(sortField == "" || nexusSortParam(sortField) != "") {
+		docs, numFound, err = b.searchPage(ctx, client, queries[0], start, limit)
+		if err != nil {
+			return err
+		}
+	} else {
+		if docs, err = b.searchAll(ctx, client, queries); err != nil {
+			return err
+		}
+		if !versionLevel {
+			docs = groupNexusArtifacts(docs)
+		}
+		sortNexusDocs(docs, sortField, searchRequest.SortAscending)
+		numFound = len(docs)
+		if start > len(docs) {
+			start = len(docs)
+		}
+		docs = docs[start:]
+		if limit >= 0 && limit < len(docs) {
+			docs = docs[:limit]
+		}
+	}
+
+	var q string
+	if searchRequest.Query != nil {
+		q = searchRequest.Query.String()
+	}
+	resp := response.Response[*nexusDoc]{
+		ResponseHeader: &response.ResponseHeader{
+			Params: &response.Params{
+				Q:     q,
+				Core:  searchRequest.Core,
+				Start: strconv.Itoa(start),
+				Rows:  strconv.Itoa(limit),
+				Wt:    "json",
+			},
+		},
+		ResponseBody: &response.ResponseBody[*nexusDoc]{
+			NumFound: numFound,
+			Start:    start,
+			Docs:     append([]*nexusDoc{}, docs...),
+		},
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// plan 将搜索请求转换为Nexus查询，同时判断结果是否按版本返回
+func (b *NexusSearchBackend) plan(searchRequest *request.SearchRequest) ([]*nexusQuery, bool, error) {
+	var expr request.Expr
+	if query := searchRequest.Query; query != nil {
+		// 与Solr请求一致，设置了CustomQuery时忽略其他字段
+		if query.CustomQuery != "" {
+			parsed, err := request.ParseQuery(query.CustomQuery)
+			if err != nil {
+				return nil, false, fmt.Errorf("%w: %v", ErrUnsupportedSearch, err)
+			}
+			expr = parsed
+		} else {
+			expr = query.Expr()
+		}
+	}
+
+	conjunctions, err := nexusConjunctions(expr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	versionLevel := searchRequest.Core == "gav"
+	queries := make([]*nexusQuery, 0, len(conjunctions))
+	for _, conjunction := range conjunctions {
+		query, ok, err := b.newQuery(conjunction)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+		if sortParam := nexusSortParam(searchRequest.SortField); sortParam != "" {
+			query.params.Set("sort", sortParam)
+			if searchRequest.SortAscending {
+				query.params.Set("direction", "asc")
+			} else {
+				query.params.Set("direction", "desc")
+			}
+		}
+		versionLevel = versionLevel || query.versionLevel
+		queries = append(queries, query)
+	}
+	return queries, versionLevel, nil
+}
+
+// nexusConjunctions 将查询表达式展开为多个条件组的或，每个条件组内的条件之间是与的关系
+func nexusConjunctions(expr request.Expr) ([][]request.Expr, error) {
+	switch e := expr.(type) {
+	case nil, *request.MatchAllExpr:
+		return [][]request.Expr{nil}, nil
+	case *request.AndExpr:
+		result := [][]request.Expr{nil}
+		for _, child := range e.Exprs {
+			alternatives, err := nexusConjunctions(child)
+			if err != nil {
+				return nil, err
+			}
+			product := make([][]request.Expr, 0, len(result)*len(alternatives))
+			for _, left := range result {
+				for _, right := range alternatives {
+					conjunction := append(append([]request.Expr(nil), left...), right...)
+					product = append(product, conjunction)
+				}
+			}
+			if len(product) > nexusMaxQueries {
+				return nil, fmt.Errorf("%w: 查询需要拆分成超过%d个Nexus查询", ErrUnsupportedSearch, nexusMaxQueries)
+			}
+			result = product
+		}
+		return result, nil
+	case *request.OrExpr:
+		var result [][]request.Expr
+		for _, child := range e.Exprs {
+			alternatives, err := nexusConjunctions(child)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, alternatives...)
+			if len(result) > nexusMaxQueries {
+				return nil, fmt.Errorf("%w: 查询需要拆分成超过%d个Nexus查询", ErrUnsupportedSearch, nexusMaxQueries)
+			}
+		}
+		return result, nil
+	case *request.NotExpr:
+		return nil, fmt.Errorf("%w: Nexus搜索不支持NOT条件", ErrUnsupportedSearch)
+	}
+	return [][]request.Expr{{expr}}, nil
+}
+
+// newQuery 将一组条件转换为Nexus查询参数，条件互相矛盾（如同一字段要求两个不同的值）时返回false
+func (b *NexusSearchBackend) newQuery(conjunction []request.Expr) (*nexusQuery, bool, error) {
+	query := &nexusQuery{params: url.Values{}}
+	query.params.Set("format", "maven2")
+	if b.repository != "" {
+		query.params.Set("repository", b.repository)
+	}
+	set := func(name, value string) bool {
+		if existing := query.params.Get(name); existing != "" && existing != value {
+			return false
+		}
+		query.params.Set(name, value)
+		return true
+	}
+
+	for _, expr := range conjunction {
+		var field, value string
+		switch e := expr.(type) {
+		case *request.TermExpr:
+			field, value = e.Field, e.Value
+		case *request.PhraseExpr:
+			field, value = e.Field, e.Text
+		case *request.WildcardExpr:
+			if e.Pattern == "*" {
+				continue
+			}
+			field, value = e.Field, e.Pattern
+		case *request.RangeExpr:
+			if e.Field != request.FieldTimestamp {
+				return nil, false, fmt.Errorf("%w: Nexus搜索不支持字段%q的范围查询", ErrUnsupportedSearch, e.Field)
+			}
+			query.ranges = append(query.ranges, e)
+			continue
+		default:
+			return nil, false, fmt.Errorf("%w: Nexus搜索不支持查询条件%s", ErrUnsupportedSearch, expr)
+		}
+
+		ok := true
+		switch field {
+		case request.FieldGroupId:
+			ok = set("group", value)
+		case request.FieldArtifactId:
+			ok = set("name", value)
+		case request.FieldVersion:
+			ok = set("version", value)
+			query.versionLevel = true
+		case request.FieldPackaging:
+			ok = set("maven.extension", value)
+		case request.FieldClassifier:
+			ok = set("maven.classifier", value)
+			query.assets, query.versionLevel = true, true
+		case request.FieldSha1:
+			ok = set("sha1", strings.ToLower(value))
+			query.assets, query.versionLevel = true, true
+		case request.FieldId:
+			parts := strings.Split(value, ":")
+			if len(parts) < 2 || len(parts) > 3 {
+				return nil, false, fmt.Errorf("%w: 无效的id %q", ErrUnsupportedSearch, value)
+			}
+			ok = set("group", parts[0]) && set("name", parts[1])
+			if len(parts) == 3 {
+				ok = ok && set("version", parts[2])
+				query.versionLevel = true
+			}
+		case "":
+			query.params.Set("q", strings.TrimSpace(query.params.Get("q")+" "+value))
+		default:
+			return nil, false, fmt.Errorf("%w: Nexus搜索不支持字段%q", ErrUnsupportedSearch, field)
+		}
+		if !ok {
+			return nil, false, nil
+		}
+	}
+	return query, true, nil
+}
+
+// endpoint 返回查询使用的接口路径
+func (q *nexusQuery) endpoint() string {
+	if q.assets {
+		return nexusSearchAssetsPath
+	}
+	return nexusSearchPath
+}
+
+// matches 判断结果是否满足需要在本地过滤的条件
+func (q *nexusQuery) matches(doc *nexusDoc) bool {
+	for _, r := range q.ranges {
+		if r.From != "" {
+			from, err := strconv.ParseInt(r.From, 10, 64)
+			if err != nil || doc.Timestamp < from || (!r.IncludeFrom && doc.Timestamp == from) {
+				return false
+			}
+		}
+		if r.To != "" {
+			to, err := strconv.ParseInt(r.To, 10, 64)
+			if err != nil || doc.Timestamp > to || (!r.IncludeTo && doc.Timestamp == to) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fetch 请求查询的一页结果，返回转换后的结果和下一页的续页令牌，没有下一页时令牌为空
+func (b *NexusSearchBackend) fetch(ctx context.Context, client *Client, query *nexusQuery, token string) ([]*nexusDoc, string, error) {
+	params := url.Values{}
+	for name, values := range query.params {
+		params[name] = values
+	}
+	if token != "" {
+		params.Set("continuationToken", token)
+	}
+	targetUrl := b.baseURL + query.endpoint() + "?" + params.Encode()
+
+	var docs []*nexusDoc
+	var next *string
+	if query.assets {
+		var page nexusPage[*nexusAsset]
+		if _, err := client.doRequest(ctx, "GET", targetUrl, nil, &page); err != nil {
+			return nil, "", err
+		}
+		for _, asset := range page.Items {
+			if doc := asset.doc(); doc != nil {
+				docs = append(docs, doc)
+			}
+		}
+		next = page.ContinuationToken
+	} else {
+		var page nexusPage[*nexusComponent]
+		if _, err := client.doRequest(ctx, "GET", targetUrl, nil, &page); err != nil {
+			return nil, "", err
+		}
+		for _, component := range page.Items {
+			docs = append(docs, component.doc())
+		}
+		next = page.ContinuationToken
+	}
+
+	if next == nil {
+		return docs, "", nil
+	}
+	return docs, *next, nil
+}
+
+// searchAll 读取所有查询的全部结果，按版本和SHA-1去重
+//
+// 结果在最后一次使用后的nexusResultTTL内被记住，同一组查询翻页时不会重新读取全部结果。
+// 返回的切片是副本，调用方可以在本地排序。
+func (b *NexusSearchBackend) searchAll(ctx context.Context, client *Client, queries []*nexusQuery) ([]*nexusDoc, error) {
+	keys := make([]string, 0, len(queries))
+	for _, query := range queries {
+		keys = append(keys, query.endpoint()+"?"+query.params.Encode())
+	}
+	key := strings.Join(keys, "\n")
+	if docs, ok := b.cachedResult(key); ok {
+		return docs, nil
+	}
+
+	var docs []*nexusDoc
+	seen := make(map[string]bool)
+	for _, query := range queries {
+		for token := ""; ; {
+			page, next, err := b.fetch(ctx, client, query, token)
+			if err != nil {
+				return nil, err
+			}
+			for _, doc := range page {
+				key := doc.ID + "\x00" + doc.Sha1
+				if !query.matches(doc) || seen[key] {
+					continue
+				}
+				seen[key] = true
+				docs = append(docs, doc)
+			}
+			if next == "" {
+				break
+			}
+			token = next
+		}
+	}
+	b.rememberResult(key, docs)
+	return append([]*nexusDoc(nil), docs...), nil
+}
+
+// cachedResult 返回记住的全部结果的副本，并延长其保留时间
+func (b *NexusSearchBackend) cachedResult(key string) ([]*nexusDoc, bool) {
+	b.resultMutex.Lock()
+	defer b.resultMutex.Unlock()
+	result, ok := b.results[key]
+	if !ok || time.Since(result.lastUsed) > nexusResultTTL {
+		return nil, false
+	}
+	result.lastUsed = time.Now()
+	return append([]*nexusDoc(nil), result.docs...), true
+}
+
+// rememberResult 记住一组查询的全部结果，同时清理过期的结果，记住的查询过多时清空重来
+func (b *NexusSearchBackend) rememberResult(key string, docs []*nexusDoc) {
+	b.resultMutex.Lock()
+	defer b.resultMutex.Unlock()
+	for k, result := range b.results {
+		if time.Since(result.lastUsed) > nexusResultTTL {
+			delete(b.results, k)
+		}
+	}
+	if b.results == nil || len(b.results) >= nexusMaxCachedQueries {
+		b.results = make(map[string]*nexusResult)
+	}
+	b.results[key] = &nexusResult{docs: docs, lastUsed: time.Now()}
+}
+
+// searchPage 按续页令牌翻页，返回从start开始的最多limit条结果以及结果总数
+//
+// 从不超过start的最近一个已知令牌开始请求，没有读完所有结果时总数为已知结果数加一。
+func (b *NexusSearchBackend) searchPage(ctx context.Context, client *Client, query *nexusQuery, start, limit int) ([]*nexusDoc, int, error) {
+	key := query.endpoint() + "?" + query.params.Encode()
+	offset, token := b.nearestToken(key, start)
+
+	var docs []*nexusDoc
+	for {
+		page, next, err := b.fetch(ctx, client, query, token)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, doc := range page {
+			if offset >= start && len(docs) < limit {
+				docs = append(docs, doc)
+			}
+			offset++
+		}
+		if next == "" {
+			return docs, offset, nil
+		}
+		b.rememberToken(key, offset, next)
+		if len(docs) >= limit {
+			return docs, offset + 1, nil
+		}
+		token = next
+	}
+}
+
+// nearestToken 返回不超过start的最近一个已知续页令牌及其偏移量，没有时从第一页开始
+func (b *NexusSearchBackend) nearestToken(key string, start int) (int, string) {
+	b.tokenMutex.Lock()
+	defer b.tokenMutex.Unlock()
+	nearest, token := 0, ""
+	for offset, t := range b.tokens[key] {
+		if offset <= start && offset > nearest {
+			nearest, token = offset, t
+		}
+	}
+	return nearest, token
+}
+
+// rememberToken 记住查询在指定偏移量处的续页令牌，记住的查询过多时清空重来
+func (b *NexusSearchBackend) rememberToken(key string, offset int, token string) {
+	b.tokenMutex.Lock()
+	defer b.tokenMutex.Unlock()
+	if _, ok := b.tokens[key]; !ok {
+		if b.tokens == nil || len(b.tokens) >= nexusMaxCachedQueries {
+			b.tokens = make(map[string]map[int]string)
+		}
+		b.tokens[key] = make(map[int]string)
+	}
+	b.tokens[key][offset] = token
+}
+
+// doc 将组件转换为按版本的结果
+func (c *nexusComponent) doc() *nexusDoc {
+	doc := &nexusDoc{
+		ID:           c.Group + ":" + c.Name + ":" + c.Version,
+		GroupId:      c.Group,
+		ArtifactId:   c.Name,
+		Version:      c.Version,
+		RepositoryID: c.Repository,
+		Ec:           []string{},
+	}
+	for _, asset := range c.Assets {
+		suffix, ok := asset.suffix(c.Name, c.Version)
+		if !ok {
+			continue
+		}
+		doc.Ec = append(doc.Ec, suffix)
+		if timestamp := asset.timestamp(); timestamp > doc.Timestamp {
+			doc.Timestamp = timestamp
+		}
+	}
+	doc.Packaging = nexusPackaging(doc.Ec)
+	return doc
+}
+
+// doc 将文件转换为其所属版本的结果，校验文件、签名文件和maven-metadata.xml等非制品文件返回nil
+func (a *nexusAsset) doc() *nexusDoc {
+	groupId, artifactId, version, ok := a.coordinates()
+	if !ok {
+		return nil
+	}
+	suffix, ok := a.suffix(artifactId, version)
+	if !ok {
+		return nil
+	}
+	return &nexusDoc{
+		ID:           groupId + ":" + artifactId + ":" + version,
+		GroupId:      groupId,
+		ArtifactId:   artifactId,
+		Version:      version,
+		RepositoryID: a.Repository,
+		Packaging:    nexusPackaging([]string{suffix}),
+		Timestamp:    a.timestamp(),
+		Ec:           []string{suffix},
+		Sha1:         a.Checksum["sha1"],
+	}
+}
+
+// coordinates 返回文件所属的groupId、artifactId和版本，优先使用maven2属性，没有时从路径中解析
+func (a *nexusAsset) coordinates() (string, string, string, bool) {
+	if m := a.Maven2; m != nil && m.GroupId != "" && m.ArtifactId != "" && m.Version != "" {
+		return m.GroupId, m.ArtifactId, m.Version, true
+	}
+	parts := strings.Split(strings.Trim(a.Path, "/"), "/")
+	if len(parts) < 4 {
+		return "", "", "", false
+	}
+	n := len(parts)
+	return strings.Join(parts[:n-3], "."), parts[n-3], parts[n-2], true
+}
+
+// suffix 返回文件名中artifactId-version之后的部分（如".jar"、"-sources.jar"），即Solr结果中ec字段的值
+func (a *nexusAsset) suffix(artifactId, version string) (string, bool) {
+	if isSignatureOrChecksumFile(a.Path) {
+		return "", false
+	}
+	if m := a.Maven2; m != nil && m.Extension != "" {
+		if m.Classifier != "" {
+			return "-" + m.Classifier + "." + m.Extension, true
+		}
+		return "." + m.Extension, true
+	}
+	name := path.Base(a.Path)
+	prefix := artifactId + "-" + version
+	if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+		return "", false
+	}
+	return name[len(prefix):], true
+}
+
+// timestamp 返回文件的最后修改时间（毫秒），旧版本Nexus不返回该字段时为0
+func (a *nexusAsset) timestamp() int64 {
+	t, err := time.Parse(time.RFC3339, a.LastModified)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// version 返回按版本的结果的版本号，或按制品的结果的最新版本号
+func (d *nexusDoc) version() string {
+	if d.Version != "" {
+		return d.Version
+	}
+	return d.LatestVersion
+}
+
+// nexusPackaging 根据版本包含的文件推断打包类型：主制品的扩展名，只有POM时为pom
+func nexusPackaging(ec []string) string {
+	packaging := ""
+	for _, suffix := range ec {
+		if !strings.HasPrefix(suffix, ".") {
+			continue
+		}
+		extension := suffix[1:]
+		if extension != "pom" {
+			return extension
+		}
+		packaging = extension
+	}
+	return packaging
+}
+
+// groupNexusArtifacts 将按版本的结果合并为每个groupId:artifactId一条结果
+func groupNexusArtifacts(docs []*nexusDoc) []*nexusDoc {
+	var artifacts []*nexusDoc
+	groups := make(map[string]*nexusDoc)
+	versions := make(map[string]map[string]bool)
+	for _, doc := range docs {
+		key := doc.GroupId + ":" + doc.ArtifactId
+		artifact, ok := groups[key]
+		if !ok {
+			artifact = &nexusDoc{ID: key, GroupId: doc.GroupId, ArtifactId: doc.ArtifactId}
+			groups[key] = artifact
+			versions[key] = make(map[string]bool)
+			artifacts = append(artifacts, artifact)
+		}
+		if artifact.LatestVersion == "" || mavenversion.Compare(doc.Version, artifact.LatestVersion) > 0 {
+			artifact.LatestVersion = doc.Version
+			artifact.RepositoryID = doc.RepositoryID
+			artifact.Packaging = doc.Packaging
+			artifact.Timestamp = doc.Timestamp
+			artifact.Ec = doc.Ec
+		}
+		versions[key][doc.Version] = true
+		artifact.VersionCount = len(versions[key])
+	}
+	return artifacts
+}
+
+// nexusSortParam 返回Nexus搜索接口支持的排序字段，不支持时返回空字符串
+func nexusSortParam(field string) string {
+	switch field {
+	case request.FieldGroupId, "groupId":
+		return "group"
+	case request.FieldArtifactId, "artifactId":
+		return "name"
+	case request.FieldVersion, "version":
+		return "version"
+	}
+	return ""
+}
+
+// sortNexusDocs 按Solr的排序字段在本地排序，不支持的字段保持原有顺序
+func sortNexusDocs(docs []*nexusDoc, field string, ascending bool) {
+	var compare func(a, b *nexusDoc) int
+	switch field {
+	case request.FieldGroupId, "groupId":
+		compare = func(a, b *nexusDoc) int { return strings.Compare(a.GroupId, b.GroupId) }
+	case request.FieldArtifactId, "artifactId":
+		compare = func(a, b *nexusDoc) int { return strings.Compare(a.ArtifactId, b.ArtifactId) }
+	case request.FieldVersion, "version":
+		compare = func(a, b *nexusDoc) int { return mavenversion.Compare(a.version(), b.version()) }
+	case request.FieldTimestamp:
+		compare = func(a, b *nexusDoc) int {
+			switch {
+			case a.Timestamp < b.Timestamp:
+				return -1
+			case a.Timestamp > b.Timestamp:
+				return 1
+			}
+			return 0
+		}
+	case request.FieldId:
+		compare = func(a, b *nexusDoc) int { return strings.Compare(a.ID, b.ID) }
+	default:
+		return
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		if ascending {
+			return compare(docs[i], docs[j]) < 0
+		}
+		return compare(docs[i], docs[j]) > 0
+	})
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// nexusTestComponent 创建测试用的Nexus组件，每个文件的SHA-1为其路径的SHA-1
+func nexusTestComponent(group, name, version, lastModified string, suffixes ...string) *nexusComponent {
+	component := &nexusComponent{Repository: "maven-releases", Format: "maven2", Group: group, Name: name, Version: version}
+	dir := strings.ReplaceAll(group, ".", "/") + "/" + name + "/" + version + "/"
+	for _, suffix := range suffixes {
+		assetPath := dir + name + "-" + version + suffix
+		component.Assets = append(component.Assets, &nexusAsset{
+			Path:         assetPath,
+			Repository:   "maven-releases",
+			Format:       "maven2",
+			Checksum:     map[string]string{"sha1": sha1Hex([]byte(assetPath))},
+			LastModified: lastModified,
+		})
+	}
+	return component
+}
+
+// newNexusTestServer 创建模拟Nexus搜索接口的测试服务器，每页返回两条结果，续页令牌为下一页的偏移量
+func newNexusTestServer(t *testing.T, components []*nexusComponent) (*httptest.Server, *int32) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		query := r.URL.Query()
+		assert.Equal(t, "maven2", query.Get("format"))
+		assert.Equal(t, "maven-releases", query.Get("repository"))
+
+		var items []interface{}
+		for _, component := range components {
+			if (query.Get("group") != "" && query.Get("group") != component.Group) ||
+				(query.Get("name") != "" && query.Get("name") != component.Name) ||
+				(query.Get("version") != "" && query.Get("version") != component.Version) ||
+				!strings.Contains(component.Name, query.Get("q")) {
+				continue
+			}
+			switch r.URL.Path {
+			case nexusSearchPath:
+				items = append(items, component)
+			case nexusSearchAssetsPath:
+				for _, asset := range component.Assets {
+					if sha1 := query.Get("sha1"); sha1 == "" || asset.Checksum["sha1"] == sha1 {
+						items = append(items, asset)
+					}
+				}
+			default:
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		offset, _ := strconv.Atoi(query.Get("continuationToken"))
+		end := offset + 2
+		page := map[string]interface{}{"continuationToken": nil}
+		if end < len(items) {
+			page["continuationToken"] = strconv.Itoa(end)
+		} else {
+			end = len(items)
+		}
+		page["items"] = items[offset:end]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server, &count
+}
+
+// TestNexusSearchBackend 测试搜索方法通过Nexus后端查询并转换为Solr格式的结果
+func TestNexusSearchBackend(t *testing.T) {
+	components := []*nexusComponent{
+		nexusTestComponent("com.example", "lib", "1.0", "2023-01-01T00:00:00.000+00:00", ".pom", ".jar", ".jar.sha1"),
+		nexusTestComponent("com.example", "lib", "1.10", "2024-01-01T00:00:00.000+00:00", ".pom", ".jar", "-sources.jar"),
+		nexusTestComponent("com.example", "lib", "1.9", "2023-06-01T00:00:00.000+00:00", ".pom", ".jar"),
+		nexusTestComponent("com.example", "bom", "1.0", "2023-02-01T00:00:00.000+00:00", ".pom"),
+		nexusTestComponent("org.other", "tool", "3.0", "", ".pom", ".war"),
+	}
+	server, count := newNexusTestServer(t, components)
+	client := NewClient(
+		WithSearchBackend(NewNexusSearchBackend(server.URL+"/", "maven-releases")),
+		WithMaxRetries(0),
+	)
+	ctx := context.Background()
+
+	t.Run("按制品分组", func(t *testing.T) {
+		artifacts, err := client.SearchByGroupId(ctx, "com.example", 10)
+		assert.NoError(t, err)
+		if assert.Len(t, artifacts, 2) {
+			assert.Equal(t, "com.example:lib", artifacts[0].ID)
+			assert.Equal(t, "1.10", artifacts[0].LatestVersion)
+			assert.Equal(t, 3, artifacts[0].VersionCount)
+			assert.Equal(t, "jar", artifacts[0].Packaging)
+			assert.Equal(t, []string{".pom", ".jar", "-sources.jar"}, artifacts[0].Ec)
+			assert.Equal(t, int64(1704067200000), artifacts[0].Timestamp)
+			assert.Equal(t, "pom", artifacts[1].Packaging)
+		}
+	})
+
+	t.Run("按制品翻页", func(t *testing.T) {
+		client := NewClient(
+			WithSearchBackend(NewNexusSearchBackend(server.URL, "maven-releases")),
+			WithMaxRetries(0),
+		)
+		before := atomic.LoadInt32(count)
+		search := request.NewSearchRequest().
+			SetQuery(request.NewQuery().SetGroupId("com.example")).
+			SetLimit(1)
+		artifacts, err := NewSearchIterator[*response.Artifact](search).WithClient(client).ToSlice()
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 2)
+		// 全部结果只读取一次，之后的页使用记住的结果
+		assert.Equal(t, int32(2), atomic.LoadInt32(count)-before)
+	})
+
+	t.Run("按版本翻页", func(t *testing.T) {
+		before := atomic.LoadInt32(count)
+		search := request.NewSearchRequest().
+			SetQuery(request.NewQuery().SetGroupId("com.example").SetArtifactId("lib")).
+			SetCore("gav").
+			SetLimit(2)
+		versions, err := NewSearchIterator[*response.Version](search).WithClient(client).ToSlice()
+		assert.NoError(t, err)
+		var ids []string
+		for _, version := range versions {
+			ids = append(ids, version.ID)
+		}
+		assert.Equal(t, []string{"com.example:lib:1.0", "com.example:lib:1.10", "com.example:lib:1.9"}, ids)
+		// 续页令牌被记住，每页只请求一次
+		assert.Equal(t, int32(2), atomic.LoadInt32(count)-before)
+
+		versions, err = client.ListVersions(ctx, "com.example", "lib", 1)
+		assert.NoError(t, err)
+		assert.Len(t, versions, 1)
+	})
+
+	t.Run("按时间排序", func(t *testing.T) {
+		search := request.NewSearchRequest().
+			SetQuery(request.NewQuery().SetGroupId("com.example")).
+			SetCore("gav").
+			SetSort(request.FieldTimestamp, false)
+		result, err := SearchRequestJsonDoc[*response.Version](client, ctx, search)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.ResponseBody.NumFound)
+		assert.Equal(t, "1.10", result.ResponseBody.Docs[0].Version)
+		assert.Equal(t, "bom", result.ResponseBody.Docs[2].ArtifactId)
+	})
+
+	t.Run("SHA-1查询", func(t *testing.T) {
+		jarSha1 := sha1Hex([]byte("com/example/lib/1.9/lib-1.9.jar"))
+		versions, err := client.SearchBySha1(ctx, strings.ToUpper(jarSha1), 10)
+		assert.NoError(t, err)
+		if assert.Len(t, versions, 1) {
+			assert.Equal(t, "com.example:lib:1.9", versions[0].ID)
+			assert.Equal(t, "jar", versions[0].Packaging)
+		}
+
+		warSha1 := sha1Hex([]byte("org/other/tool/3.0/tool-3.0.war"))
+		missing := strings.Repeat("0", 40)
+		results, err := client.SearchBySha1Batch(ctx, []string{jarSha1, warSha1, missing})
+		assert.NoError(t, err)
+		assert.Len(t, results[jarSha1], 1)
+		if assert.Len(t, results[warSha1], 1) {
+			assert.Equal(t, "tool", results[warSha1][0].ArtifactId)
+		}
+		assert.Empty(t, results[missing])
+	})
+
+	t.Run("关键字和自定义查询", func(t *testing.T) {
+		artifacts, err := client.SearchByArtifactId(ctx, "tool", 10)
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 1)
+
+		gavs, err := client.ListGAVs(ctx, `g:com.example AND (a:bom OR v:1.0)`, 10)
+		assert.NoError(t, err)
+		assert.Len(t, gavs, 2)
+	})
+
+	t.Run("不支持的查询", func(t *testing.T) {
+		_, err := client.SearchByFullyQualifiedClassName(ctx, "com.example.Lib", 10)
+		assert.True(t, errors.Is(err, ErrUnsupportedSearch), fmt.Sprint(err))
+
+		search := request.NewSearchRequest().SetQuery(request.NewQuery().SetCustomQuery("g:com.example AND NOT a:lib"))
+		_, err = SearchRequestJsonDoc[*response.Artifact](client, ctx, search)
+		assert.ErrorIs(t, err, ErrUnsupportedSearch)
+	})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/policy"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// EvaluatePolicy 对一组制品评估策略
+//
+// 该方法是policy.NewEngine(c, p).EvaluatePolicy的便捷形式，许可证、安全评分和版本发布时间
+// 分别通过GetComponentLicenses、GetSecurityRating和ListVersions获取。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - p: 通过policy.Load、policy.Parse加载或已调用Validate的策略
+//   - refs: 要评估的制品列表
+//
+// 返回:
+//   - *policy.Report: 每个制品违反的规则
+//   - error: 上下文被取消时返回错误
+//
+// 使用示例:
+//
+//	p, err := policy.Load(".ci/dependency-policy.yaml")
+//	if err != nil {
+//	    log.Fatalf("加载策略失败: %v", err)
+//	}
+//
+//	report, err := client.EvaluatePolicy(ctx, p, refs)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !report.Passed() {
+//	    log.Fatal("依赖不符合策略要求")
+//	}
+func (c *Client) EvaluatePolicy(ctx context.Context, p *policy.Policy, refs []response.ArtifactRef) (*policy.Report, error) {
+	return policy.NewEngine(c, p).EvaluatePolicy(ctx, refs)
+}
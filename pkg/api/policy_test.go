@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/policy"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// TestEvaluatePolicyWithoutVulnerabilityDB 测试未配置漏洞数据库时严重性规则无法评估，报告不通过
+func TestEvaluatePolicyWithoutVulnerabilityDB(t *testing.T) {
+	p, err := policy.Parse([]byte(`
+rules:
+  - id: severity-cap
+    type: max-severity
+    maxSeverity: high
+`))
+	assert.NoError(t, err)
+
+	client := newTestClient(newTestServer(t, nil).URL)
+	refs := []response.ArtifactRef{{GroupId: "org.apache.logging.log4j", ArtifactId: "log4j-core", Version: "2.14.1"}}
+	report, err := client.EvaluatePolicy(context.Background(), p, refs)
+	assert.NoError(t, err)
+	assert.False(t, report.Passed())
+
+	result := report.Results[0]
+	assert.Empty(t, result.Violations)
+	assert.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], ErrNoVulnerabilityDB.Error())
+}
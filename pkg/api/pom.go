@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/pom"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// ResolvePom 获取并解析制品的有效POM模型
+//
+// 该方法通过DownloadPom下载指定制品的POM文件，沿<parent>链合并父POM，替换${...}属性占位符，
+// 导入dependencyManagement中声明的BOM，并用依赖管理补全依赖声明中缺失的版本和作用域，
+// 最终得到与`mvn help:effective-pom`基本一致的有效模型。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 制品的组ID
+//   - artifactId: 制品ID
+//   - version: 制品版本
+//
+// 返回:
+//   - *pom.Project: 有效POM模型
+//   - error: 如果POM、父POM或导入的BOM无法下载或解析，返回相应错误
+//
+// 使用示例:
+//
+//	client := api.NewClient()
+//	ctx := context.Background()
+//
+//	project, err := client.ResolvePom(ctx, "org.springframework", "spring-core", "5.3.25")
+//	if err != nil {
+//	    log.Fatalf("解析POM失败: %v", err)
+//	}
+//
+//	for _, dep := range project.Dependencies {
+//	    fmt.Printf("%s:%s:%s (%s)\n", dep.GroupId, dep.ArtifactId, dep.Version, dep.GetScope())
+//	}
+func (c *Client) ResolvePom(ctx context.Context, groupId, artifactId, version string) (*pom.Project, error) {
+	return pom.NewResolver(c).Resolve(ctx, groupId, artifactId, version)
+}
+
+// applyPomToMetadata 将有效POM模型中的信息填充到制品元数据中
+func applyPomToMetadata(metadata *response.ArtifactMetadata, project *pom.Project) {
+	if project.Packaging != "" {
+		metadata.Packaging = project.Packaging
+	}
+
+	metadata.Dependencies = convertPomDependencies(project.Dependencies)
+	if project.DependencyManagement != nil {
+		metadata.ManagedDependencies = convertPomDependencies(project.DependencyManagement.Dependencies)
+	}
+
+	if len(project.Properties) > 0 {
+		metadata.Properties = make(map[string]string, len(project.Properties))
+		for k, v := range project.Properties {
+			metadata.Properties[k] = v
+		}
+	}
+
+	if project.Parent != nil {
+		metadata.Parent = &response.ArtifactRef{
+			GroupId:    project.Parent.GroupId,
+			ArtifactId: project.Parent.ArtifactId,
+			Version:    project.Parent.Version,
+		}
+	}
+
+	metadata.Licenses = make([]string, 0, len(project.Licenses))
+	for _, license := range project.Licenses {
+		name := license.Name
+		if name == "" {
+			name = license.URL
+		}
+		if name != "" {
+			metadata.Licenses = append(metadata.Licenses, name)
+		}
+	}
+
+	metadata.Developers = make([]*response.Developer, 0, len(project.Developers))
+	for _, developer := range project.Developers {
+		metadata.Developers = append(metadata.Developers, &response.Developer{
+			ID:      developer.ID,
+			Name:    developer.Name,
+			Email:   developer.Email,
+			URL:     developer.URL,
+			Company: developer.Organization,
+		})
+	}
+
+	info := &response.ProjectInfo{
+		Name:        strings.TrimSpace(project.Name),
+		Description: strings.TrimSpace(project.Description),
+		URL:         project.URL,
+	}
+	if project.SCM != nil {
+		info.SCM = project.SCM.URL
+		if info.SCM == "" {
+			info.SCM = project.SCM.Connection
+		}
+	}
+	if project.IssueManagement != nil {
+		info.Issues = project.IssueManagement.URL
+	}
+	metadata.ProjectInfo = info
+}
+
+// convertPomDependencies 将POM依赖声明转换为响应结构
+func convertPomDependencies(dependencies []*pom.Dependency) []*response.Dependency {
+	result := make([]*response.Dependency, 0, len(dependencies))
+	for _, dep := range dependencies {
+		converted := &response.Dependency{
+			GroupId:    dep.GroupId,
+			ArtifactId: dep.ArtifactId,
+			Version:    dep.Version,
+			Scope:      dep.GetScope(),
+			Optional:   dep.IsOptional(),
+			Type:       dep.GetType(),
+			Classifier: dep.Classifier,
+		}
+		for _, exclusion := range dep.Exclusions {
+			converted.Exclusions = append(converted.Exclusions, &response.Exclusion{
+				GroupId:    exclusion.GroupId,
+				ArtifactId: exclusion.ArtifactId,
+			})
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+// ResolveDependencyGraph 解析制品的传递依赖图
+//
+// 该方法基于DownloadPom逐级获取依赖的有效POM，按照Maven的规则构建完整的传递依赖树:
+// 就近原则的版本仲裁、compile/runtime/provided/test作用域传播、可选依赖剪除以及
+// <exclusions>排除项。返回的依赖图支持循环依赖检测，并可以通过Classpath方法得到
+// 扁平化的已解析类路径。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 根制品的组ID
+//   - artifactId: 根制品ID
+//   - version: 根制品版本
+//   - opts: 解析选项，传入nil时只解析compile和runtime作用域的非可选依赖
+//
+// 返回:
+//   - *pom.DependencyGraph: 依赖图，个别传递依赖的POM获取失败时会记录在对应节点的Error字段中
+//   - error: 根制品的POM无法获取或解析时返回错误
+//
+// 使用示例:
+//
+//	client := api.NewClient()
+//	ctx := context.Background()
+//
+//	graph, err := client.ResolveDependencyGraph(ctx, "org.springframework", "spring-context", "5.3.25", nil)
+//	if err != nil {
+//	    log.Fatalf("解析依赖图失败: %v", err)
+//	}
+//
+//	// 打印类似mvn dependency:tree的依赖树
+//	fmt.Print(graph.String())
+//
+//	// 获取运行时类路径
+//	for _, node := range graph.Classpath("compile", "runtime") {
+//	    fmt.Printf("%s (%s)\n", node.Coordinate(), node.Scope)
+//	}
+func (c *Client) ResolveDependencyGraph(ctx context.Context, groupId, artifactId, version string, opts *pom.GraphOptions) (*pom.DependencyGraph, error) {
+	return pom.NewResolver(c).ResolveGraph(ctx, groupId, artifactId, version, opts)
+}
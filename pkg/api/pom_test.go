@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+var pomFixtures = map[string]string{
+	"org/example/example-parent/1.0.0/example-parent-1.0.0.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>example-parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <properties><guava.version>31.1-jre</guava.version></properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <licenses><license><name>Apache-2.0</name></license></licenses>
+  <scm><url>https://github.com/example/example</url></scm>
+</project>`,
+	"com/google/guava/guava/31.1-jre/guava-31.1-jre.pom": `<project>
+  <groupId>com.google.guava</groupId>
+  <artifactId>guava</artifactId>
+  <version>31.1-jre</version>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>failureaccess</artifactId>
+      <version>1.0.1</version>
+    </dependency>
+  </dependencies>
+</project>`,
+	"com/google/guava/failureaccess/1.0.1/failureaccess-1.0.1.pom": `<project>
+  <groupId>com.google.guava</groupId>
+  <artifactId>failureaccess</artifactId>
+  <version>1.0.1</version>
+</project>`,
+	"org/example/example-core/1.0.0/example-core-1.0.0.pom": `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>example-parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>example-core</artifactId>
+  <name>Example Core</name>
+  <developers><developer><id>dev</id><name>Dev</name><organization>Example</organization></developer></developers>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>`,
+}
+
+// pomFixtureDoc 补充example-core的打包类型和发布时间
+var pomFixtureDoc = &testserver.Document{GroupId: "org.example", ArtifactId: "example-core", Version: "1.0.0", Packaging: "jar", Timestamp: 1700000000000}
+
+// TestResolvePom 测试通过客户端解析有效POM
+func TestResolvePom(t *testing.T) {
+	server := newTestServer(t, pomFixtures)
+	client := newTestClient(server.URL)
+
+	project, err := client.ResolvePom(context.Background(), "org.example", "example-core", "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "org.example", project.GroupId)
+	assert.Len(t, project.Dependencies, 2)
+	assert.Equal(t, "31.1-jre", project.Dependencies[0].Version)
+}
+
+// TestGetArtifactMetadataFromPom 测试制品元数据由POM填充
+func TestGetArtifactMetadataFromPom(t *testing.T) {
+	server := newTestServer(t, pomFixtures, pomFixtureDoc)
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	metadata, err := client.GetArtifactMetadata(ctx, "org.example", "example-core", "1.0.0")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, metadata.PomContent)
+	assert.Equal(t, []string{"Apache-2.0"}, metadata.Licenses)
+	assert.Len(t, metadata.Developers, 1)
+	assert.Equal(t, "Example", metadata.Developers[0].Company)
+	assert.Equal(t, "Example Core", metadata.ProjectInfo.Name)
+	assert.Equal(t, "https://github.com/example/example", metadata.ProjectInfo.SCM)
+	assert.Equal(t, "example-parent", metadata.Parent.ArtifactId)
+	assert.Len(t, metadata.Dependencies, 2)
+	assert.Equal(t, "31.1-jre", metadata.Dependencies[0].Version)
+	assert.Equal(t, "compile", metadata.Dependencies[0].Scope)
+	assert.Len(t, metadata.ManagedDependencies, 1)
+
+	deps, err := client.GetArtifactDependencies(ctx, "org.example", "example-core", "1.0.0")
+	assert.NoError(t, err)
+	assert.Len(t, deps.DirectDependencies, 1)
+	assert.Len(t, deps.TestDependencies, 1)
+	assert.Len(t, deps.ManagedDependencies, 1)
+	assert.Len(t, deps.TransitiveDependencies, 1)
+	assert.Equal(t, "failureaccess", deps.TransitiveDependencies[0].ArtifactId)
+}
+
+// TestGetArtifactDependenciesPartial 测试依赖分析共用POM解析器，传递依赖解析失败时仍返回直接依赖
+func TestGetArtifactDependenciesPartial(t *testing.T) {
+	var parentRequests int32
+	fixture := newTestServer(t, pomFixtures, pomFixtureDoc).Config.Handler
+	server, _ := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "example-parent-1.0.0.pom") {
+			atomic.AddInt32(&parentRequests, 1)
+		}
+		fixture.ServeHTTP(w, r)
+	}))
+	ctx := context.Background()
+
+	// 父POM只下载一次
+	deps, err := newTestClient(server.URL).GetArtifactDependencies(ctx, "org.example", "example-core", "1.0.0")
+	assert.NoError(t, err)
+	assert.NoError(t, deps.TransitiveError)
+	assert.Len(t, deps.TransitiveDependencies, 1)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&parentRequests))
+
+	// 父POM缺失时无法构建依赖图，直接依赖仍然可用
+	files := make(map[string]string)
+	for path, content := range pomFixtures {
+		if !strings.Contains(path, "example-parent") {
+			files[path] = content
+		}
+	}
+	client := newTestClient(newTestServer(t, files, pomFixtureDoc).URL)
+	deps, err = client.GetArtifactDependencies(ctx, "org.example", "example-core", "1.0.0")
+	assert.NoError(t, err)
+	assert.Error(t, deps.TransitiveError)
+	assert.Len(t, deps.DirectDependencies, 1)
+	assert.Equal(t, "guava", deps.DirectDependencies[0].ArtifactId)
+	assert.Len(t, deps.TestDependencies, 1)
+	assert.Empty(t, deps.TransitiveDependencies)
+}
+
+// TestResolveDependencyGraph 测试通过客户端解析传递依赖图
+func TestResolveDependencyGraph(t *testing.T) {
+	server := newTestServer(t, pomFixtures)
+	client := newTestClient(server.URL)
+
+	graph, err := client.ResolveDependencyGraph(context.Background(), "org.example", "example-core", "1.0.0", nil)
+	assert.NoError(t, err)
+	assert.False(t, graph.HasCycles())
+
+	classpath := graph.Classpath()
+	assert.Len(t, classpath, 2)
+	assert.Equal(t, "com.google.guava:guava:31.1-jre", classpath[0].Coordinate())
+	assert.Equal(t, "com.google.guava:failureaccess:1.0.1", classpath[1].Coordinate())
+}
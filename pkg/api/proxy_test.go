@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// newForwardProxy 创建一个HTTP正向代理，记录收到的Proxy-Authorization头并转发到target
+func newForwardProxy(t *testing.T, target *httptest.Server, auth *string) *httptest.Server {
+	targetURL, _ := url.Parse(target.URL)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*auth = r.Header.Get("Proxy-Authorization")
+		if !r.URL.IsAbs() || r.URL.Host != "central.example" {
+			http.Error(w, "not a proxy request", http.StatusBadRequest)
+			return
+		}
+		r.URL.Scheme, r.URL.Host = targetURL.Scheme, targetURL.Host
+		r.RequestURI = ""
+		r.Header.Del("Proxy-Authorization")
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+// newSocks5Proxy 创建一个只支持用户名/密码认证和CONNECT命令的SOCKS5代理，所有连接都转发到target
+func newSocks5Proxy(t *testing.T, target string, user, password string) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	requested := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				buf := make([]byte, 512)
+				// 协商认证方式：只接受用户名/密码认证
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{5, 2})
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				u := make([]byte, buf[1])
+				_, _ = io.ReadFull(conn, u)
+				_, _ = io.ReadFull(conn, buf[:1])
+				p := make([]byte, buf[0])
+				_, _ = io.ReadFull(conn, p)
+				if string(u) != user || string(p) != password {
+					_, _ = conn.Write([]byte{1, 1})
+					return
+				}
+				_, _ = conn.Write([]byte{1, 0})
+
+				// CONNECT请求，只处理域名地址
+				if _, err := io.ReadFull(conn, buf[:5]); err != nil || buf[3] != 3 {
+					return
+				}
+				host := make([]byte, buf[4])
+				_, _ = io.ReadFull(conn, host)
+				_, _ = io.ReadFull(conn, buf[:2])
+				requested <- net.JoinHostPort(string(host), strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2]))))
+
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}(conn)
+		}
+	}()
+	return listener.Addr().String(), requested
+}
+
+// TestWithProxy 测试通过HTTP代理访问，代理凭据来自代理地址
+func TestWithProxy(t *testing.T) {
+	target := newTestServer(t, map[string]string{"org/example/demo/1.0/demo-1.0.pom": "<project/>"})
+	var auth string
+	proxy := newForwardProxy(t, target.Server, &auth)
+
+	userHTTPClient := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{MaxIdleConns: 1}}
+	userTransport := userHTTPClient.Transport
+	client := NewClient(
+		WithHTTPClient(userHTTPClient),
+		WithProxy("http://alice:p%40ss@"+proxy.Listener.Addr().String()),
+		WithRepoBaseURL("http://central.example/maven2"),
+		WithMaxRetries(0),
+	)
+	data, err := client.Download(context.Background(), "org/example/demo/1.0/demo-1.0.pom")
+	assert.NoError(t, err)
+	assert.Equal(t, "<project/>", string(data))
+	assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("alice:p@ss")), auth)
+
+	// 不修改调用方传入的HTTP客户端，保留其超时等配置
+	assert.Same(t, userTransport, userHTTPClient.Transport)
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+	assert.Equal(t, 1, client.httpClient.Transport.(*http.Transport).MaxIdleConns)
+}
+
+// TestWithProxySocks5 测试通过需要认证的SOCKS5代理访问
+func TestWithProxySocks5(t *testing.T) {
+	target := newTestServer(t, nil, &testserver.Document{GroupId: "g", ArtifactId: "a", Version: "1.0"})
+	addr, requested := newSocks5Proxy(t, target.Listener.Addr().String(), "bob", "secret")
+
+	client := NewClient(
+		WithProxy("socks5h://bob:secret@"+addr),
+		WithBaseURL("http://central.example:8081"),
+		WithMaxRetries(0),
+	)
+	versions, err := client.ListVersions(context.Background(), "g", "a", 1)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+	assert.Equal(t, "central.example:8081", <-requested)
+}
+
+// TestProxyFunc 测试代理选择：NO_PROXY列表、环境变量回退和无效地址
+func TestProxyFunc(t *testing.T) {
+	proxyFor := func(client *Client, rawURL string) (*url.URL, error) {
+		req, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+		return client.httpClient.Transport.(*http.Transport).Proxy(req)
+	}
+
+	client := NewClient(WithProxy("proxy.corp:3128"), WithNoProxy(" .internal.corp, 10.0.0.0/8,nexus:8443,[::1]"))
+	cases := map[string]bool{
+		"https://search.maven.org/solrsearch/select": true,
+		"http://internal.corp/x":                     false,
+		"http://repo.internal.corp/x":                false,
+		"http://notinternal.corp/x":                  true,
+		"http://10.1.2.3/x":                          false,
+		"http://11.1.2.3/x":                          true,
+		"https://nexus:8443/x":                       false,
+		"https://nexus/x":                            true,
+		"http://[::1]:8080/x":                        false,
+	}
+	for rawURL, proxied := range cases {
+		u, err := proxyFor(client, rawURL)
+		assert.NoError(t, err, rawURL)
+		if proxied {
+			assert.Equal(t, "http://proxy.corp:3128", u.String(), rawURL)
+		} else {
+			assert.Nil(t, u, rawURL)
+		}
+	}
+
+	// 未设置WithProxy时回退到环境变量
+	t.Setenv("HTTP_PROXY", "http://env-proxy:8080")
+	t.Setenv("HTTPS_PROXY", "socks5://env-socks:1080")
+	t.Setenv("NO_PROXY", "skip.example")
+	client = NewClient(WithNoProxy("direct.example"))
+	for rawURL, expected := range map[string]string{
+		"http://a.example/":      "http://env-proxy:8080",
+		"https://a.example/":     "socks5://env-socks:1080",
+		"https://skip.example/":  "",
+		"http://direct.example/": "",
+	} {
+		u, err := proxyFor(client, rawURL)
+		assert.NoError(t, err, rawURL)
+		if expected == "" {
+			assert.Nil(t, u, rawURL)
+		} else {
+			assert.Equal(t, expected, u.String(), rawURL)
+		}
+	}
+
+	_, err := proxyFor(NewClient(WithProxy("ftp://proxy:21")), "https://search.maven.org/")
+	assert.Error(t, err)
+
+}
+
+// TestWithProxyCustomTransport 测试代理与自定义传输层一起使用
+func TestWithProxyCustomTransport(t *testing.T) {
+	server := newTestServer(t, map[string]string{"org/example/demo/1.0/demo-1.0.pom": "<project/>"})
+	ctx := context.Background()
+	pomPath := "org/example/demo/1.0/demo-1.0.pom"
+
+	// 非*http.Transport的传输层无法设置代理，请求不能绕过代理直接发出
+	client := newTestClient(server.URL, WithTransport(server.Transport()), WithProxy("proxy:3128"))
+	_, err := client.Download(ctx, pomPath)
+	assert.ErrorIs(t, err, ErrProxyNotSupported)
+
+	// 只设置WithNoProxy时不需要代理，保持原有传输层
+	client = newTestClient(server.URL, WithTransport(server.Transport()), WithNoProxy("localhost"))
+	data, err := client.Download(ctx, pomPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "<project/>", string(data))
+
+	// 没有设置WithProxy/WithNoProxy时保留调用方的传输层，Proxy为nil表示直连，不读取代理环境变量
+	var auth string
+	proxy := newForwardProxy(t, server.Server, &auth)
+	t.Setenv("HTTP_PROXY", "http://carol:secret@"+proxy.Listener.Addr().String())
+	t.Setenv("NO_PROXY", "")
+	userTransport := &http.Transport{}
+	client = newTestClient(server.URL, WithTransport(userTransport))
+	assert.Same(t, userTransport, client.httpClient.Transport)
+	data, err = client.Download(ctx, pomPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "<project/>", string(data))
+	assert.Empty(t, auth)
+	assert.Nil(t, userTransport.Proxy)
+}
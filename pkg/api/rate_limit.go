@@ -301,6 +301,12 @@ func shouldRetryError(err error) bool {
 		return isRetriableStatusCode(httpErr.StatusCode)
 	}
 
+	// 传输中途断开的下载可以从断点继续
+	var interrupted *interruptedTransferError
+	if errors.As(err, &interrupted) {
+		return true
+	}
+
 	// 检查是否是网络连接错误或者超时错误
 	// 这里可以添加更多可重试的错误类型
 	return false
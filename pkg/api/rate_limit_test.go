@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
@@ -169,3 +171,50 @@ func TestRateLimitWithCache(t *testing.T) {
 	// 暂时跳过
 	t.Skip("暂未实现")
 }
+
+// TestClientRateLimiterIntegration 测试客户端的搜索和下载请求会经过速率限制器
+func TestClientRateLimiterIntegration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/solrsearch/select" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"response":{"numFound":0,"start":0,"docs":[]}}`))
+			return
+		}
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	limiter := NewRateLimiterWithConfig(RateLimitConfig{
+		SearchRequestsPerSecond:   100,
+		DownloadRequestsPerSecond: 100,
+		DefaultRequestsPerSecond:  100,
+		EnableStats:               true,
+	})
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRepoBaseURL(server.URL+"/maven2"),
+		WithRateLimiter(limiter),
+	)
+	assert.Equal(t, limiter, client.GetRateLimiter())
+
+	ctx := context.Background()
+	_, err = client.SearchByGroupId(ctx, "org.example", 1)
+	assert.NoError(t, err)
+	_, err = client.Download(ctx, "org/example/demo/1.0/demo-1.0.pom")
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(1), limiter.GetRequestCountByType(serverURL.Host, "search"))
+	assert.Equal(t, int64(1), limiter.GetRequestCountByType(serverURL.Host, "download"))
+	assert.Equal(t, int64(2), limiter.GetTotalRequestCount(serverURL.Host))
+}
+
+// TestWithRateLimitConfig 测试通过配置创建速率限制器
+func TestWithRateLimitConfig(t *testing.T) {
+	client := NewClient(WithRateLimitConfig(DefaultRateLimitConfig))
+	assert.NotNil(t, client.GetRateLimiter())
+
+	assert.Nil(t, NewClient().GetRateLimiter())
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// RecorderMode 录制/回放传输层的工作模式
+//
+//   - RecorderModeRecord: 所有请求都发送到真实服务器，并重新录制到一个新的录制文件中
+//   - RecorderModeReplay: 只从录制文件回放，没有录制的请求返回ErrCassetteMiss，不会访问网络
+//   - RecorderModeReplayOrRecord: 优先回放，没有录制的请求发送到真实服务器并追加到录制文件
+type RecorderMode int
+
+const (
+	// RecorderModeRecord 录制模式
+	RecorderModeRecord RecorderMode = iota
+
+	// RecorderModeReplay 回放模式
+	RecorderModeReplay
+
+	// RecorderModeReplayOrRecord 回放，缺失时录制
+	RecorderModeReplayOrRecord
+)
+
+// String 返回模式名称
+func (m RecorderMode) String() string {
+	switch m {
+	case RecorderModeRecord:
+		return "record"
+	case RecorderModeReplay:
+		return "replay"
+	case RecorderModeReplayOrRecord:
+		return "replay-or-record"
+	default:
+		return fmt.Sprintf("RecorderMode(%d)", int(m))
+	}
+}
+
+// ErrCassetteMiss 回放模式下录制文件中没有匹配的请求
+var ErrCassetteMiss = errors.New("request not found in cassette")
+
+// cassetteBodyBase64 响应体不是合法UTF-8文本时使用的编码
+const cassetteBodyBase64 = "base64"
+
+// cassette 录制文件的持久化格式
+type cassette struct {
+	Interactions []*cassetteInteraction `json:"interactions"`
+}
+
+// cassetteInteraction 一次录制的请求和响应
+//
+// 字段说明:
+//   - Method: 请求方法
+//   - URL: 规范化后的请求URL，用于匹配
+//   - Status: 响应状态码
+//   - Header: 响应头
+//   - Body: 响应体，BodyEncoding为base64时是编码后的二进制内容
+//   - RecordedAt: 录制时间
+type cassetteInteraction struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+	RecordedAt   time.Time   `json:"recordedAt"`
+}
+
+// key 返回交互的匹配键
+func (i *cassetteInteraction) key() string {
+	return i.Method + " " + i.URL
+}
+
+// body 返回解码后的响应体
+func (i *cassetteInteraction) body() ([]byte, error) {
+	if i.BodyEncoding == cassetteBodyBase64 {
+		return base64.StdEncoding.DecodeString(i.Body)
+	}
+	return []byte(i.Body), nil
+}
+
+// Recorder 录制/回放HTTP响应的传输层
+//
+// Recorder实现了http.RoundTripper接口，把经过它的请求和真实服务器的响应保存到一个JSON录制文件
+// （cassette）中，之后可以在没有网络的环境中回放。搜索请求和下载请求都会经过它，因此
+// SearchRequestJsonDoc、downloadWithCache等逻辑在回放时看到的都是真实的响应格式。
+//
+// 请求按"方法+规范化URL"匹配：协议和主机名不区分大小写，省略默认端口，查询参数按名称排序，
+// 忽略片段和请求头。录制时会去掉Range请求头，使录制文件中总是保存完整的文件内容；
+// 回放时对Range请求返回完整内容，下载逻辑会按服务器不支持Range的情况处理。
+// 每次录制新的响应后立即写回录制文件，Recorder是并发安全的。
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions map[string]*cassetteInteraction
+
+	// 加载录制文件时的错误，不为nil时所有请求都返回该错误
+	err error
+}
+
+// NewRecorder 创建录制/回放传输层
+//
+// 录制模式下总是从空的录制文件开始，旧文件会在第一次录制时被覆盖；其他模式会先加载已有的录制文件，
+// 回放模式下文件必须存在。通常不需要直接调用该函数，使用WithRecorder选项即可。
+//
+// 参数:
+//   - path: 录制文件路径
+//   - mode: 工作模式
+//   - transport: 发送真实请求的传输层，为nil时使用http.DefaultTransport
+//
+// 返回:
+//   - *Recorder: 录制/回放传输层
+//   - error: 录制文件读取或解析失败时返回错误
+//
+// 使用示例:
+//
+//	recorder, err := api.NewRecorder("testdata/central.json", api.RecorderModeReplay, nil)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	client := api.NewClient(api.WithTransport(recorder))
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	r := newRecorder(path, mode, transport)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r, nil
+}
+
+// newRecorder 创建录制/回放传输层，加载失败时错误保存在err字段中
+func newRecorder(path string, mode RecorderMode, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{
+		path:         path,
+		mode:         mode,
+		transport:    transport,
+		interactions: make(map[string]*cassetteInteraction),
+	}
+	if mode != RecorderModeRecord {
+		r.err = r.load()
+	}
+	return r
+}
+
+// load 加载录制文件，replay-or-record模式下文件不存在时从空文件开始
+func (r *Recorder) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		if os.IsNotExist(err) && r.mode == RecorderModeReplayOrRecord {
+			return nil
+		}
+		return fmt.Errorf("读取录制文件失败: %w", err)
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("解析录制文件%s失败: %w", r.path, err)
+	}
+	for _, interaction := range c.Interactions {
+		r.interactions[interaction.key()] = interaction
+	}
+	return nil
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	method, normalized := normalizeRecordedRequest(req)
+	key := method + " " + normalized
+
+	if r.mode != RecorderModeRecord {
+		r.mu.Lock()
+		interaction, ok := r.interactions[key]
+		r.mu.Unlock()
+		if ok {
+			return interaction.response(req)
+		}
+		if r.mode == RecorderModeReplay {
+			return nil, fmt.Errorf("%w: %s", ErrCassetteMiss, key)
+		}
+	}
+
+	interaction, err := r.record(req, method, normalized)
+	if err != nil {
+		return nil, err
+	}
+	return interaction.response(req)
+}
+
+// record 发送真实请求并保存响应
+func (r *Recorder) record(req *http.Request, method, normalized string) (*cassetteInteraction, error) {
+	outReq := req
+	if req.Header.Get("Range") != "" {
+		outReq = req.Clone(req.Context())
+		outReq.Header.Del("Range")
+	}
+
+	resp, err := r.transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	interaction := &cassetteInteraction{
+		Method:     method,
+		URL:        normalized,
+		Status:     resp.StatusCode,
+		Header:     header,
+		RecordedAt: time.Now().UTC(),
+	}
+	if utf8.Valid(body) {
+		interaction.Body = string(body)
+	} else {
+		interaction.Body = base64.StdEncoding.EncodeToString(body)
+		interaction.BodyEncoding = cassetteBodyBase64
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions[interaction.key()] = interaction
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return interaction, nil
+}
+
+// save 按匹配键排序后写回录制文件，调用方需要持有锁
+func (r *Recorder) save() error {
+	c := cassette{Interactions: make([]*cassetteInteraction, 0, len(r.interactions))}
+	for _, interaction := range r.interactions {
+		c.Interactions = append(c.Interactions, interaction)
+	}
+	sort.Slice(c.Interactions, func(i, j int) bool {
+		return c.Interactions[i].key() < c.Interactions[j].key()
+	})
+
+	data, err := json.MarshalIndent(&c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(r.path, data); err != nil {
+		return fmt.Errorf("写入录制文件失败: %w", err)
+	}
+	return nil
+}
+
+// response 根据录制内容构造响应
+func (i *cassetteInteraction) response(req *http.Request) (*http.Response, error) {
+	body, err := i.body()
+	if err != nil {
+		return nil, fmt.Errorf("解码录制的响应体失败: %w", err)
+	}
+	header := i.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// normalizeRecordedRequest 返回用于匹配的请求方法和规范化URL
+//
+// 协议和主机名转为小写并省略默认端口，空路径视为"/"，查询参数按名称排序（同名参数保持原有顺序），
+// 片段被忽略。
+func normalizeRecordedRequest(req *http.Request) (string, string) {
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	u := *req.URL
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if (u.Scheme == "http" && strings.HasSuffix(host, ":80")) || (u.Scheme == "https" && strings.HasSuffix(host, ":443")) {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+	u.Host = host
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+	return method, u.String()
+}
@@ -0,0 +1,436 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// 常用的远程仓库
+var (
+	// MavenCentral Maven中央仓库
+	MavenCentral = MavenRepository{ID: "central", Name: "Maven Central", URL: "https://repo1.maven.org/maven2"}
+
+	// GoogleMaven Google的Maven仓库，提供Android相关制品
+	GoogleMaven = MavenRepository{ID: "google", Name: "Google Maven", URL: "https://maven.google.com"}
+
+	// JitPack 按需从Git仓库构建制品的JitPack仓库
+	JitPack = MavenRepository{ID: "jitpack", Name: "JitPack", URL: "https://jitpack.io"}
+)
+
+// WithRepositories 设置按顺序尝试的远程仓库列表
+//
+// 设置后，Download、DownloadPom、DownloadJar等所有下载方法依次尝试列表中的仓库，
+// 某个仓库返回404、5xx或网络错误时自动尝试下一个仓库，并记住每个GAV（groupId/artifactId/version目录）
+// 由哪个仓库提供：之后下载同一GAV的其他文件时优先使用该仓库，校验文件和签名文件只从该仓库获取。
+// 所有仓库都失败时返回*RepositoryChainError，其中包含每个仓库的错误。
+// 列表会替代WithRepoBaseURL设置的下载地址；仓库可以通过WithMirrors重定向到镜像。
+// maven-metadata.xml同样按顺序获取，不会合并多个仓库的内容。
+//
+// 参数:
+//   - repositories: 远程仓库列表，ID用于匹配镜像的mirrorOf规则
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+//
+// 使用示例:
+//
+//	client := api.NewClient(
+//	    api.WithRepositories(
+//	        api.MavenRepository{ID: "nexus", URL: "https://nexus.example.com/repository/releases"},
+//	        api.MavenCentral,
+//	        api.GoogleMaven,
+//	    ),
+//	)
+//
+//	pom, err := client.DownloadPom(ctx, "androidx.core", "core", "1.12.0")
+//	var chainErr *api.RepositoryChainError
+//	if errors.As(err, &chainErr) {
+//	    for _, repoErr := range chainErr.Errors {
+//	        log.Printf("%s: %v", repoErr.Repository.ID, repoErr.Err)
+//	    }
+//	}
+func WithRepositories(repositories ...MavenRepository) ClientOption {
+	return func(c *Client) {
+		c.repositories = append([]MavenRepository(nil), repositories...)
+	}
+}
+
+// WithMirrors 设置远程仓库的镜像
+//
+// 镜像规则与Maven settings.xml中的<mirrors>相同：mirrorOf可以是仓库ID、"*"、"external:*"、
+// "external:http:*"，或者以逗号分隔的组合，"!id"表示排除。一个仓库优先使用mirrorOf与其ID完全相同的镜像，
+// 否则使用第一个匹配的镜像。多个仓库使用同一个镜像时只会请求该镜像一次。
+// 没有通过WithRepositories设置仓库时，镜像规则作用于ID为"central"的默认仓库（WithRepoBaseURL设置的地址）。
+//
+// 参数:
+//   - mirrors: 镜像列表，可以直接使用MavenSettings.Mirrors
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+//
+// 使用示例:
+//
+//	settings, err := api.DefaultMavenSettings()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client := api.NewClient(
+//	    api.WithRepositories(append([]api.MavenRepository{api.MavenCentral}, settings.Repositories...)...),
+//	    api.WithMirrors(settings.Mirrors...),
+//	    api.WithMavenSettings(settings),
+//	)
+func WithMirrors(mirrors ...MavenMirror) ClientOption {
+	return func(c *Client) {
+		c.mirrors = append([]MavenMirror(nil), mirrors...)
+	}
+}
+
+// Repositories 返回下载时按顺序尝试的远程仓库，镜像规则已经应用
+func (c *Client) Repositories() []MavenRepository {
+	return append([]MavenRepository(nil), c.repositoryChain...)
+}
+
+// RepositoryFor 返回提供过指定GAV文件的远程仓库
+//
+// 参数:
+//   - groupId: 组ID
+//   - artifactId: 制品ID
+//   - version: 版本号
+//
+// 返回:
+//   - MavenRepository: 最近一次成功提供该GAV文件的仓库
+//   - bool: 还没有从远程仓库下载过该GAV的文件时返回false
+func (c *Client) RepositoryFor(groupId, artifactId, version string) (MavenRepository, bool) {
+	dir := fmt.Sprintf("%s/%s/%s", strings.ReplaceAll(groupId, ".", "/"), artifactId, version)
+	c.repositoryMutex.RLock()
+	defer c.repositoryMutex.RUnlock()
+	repo, ok := c.repositorySources[dir]
+	return repo, ok
+}
+
+// sourceRepository 返回GAV目录最近一次的来源仓库，没有记录时返回仓库列表中的第一个仓库
+func (c *Client) sourceRepository(dir string) MavenRepository {
+	c.repositoryMutex.RLock()
+	repo, ok := c.repositorySources[dir]
+	c.repositoryMutex.RUnlock()
+	if ok {
+		return repo
+	}
+	return c.repositoryChain[0]
+}
+
+// RepositoryError 从某个远程仓库下载文件失败的错误
+//
+// 字段说明:
+//   - Repository: 失败的仓库
+//   - URL: 请求的完整地址
+//   - Err: 具体错误
+type RepositoryError struct {
+	Repository MavenRepository
+	URL        string
+	Err        error
+}
+
+func (e *RepositoryError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Repository.ID, e.URL, e.Err)
+}
+
+func (e *RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// RepositoryChainError 所有远程仓库都下载失败的错误
+//
+// 字段说明:
+//   - Path: 文件在仓库中的相对路径
+//   - Errors: 按尝试顺序排列的每个仓库的错误
+//
+// 通过errors.Is/errors.As检查时，如果有仓库返回了404以外的错误，匹配第一个这样的错误，
+// 否则匹配第一个仓库的错误；因此只有所有仓库都不存在该文件时，才会被视为资源不存在。
+type RepositoryChainError struct {
+	Path   string
+	Errors []*RepositoryError
+}
+
+func (e *RepositoryChainError) Error() string {
+	details := make([]string, 0, len(e.Errors))
+	for _, repoErr := range e.Errors {
+		details = append(details, repoErr.Error())
+	}
+	return fmt.Sprintf("从%d个仓库下载%s均失败: %s", len(e.Errors), e.Path, strings.Join(details, "; "))
+}
+
+func (e *RepositoryChainError) Unwrap() error {
+	for _, repoErr := range e.Errors {
+		if !isNotFoundError(repoErr.Err) {
+			return repoErr
+		}
+	}
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[0]
+}
+
+// buildRepositoryChain 根据仓库列表和镜像规则生成实际请求的仓库列表
+func (c *Client) buildRepositoryChain() {
+	repositories := c.repositories
+	if len(repositories) == 0 {
+		repositories = []MavenRepository{{ID: MavenCentral.ID, Name: MavenCentral.Name, URL: c.repoBaseURL}}
+	}
+
+	chain := make([]MavenRepository, 0, len(repositories))
+	seen := make(map[string]bool)
+	for _, repo := range repositories {
+		if mirror, ok := selectMirror(c.mirrors, repo); ok {
+			repo = MavenRepository{ID: mirror.ID, Name: mirror.Name, URL: mirror.URL}
+		}
+		key := repo.ID + "\x00" + repo.URL
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		chain = append(chain, repo)
+	}
+	c.repositoryChain = chain
+}
+
+// selectMirror 按Maven的规则为仓库选择镜像
+func selectMirror(mirrors []MavenMirror, repo MavenRepository) (MavenMirror, bool) {
+	for _, mirror := range mirrors {
+		if mirror.MirrorOf == repo.ID {
+			return mirror, true
+		}
+	}
+	for _, mirror := range mirrors {
+		if matchesMirrorOf(mirror.MirrorOf, repo) {
+			return mirror, true
+		}
+	}
+	return MavenMirror{}, false
+}
+
+// matchesMirrorOf 判断仓库是否匹配mirrorOf规则，逻辑与Maven的DefaultMirrorSelector一致
+func matchesMirrorOf(pattern string, repo MavenRepository) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "*" || pattern == repo.ID {
+		return true
+	}
+
+	matched := false
+	for _, item := range strings.Split(pattern, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case len(item) > 1 && strings.HasPrefix(item, "!"):
+			if item[1:] == repo.ID {
+				return false
+			}
+		case item == repo.ID:
+			return true
+		case item == "external:http:*":
+			if isExternalRepository(repo) && strings.HasPrefix(strings.ToLower(repo.URL), "http:") {
+				matched = true
+			}
+		case item == "external:*":
+			if isExternalRepository(repo) {
+				matched = true
+			}
+		case item == "*":
+			matched = true
+		}
+	}
+	return matched
+}
+
+// isExternalRepository 判断仓库是否不在本机（非localhost且不是file协议）
+func isExternalRepository(repo MavenRepository) bool {
+	u, err := url.Parse(repo.URL)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Scheme, "file") {
+		return false
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || !ip.IsLoopback()
+}
+
+// repositoriesFor 返回下载指定文件时依次尝试的仓库
+//
+// 已经记住了该文件所在GAV目录的来源仓库时，该仓库排在最前面；校验文件和签名文件只从该仓库获取，
+// 避免使用其他仓库中可能不一致的校验值。来源仓库按ID匹配：镜像选择器把同一个仓库展开为多个ID相同的候选镜像，
+// 它们之间的顺序仍然由选择器的当前排名决定，校验文件也可以在这些镜像之间切换。
+func (c *Client) repositoriesFor(filePath string) []MavenRepository {
+	c.repositoryMutex.RLock()
+	source, ok := c.repositorySources[path.Dir(filePath)]
+	c.repositoryMutex.RUnlock()
+	chain := c.selectedRepositoryChain()
+	if !ok {
+		return chain
+	}
+
+	preferred := make([]MavenRepository, 0, len(chain))
+	others := make([]MavenRepository, 0, len(chain))
+	for _, repo := range chain {
+		if repo.ID == source.ID {
+			preferred = append(preferred, repo)
+		} else {
+			others = append(others, repo)
+		}
+	}
+	if len(preferred) == 0 {
+		preferred = append(preferred, source)
+	}
+	if isSignatureOrChecksumFile(filePath) {
+		return preferred
+	}
+	return append(preferred, others...)
+}
+
+// selectedRepositoryChain 返回仓库列表，设置了镜像选择器时ID为"central"的仓库被替换为按排名排列的候选镜像
+func (c *Client) selectedRepositoryChain() []MavenRepository {
+	if c.mirrorSelector == nil {
+		return c.repositoryChain
+	}
+	ranked := c.mirrorSelector.Ranked()
+	if len(ranked) == 0 {
+		return c.repositoryChain
+	}
+	chain := make([]MavenRepository, 0, len(c.repositoryChain)+len(ranked))
+	for _, repo := range c.repositoryChain {
+		if repo.ID != MavenCentral.ID {
+			chain = append(chain, repo)
+			continue
+		}
+		for _, candidate := range ranked {
+			chain = append(chain, MavenRepository{ID: repo.ID, Name: repo.Name, URL: candidate})
+		}
+	}
+	return chain
+}
+
+// rememberRepository 记住提供文件的仓库，校验文件和签名文件不会改变记录
+func (c *Client) rememberRepository(filePath string, repo MavenRepository) {
+	if isSignatureOrChecksumFile(filePath) || len(c.repositoryChain) < 2 {
+		return
+	}
+	// 记录仓库列表中的仓库而不是选择器展开后的具体镜像
+	for _, candidate := range c.repositoryChain {
+		if candidate.ID == repo.ID {
+			repo = candidate
+			break
+		}
+	}
+	c.repositoryMutex.Lock()
+	defer c.repositoryMutex.Unlock()
+	if c.repositorySources == nil {
+		c.repositorySources = make(map[string]MavenRepository)
+	}
+	c.repositorySources[path.Dir(filePath)] = repo
+}
+
+// repositoryURL 返回文件的下载地址，优先使用记住的来源仓库
+func (c *Client) repositoryURL(filePath string) (string, error) {
+	return url.JoinPath(c.repositoriesFor(filePath)[0].URL, filePath)
+}
+
+// downloadCacheKey 返回下载内容在缓存中的键，与仓库列表中的第一个仓库关联
+func (c *Client) downloadCacheKey(filePath string) (string, error) {
+	targetUrl, err := url.JoinPath(c.repositoryChain[0].URL, filePath)
+	if err != nil {
+		return "", err
+	}
+	return "download:" + targetUrl, nil
+}
+
+// streamRemote 从远程仓库流式下载文件并写入target，不经过缓存、本地仓库和校验
+//
+// 依次尝试repositoriesFor返回的仓库，成功后记住提供文件的仓库，设置了镜像选择器时把每次尝试的结果反馈给选择器。如果某个仓库已经改变了target中的内容，
+// 不能再切换到其他仓库，直接返回该仓库的错误。只有一个仓库时直接返回其错误，与单仓库时的行为一致。
+// 只有404、5xx和网络错误才会切换到下一个仓库；认证失败、TLS错误、响应格式错误等其他错误以RepositoryError立即返回，
+// 避免把同一路径再发给其他仓库。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - filePath: 文件在Maven仓库中的相对路径
+//   - target: 写入下载内容的目标，下载完成后target.offset为文件的总字节数
+//   - progress: 进度回调，为nil时不报告进度
+//
+// 返回:
+//   - MavenRepository: 提供文件的仓库，用于在本地仓库的_remote.repositories中记录来源
+//   - error: 所有仓库都失败时返回错误
+func (c *Client) streamRemote(ctx context.Context, filePath string, target *downloadTarget, progress DownloadProgress) (MavenRepository, error) {
+	repos := c.repositoriesFor(filePath)
+	chainErr := &RepositoryChainError{Path: filePath}
+	for _, repo := range repos {
+		offset, validator := target.offset, target.validator
+		err := c.streamFromRepository(ctx, repo.URL, filePath, target, progress)
+		if c.mirrorSelector != nil && ctx.Err() == nil {
+			c.mirrorSelector.reportDownload(repo.URL, err)
+		}
+		if err == nil {
+			c.rememberRepository(filePath, repo)
+			return repo, nil
+		}
+		if len(repos) == 1 || ctx.Err() != nil || target.offset != offset || target.validator != validator {
+			return repo, err
+		}
+		targetUrl, _ := url.JoinPath(repo.URL, filePath)
+		if !shouldFailOver(err) {
+			return repo, &RepositoryError{Repository: repo, URL: targetUrl, Err: err}
+		}
+		chainErr.Errors = append(chainErr.Errors, &RepositoryError{Repository: repo, URL: targetUrl, Err: err})
+	}
+	return MavenRepository{}, chainErr
+}
+
+// shouldFailOver 判断从某个仓库下载失败后是否应该尝试下一个仓库
+//
+// 404和5xx说明该仓库没有文件或暂时不可用，网络错误说明仓库无法访问，这些情况换一个仓库可能成功；
+// TLS证书错误虽然发生在传输层，但说明连接不可信，与其他错误一样不切换仓库。
+func shouldFailOver(err error) bool {
+	if isNotFoundError(err) {
+		return true
+	}
+	var apiErr *response.APIError
+	if errors.As(err, &apiErr) {
+		code, convErr := strconv.Atoi(apiErr.Code)
+		return convErr == nil && code >= 500
+	}
+	var httpErr *response.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+	var interrupted *interruptedTransferError
+	if errors.As(err, &interrupted) {
+		return true
+	}
+	if isTLSError(err) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isTLSError 判断错误是否来自TLS握手或证书校验
+func isTLSError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &recordErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr)
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingServer 包装测试服务器并统计请求次数
+func countingServer(t *testing.T, handler http.Handler) (*httptest.Server, *int32) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &count
+}
+
+// TestRepositoryChain 测试按顺序尝试多个仓库并记住来源仓库
+func TestRepositoryChain(t *testing.T) {
+	jarPath := "org/example/demo/1.0/demo-1.0.jar"
+	nexus, nexusCount := countingServer(t, newTestServer(t, map[string]string{
+		"com/internal/lib/2.0/lib-2.0.jar": "internal",
+		jarPath + ".sha1":                  "0000000000000000000000000000000000000000",
+	}).Config.Handler)
+	failing, _ := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	central, _ := countingServer(t, newTestServer(t, map[string]string{
+		jarPath:                                  "public",
+		jarPath + ".sha1":                        sha1Hex([]byte("public")),
+		"org/example/demo/1.0/demo-1.0.pom":      "<project/>",
+		"org/example/demo/1.0/demo-1.0.pom.sha1": sha1Hex([]byte("<project/>")),
+	}).Config.Handler)
+
+	repos := []MavenRepository{
+		{ID: "nexus", URL: nexus.URL + "/maven2"},
+		{ID: "flaky", URL: failing.URL},
+		{ID: "central", URL: central.URL + "/maven2"},
+	}
+	client := NewClient(WithRepositories(repos...), WithMaxRetries(0), WithChecksumPolicy(ChecksumPolicyFail))
+	ctx := context.Background()
+
+	// 依次在nexus（404）和flaky（502）失败后从central下载，校验文件只从central获取
+	data, err := client.DownloadJar(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "public", string(data))
+	repo, ok := client.RepositoryFor("org.example", "demo", "1.0")
+	assert.True(t, ok)
+	assert.Equal(t, "central", repo.ID)
+
+	// 同一GAV的其他文件直接从记住的仓库下载
+	before := atomic.LoadInt32(nexusCount)
+	_, err = client.DownloadPom(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, before, atomic.LoadInt32(nexusCount))
+
+	data, err = NewClient(WithRepositories(repos...), WithMaxRetries(0)).Download(ctx, "com/internal/lib/2.0/lib-2.0.jar")
+	assert.NoError(t, err)
+	assert.Equal(t, "internal", string(data))
+
+	// 所有仓库都失败时返回每个仓库的错误
+	_, err = client.DownloadJar(ctx, "org.example", "missing", "1.0")
+	var chainErr *RepositoryChainError
+	if assert.True(t, errors.As(err, &chainErr)) {
+		assert.Len(t, chainErr.Errors, 3)
+		assert.Equal(t, "nexus", chainErr.Errors[0].Repository.ID)
+		assert.Contains(t, err.Error(), failing.URL)
+	}
+	assert.False(t, isNotFoundError(err), "flaky仓库返回了502，不能视为不存在")
+	var repoErr *RepositoryError
+	assert.True(t, errors.As(err, &repoErr))
+	assert.Equal(t, "flaky", repoErr.Repository.ID)
+
+	_, err = NewClient(WithRepositories(repos[0], repos[2]), WithMaxRetries(0)).Download(ctx, "org/example/missing/1.0/missing-1.0.pom")
+	assert.True(t, isNotFoundError(err))
+
+	// 认证失败和TLS错误立即返回，不再向后面的仓库请求同一路径
+	forbidden, _ := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+	for _, first := range []MavenRepository{{ID: "forbidden", URL: forbidden.URL}, {ID: "untrusted", URL: tlsServer.URL}} {
+		before := atomic.LoadInt32(nexusCount)
+		_, err = NewClient(WithRepositories(first, repos[0]), WithMaxRetries(0)).Download(ctx, "org/example/missing/1.0/missing-1.0.pom")
+		if assert.True(t, errors.As(err, &repoErr), first.ID) {
+			assert.Equal(t, first.ID, repoErr.Repository.ID)
+		}
+		assert.False(t, errors.As(err, &chainErr), first.ID)
+		assert.Equal(t, before, atomic.LoadInt32(nexusCount), first.ID)
+	}
+
+	// 无法连接的仓库切换到下一个仓库
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	data, err = NewClient(WithRepositories(MavenRepository{ID: "down", URL: closed.URL}, repos[2]), WithMaxRetries(0)).Download(ctx, jarPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "public", string(data))
+
+	// 只有一个仓库时保持原有的错误
+	_, err = NewClient(WithRepoBaseURL(central.URL+"/maven2"), WithMaxRetries(0)).Download(ctx, "org/example/missing/1.0/missing-1.0.pom")
+	assert.True(t, isNotFoundError(err))
+	assert.False(t, errors.As(err, &chainErr))
+}
+
+// TestRepositoryMirrors 测试mirrorOf规则
+func TestRepositoryMirrors(t *testing.T) {
+	local := MavenRepository{ID: "local", URL: "http://localhost:8081/repository"}
+	plain := MavenRepository{ID: "plain", URL: "http://repo.example.com/maven2"}
+	cases := []struct {
+		pattern  string
+		repo     MavenRepository
+		expected bool
+	}{
+		{"*", local, true},
+		{"central", MavenCentral, true},
+		{"central", GoogleMaven, false},
+		{"central,google", GoogleMaven, true},
+		{"*,!google", GoogleMaven, false},
+		{"*,!google", MavenCentral, true},
+		{"external:*", local, false},
+		{"external:*", MavenCentral, true},
+		{"external:http:*", MavenCentral, false},
+		{"external:http:*", plain, true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, matchesMirrorOf(c.pattern, c.repo), "%s %s", c.pattern, c.repo.ID)
+	}
+
+	client := NewClient(
+		WithRepositories(MavenCentral, GoogleMaven, JitPack, local),
+		WithMirrors(
+			MavenMirror{ID: "corp", URL: "https://nexus.example.com/repository/public", MirrorOf: "external:*,!google"},
+			MavenMirror{ID: "google-proxy", URL: "https://nexus.example.com/repository/google", MirrorOf: "google"},
+		),
+	)
+	assert.Equal(t, []MavenRepository{
+		{ID: "corp", URL: "https://nexus.example.com/repository/public"},
+		{ID: "google-proxy", URL: "https://nexus.example.com/repository/google"},
+		local,
+	}, client.Repositories())
+
+	// 没有设置仓库列表时镜像作用于默认仓库
+	client = NewClient(WithMirrors(MavenMirror{ID: "corp", URL: "https://nexus.example.com/maven2", MirrorOf: "central"}))
+	assert.Equal(t, "https://nexus.example.com/maven2", client.Repositories()[0].URL)
+	assert.Equal(t, "https://repo1.maven.org/maven2", NewClient().Repositories()[0].URL)
+}
+
+// TestRepositoryChainLocalRepository 测试写回本地仓库时在_remote.repositories中记录实际提供文件的仓库
+func TestRepositoryChainLocalRepository(t *testing.T) {
+	dir := "org/example/demo/1.0/"
+	central, _ := countingServer(t, newTestServer(t, map[string]string{
+		"org/example/other/1.0/other-1.0.jar": "other",
+	}).Config.Handler)
+	google, _ := countingServer(t, newTestServer(t, map[string]string{
+		dir + "demo-1.0.jar":      "jar",
+		dir + "demo-1.0.jar.sha1": sha1Hex([]byte("jar")),
+		dir + "demo-1.0.pom":      "<project/>",
+		dir + "demo-1.0.pom.sha1": sha1Hex([]byte("<project/>")),
+	}).Config.Handler)
+
+	repo := NewLocalRepository(t.TempDir())
+	client := NewClient(
+		WithRepositories(
+			MavenRepository{ID: "central", URL: central.URL + "/maven2"},
+			MavenRepository{ID: "google", URL: google.URL + "/maven2"},
+		),
+		WithLocalRepository(repo),
+		WithMaxRetries(0),
+	)
+	ctx := context.Background()
+
+	// 读入内存和下载到文件两条路径都记录实际的来源仓库
+	_, err := client.DownloadJar(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.NoError(t, client.DownloadFile(ctx, dir+"demo-1.0.pom", t.TempDir()+"/demo-1.0.pom"))
+	_, err = client.Download(ctx, "org/example/other/1.0/other-1.0.jar")
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"demo-1.0.jar": {"google"},
+		"demo-1.0.pom": {"google"},
+	}, repo.RemoteRepositories("org.example", "demo", "1.0"))
+	assert.Equal(t, []string{"central"}, repo.RemoteRepositories("org.example", "other", "1.0")["other-1.0.jar"])
+
+	// 保存制品包时同样使用该GAV的来源仓库
+	bundleRepo := NewLocalRepository(t.TempDir())
+	bundleClient := NewClient(WithRepositories(
+		MavenRepository{ID: "central", URL: central.URL + "/maven2"},
+		MavenRepository{ID: "google", URL: google.URL + "/maven2"},
+	), WithLocalRepository(bundleRepo), WithMaxRetries(0))
+	bundle, err := bundleClient.DownloadCompleteBundle(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.NoError(t, bundleClient.SaveBundleToLocalRepository(bundle))
+	assert.Equal(t, []string{"google"}, bundleRepo.RemoteRepositories("org.example", "demo", "1.0")["demo-1.0.jar"])
+}
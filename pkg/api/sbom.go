@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/license"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/pom"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/sbom"
+)
+
+// GenerateSBOM 为一组制品生成软件物料清单(SBOM)
+//
+// 该方法对每个制品解析有效POM获取许可证和打包类型，通过下载路径流式读取制品主文件并计算
+// SHA-1和SHA-256哈希，再根据POM中的依赖声明建立制品之间的依赖关系（只连接列表内的制品），
+// 最后按指定格式输出CycloneDX 1.5 JSON/XML或SPDX 2.3 JSON文档。
+// 单个制品的POM或文件获取失败不会中断生成，该组件只是缺少相应信息。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - refs: 需要写入SBOM的制品列表
+//   - format: 输出格式，如sbom.FormatCycloneDXJSON
+//
+// 返回:
+//   - []byte: 编码后的SBOM文档
+//   - error: 格式不受支持、上下文被取消或编码失败时返回错误
+//
+// 使用示例:
+//
+//	client := api.NewClient()
+//	ctx := context.Background()
+//
+//	refs := []response.ArtifactRef{
+//	    {GroupId: "org.apache.commons", ArtifactId: "commons-lang3", Version: "3.12.0"},
+//	    {GroupId: "com.google.guava", ArtifactId: "guava", Version: "31.1-jre"},
+//	}
+//	data, err := client.GenerateSBOM(ctx, refs, sbom.FormatCycloneDXJSON)
+//	if err != nil {
+//	    log.Fatalf("生成SBOM失败: %v", err)
+//	}
+//	os.WriteFile("bom.json", data, 0644)
+func (c *Client) GenerateSBOM(ctx context.Context, refs []response.ArtifactRef, format sbom.Format) ([]byte, error) {
+	if _, err := sbom.ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+	doc, err := c.BuildSBOM(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	return sbom.Encode(doc, format)
+}
+
+// BuildSBOM 为一组制品构建与输出格式无关的SBOM文档模型
+//
+// 与GenerateSBOM收集的信息相同，但返回sbom.BOM以便调用方在编码前补充或调整组件信息。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - refs: 需要写入SBOM的制品列表
+//
+// 返回:
+//   - *sbom.BOM: SBOM文档模型，组件顺序与refs一致（重复的制品只保留一个）
+//   - error: 上下文被取消时返回错误
+func (c *Client) BuildSBOM(ctx context.Context, refs []response.ArtifactRef) (*sbom.BOM, error) {
+	doc := sbom.New()
+	resolver := pom.NewResolver(c)
+
+	projects := make(map[*sbom.Component]*pom.Project, len(refs))
+	byArtifact := make(map[string]*sbom.Component, len(refs))
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		component := &sbom.Component{GroupId: ref.GroupId, ArtifactId: ref.ArtifactId, Version: ref.Version}
+		if seen[component.Coordinate()] {
+			continue
+		}
+		seen[component.Coordinate()] = true
+
+		project, err := c.describeSBOMComponent(ctx, resolver, component)
+		if err != nil {
+			return nil, err
+		}
+		projects[component] = project
+		doc.Components = append(doc.Components, component)
+
+		key := ref.GroupId + ":" + ref.ArtifactId
+		if _, exists := byArtifact[key]; !exists {
+			byArtifact[key] = component
+		}
+	}
+
+	// 根据POM中的依赖声明连接列表内的制品，测试和可选依赖不计入依赖关系
+	for _, component := range doc.Components {
+		project := projects[component]
+		if project == nil {
+			continue
+		}
+		linked := make(map[string]bool)
+		for _, dep := range project.Dependencies {
+			if dep.GetScope() == pom.ScopeTest || dep.IsOptional() {
+				continue
+			}
+			target, ok := byArtifact[dep.GroupId+":"+dep.ArtifactId]
+			if !ok || target == component || linked[target.Ref()] {
+				continue
+			}
+			linked[target.Ref()] = true
+			component.DependsOn = append(component.DependsOn, target.Ref())
+		}
+	}
+
+	return doc, nil
+}
+
+// GenerateSBOMFromGraph 根据传递依赖图生成软件物料清单(SBOM)
+//
+// 依赖图的根制品作为文档描述的主组件，所有经过版本仲裁后参与类路径的节点作为组件写入，
+// 依赖关系取自依赖树的父子结构；被省略的节点指向同一制品胜出的版本。
+// 依赖图由ResolveDependencyGraph得到时复用解析过程中获取的POM，不会重复下载。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - graph: 通过ResolveDependencyGraph得到的依赖图
+//   - format: 输出格式
+//
+// 返回:
+//   - []byte: 编码后的SBOM文档
+//   - error: 格式不受支持、上下文被取消或编码失败时返回错误
+//
+// 使用示例:
+//
+//	graph, err := client.ResolveDependencyGraph(ctx, "org.springframework", "spring-context", "5.3.25", nil)
+//	if err != nil {
+//	    log.Fatalf("解析依赖图失败: %v", err)
+//	}
+//	data, err := client.GenerateSBOMFromGraph(ctx, graph, sbom.FormatSPDXJSON)
+//	if err != nil {
+//	    log.Fatalf("生成SBOM失败: %v", err)
+//	}
+func (c *Client) GenerateSBOMFromGraph(ctx context.Context, graph *pom.DependencyGraph, format sbom.Format) ([]byte, error) {
+	if _, err := sbom.ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+	doc, err := c.BuildSBOMFromGraph(ctx, graph)
+	if err != nil {
+		return nil, err
+	}
+	return sbom.Encode(doc, format)
+}
+
+// GenerateSBOMForArtifact 解析制品的传递依赖并生成软件物料清单(SBOM)
+//
+// 等价于先调用ResolveDependencyGraph再调用GenerateSBOMFromGraph。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 根制品的组ID
+//   - artifactId: 根制品ID
+//   - version: 根制品版本
+//   - format: 输出格式
+//   - opts: 依赖图解析选项，传入nil时只包含compile和runtime作用域的依赖
+//
+// 返回:
+//   - []byte: 编码后的SBOM文档
+//   - error: 根制品POM无法解析、格式不受支持或编码失败时返回错误
+func (c *Client) GenerateSBOMForArtifact(ctx context.Context, groupId, artifactId, version string, format sbom.Format, opts *pom.GraphOptions) ([]byte, error) {
+	if _, err := sbom.ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+	graph, err := c.ResolveDependencyGraph(ctx, groupId, artifactId, version, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.GenerateSBOMFromGraph(ctx, graph, format)
+}
+
+// BuildSBOMFromGraph 根据传递依赖图构建与输出格式无关的SBOM文档模型
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - graph: 通过ResolveDependencyGraph得到的依赖图
+//
+// 返回:
+//   - *sbom.BOM: SBOM文档模型，组件按广度优先顺序排列
+//   - error: 上下文被取消时返回错误
+func (c *Client) BuildSBOMFromGraph(ctx context.Context, graph *pom.DependencyGraph) (*sbom.BOM, error) {
+	doc := sbom.New()
+	if graph == nil || graph.Root == nil {
+		return doc, nil
+	}
+	// 复用构建依赖图的解析器，图中节点的POM不需要重新下载
+	resolver := graph.Resolver()
+	if resolver == nil {
+		resolver = pom.NewResolver(c)
+	}
+
+	// 每个冲突键只对应一个参与类路径的节点
+	byKey := make(map[string]*sbom.Component)
+	nodes := append([]*pom.DependencyNode{graph.Root}, graph.Classpath()...)
+	for i, node := range nodes {
+		component := &sbom.Component{
+			GroupId:    node.GroupId,
+			ArtifactId: node.ArtifactId,
+			Version:    node.Version,
+			Type:       artifactExtension(node.Type),
+			Classifier: node.Classifier,
+			Scope:      node.Scope,
+		}
+		if _, err := c.describeSBOMComponent(ctx, resolver, component); err != nil {
+			return nil, err
+		}
+		byKey[node.ConflictKey()] = component
+		if i == 0 {
+			doc.Root = component
+		} else {
+			doc.Components = append(doc.Components, component)
+		}
+	}
+
+	for _, node := range nodes {
+		component := byKey[node.ConflictKey()]
+		linked := make(map[string]bool)
+		for _, child := range node.Children {
+			target, ok := byKey[child.ConflictKey()]
+			if !ok || target == component || linked[target.Ref()] {
+				continue
+			}
+			linked[target.Ref()] = true
+			component.DependsOn = append(component.DependsOn, target.Ref())
+		}
+	}
+
+	return doc, nil
+}
+
+// describeSBOMComponent 为组件补充许可证、文件类型、下载地址和哈希值
+//
+// 组件信息获取失败时记录在Error字段中，只有上下文被取消时返回错误。
+// 返回组件的有效POM模型，POM无法解析时为nil。
+func (c *Client) describeSBOMComponent(ctx context.Context, resolver *pom.Resolver, component *sbom.Component) (*pom.Project, error) {
+	var errs []string
+
+	project, err := resolver.Resolve(ctx, component.GroupId, component.ArtifactId, component.Version)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, err.Error())
+		project = nil
+	} else {
+		if component.Type == "" {
+			component.Type = artifactExtension(project.GetPackaging())
+		}
+		component.Description = strings.TrimSpace(project.Description)
+		for _, lic := range project.Licenses {
+			if lic.Name == "" && lic.URL == "" {
+				continue
+			}
+			component.Licenses = append(component.Licenses, sbomLicense(strings.TrimSpace(lic.Name), lic.URL))
+		}
+	}
+	if component.Type == "" {
+		component.Type = JAR
+	}
+
+	filePath := BuildArtifactPath(component.GroupId, component.ArtifactId, component.Version, component.Type, component.Classifier)
+	// 流式计算哈希，不把整个制品文件读入内存
+	sha1Hash, sha256Hash := sha1.New(), sha256.New()
+	err = c.DownloadToWriter(ctx, filePath, io.MultiWriter(sha1Hash, sha256Hash))
+	if downloadURL, urlErr := c.repositoryURL(filePath); urlErr == nil {
+		component.DownloadURL = downloadURL
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, err.Error())
+	} else {
+		component.Hashes = map[string]string{
+			sbom.HashSHA1:   hex.EncodeToString(sha1Hash.Sum(nil)),
+			sbom.HashSHA256: hex.EncodeToString(sha256Hash.Sum(nil)),
+		}
+	}
+
+	component.Error = strings.Join(errs, "; ")
+	return project, nil
+}
+
+// sbomLicense 将POM中的许可证声明转换为SBOM许可证，能识别为单个SPDX许可证时填入ID
+func sbomLicense(name, licenseURL string) sbom.License {
+	result := sbom.License{Name: name, URL: licenseURL}
+	if expr, ok := license.Normalize(name, licenseURL); ok {
+		if parsed, err := license.Parse(expr); err == nil {
+			if simple, ok := parsed.(*license.Simple); ok && simple.Exception == "" && !simple.OrLater {
+				result = sbom.License{ID: simple.ID}
+			}
+		}
+	}
+	return result
+}
+
+// artifactExtension 将Maven打包类型或依赖类型转换为制品主文件的扩展名
+func artifactExtension(packaging string) string {
+	switch packaging {
+	case "", "jar", "bundle", "maven-plugin", "eclipse-plugin", "ejb", "test-jar", "java-source", "javadoc":
+		return JAR
+	}
+	return packaging
+}
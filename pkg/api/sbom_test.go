@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/sbom"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSBOMFixtures 在POM测试数据的基础上加入制品JAR文件
+func newSBOMFixtures() map[string]string {
+	files := make(map[string]string, len(pomFixtures)+3)
+	for path, content := range pomFixtures {
+		files[path] = content
+	}
+	files[BuildArtifactPath("org.example", "example-core", "1.0.0", JAR)] = "core-jar"
+	files[BuildArtifactPath("com.google.guava", "guava", "31.1-jre", JAR)] = "guava-jar"
+	files[BuildArtifactPath("com.google.guava", "failureaccess", "1.0.1", JAR)] = "failureaccess-jar"
+	return files
+}
+
+// TestGenerateSBOMFromRefs 测试为制品列表生成CycloneDX JSON
+func TestGenerateSBOMFromRefs(t *testing.T) {
+	server := newTestServer(t, newSBOMFixtures())
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	refs := []response.ArtifactRef{
+		{GroupId: "org.example", ArtifactId: "example-core", Version: "1.0.0"},
+		{GroupId: "com.google.guava", ArtifactId: "guava", Version: "31.1-jre"},
+		{GroupId: "org.example", ArtifactId: "missing", Version: "1.0.0"},
+	}
+
+	doc, err := client.BuildSBOM(ctx, refs)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Components, 3)
+
+	core := doc.Components[0]
+	assert.Equal(t, "pkg:maven/org.example/example-core@1.0.0", core.PURL())
+	assert.Equal(t, []sbom.License{{ID: "Apache-2.0"}}, core.Licenses)
+	expectedSha1, _ := computeChecksum("sha1", []byte("core-jar"))
+	assert.Equal(t, expectedSha1, core.Hashes[sbom.HashSHA1])
+	assert.Len(t, core.Hashes[sbom.HashSHA256], 64)
+	assert.Equal(t, []string{"pkg:maven/com.google.guava/guava@31.1-jre"}, core.DependsOn)
+	assert.Empty(t, core.Error)
+
+	// 无法获取的制品仍然写入文档，并记录错误
+	assert.NotEmpty(t, doc.Components[2].Error)
+	assert.Empty(t, doc.Components[2].Hashes)
+
+	data, err := client.GenerateSBOM(ctx, refs, sbom.FormatCycloneDXJSON)
+	assert.NoError(t, err)
+	var bom map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &bom))
+	assert.Equal(t, "CycloneDX", bom["bomFormat"])
+	assert.Equal(t, "1.5", bom["specVersion"])
+	assert.Len(t, bom["components"], 3)
+
+	_, err = client.GenerateSBOM(ctx, refs, sbom.Format("yaml"))
+	assert.ErrorIs(t, err, sbom.ErrUnsupportedFormat)
+}
+
+// TestGenerateSBOMFromGraph 测试根据依赖图生成SPDX和CycloneDX XML
+func TestGenerateSBOMFromGraph(t *testing.T) {
+	var pomRequests int32
+	handler := newTestServer(t, newSBOMFixtures()).Config.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".pom") {
+			atomic.AddInt32(&pomRequests, 1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	graph, err := client.ResolveDependencyGraph(ctx, "org.example", "example-core", "1.0.0", nil)
+	assert.NoError(t, err)
+
+	// 复用解析依赖图时获取的POM
+	before := atomic.LoadInt32(&pomRequests)
+	doc, err := client.BuildSBOMFromGraph(ctx, graph)
+	assert.NoError(t, err)
+	assert.Equal(t, before, atomic.LoadInt32(&pomRequests))
+	assert.Equal(t, sha1Hex([]byte("core-jar")), doc.Root.Hashes[sbom.HashSHA1])
+	assert.Equal(t, "org.example:example-core:1.0.0", doc.Root.Coordinate())
+	assert.Len(t, doc.Components, 2)
+	assert.Equal(t, []string{"pkg:maven/com.google.guava/failureaccess@1.0.1"}, doc.Components[0].DependsOn)
+	assert.Equal(t, "compile", doc.Components[1].Scope)
+
+	data, err := client.GenerateSBOMFromGraph(ctx, graph, sbom.FormatSPDXJSON)
+	assert.NoError(t, err)
+	var spdx struct {
+		SPDXVersion   string `json:"spdxVersion"`
+		Packages      []map[string]interface{}
+		Relationships []struct {
+			SPDXElementId      string `json:"spdxElementId"`
+			RelationshipType   string `json:"relationshipType"`
+			RelatedSPDXElement string `json:"relatedSpdxElement"`
+		}
+	}
+	assert.NoError(t, json.Unmarshal(data, &spdx))
+	assert.Equal(t, "SPDX-2.3", spdx.SPDXVersion)
+	assert.Len(t, spdx.Packages, 3)
+	assert.Equal(t, "DESCRIBES", spdx.Relationships[0].RelationshipType)
+	assert.Len(t, spdx.Relationships, 3)
+
+	data, err = client.GenerateSBOMForArtifact(ctx, "org.example", "example-core", "1.0.0", sbom.FormatCycloneDXXML, nil)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), xml.Header))
+	assert.Contains(t, string(data), `<bom xmlns="http://cyclonedx.org/schema/bom/1.5"`)
+	assert.Contains(t, string(data), `<dependency ref="pkg:maven/com.google.guava/failureaccess@1.0.1"></dependency>`)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
+)
+
+// ErrUnsupportedSearch 搜索后端不支持该查询条件
+var ErrUnsupportedSearch = errors.New("search not supported by backend")
+
+// SearchBackend 搜索后端，执行SearchRequest描述的查询
+//
+// SearchRequest、SearchRequestJsonDoc和SearchIterator，以及基于它们的SearchByGroupId、SearchBySha1等
+// 所有搜索方法都通过客户端的搜索后端执行查询。后端必须把结果转换为search.maven.org的Solr响应格式
+// （即response.Response，文档字段使用g、a、v、p、timestamp、ec等Solr字段名），
+// 这样上层方法不需要关心查询实际发往哪种服务。
+//
+// 内置的实现有:
+//   - SolrSearchBackend: search.maven.org的/solrsearch/select接口，默认使用
+//   - NexusSearchBackend: Nexus Repository Manager 3的/service/rest/v1/search接口
+type SearchBackend interface {
+	// Search 执行搜索请求，并将Solr格式的响应解析到result中
+	//
+	// 参数:
+	//   - ctx: 上下文对象，用于控制请求的超时和取消
+	//   - client: 发起搜索的客户端，后端可以使用其基础URL、HTTP客户端等配置
+	//   - searchRequest: 搜索请求
+	//   - result: 用于存储解析后响应的结构体指针，通常是*response.Response[Doc]
+	//
+	// 返回:
+	//   - error: 请求失败、解析出错或后端不支持该查询（ErrUnsupportedSearch）时返回错误
+	Search(ctx context.Context, client *Client, searchRequest *request.SearchRequest, result interface{}) error
+}
+
+// SolrSearchBackend search.maven.org的Solr搜索后端，请求发往客户端基础URL下的/solrsearch/select
+type SolrSearchBackend struct{}
+
+var _ SearchBackend = SolrSearchBackend{}
+
+// Search 实现SearchBackend接口
+func (SolrSearchBackend) Search(ctx context.Context, client *Client, searchRequest *request.SearchRequest, result interface{}) error {
+	targetUrl := fmt.Sprintf("%s/solrsearch/select?%s", client.baseURL, searchRequest.ToRequestParams())
+	_, err := client.doRequest(ctx, "GET", targetUrl, nil, result)
+	return err
+}
+
+// WithSearchBackend 设置执行搜索的后端
+//
+// 默认使用SolrSearchBackend，即search.maven.org的搜索接口。设置为NexusSearchBackend后，
+// SearchByGroupId、SearchBySha1、IteratorGAVs等搜索方法会改为查询内部的Nexus仓库，调用方式不变。
+// 下载地址不受影响，仍然需要通过WithRepoBaseURL或WithRepositories设置。
+//
+// 参数:
+//   - backend: 搜索后端，传入nil时恢复为SolrSearchBackend
+//
+// 返回:
+//   - ClientOption: 一个可以应用到NewClient的配置函数
+//
+// 使用示例:
+//
+//	client := api.NewClient(
+//	    api.WithSearchBackend(api.NewNexusSearchBackend("https://nexus.example.com", "maven-public")),
+//	    api.WithRepoBaseURL("https://nexus.example.com/repository/maven-public"),
+//	    api.WithBasicAuth("nexus.example.com", "reader", os.Getenv("NEXUS_PASSWORD")),
+//	)
+//	versions, err := client.SearchBySha1(ctx, "3c9b3a6e1bc7a3b4f8f3e4a1f0b6f2d3c4e5a6b7", 10)
+func WithSearchBackend(backend SearchBackend) ClientOption {
+	return func(c *Client) {
+		if backend == nil {
+			backend = SolrSearchBackend{}
+		}
+		c.searchBackend = backend
+	}
+}
+
+// GetSearchBackend 获取客户端当前使用的搜索后端
+func (c *Client) GetSearchBackend() SearchBackend {
+	return c.searchBackend
+}
+
+// search 使用客户端的搜索后端执行搜索请求
+func (c *Client) search(ctx context.Context, searchRequest *request.SearchRequest, result interface{}) error {
+	backend := c.searchBackend
+	if backend == nil {
+		backend = SolrSearchBackend{}
+	}
+	return backend.Search(ctx, c, searchRequest, result)
+}
@@ -378,6 +378,12 @@ func (x *SearchIterator[Doc]) NextE() (bool, error) {
 			return false, x.err
 		}
 
+		// 更新结果总数，Nexus等搜索后端无法预先给出准确总数，随翻页更新；返回空页表示已经没有更多结果
+		x.total = r.ResponseBody.NumFound
+		if len(r.ResponseBody.Docs) == 0 {
+			x.total = x.current
+		}
+
 		// 更新下一次请求的起始位置
 		x.nextStart = x.nextStart + len(r.ResponseBody.Docs)
 
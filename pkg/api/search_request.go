@@ -2,15 +2,14 @@ package api
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 )
 
 // SearchRequest 执行搜索请求并将结果解析到指定的结构体中
 //
-// 该方法是SDK中搜索功能的底层实现之一，它接收一个搜索请求对象，构建完整的URL，
-// 执行HTTP GET请求，并将返回的JSON响应解析到提供的result结构体中。与SearchRequestJsonDoc不同，
+// 该方法是SDK中搜索功能的底层实现之一，它接收一个搜索请求对象，通过客户端的搜索后端（见WithSearchBackend）
+// 执行查询，并将Solr格式的JSON响应解析到提供的result结构体中。与SearchRequestJsonDoc不同，
 // 此方法需要调用者提供用于接收结果的结构体实例。
 //
 // 参数:
@@ -45,8 +44,5 @@ import (
 //	    fmt.Printf("%s:%s:%s\n", artifact.GroupId, artifact.ArtifactId, artifact.LatestVersion)
 //	}
 func (c *Client) SearchRequest(ctx context.Context, searchRequest *request.SearchRequest, result interface{}) error {
-	targetUrl := fmt.Sprintf("%s/solrsearch/select?%s", c.baseURL, searchRequest.ToRequestParams())
-
-	_, err := c.doRequest(ctx, "GET", targetUrl, nil, result)
-	return err
+	return c.search(ctx, searchRequest, result)
 }
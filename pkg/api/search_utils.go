@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
@@ -10,7 +9,7 @@ import (
 
 // SearchRequestJsonDoc 执行搜索请求并将JSON响应解析为指定类型的结构体
 //
-// 该函数是SDK中所有搜索操作的核心实现，它接收一个搜索请求，构建URL，执行HTTP GET请求，
+// 该函数是SDK中所有搜索操作的核心实现，它接收一个搜索请求，通过客户端的搜索后端（见WithSearchBackend）执行查询，
 // 并将返回的JSON响应解析为泛型类型Response[Doc]。函数利用Go泛型特性，可以适应不同的
 // 文档类型（Artifact、Version等），使API调用更加类型安全。
 //
@@ -45,10 +44,12 @@ import (
 //	    fmt.Printf("%s:%s:%s\n", artifact.GroupId, artifact.ArtifactId, artifact.LatestVersion)
 //	}
 func SearchRequestJsonDoc[Doc any](c *Client, ctx context.Context, searchRequest *request.SearchRequest) (*response.Response[Doc], error) {
-	targetUrl := fmt.Sprintf("%s/solrsearch/select?%s", c.baseURL, searchRequest.ToRequestParams())
+	if c == nil {
+		c = NewClient()
+	}
 
 	var result response.Response[Doc]
-	_, err := c.doRequest(ctx, "GET", targetUrl, nil, &result)
+	err := c.search(ctx, searchRequest, &result)
 	if err != nil {
 		return nil, err
 	}
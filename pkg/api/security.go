@@ -7,6 +7,7 @@ import (
 
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	mavenversion "github.com/scagogogo/sonatype-central-sdk/pkg/version"
 )
 
 // SecuritySeverity 安全严重性级别
@@ -23,7 +24,9 @@ const (
 // GetSecurityRating 获取制品的安全评分
 //
 // 此方法提供了一种获取指定制品版本安全评分的方式，返回包含安全评级和相关详情的安全评分信息。
-// 安全评分基于该制品版本已知的漏洞数量和严重性来计算，可用于评估使用该制品的潜在风险。
+// 安全评分基于客户端漏洞数据库中影响该版本的漏洞数量和严重性来计算，可用于评估使用该制品的潜在风险。
+// Score为0-10的安全评分，越高越安全，没有已知漏洞时为10；RiskScore为漏洞中最高的CVSS评分，
+// 越高风险越大，没有已知漏洞时为0，两者之和为10。
 //
 // 参数:
 //   - ctx: 上下文，用于控制请求的生命周期
@@ -32,34 +35,37 @@ const (
 //   - version: 制品的版本号
 //
 // 返回:
-//   - *response.SecurityRating: 包含安全评级、评分和相关详情的安全评分信息
-//   - error: 如果获取安全评分失败，返回错误信息
+//   - *response.SecurityRating: 包含漏洞数量、最高严重性、评分和安全公告链接的安全评分信息
+//   - error: 客户端没有配置漏洞数据库时返回ErrNoVulnerabilityDB
 //
 // 例子:
 //
-//	// 初始化客户端
-//	client := sonatype.NewClient("https://repo1.maven.org/maven2")
+//	db, err := api.LoadVulnerabilityDB("/var/lib/osv/Maven-all.zip")
+//	if err != nil {
+//	    log.Fatalf("加载漏洞数据库失败: %v", err)
+//	}
+//	client := api.NewClient(api.WithVulnerabilityDB(db))
 //
 //	// 获取指定制品版本的安全评分
-//	rating, err := client.GetSecurityRating(ctx, "org.apache.commons", "commons-lang3", "3.12.0")
+//	rating, err := client.GetSecurityRating(ctx, "org.apache.commons", "commons-text", "1.9")
 //	if err != nil {
 //	    log.Fatalf("获取安全评分失败: %v", err)
 //	}
 //
 //	// 使用安全评分信息
-//	fmt.Printf("安全评级: %s\n", rating.Level)
-//	fmt.Printf("安全评分: %.2f\n", rating.Score)
-//	if rating.Score > 7.0 {
+//	fmt.Printf("最高严重性: %s\n", rating.Severity)
+//	fmt.Printf("安全评分: %.1f, 风险评分: %.1f\n", rating.Score, rating.RiskScore)
+//	if rating.RiskScore >= 7.0 {
 //	    fmt.Println("警告: 该制品存在高风险漏洞，建议升级或更换替代品")
 //	}
 func (c *Client) GetSecurityRating(ctx context.Context, groupId, artifactId, version string) (*response.SecurityRating, error) {
-	targetUrl := fmt.Sprintf("%s/api/security/rating/%s/%s/%s", c.baseURL, groupId, artifactId, version)
-	var securityRating response.SecurityRating
-	_, err := c.doRequest(ctx, "GET", targetUrl, nil, &securityRating)
-	if err != nil {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	return &securityRating, nil
+	if c.vulnerabilityDB == nil {
+		return nil, ErrNoVulnerabilityDB
+	}
+	return c.vulnerabilityDB.Rating(groupId, artifactId, version), nil
 }
 
 // SearchVulnerableArtifacts 搜索Maven中央仓库中具有已知漏洞的构件。
@@ -115,17 +121,26 @@ func (c *Client) SearchVulnerableArtifacts(ctx context.Context, query *request.Q
 }
 
 // GetVulnerabilityDetails 获取特定构件版本的漏洞详情
+//
+// 漏洞信息来自客户端的漏洞数据库，按严重性从高到低排列；没有配置漏洞数据库时返回ErrNoVulnerabilityDB。
 func (c *Client) GetVulnerabilityDetails(ctx context.Context, groupId, artifactId, version string) (*response.VulnerabilityDetails, error) {
-	targetUrl := fmt.Sprintf("%s/api/security/vulnerabilities/%s/%s/%s", c.baseURL, groupId, artifactId, version)
-	var details response.VulnerabilityDetails
-	_, err := c.doRequest(ctx, "GET", targetUrl, nil, &details)
-	if err != nil {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	return &details, nil
+	if c.vulnerabilityDB == nil {
+		return nil, ErrNoVulnerabilityDB
+	}
+	return &response.VulnerabilityDetails{
+		GroupId:         groupId,
+		ArtifactId:      artifactId,
+		Version:         version,
+		Vulnerabilities: c.vulnerabilityDB.Vulnerabilities(groupId, artifactId, version),
+	}, nil
 }
 
 // CheckCVEImpact 检查特定构件是否受到某个CVE编号漏洞的影响
+//
+// cveId也可以是漏洞数据库中的其他漏洞ID，如GHSA编号。
 func (c *Client) CheckCVEImpact(ctx context.Context, cveId, groupId, artifactId, version string) (bool, *response.Vulnerability, error) {
 	details, err := c.GetVulnerabilityDetails(ctx, groupId, artifactId, version)
 	if err != nil {
@@ -133,7 +148,7 @@ func (c *Client) CheckCVEImpact(ctx context.Context, cveId, groupId, artifactId,
 	}
 
 	for _, vuln := range details.Vulnerabilities {
-		if strings.EqualFold(vuln.CVE, cveId) {
+		if strings.EqualFold(vuln.CVE, cveId) || strings.EqualFold(vuln.ID, cveId) {
 			return true, vuln, nil
 		}
 	}
@@ -145,7 +160,7 @@ func (c *Client) CheckCVEImpact(ctx context.Context, cveId, groupId, artifactId,
 func (c *Client) FindArtifactsByCVE(ctx context.Context, cveId string, limit int) ([]*response.Artifact, error) {
 	// 构建请求
 	vulnQuery := request.NewQuery().
-		SetCustomQuery(fmt.Sprintf("cve:%s", cveId))
+		SetExpr(request.Field("cve", cveId))
 
 	vulnRequest := request.NewSearchRequest().
 		SetQuery(vulnQuery).
@@ -185,19 +200,23 @@ func (c *Client) CompareVersionSecurity(ctx context.Context, groupId, artifactId
 		ScoreDifference: 0,
 	}
 
-	// 计算分数差异（较高分数表示较高风险）
-	if rating1.Score > rating2.Score {
+	// 计算分数差异（较高分数表示更安全）
+	if rating2.Score > rating1.Score {
 		comparison.SaferVersion = version2
-		comparison.ScoreDifference = rating1.Score - rating2.Score
-	} else if rating2.Score > rating1.Score {
-		comparison.SaferVersion = version1
 		comparison.ScoreDifference = rating2.Score - rating1.Score
+	} else if rating1.Score > rating2.Score {
+		comparison.SaferVersion = version1
+		comparison.ScoreDifference = rating1.Score - rating2.Score
 	}
 
 	return comparison, nil
 }
 
 // GetRecommendedSecureVersion 获取修复特定漏洞的推荐版本
+//
+// 按照Maven版本比较规则从低到高检查比当前版本更新的正式版本，返回第一个没有已知漏洞的版本；
+// 没有这样的正式版本时，再按同样的顺序检查alpha、beta、milestone、rc等预发布版本，快照版本不会被推荐。
+// 无法从仓库获取版本列表时，改为在漏洞数据库记录的修复版本中查找。
 func (c *Client) GetRecommendedSecureVersion(ctx context.Context, groupId, artifactId, currentVersion string) (string, error) {
 	// 获取当前版本的漏洞信息
 	vulnDetails, err := c.GetVulnerabilityDetails(ctx, groupId, artifactId, currentVersion)
@@ -210,43 +229,53 @@ func (c *Client) GetRecommendedSecureVersion(ctx context.Context, groupId, artif
 		return currentVersion, nil
 	}
 
-	// 获取所有版本
-	versions, err := c.ListVersions(ctx, groupId, artifactId, 100)
+	// 获取所有版本，无法获取时使用漏洞数据库中记录的修复版本作为候选
+	var candidates []string
+	versions, err := c.ListVersions(ctx, groupId, artifactId, 0)
 	if err != nil {
-		return "", err
-	}
-
-	// 找到比当前版本更新的版本
-	var newerVersions []string
-	foundCurrent := false
-	for i := len(versions) - 1; i >= 0; i-- {
-		v := versions[i].Version
-		if v == currentVersion {
-			foundCurrent = true
-			continue
+		if ctx.Err() != nil {
+			return "", err
 		}
-		if foundCurrent {
-			newerVersions = append(newerVersions, v)
+		candidates = c.vulnerabilityDB.fixedVersions(groupId, artifactId)
+		if len(candidates) == 0 {
+			return "", err
 		}
 	}
-
-	// 如果没有更新的版本，则返回当前版本
-	if len(newerVersions) == 0 {
-		return currentVersion, nil
+	for _, v := range versions {
+		candidates = append(candidates, v.Version)
 	}
 
-	// 检查每个更新的版本，找到第一个没有漏洞的版本
-	for _, v := range newerVersions {
-		details, err := c.GetVulnerabilityDetails(ctx, groupId, artifactId, v)
-		if err != nil {
+	// 按照Maven版本比较规则找到比当前版本更新的非快照版本，正式版本和预发布版本分别从低到高排列
+	current := mavenversion.Parse(currentVersion)
+	var releases, preReleases []string
+	for _, v := range candidates {
+		parsed := mavenversion.Parse(v)
+		if parsed.IsSnapshot() || parsed.Compare(current) <= 0 {
 			continue
 		}
-		if len(details.Vulnerabilities) == 0 {
-			return v, nil
+		if parsed.IsPreRelease() {
+			preReleases = append(preReleases, v)
+		} else {
+			releases = append(releases, v)
+		}
+	}
+	mavenversion.Sort(releases)
+	mavenversion.Sort(preReleases)
+
+	// 优先推荐没有漏洞的正式版本，没有可用的正式版本时才考虑alpha、beta、rc等预发布版本
+	for _, newerVersions := range [][]string{releases, preReleases} {
+		for _, v := range newerVersions {
+			details, err := c.GetVulnerabilityDetails(ctx, groupId, artifactId, v)
+			if err != nil {
+				continue
+			}
+			if len(details.Vulnerabilities) == 0 {
+				return v, nil
+			}
 		}
 	}
 
-	// 如果所有更新的版本都有漏洞，则返回当前版本
+	// 如果没有更新的版本或所有更新的版本都有漏洞，则返回当前版本
 	return currentVersion, nil
 }
 
@@ -318,10 +347,10 @@ func (c *Client) GetVulnerabilityTimeline(ctx context.Context, groupId, artifact
 
 		// 与前一个版本比较
 		if previousEntry != nil {
-			if entry.Score < previousScore {
+			if entry.Score > previousScore {
 				entry.Change = "IMPROVED"
 				entry.ChangeDetails = fmt.Sprintf("安全评分从 %.2f 提升到 %.2f", previousScore, entry.Score)
-			} else if entry.Score > previousScore {
+			} else if entry.Score < previousScore {
 				entry.Change = "DEGRADED"
 				entry.ChangeDetails = fmt.Sprintf("安全评分从 %.2f 降低到 %.2f", previousScore, entry.Score)
 			}
@@ -412,13 +441,14 @@ func (c *Client) FindSimilarVulnerableArtifacts(ctx context.Context, groupId, ar
 	}
 
 	// 构建查询，查找具有相同CVE的其他组件
-	cveQuery := strings.Join(cveList, " OR ")
-	query := request.NewQuery().
-		SetCustomQuery("cve:(" + cveQuery + ")")
-
 	// 排除当前组件
-	excludeFilter := fmt.Sprintf("(g:%s AND a:%s)", groupId, artifactId)
-	query.SetCustomQuery(query.ToRequestParamValue() + " AND -" + excludeFilter)
+	query := request.NewQuery().SetExpr(request.And(
+		request.Field("cve", cveList...),
+		request.Not(request.And(
+			request.Field(request.FieldGroupId, groupId),
+			request.Field(request.FieldArtifactId, artifactId),
+		)),
+	))
 
 	searchRequest := request.NewSearchRequest().
 		SetQuery(query).
@@ -267,7 +267,7 @@ func TestSecurityDataStructures(t *testing.T) {
 		assert.NotEmpty(t, rating.Severity)
 
 		// 检查评分和严重性的一致性
-		if rating.Score >= 7.0 {
+		if rating.RiskScore >= 7.0 {
 			assert.Contains(t, []string{"HIGH", "CRITICAL"}, rating.Severity)
 		}
 	}
@@ -296,7 +296,8 @@ func TestSecurityEdgeCases(t *testing.T) {
 		assert.Error(t, err) // 期望出现错误
 	} else {
 		t.Log("预期会有错误，但API返回了结果")
-		assert.Equal(t, 0.0, rating.Score)
+		assert.Equal(t, 10.0, rating.Score)
+		assert.Equal(t, 0.0, rating.RiskScore)
 		assert.Equal(t, "NONE", rating.Severity)
 	}
 
@@ -372,7 +373,7 @@ func TestSecurityEdgeCases(t *testing.T) {
 			// 检查log4j-core的扫描结果
 			if result.GroupId == "org.apache.logging.log4j" && result.ArtifactId == "log4j-core" {
 				assert.NotNil(t, result.SecurityRating)
-				assert.Greater(t, result.SecurityRating.Score, 7.0)
+				assert.Greater(t, result.SecurityRating.RiskScore, 7.0)
 				assert.Contains(t, []string{"CRITICAL", "HIGH"}, result.SecurityRating.Severity)
 				assert.GreaterOrEqual(t, result.SecurityRating.VulnCount, 1)
 
@@ -203,9 +203,9 @@ func (c *Client) SearchBySha1Prefix(ctx context.Context, sha1Prefix string, limi
 		return c.IteratorBySha1Prefix(ctx, sha1Prefix).ToSlice()
 	}
 
-	// 使用自定义查询构建SHA1前缀搜索
-	customQuery := "1:" + sha1Prefix + "*"
-	search := request.NewSearchRequest().SetQuery(request.NewQuery().SetCustomQuery(customQuery)).SetLimit(limit)
+	// 构建SHA1前缀搜索
+	prefixQuery := request.Wildcard(request.FieldSha1, sha1Prefix+"*")
+	search := request.NewSearchRequest().SetQuery(request.NewQuery().SetExpr(prefixQuery)).SetLimit(limit)
 
 	result, err := SearchRequestJsonDoc[*response.Version](c, ctx, search)
 	if err != nil {
@@ -230,8 +230,8 @@ func (c *Client) IteratorBySha1Prefix(ctx context.Context, sha1Prefix string) *S
 		return c.IteratorBySha1(ctx, sha1Prefix)
 	}
 
-	// 使用自定义查询构建SHA1前缀搜索
-	customQuery := "1:" + sha1Prefix + "*"
-	search := request.NewSearchRequest().SetQuery(request.NewQuery().SetCustomQuery(customQuery))
+	// 构建SHA1前缀搜索
+	prefixQuery := request.Wildcard(request.FieldSha1, sha1Prefix+"*")
+	search := request.NewSearchRequest().SetQuery(request.NewQuery().SetExpr(prefixQuery))
 	return NewSearchIterator[*response.Version](search).WithClient(c)
 }
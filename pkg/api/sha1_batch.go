@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+const (
+	// sha1BatchMaxURLLength 批量查询时单个请求URL的最大长度，留有余量以兼容常见代理的限制
+	sha1BatchMaxURLLength = 4000
+
+	// sha1BatchConcurrency 批量查询时同时进行的请求数
+	sha1BatchConcurrency = 4
+
+	// sha1BatchRows 批量查询时每页返回的结果数
+	sha1BatchRows = 200
+
+	// sha1BatchFields 批量查询时请求返回的字段，"1"为SHA-1字段，服务器返回时可以直接将结果对应到哈希
+	sha1BatchFields = "id,g,a,v,p,timestamp,ec,tags,1"
+)
+
+// sha1HexPattern 40位十六进制的SHA-1
+var sha1HexPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Sha1BatchError 批量SHA-1查询中部分哈希查询失败
+//
+// SearchBySha1Batch在部分哈希查询失败时返回该错误，同时返回其余哈希的查询结果。
+type Sha1BatchError struct {
+	// Errors 查询失败的哈希及其错误
+	Errors map[string]error
+}
+
+// Error 实现error接口
+func (e *Sha1BatchError) Error() string {
+	hashes := make([]string, 0, len(e.Errors))
+	for hash := range e.Errors {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return fmt.Sprintf("%d个SHA-1查询失败，其中%s: %v", len(hashes), hashes[0], e.Errors[hashes[0]])
+}
+
+// sha1Doc 批量查询返回的文档，服务器返回SHA-1字段时Sha1不为空
+type sha1Doc struct {
+	response.Version
+	Sha1 string `json:"1"`
+}
+
+// SearchBySha1Batch 批量查询多个SHA-1对应的构件版本
+//
+// 多个哈希会被合并为"1:(a OR b OR ...)"形式的查询，在URL长度限制内尽可能多地放入同一个请求，
+// 多个请求并发进行。查询结果中没有SHA-1字段时无法直接判断每个版本对应哪个哈希，
+// 此时对有结果的批次二分拆分后继续查询，没有结果的批次中的哈希直接判定为未找到，
+// 因此在大多数哈希都不在中央仓库中的场景下请求数远少于逐个查询。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - hashes: SHA-1哈希列表，大小写不敏感，重复的哈希只查询一次
+//
+// 返回:
+//   - map[string][]*response.Version: 以小写哈希为键的查询结果，未找到的哈希对应空切片
+//   - error: 部分哈希查询失败（或哈希格式无效）时返回*Sha1BatchError，结果中不包含这些哈希
+//
+// 使用示例:
+//
+//	results, err := client.SearchBySha1Batch(ctx, hashes)
+//	var batchErr *api.Sha1BatchError
+//	if err != nil && !errors.As(err, &batchErr) {
+//	    log.Fatal(err)
+//	}
+//	for hash, versions := range results {
+//	    for _, v := range versions {
+//	        fmt.Printf("%s => %s:%s:%s\n", hash, v.GroupId, v.ArtifactId, v.Version)
+//	    }
+//	}
+//	if batchErr != nil {
+//	    for hash, err := range batchErr.Errors {
+//	        fmt.Printf("%s 查询失败: %v\n", hash, err)
+//	    }
+//	}
+func (c *Client) SearchBySha1Batch(ctx context.Context, hashes []string) (map[string][]*response.Version, error) {
+	results := make(map[string][]*response.Version, len(hashes))
+	errs := make(map[string]error)
+
+	seen := make(map[string]bool, len(hashes))
+	unique := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		normalized := strings.ToLower(strings.TrimSpace(hash))
+		if !sha1HexPattern.MatchString(normalized) {
+			errs[hash] = fmt.Errorf("无效的SHA-1: %q", hash)
+			continue
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			unique = append(unique, normalized)
+		}
+	}
+	sort.Strings(unique)
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, sha1BatchConcurrency)
+	)
+	for _, batch := range c.packSha1Batches(unique) {
+		wg.Add(1)
+		go func(batch []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				for _, hash := range batch {
+					errs[hash] = ctx.Err()
+				}
+				mu.Unlock()
+				return
+			}
+
+			batchResults, batchErrs := c.resolveSha1Batch(ctx, batch, false)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for hash, versions := range batchResults {
+				results[hash] = versions
+			}
+			for hash, err := range batchErrs {
+				errs[hash] = err
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return results, &Sha1BatchError{Errors: errs}
+	}
+	return results, nil
+}
+
+// packSha1Batches 按URL长度限制将哈希分组
+func (c *Client) packSha1Batches(hashes []string) [][]string {
+	var batches [][]string
+	var current []string
+	for _, hash := range hashes {
+		if len(current) > 0 && len(c.sha1BatchURL(append(current, hash))) > sha1BatchMaxURLLength {
+			batches = append(batches, current)
+			current = nil
+		}
+		current = append(current, hash)
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// sha1BatchRequest 构建查询一组哈希的搜索请求
+func sha1BatchRequest(hashes []string, start int) *request.SearchRequest {
+	return request.NewSearchRequest().
+		SetQuery(request.NewQuery().SetExpr(request.Field(request.FieldSha1, hashes...))).
+		SetStart(start).
+		SetLimit(sha1BatchRows).
+		AddCustomParam("fl", sha1BatchFields)
+}
+
+// sha1BatchURL 返回查询一组哈希的请求URL，用于计算长度
+func (c *Client) sha1BatchURL(hashes []string) string {
+	return fmt.Sprintf("%s/solrsearch/select?%s", c.baseURL, sha1BatchRequest(hashes, 0).ToRequestParams())
+}
+
+// resolveSha1Batch 查询一组哈希并将结果对应到每个哈希
+//
+// knownHit为true表示已经确定这组哈希中至少有一个有结果，此时直接拆分而不再查询整组。
+func (c *Client) resolveSha1Batch(ctx context.Context, hashes []string, knownHit bool) (map[string][]*response.Version, map[string]error) {
+	results := make(map[string][]*response.Version, len(hashes))
+	errs := make(map[string]error)
+
+	if !knownHit || len(hashes) == 1 {
+		docs, err := c.querySha1s(ctx, hashes)
+		if err != nil {
+			for _, hash := range hashes {
+				errs[hash] = err
+			}
+			return results, errs
+		}
+
+		if len(hashes) == 1 {
+			results[hashes[0]] = sha1DocVersions(docs)
+			return results, errs
+		}
+		if len(docs) == 0 {
+			for _, hash := range hashes {
+				results[hash] = []*response.Version{}
+			}
+			return results, errs
+		}
+		if grouped, ok := groupSha1Docs(hashes, docs); ok {
+			return grouped, errs
+		}
+	}
+
+	// 结果中没有SHA-1字段，二分拆分后继续查询；前一半没有结果时后一半一定有结果
+	middle := len(hashes) / 2
+	left, leftErrs := c.resolveSha1Batch(ctx, hashes[:middle], false)
+	leftHit := len(leftErrs) > 0
+	for _, versions := range left {
+		if len(versions) > 0 {
+			leftHit = true
+		}
+	}
+	right, rightErrs := c.resolveSha1Batch(ctx, hashes[middle:], !leftHit)
+
+	for _, part := range []map[string][]*response.Version{left, right} {
+		for hash, versions := range part {
+			results[hash] = versions
+		}
+	}
+	for _, part := range []map[string]error{leftErrs, rightErrs} {
+		for hash, err := range part {
+			errs[hash] = err
+		}
+	}
+	return results, errs
+}
+
+// querySha1s 查询一组哈希的全部结果，结果超过一页时继续翻页
+func (c *Client) querySha1s(ctx context.Context, hashes []string) ([]*sha1Doc, error) {
+	var docs []*sha1Doc
+	for start := 0; ; {
+		result, err := SearchRequestJsonDoc[*sha1Doc](c, ctx, sha1BatchRequest(hashes, start))
+		if err != nil {
+			return nil, err
+		}
+		if result == nil || result.ResponseBody == nil {
+			return nil, fmt.Errorf("empty response body")
+		}
+		docs = append(docs, result.ResponseBody.Docs...)
+		start += len(result.ResponseBody.Docs)
+		if len(result.ResponseBody.Docs) == 0 || start >= result.ResponseBody.NumFound {
+			return docs, nil
+		}
+	}
+}
+
+// groupSha1Docs 按结果中的SHA-1字段分组，有结果缺少该字段时返回false
+func groupSha1Docs(hashes []string, docs []*sha1Doc) (map[string][]*response.Version, bool) {
+	results := make(map[string][]*response.Version, len(hashes))
+	for _, hash := range hashes {
+		results[hash] = []*response.Version{}
+	}
+	for _, doc := range docs {
+		hash := strings.ToLower(doc.Sha1)
+		if _, ok := results[hash]; !ok {
+			return nil, false
+		}
+		version := doc.Version
+		results[hash] = append(results[hash], &version)
+	}
+	return results, true
+}
+
+// sha1DocVersions 将查询结果转换为版本列表
+func sha1DocVersions(docs []*sha1Doc) []*response.Version {
+	versions := make([]*response.Version, 0, len(docs))
+	for _, doc := range docs {
+		version := doc.Version
+		versions = append(versions, &version)
+	}
+	return versions
+}
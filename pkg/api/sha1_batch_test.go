@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSha1 返回字符串的SHA-1
+func testSha1(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSha1BatchServer 创建模拟SHA-1查询的测试服务器
+//
+// known中的哈希有查询结果，withField为true时结果中带有SHA-1字段，查询中包含failing的请求返回500。
+func newSha1BatchServer(t *testing.T, known map[string]int, withField bool, failing string) (*httptest.Server, func() []string) {
+	hashPattern := regexp.MustCompile(`[0-9a-f]{40}`)
+	var mu sync.Mutex
+	var queries []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		mu.Lock()
+		queries = append(queries, r.URL.String())
+		mu.Unlock()
+
+		if failing != "" && strings.Contains(q, failing) {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		var docs []map[string]interface{}
+		for _, hash := range hashPattern.FindAllString(q, -1) {
+			for i := 0; i < known[hash]; i++ {
+				doc := map[string]interface{}{
+					"id": fmt.Sprintf("org.example:%s:%d", hash[:8], i),
+					"g":  "org.example",
+					"a":  hash[:8],
+					"v":  strconv.Itoa(i),
+					"p":  "jar",
+				}
+				if withField {
+					doc["1"] = hash
+				}
+				docs = append(docs, doc)
+			}
+		}
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		rows, _ := strconv.Atoi(r.URL.Query().Get("rows"))
+		numFound := len(docs)
+		if start > len(docs) {
+			start = len(docs)
+		}
+		docs = docs[start:]
+		if len(docs) > rows {
+			docs = docs[:rows]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": map[string]interface{}{"numFound": numFound, "start": start, "docs": docs},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+// TestSearchBySha1Batch 测试批量SHA-1查询
+func TestSearchBySha1Batch(t *testing.T) {
+	var hashes []string
+	for i := 0; i < 200; i++ {
+		hashes = append(hashes, testSha1(fmt.Sprintf("file-%d", i)))
+	}
+	known := map[string]int{hashes[3]: 1, hashes[42]: 2, hashes[150]: sha1BatchRows + 5}
+
+	for _, withField := range []bool{true, false} {
+		t.Run(fmt.Sprintf("withField=%v", withField), func(t *testing.T) {
+			server, queries := newSha1BatchServer(t, known, withField, "")
+			client := newTestClient(server.URL)
+
+			input := append([]string{strings.ToUpper(hashes[3]), "not-a-hash"}, hashes...)
+			results, err := client.SearchBySha1Batch(context.Background(), input)
+
+			var batchErr *Sha1BatchError
+			assert.True(t, errors.As(err, &batchErr))
+			assert.Len(t, batchErr.Errors, 1)
+			assert.Error(t, batchErr.Errors["not-a-hash"])
+
+			assert.Len(t, results, len(hashes))
+			for i, hash := range hashes {
+				assert.Len(t, results[hash], known[hash], "hash %d", i)
+			}
+			assert.Equal(t, "org.example", results[hashes[42]][1].GroupId)
+
+			for _, query := range queries() {
+				assert.LessOrEqual(t, len(server.URL+query), sha1BatchMaxURLLength)
+			}
+			if withField {
+				// 200个哈希分为3个批次，其中一个批次结果超过一页
+				assert.Len(t, queries(), 4)
+			} else {
+				assert.Less(t, len(queries()), 40, "结果无法直接对应到哈希时二分查询")
+			}
+		})
+	}
+}
+
+// TestSearchBySha1BatchPartialFailure 测试部分批次失败
+func TestSearchBySha1BatchPartialFailure(t *testing.T) {
+	var hashes []string
+	for i := 0; i < 200; i++ {
+		hashes = append(hashes, testSha1(fmt.Sprintf("file-%d", i)))
+	}
+	server, _ := newSha1BatchServer(t, map[string]int{hashes[0]: 1}, true, hashes[1])
+	client := newTestClient(server.URL)
+
+	results, err := client.SearchBySha1Batch(context.Background(), hashes)
+	var batchErr *Sha1BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	assert.NotEmpty(t, batchErr.Errors)
+	assert.Error(t, batchErr.Errors[hashes[1]])
+	assert.Less(t, len(batchErr.Errors), len(hashes), "只有失败批次中的哈希报告错误")
+	assert.Equal(t, len(hashes), len(results)+len(batchErr.Errors))
+	for hash := range batchErr.Errors {
+		assert.NotContains(t, results, hash)
+	}
+	if _, failed := batchErr.Errors[hashes[0]]; !failed {
+		assert.Len(t, results[hashes[0]], 1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.SearchBySha1Batch(ctx, hashes)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,387 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// armoredPublicKeyHeader ASCII Armor格式公钥块的起始标记
+const armoredPublicKeyHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
+// Keyring OpenPGP公钥环
+//
+// 用于验证制品的.asc签名。公钥环通常由项目发布者公开的KEYS文件或单独导出的公钥文件构建，
+// 例如Apache项目在https://downloads.apache.org/<project>/KEYS中发布所有发布者的公钥。
+type Keyring struct {
+	entities openpgp.EntityList
+}
+
+// LoadKeyring 从磁盘上的公钥文件加载公钥环
+//
+// 支持ASCII Armor格式（gpg --armor --export的输出）和二进制格式的公钥文件，
+// 单个文件中可以包含多个Armor块（如Apache的KEYS文件，块之间的说明文字会被忽略）。
+// 传入目录时会加载目录下的所有文件。
+//
+// 参数:
+//   - paths: 公钥文件或目录路径
+//
+// 返回:
+//   - *Keyring: 包含所有公钥的公钥环
+//   - error: 文件无法读取或不包含任何有效公钥时返回错误
+//
+// 使用示例:
+//
+//	keyring, err := api.LoadKeyring("/etc/maven/trusted-keys/commons.asc", "/etc/maven/trusted-keys/KEYS")
+//	if err != nil {
+//	    log.Fatalf("加载公钥失败: %v", err)
+//	}
+//	fmt.Println(keyring.Fingerprints())
+func LoadKeyring(paths ...string) (*Keyring, error) {
+	keyring := &Keyring{}
+	for _, p := range paths {
+		files := []string{p}
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			entries, err := os.ReadDir(p)
+			if err != nil {
+				return nil, fmt.Errorf("读取公钥目录失败: %w", err)
+			}
+			files = files[:0]
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(p, entry.Name()))
+				}
+			}
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("读取公钥文件失败: %w", err)
+			}
+			entities, err := readKeys(data)
+			if err != nil {
+				return nil, fmt.Errorf("解析公钥文件%s失败: %w", file, err)
+			}
+			keyring.entities = append(keyring.entities, entities...)
+		}
+	}
+
+	if len(keyring.entities) == 0 {
+		return nil, errors.New("公钥环中没有任何公钥")
+	}
+	return keyring, nil
+}
+
+// ParseKeyring 从内存中的公钥数据构建公钥环
+//
+// 参数:
+//   - data: ASCII Armor或二进制格式的公钥数据
+//
+// 返回:
+//   - *Keyring: 公钥环
+//   - error: 数据中不包含任何有效公钥时返回错误
+func ParseKeyring(data []byte) (*Keyring, error) {
+	entities, err := readKeys(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		return nil, errors.New("公钥环中没有任何公钥")
+	}
+	return &Keyring{entities: entities}, nil
+}
+
+// readKeys 解析公钥数据，支持多个Armor块和二进制格式
+func readKeys(data []byte) (openpgp.EntityList, error) {
+	text := string(data)
+	if !strings.Contains(text, armoredPublicKeyHeader) {
+		return openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+
+	var entities openpgp.EntityList
+	blocks := strings.Split(text, armoredPublicKeyHeader)
+	for _, block := range blocks[1:] {
+		parsed, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredPublicKeyHeader + block))
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, parsed...)
+	}
+	return entities, nil
+}
+
+// Len 返回公钥环中公钥（主密钥）的数量
+func (k *Keyring) Len() int {
+	return len(k.entities)
+}
+
+// Fingerprints 返回公钥环中所有主密钥的指纹（大写十六进制）
+func (k *Keyring) Fingerprints() []string {
+	result := make([]string, 0, len(k.entities))
+	for _, entity := range k.entities {
+		result = append(result, formatFingerprint(entity.PrimaryKey.Fingerprint))
+	}
+	return result
+}
+
+// SignatureStatus 签名验证结果
+type SignatureStatus string
+
+const (
+	// SignatureValid 签名有效，且签名者在公钥环中
+	SignatureValid SignatureStatus = "valid"
+
+	// SignatureInvalid 签名与文件内容不匹配或签名格式错误
+	SignatureInvalid SignatureStatus = "invalid"
+
+	// SignatureUnknownKey 签名者的公钥不在公钥环中
+	SignatureUnknownKey SignatureStatus = "unknown_key"
+
+	// SignatureExpired 签名或签名者的公钥已过期
+	SignatureExpired SignatureStatus = "expired"
+
+	// SignatureRevoked 签名者的公钥已被吊销
+	SignatureRevoked SignatureStatus = "revoked"
+
+	// SignatureMissing 远程仓库中没有对应的.asc签名文件
+	SignatureMissing SignatureStatus = "missing"
+)
+
+// SignatureVerification 单个文件的签名验证结果
+//
+// 字段说明:
+//   - Path: 文件在仓库中的相对路径
+//   - Status: 验证结果
+//   - KeyId: 签名使用的密钥ID（大写十六进制），签名文件存在时总是可用
+//   - Fingerprint: 签名者主密钥的指纹，签名者不在公钥环中时为签名中携带的颁发者指纹（可能为空）
+//   - Signer: 签名者的主用户ID，如"John Doe <john@example.com>"
+//   - SignedAt: 签名时间
+//   - Error: 验证失败的具体原因，验证成功时为nil
+type SignatureVerification struct {
+	Path        string
+	Status      SignatureStatus
+	KeyId       string
+	Fingerprint string
+	Signer      string
+	SignedAt    time.Time
+	Error       error
+}
+
+// Valid 判断签名是否有效
+func (v *SignatureVerification) Valid() bool {
+	return v != nil && v.Status == SignatureValid
+}
+
+// SignatureError 在签名验证策略为Fail时，下载因签名验证失败而返回的错误
+type SignatureError struct {
+	Verification *SignatureVerification
+}
+
+// Error 实现error接口
+func (e *SignatureError) Error() string {
+	v := e.Verification
+	msg := fmt.Sprintf("签名验证失败 %s: %s", v.Path, v.Status)
+	if v.KeyId != "" {
+		msg += " (密钥 " + v.KeyId + ")"
+	}
+	if v.Error != nil {
+		msg += ": " + v.Error.Error()
+	}
+	return msg
+}
+
+// Unwrap 返回底层的验证错误
+func (e *SignatureError) Unwrap() error {
+	return e.Verification.Error
+}
+
+// VerifySignature 使用公钥环验证文件的.asc签名
+//
+// 该方法从仓库获取path对应的.asc分离签名（Maven Central要求每个制品都附带签名），
+// 并使用调用方提供的公钥环验证data。验证结果中包含签名者指纹和有效性；签名不匹配、
+// 签名者未知等情况通过结果中的Status表示，而不是返回错误。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - path: 文件在仓库中的相对路径，如"org/apache/commons/commons-lang3/3.12.0/commons-lang3-3.12.0.jar"
+//   - data: 文件内容
+//   - keyring: 受信任的公钥环
+//
+// 返回:
+//   - *SignatureVerification: 验证结果
+//   - error: 获取签名文件时发生网络错误等非验证类错误时返回错误
+//
+// 使用示例:
+//
+//	keyring, _ := api.LoadKeyring("commons-KEYS")
+//	client := api.NewClient()
+//
+//	jarPath := api.BuildArtifactPath("org.apache.commons", "commons-lang3", "3.12.0", "jar")
+//	jar, _ := client.Download(ctx, jarPath)
+//
+//	result, err := client.VerifySignature(ctx, jarPath, jar, keyring)
+//	if err != nil {
+//	    log.Fatalf("获取签名失败: %v", err)
+//	}
+//	if !result.Valid() {
+//	    log.Fatalf("签名无效: %s (%v)", result.Status, result.Error)
+//	}
+//	fmt.Printf("由 %s 签名，指纹 %s\n", result.Signer, result.Fingerprint)
+func (c *Client) VerifySignature(ctx context.Context, path string, data []byte, keyring *Keyring) (*SignatureVerification, error) {
+	if keyring == nil {
+		return nil, errors.New("公钥环不能为空")
+	}
+	return c.verifySignatureReader(ctx, path, bytes.NewReader(data), keyring)
+}
+
+// verifySignatureReader 与VerifySignature相同，但从data中流式读取被签名的内容
+func (c *Client) verifySignatureReader(ctx context.Context, path string, data io.Reader, keyring *Keyring) (*SignatureVerification, error) {
+	signature, err := c.Download(ctx, path+".asc")
+	if err != nil {
+		if isNotFoundError(err) {
+			return &SignatureVerification{
+				Path:   path,
+				Status: SignatureMissing,
+				Error:  fmt.Errorf("签名文件不存在: %s.asc", path),
+			}, nil
+		}
+		return nil, err
+	}
+
+	return verifyDetachedSignature(path, data, signature, keyring), nil
+}
+
+// verifyDetachedSignature 验证分离签名并将结果转换为SignatureVerification
+func verifyDetachedSignature(path string, data io.Reader, signature []byte, keyring *Keyring) *SignatureVerification {
+	result := &SignatureVerification{Path: path}
+
+	body, err := decodeSignature(signature)
+	if err != nil {
+		result.Status = SignatureInvalid
+		result.Error = err
+		return result
+	}
+
+	// 先解析签名包，即使签名者未知也能报告密钥ID
+	if p, err := packet.Read(bytes.NewReader(body)); err == nil {
+		if sig, ok := p.(*packet.Signature); ok {
+			result.SignedAt = sig.CreationTime
+			if sig.IssuerKeyId != nil {
+				result.KeyId = fmt.Sprintf("%016X", *sig.IssuerKeyId)
+			}
+			if len(sig.IssuerFingerprint) > 0 {
+				result.Fingerprint = formatFingerprint(sig.IssuerFingerprint)
+			}
+		}
+	}
+
+	_, signer, err := openpgp.VerifyDetachedSignature(keyring.entities, data, bytes.NewReader(body), nil)
+	if signer != nil {
+		result.Fingerprint = formatFingerprint(signer.PrimaryKey.Fingerprint)
+		if identity := signer.PrimaryIdentity(); identity != nil {
+			result.Signer = identity.Name
+		}
+	}
+
+	switch {
+	case err == nil:
+		result.Status = SignatureValid
+	case errors.Is(err, pgperrors.ErrUnknownIssuer):
+		result.Status = SignatureUnknownKey
+	case errors.Is(err, pgperrors.ErrSignatureExpired), errors.Is(err, pgperrors.ErrKeyExpired):
+		result.Status = SignatureExpired
+	case errors.Is(err, pgperrors.ErrKeyRevoked):
+		result.Status = SignatureRevoked
+	default:
+		result.Status = SignatureInvalid
+	}
+	result.Error = err
+	return result
+}
+
+// decodeSignature 解码签名文件，支持ASCII Armor和二进制格式
+func decodeSignature(signature []byte) ([]byte, error) {
+	if !bytes.Contains(signature, []byte("-----BEGIN PGP SIGNATURE-----")) {
+		return signature, nil
+	}
+	block, err := armor.Decode(bytes.NewReader(signature))
+	if err != nil {
+		return nil, fmt.Errorf("解析签名文件失败: %w", err)
+	}
+	if block.Type != openpgp.SignatureType {
+		return nil, fmt.Errorf("签名文件类型错误: %s", block.Type)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(block.Body); err != nil {
+		return nil, fmt.Errorf("解析签名文件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatFingerprint 将指纹格式化为大写十六进制字符串
+func formatFingerprint(fingerprint []byte) string {
+	return strings.ToUpper(fmt.Sprintf("%x", fingerprint))
+}
+
+// isNotFoundError 判断错误是否表示远程资源不存在
+func isNotFoundError(err error) bool {
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	var apiErr *response.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == "404" {
+		return true
+	}
+	var httpErr *response.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == 404
+}
+
+// isSignedFile 判断仓库路径是否为需要签名的文件
+func isSignedFile(filePath string) bool {
+	if isSignatureOrChecksumFile(filePath) {
+		return false
+	}
+	return !strings.HasPrefix(filepath.Base(filePath), "maven-metadata")
+}
+
+// verifyDownloadSignature 按照客户端的签名验证策略验证从远程仓库下载的内容
+//
+// 返回验证结果供调用方复用；客户端不需要验证该文件的签名时返回nil。
+func (c *Client) verifyDownloadSignature(ctx context.Context, filePath string, data io.Reader) (*SignatureVerification, error) {
+	if !c.verifiesSignature(filePath) {
+		return nil, nil
+	}
+
+	result, err := c.verifySignatureReader(ctx, filePath, data, c.signatureKeyring)
+	if err != nil {
+		return nil, err
+	}
+	if result.Valid() {
+		return result, nil
+	}
+
+	sigErr := &SignatureError{Verification: result}
+	if c.signaturePolicy == ChecksumPolicyWarn {
+		c.warnChecksum(filePath, sigErr)
+		return result, nil
+	}
+	return result, sigErr
+}
+
+// verifiesSignature 判断下载该文件时是否需要验证签名
+func (c *Client) verifiesSignature(filePath string) bool {
+	return c.signaturePolicy != ChecksumPolicyIgnore && c.signatureKeyring != nil && isSignedFile(filePath)
+}
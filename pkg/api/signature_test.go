@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSigner 生成测试用的签名密钥，并返回其ASCII Armor格式的公钥
+func newTestSigner(t *testing.T, name string) (*openpgp.Entity, []byte) {
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(w))
+	assert.NoError(t, w.Close())
+	return entity, buf.Bytes()
+}
+
+// signArmored 生成ASCII Armor格式的分离签名
+func signArmored(t *testing.T, signer *openpgp.Entity, data string) string {
+	var buf bytes.Buffer
+	assert.NoError(t, openpgp.ArmoredDetachSign(&buf, signer, bytes.NewReader([]byte(data)), nil))
+	return buf.String()
+}
+
+// TestVerifySignature 测试使用公钥环验证.asc签名
+func TestVerifySignature(t *testing.T) {
+	trusted, trustedKey := newTestSigner(t, "Trusted Releaser")
+	stranger, strangerKey := newTestSigner(t, "Stranger")
+
+	// 公钥文件中包含多个Armor块和说明文字
+	keyDir := t.TempDir()
+	keysFile := filepath.Join(keyDir, "KEYS")
+	assert.NoError(t, os.WriteFile(keysFile, append([]byte("This file contains the release keys.\n\n"), trustedKey...), 0644))
+
+	keyring, err := LoadKeyring(keysFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, keyring.Len())
+
+	jar := BuildArtifactPath("org.example", "demo", "1.0", JAR)
+	pom := BuildArtifactPath("org.example", "demo", "1.0", POM)
+	sources := BuildArtifactPath("org.example", "demo", "1.0", JAR, "sources")
+	tampered := BuildArtifactPath("org.example", "demo", "1.0", JAR, "javadoc")
+	server := newTestServer(t, map[string]string{
+		jar:               "jar-content",
+		jar + ".asc":      signArmored(t, trusted, "jar-content"),
+		pom:               "<project/>",
+		pom + ".asc":      signArmored(t, stranger, "<project/>"),
+		sources:           "sources-content",
+		tampered:          "tampered-content",
+		tampered + ".asc": signArmored(t, trusted, "original-content"),
+	})
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	result, err := client.VerifySignature(ctx, jar, []byte("jar-content"), keyring)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid())
+	assert.Equal(t, keyring.Fingerprints()[0], result.Fingerprint)
+	assert.Equal(t, "Trusted Releaser <Trusted Releaser@example.com>", result.Signer)
+	assert.Len(t, result.KeyId, 16)
+	assert.False(t, result.SignedAt.IsZero())
+
+	result, err = client.VerifySignature(ctx, pom, []byte("<project/>"), keyring)
+	assert.NoError(t, err)
+	assert.Equal(t, SignatureUnknownKey, result.Status)
+	assert.NotEmpty(t, result.KeyId)
+
+	result, err = client.VerifySignature(ctx, sources, []byte("sources-content"), keyring)
+	assert.NoError(t, err)
+	assert.Equal(t, SignatureMissing, result.Status)
+
+	result, err = client.VerifySignature(ctx, tampered, []byte("tampered-content"), keyring)
+	assert.NoError(t, err)
+	assert.Equal(t, SignatureInvalid, result.Status)
+
+	// 加入第二把公钥后，陌生人的签名也被认可
+	assert.NoError(t, os.WriteFile(filepath.Join(keyDir, "stranger.asc"), strangerKey, 0644))
+	keyring, err = LoadKeyring(keyDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, keyring.Len())
+	result, err = client.VerifySignature(ctx, pom, []byte("<project/>"), keyring)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = ParseKeyring([]byte("not a key"))
+	assert.Error(t, err)
+}
+
+// TestDownloadSignatureVerification 测试下载时的签名验证和制品包的签名状态
+func TestDownloadSignatureVerification(t *testing.T) {
+	trusted, trustedKey := newTestSigner(t, "Trusted Releaser")
+	keyring, err := ParseKeyring(trustedKey)
+	assert.NoError(t, err)
+
+	jar := BuildArtifactPath("org.example", "demo", "1.0", JAR)
+	pom := BuildArtifactPath("org.example", "demo", "1.0", POM)
+	server := newTestServer(t, map[string]string{
+		jar:          "jar-content",
+		jar + ".asc": signArmored(t, trusted, "jar-content"),
+		pom:          "<project/>",
+	})
+	ctx := context.Background()
+
+	client := newTestClient(server.URL, WithSignatureVerification(keyring, ChecksumPolicyFail))
+	data, err := client.Download(ctx, jar)
+	assert.NoError(t, err)
+	assert.Equal(t, "jar-content", string(data))
+
+	_, err = client.Download(ctx, pom)
+	var sigErr *SignatureError
+	assert.True(t, errors.As(err, &sigErr))
+	assert.Equal(t, SignatureMissing, sigErr.Verification.Status)
+
+	// 制品包复用下载过程中的验证结果，每个文件的签名只获取一次
+	var signatureRequests int32
+	counted, _ := countingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".asc") {
+			atomic.AddInt32(&signatureRequests, 1)
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	client = newTestClient(counted.URL, WithSignatureVerification(keyring, ChecksumPolicyFail))
+	bundle, _ := client.DownloadCompleteBundle(ctx, "org.example", "demo", "1.0")
+	assert.Equal(t, int32(len(bundle.Signatures)), atomic.LoadInt32(&signatureRequests))
+	assert.True(t, bundle.Signatures["JAR"].Valid())
+	assert.Equal(t, SignatureMissing, bundle.Signatures["POM"].Status)
+	assert.Error(t, bundle.Errors["POM"])
+
+	// Warn策略下签名缺失只产生警告，制品包仍记录验证结果
+	var warnings []error
+	client = newTestClient(server.URL,
+		WithSignatureVerification(keyring, ChecksumPolicyWarn),
+		WithChecksumWarningHandler(func(filePath string, err error) {
+			warnings = append(warnings, err)
+		}),
+	)
+	bundle, err = client.DownloadCompleteBundle(ctx, "org.example", "demo", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "<project/>", string(bundle.Pom))
+	assert.Equal(t, SignatureMissing, bundle.Signatures["POM"].Status)
+	assert.NotEmpty(t, warnings)
+}
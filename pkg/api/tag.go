@@ -40,17 +40,12 @@ func (c *Client) SearchByMultipleTags(ctx context.Context, tags []string, limit
 		return nil, errors.New("at least one tag must be provided")
 	}
 
-	// 创建自定义查询字符串来包含所有标签
-	var queryParts []string
+	// 使用AND连接所有标签查询条件
+	var queryParts []request.Expr
 	for _, tag := range tags {
-		queryParts = append(queryParts, "tags:"+tag)
+		queryParts = append(queryParts, request.Field(request.FieldTags, tag))
 	}
-
-	// 使用AND连接所有标签查询条件
-	customQuery := strings.Join(queryParts, " AND ")
-
-	// 使用自定义查询
-	query := request.NewQuery().SetCustomQuery(customQuery)
+	query := request.NewQuery().SetExpr(request.And(queryParts...))
 
 	if limit <= 0 {
 		searchRequest := request.NewSearchRequest().SetQuery(query)
@@ -311,9 +306,9 @@ func (c *Client) SearchByTagPrefix(ctx context.Context, prefix string, limit int
 		return nil, errors.New("tag prefix cannot be empty")
 	}
 
-	// 使用自定义查询构建标签前缀搜索
-	customQuery := "tags:" + prefix + "*"
-	search := request.NewSearchRequest().SetQuery(request.NewQuery().SetCustomQuery(customQuery))
+	// 构建标签前缀搜索
+	prefixQuery := request.Wildcard(request.FieldTags, prefix+"*")
+	search := request.NewSearchRequest().SetQuery(request.NewQuery().SetExpr(prefixQuery))
 
 	if limit > 0 {
 		search.SetLimit(limit)
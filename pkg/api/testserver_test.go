@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer 启动一个离线测试用的模拟服务器，测试结束时自动关闭
+//
+// files的键是仓库中的相对路径，如"org/example/demo/1.0/demo-1.0.pom"，docs用于补充搜索文档；
+// 没有提供的校验文件和制品级maven-metadata.xml由模拟服务器生成。
+func newTestServer(t *testing.T, files map[string]string, docs ...*testserver.Document) *testserver.Server {
+	corpus := testserver.NewCorpus()
+	for path, content := range files {
+		corpus.AddFile(path, []byte(content))
+	}
+	for _, doc := range docs {
+		corpus.AddDocument(doc)
+	}
+	server := testserver.New(corpus)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient 创建一个指向测试服务器的客户端，仓库地址为baseURL下的/maven2，失败时不重试
+func newTestClient(baseURL string, options ...ClientOption) *Client {
+	options = append([]ClientOption{
+		WithBaseURL(baseURL),
+		WithRepoBaseURL(baseURL + "/maven2"),
+		WithMaxRetries(0),
+	}, options...)
+	return NewClient(options...)
+}
+
+// TestClientWithTestServer 测试客户端与进程内模拟服务器配合使用
+func TestClientWithTestServer(t *testing.T) {
+	server, err := testserver.NewFromDir("../testserver/testdata/corpus")
+	assert.NoError(t, err)
+	defer server.Close()
+
+	ctx := context.Background()
+	clients := map[string]*Client{
+		"http": NewClient(WithBaseURL(server.URL), WithRepoBaseURL(server.RepoURL()), WithMaxRetries(0)),
+		// 使用进程内传输层时不需要修改默认地址
+		"transport": NewClient(WithTransport(server.Transport()), WithMaxRetries(0)),
+	}
+	for name, client := range clients {
+		versions, err := client.ListVersions(ctx, "org.example", "demo", 10)
+		assert.NoError(t, err, name)
+		if assert.Len(t, versions, 3, name) {
+			assert.Equal(t, "2.0-SNAPSHOT", versions[0].Version, name)
+		}
+
+		result, err := client.SearchClassesWithHighlighting(ctx, "com.acme.widgets", 10)
+		assert.NoError(t, err, name)
+		if assert.Len(t, result.ResponseBody.Docs, 1, name) {
+			assert.Equal(t, "widgets", result.ResponseBody.Docs[0].ArtifactId, name)
+		}
+
+		data, err := client.Download(ctx, "org/example/demo/1.1/demo-1.1.pom")
+		assert.NoError(t, err, name)
+		assert.Contains(t, string(data), "<artifactId>demo</artifactId>", name)
+
+		metadata, err := client.GetMavenMetadata(ctx, "org.example", "demo")
+		assert.NoError(t, err, name)
+		if assert.NotNil(t, metadata, name) {
+			assert.Equal(t, "1.1", metadata.Versioning.Release, name)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/testserver"
 )
 
 // TestListVersionsReal 使用真实 API 测试获取版本列表功能
@@ -471,3 +474,59 @@ func TestVersionsEdgeCases(t *testing.T) {
 		assert.Empty(t, versions, "拒绝所有版本的过滤器应返回空列表")
 	})
 }
+
+// TestVersionOrderingOffline 测试GetLatestVersion和FilterVersionsByRange不依赖服务端排序
+func TestVersionOrderingOffline(t *testing.T) {
+	searchJson := `{"response":{"numFound":5,"start":0,"docs":[
+  {"id":"org.example:demo:1.9","g":"org.example","a":"demo","v":"1.9"},
+  {"id":"org.example:demo:2.0-SNAPSHOT","g":"org.example","a":"demo","v":"2.0-SNAPSHOT"},
+  {"id":"org.example:demo:1.10","g":"org.example","a":"demo","v":"1.10"},
+  {"id":"org.example:demo:1.10-RC1","g":"org.example","a":"demo","v":"1.10-RC1"},
+  {"id":"org.example:demo:1.2","g":"org.example","a":"demo","v":"1.2"}
+]}}`
+	// testserver会按版本排序返回结果，这里使用固定的乱序响应
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(searchJson))
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	latest, err := client.GetLatestVersion(ctx, "org.example", "demo")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.10", latest.Version)
+
+	versions, err := client.FilterVersionsByRange(ctx, "org.example", "demo", "[1.2,1.10)")
+	assert.NoError(t, err)
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		names = append(names, v.Version)
+	}
+	assert.Equal(t, []string{"1.10-RC1", "1.9", "1.2"}, names)
+
+	_, err = client.FilterVersionsByRange(ctx, "org.example", "demo", "[2.0,1.0]")
+	assert.Error(t, err)
+}
+
+// TestGetLatestVersionSkipsPreReleases 测试最新版本跳过高于正式版本的预发布版本，只有预发布版本时才返回它们
+func TestGetLatestVersionSkipsPreReleases(t *testing.T) {
+	client := newTestClient(newTestServer(t, nil, versionDocs("org.example", "demo", "3.0.0-M1", "2.7.1", "3.0.0-beta-2", "2.7.0")...).URL)
+	latest, err := client.GetLatestVersion(context.Background(), "org.example", "demo")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.7.1", latest.Version)
+
+	client = newTestClient(newTestServer(t, nil, versionDocs("org.example", "demo", "1.0-alpha1", "1.0-SNAPSHOT", "1.0-RC1")...).URL)
+	latest, err = client.GetLatestVersion(context.Background(), "org.example", "demo")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0-SNAPSHOT", latest.Version)
+}
+
+// versionDocs 生成同一构件各个版本的搜索文档
+func versionDocs(groupId, artifactId string, versions ...string) []*testserver.Document {
+	docs := make([]*testserver.Document, 0, len(versions))
+	for _, v := range versions {
+		docs = append(docs, &testserver.Document{GroupId: groupId, ArtifactId: artifactId, Version: v})
+	}
+	return docs
+}
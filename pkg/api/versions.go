@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/url"
+	"sort"
 
 	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
 	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	mavenversion "github.com/scagogogo/sonatype-central-sdk/pkg/version"
 )
 
 // GetVersionInfo 获取组件的版本信息
@@ -53,18 +54,15 @@ import (
 //	    fmt.Printf("- %s (%s)\n", file.Name, file.Type)
 //	}
 func (c *Client) GetVersionInfo(ctx context.Context, groupId, artifactId, version string) (*response.VersionInfo, error) {
-	// 构建URL
-	targetUrl := fmt.Sprintf("%s/solrsearch/select?q=g:%s+AND+a:%s+AND+v:%s&rows=1&wt=json",
-		c.baseURL,
-		url.QueryEscape(groupId),
-		url.QueryEscape(artifactId),
-		url.QueryEscape(version))
+	// 构建搜索请求
+	query := request.NewQuery().SetGroupId(groupId).SetArtifactId(artifactId).SetVersion(version)
+	search := request.NewSearchRequest().SetQuery(query).SetLimit(1)
 
 	// 创建响应对象
 	var result response.VersionInfo
 
 	// 执行请求
-	_, err := c.doRequest(ctx, "GET", targetUrl, nil, &result)
+	err := c.SearchRequest(ctx, search, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -190,9 +188,11 @@ func (c *Client) IteratorVersions(ctx context.Context, groupId, artifactId strin
 
 // GetLatestVersion 获取最新的发布版本
 //
-// 该方法提供了快速获取指定Maven制品最新发布版本的便捷方式。它返回按时间戳排序的
-// 第一个版本，通常是制品的最新稳定版本。这对于确保使用最新版本、检查更新或获取
-// 当前推荐版本非常有用。
+// 该方法提供了快速获取指定Maven制品最新发布版本的便捷方式。它获取制品的全部版本，
+// 按照Maven的版本比较规则（与ComparableVersion一致，"1.10" > "1.9"，"1.0-RC1" < "1.0"）
+// 选出最高的正式版本，跳过alpha、beta、milestone、rc等预发布版本和快照版本；
+// 如果制品没有正式版本，则返回最高的预发布或快照版本。
+// 结果不依赖服务端返回的排序，这对于确保使用最新版本、检查更新或获取当前推荐版本非常有用。
 //
 // 参数:
 //   - ctx: 上下文对象，用于控制请求的超时和取消
@@ -230,13 +230,20 @@ func (c *Client) IteratorVersions(ctx context.Context, groupId, artifactId strin
 //	    latestVersion.Version)
 //	fmt.Printf("下载地址: %s\n", downloadUrl)
 func (c *Client) GetLatestVersion(ctx context.Context, groupId, artifactId string) (*response.Version, error) {
-	versions, err := c.ListVersions(ctx, groupId, artifactId, 1)
+	versions, err := c.ListVersions(ctx, groupId, artifactId, 0)
 	if err != nil {
 		return nil, err
 	}
 	if len(versions) == 0 {
 		return nil, fmt.Errorf("no versions found for %s:%s", groupId, artifactId)
 	}
+
+	sortVersionsDescending(versions)
+	for _, v := range versions {
+		if !mavenversion.Parse(v.Version).IsPreRelease() {
+			return v, nil
+		}
+	}
 	return versions[0], nil
 }
 
@@ -264,6 +271,8 @@ func (c *Client) GetVersionsWithMetadata(ctx context.Context, groupId, artifactI
 }
 
 // FilterVersions 根据条件过滤版本
+//
+// 返回的结果按照Maven版本比较规则从高到低排序。
 func (c *Client) FilterVersions(ctx context.Context, groupId, artifactId string, filter func(*response.Version) bool) ([]*response.Version, error) {
 	versions, err := c.ListVersions(ctx, groupId, artifactId, 0)
 	if err != nil {
@@ -277,10 +286,47 @@ func (c *Client) FilterVersions(ctx context.Context, groupId, artifactId string,
 		}
 	}
 
+	sortVersionsDescending(result)
 	return result, nil
 }
 
+// FilterVersionsByRange 获取满足Maven版本范围的版本
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - groupId: 制品的组ID
+//   - artifactId: 制品ID
+//   - versionRange: Maven版本范围，如"[1.2,2.0)"、"(,1.5],[2.0,)"
+//
+// 返回:
+//   - []*response.Version: 满足范围的版本，按照Maven版本比较规则从高到低排序
+//   - error: 版本范围格式错误或获取版本列表失败时返回错误
+//
+// 使用示例:
+//
+//	client := api.NewClient()
+//	ctx := context.Background()
+//
+//	versions, err := client.FilterVersionsByRange(ctx, "com.google.guava", "guava", "[30.0,32.0)")
+//	if err != nil {
+//	    log.Fatalf("过滤版本失败: %v", err)
+//	}
+//	for _, v := range versions {
+//	    fmt.Println(v.Version)
+//	}
+func (c *Client) FilterVersionsByRange(ctx context.Context, groupId, artifactId, versionRange string) ([]*response.Version, error) {
+	r, err := mavenversion.ParseRange(versionRange)
+	if err != nil {
+		return nil, err
+	}
+	return c.FilterVersions(ctx, groupId, artifactId, func(v *response.Version) bool {
+		return r.Matches(v.Version)
+	})
+}
+
 // CompareVersions 比较两个版本
+//
+// 除了两个版本的更新时间外，结果中还包含按照Maven版本比较规则得到的比较结果。
 func (c *Client) CompareVersions(ctx context.Context, groupId, artifactId string, version1, version2 string) (*response.VersionComparison, error) {
 	v1Info, err := c.GetVersionInfo(ctx, groupId, artifactId, version1)
 	if err != nil {
@@ -292,12 +338,18 @@ func (c *Client) CompareVersions(ctx context.Context, groupId, artifactId string
 		return nil, err
 	}
 
-	return &response.VersionComparison{
+	comparison := &response.VersionComparison{
 		Version1:    version1,
 		Version2:    version2,
 		V1Timestamp: v1Info.LastUpdated,
 		V2Timestamp: v2Info.LastUpdated,
-	}, nil
+		Result:      mavenversion.Compare(version1, version2),
+	}
+	comparison.NewerVersion = version1
+	if comparison.Result < 0 {
+		comparison.NewerVersion = version2
+	}
+	return comparison, nil
 }
 
 // HasVersion 检查特定版本是否存在
@@ -313,3 +365,14 @@ func (c *Client) HasVersion(ctx context.Context, groupId, artifactId, version st
 	}
 	return true, nil
 }
+
+// sortVersionsDescending 按照Maven版本比较规则将版本从高到低排序（原地排序）
+func sortVersionsDescending(versions []*response.Version) {
+	parsed := make(map[*response.Version]*mavenversion.Version, len(versions))
+	for _, v := range versions {
+		parsed[v] = mavenversion.Parse(v.Version)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return parsed[versions[i]].Compare(parsed[versions[j]]) > 0
+	})
+}
@@ -0,0 +1,619 @@
+package api
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	mavenversion "github.com/scagogogo/sonatype-central-sdk/pkg/version"
+)
+
+// ErrNoVulnerabilityDB 客户端没有配置漏洞数据库
+//
+// Maven中央仓库不提供漏洞查询接口，安全相关的方法需要先通过WithVulnerabilityDB配置离线漏洞数据库。
+var ErrNoVulnerabilityDB = errors.New("no vulnerability database configured, use WithVulnerabilityDB")
+
+// osvEcosystemMaven OSV数据中Maven生态的名称
+const osvEcosystemMaven = "Maven"
+
+// osvEntry OSV格式的一条漏洞记录，只包含需要用到的字段
+//
+// 格式说明见 https://ossf.github.io/osv-schema/
+type osvEntry struct {
+	ID        string   `json:"id"`
+	Modified  string   `json:"modified"`
+	Withdrawn string   `json:"withdrawn"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Details   string   `json:"details"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected   []*osvAffected `json:"affected"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+
+	// 加载时计算的漏洞信息
+	vulnerability *response.Vulnerability
+}
+
+// osvAffected 漏洞影响的一个软件包
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string      `json:"type"`
+		Events []*osvEvent `json:"events"`
+	} `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+// osvEvent 版本范围中的一个事件，四个字段中只有一个有值
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+
+	// 事件对应的版本，introduced为"0"时为nil，表示最低版本
+	version *mavenversion.Version
+}
+
+// VulnerabilityDB 基于OSV数据的离线漏洞数据库
+//
+// 数据来自OSV（https://osv.dev）发布的Maven生态漏洞数据，可以从
+// https://osv-vulnerabilities.storage.googleapis.com/Maven/all.zip 下载。
+// 数据库按groupId:artifactId索引漏洞，判断版本是否受影响时使用Maven的版本比较规则，
+// 因此"2.0-beta9"、"2.3.1"、"2.12.2"这样的版本范围能被正确处理。
+//
+// VulnerabilityDB可以被多个goroutine并发使用。
+type VulnerabilityDB struct {
+	mu        sync.RWMutex
+	byID      map[string]*osvEntry
+	byPackage map[string][]*osvEntry
+}
+
+// NewVulnerabilityDB 创建一个空的漏洞数据库
+func NewVulnerabilityDB() *VulnerabilityDB {
+	return &VulnerabilityDB{
+		byID:      make(map[string]*osvEntry),
+		byPackage: make(map[string][]*osvEntry),
+	}
+}
+
+// LoadVulnerabilityDB 从磁盘上的OSV数据加载漏洞数据库
+//
+// 每个路径可以是OSV发布的zip压缩包、单个OSV JSON文件，或者包含这些文件的目录（递归查找）。
+// 非Maven生态的记录和已撤回的记录会被忽略；同一ID出现多次时保留modified较新的一条。
+//
+// 参数:
+//   - paths: zip文件、JSON文件或目录路径
+//
+// 返回:
+//   - *VulnerabilityDB: 加载完成的漏洞数据库
+//   - error: 文件无法读取、格式错误或没有加载到任何Maven漏洞时返回错误
+//
+// 使用示例:
+//
+//	// 先下载 https://osv-vulnerabilities.storage.googleapis.com/Maven/all.zip
+//	db, err := api.LoadVulnerabilityDB("/var/lib/osv/Maven-all.zip")
+//	if err != nil {
+//	    log.Fatalf("加载漏洞数据库失败: %v", err)
+//	}
+//
+//	client := api.NewClient(api.WithVulnerabilityDB(db))
+//	rating, err := client.GetSecurityRating(ctx, "org.apache.logging.log4j", "log4j-core", "2.14.1")
+func LoadVulnerabilityDB(paths ...string) (*VulnerabilityDB, error) {
+	db := NewVulnerabilityDB()
+	for _, p := range paths {
+		if err := db.Load(p); err != nil {
+			return nil, err
+		}
+	}
+	if db.Len() == 0 {
+		return nil, errors.New("漏洞数据库中没有任何Maven漏洞记录")
+	}
+	return db, nil
+}
+
+// Load 向数据库中加载zip文件、JSON文件或目录中的OSV数据，路径格式见LoadVulnerabilityDB
+func (db *VulnerabilityDB) Load(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("读取漏洞数据失败: %w", err)
+	}
+	if !info.IsDir() {
+		return db.loadFile(path)
+	}
+
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("读取漏洞数据目录失败: %w", err)
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(p))
+		if ext != ".json" && ext != ".zip" {
+			return nil
+		}
+		return db.loadFile(p)
+	})
+}
+
+// loadFile 加载单个zip或JSON文件
+func (db *VulnerabilityDB) loadFile(path string) error {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return fmt.Errorf("打开漏洞数据压缩包失败: %w", err)
+		}
+		defer archive.Close()
+
+		for _, file := range archive.File {
+			if file.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(file.Name), ".json") {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return fmt.Errorf("读取%s失败: %w", file.Name, err)
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("读取%s失败: %w", file.Name, err)
+			}
+			if err := db.AddOSV(data); err != nil {
+				return fmt.Errorf("%s: %w", file.Name, err)
+			}
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取漏洞数据失败: %w", err)
+	}
+	if err := db.AddOSV(data); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// AddOSV 向数据库中添加一条OSV JSON格式的漏洞记录
+//
+// 数据也可以是记录组成的JSON数组。不影响Maven软件包的记录会被忽略。
+func (db *VulnerabilityDB) AddOSV(data []byte) error {
+	var entries []*osvEntry
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("解析OSV数据失败: %w", err)
+		}
+	} else {
+		var entry osvEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("解析OSV数据失败: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, entry := range entries {
+		db.add(entry)
+	}
+	return nil
+}
+
+// add 索引一条漏洞记录，调用方需持有写锁
+func (db *VulnerabilityDB) add(entry *osvEntry) {
+	if entry.ID == "" || entry.Withdrawn != "" {
+		return
+	}
+
+	packages := make(map[string]bool)
+	for _, affected := range entry.Affected {
+		if affected.Package.Ecosystem != osvEcosystemMaven || affected.Package.Name == "" {
+			continue
+		}
+		packages[affected.Package.Name] = true
+		for _, r := range affected.Ranges {
+			for _, event := range r.Events {
+				if v := event.value(); v != "" && v != "0" {
+					event.version = mavenversion.Parse(v)
+				}
+			}
+			sort.SliceStable(r.Events, func(i, j int) bool {
+				return compareEventVersions(r.Events[i].version, r.Events[j].version) < 0
+			})
+		}
+	}
+	if len(packages) == 0 {
+		return
+	}
+
+	if existing, ok := db.byID[entry.ID]; ok {
+		// OSV的modified字段为RFC 3339格式，可以直接按字符串比较
+		if existing.Modified >= entry.Modified {
+			return
+		}
+		db.remove(existing)
+	}
+
+	entry.vulnerability = entry.toVulnerability()
+	db.byID[entry.ID] = entry
+	for name := range packages {
+		db.byPackage[name] = append(db.byPackage[name], entry)
+	}
+}
+
+// remove 从索引中删除一条漏洞记录，调用方需持有写锁
+func (db *VulnerabilityDB) remove(entry *osvEntry) {
+	delete(db.byID, entry.ID)
+	for _, affected := range entry.Affected {
+		name := affected.Package.Name
+		entries := db.byPackage[name]
+		for i, e := range entries {
+			if e == entry {
+				db.byPackage[name] = append(entries[:i:i], entries[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Len 返回数据库中漏洞记录的数量
+func (db *VulnerabilityDB) Len() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return len(db.byID)
+}
+
+// Vulnerabilities 返回影响指定制品版本的漏洞，按严重性从高到低排列
+//
+// 参数:
+//   - groupId: 组ID
+//   - artifactId: 制品ID
+//   - version: 版本号
+//
+// 返回:
+//   - []*response.Vulnerability: 影响该版本的漏洞，没有时返回空切片
+func (db *VulnerabilityDB) Vulnerabilities(groupId, artifactId, version string) []*response.Vulnerability {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	v := mavenversion.Parse(version)
+	vulnerabilities := make([]*response.Vulnerability, 0)
+	for _, entry := range db.byPackage[groupId+":"+artifactId] {
+		if entry.affects(groupId+":"+artifactId, version, v) {
+			vuln := *entry.vulnerability
+			vulnerabilities = append(vulnerabilities, &vuln)
+		}
+	}
+
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		a, b := vulnerabilities[i], vulnerabilities[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) > severityRank(b.Severity)
+		}
+		if a.CvssScore != b.CvssScore {
+			return a.CvssScore > b.CvssScore
+		}
+		return a.ID < b.ID
+	})
+	return vulnerabilities
+}
+
+// Rating 根据影响指定制品版本的漏洞计算安全评分
+//
+// RiskScore为漏洞中最高的CVSS评分（0-10，越高风险越大），漏洞没有CVSS向量时按严重性估算；
+// Score为10减去RiskScore（越高越安全）；Severity为漏洞中最高的严重性，没有漏洞时为NONE。
+func (db *VulnerabilityDB) Rating(groupId, artifactId, version string) *response.SecurityRating {
+	vulnerabilities := db.Vulnerabilities(groupId, artifactId, version)
+	rating := &response.SecurityRating{
+		VulnCount:  len(vulnerabilities),
+		Severity:   string(SecuritySeverityNone),
+		Advisories: make([]string, 0, len(vulnerabilities)),
+	}
+	for _, vuln := range vulnerabilities {
+		if severityRank(vuln.Severity) > severityRank(rating.Severity) {
+			rating.Severity = vuln.Severity
+		}
+		score := vuln.CvssScore
+		if score == 0 {
+			score = severityScore(vuln.Severity)
+		}
+		rating.RiskScore = math.Max(rating.RiskScore, score)
+		rating.Advisories = append(rating.Advisories, vuln.Advisory)
+	}
+	rating.Score = math.Round((10-rating.RiskScore)*10) / 10
+	return rating
+}
+
+// fixedVersions 返回数据库中记录的制品修复版本，按版本从低到高排列
+func (db *VulnerabilityDB) fixedVersions(groupId, artifactId string) []string {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	name := groupId + ":" + artifactId
+	seen := make(map[string]bool)
+	var versions []string
+	for _, entry := range db.byPackage[name] {
+		for _, affected := range entry.Affected {
+			if affected.Package.Ecosystem != osvEcosystemMaven || affected.Package.Name != name {
+				continue
+			}
+			for _, r := range affected.Ranges {
+				for _, event := range r.Events {
+					if event.Fixed != "" && !seen[event.Fixed] {
+						seen[event.Fixed] = true
+						versions = append(versions, event.Fixed)
+					}
+				}
+			}
+		}
+	}
+	mavenversion.Sort(versions)
+	return versions
+}
+
+// affects 判断漏洞是否影响指定软件包的版本
+func (e *osvEntry) affects(name, raw string, v *mavenversion.Version) bool {
+	for _, affected := range e.Affected {
+		if affected.Package.Ecosystem != osvEcosystemMaven || affected.Package.Name != name {
+			continue
+		}
+		for _, listed := range affected.Versions {
+			if listed == raw || mavenversion.Parse(listed).Equal(v) {
+				return true
+			}
+		}
+		for _, r := range affected.Ranges {
+			if r.Type == "ECOSYSTEM" && eventsContain(r.Events, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// eventsContain 按OSV规范判断版本是否落在已排序的事件序列描述的范围内
+func eventsContain(events []*osvEvent, v *mavenversion.Version) bool {
+	affected := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if event.version == nil || event.version.Compare(v) <= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if event.version.Compare(v) <= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if event.version.Compare(v) < 0 {
+				affected = false
+			}
+		case event.Limit != "" && event.Limit != "*":
+			if event.version.Compare(v) <= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// value 返回事件中记录的版本
+func (e *osvEvent) value() string {
+	for _, v := range []string{e.Introduced, e.Fixed, e.LastAffected, e.Limit} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// compareEventVersions 比较事件版本，nil表示最低版本
+func compareEventVersions(a, b *mavenversion.Version) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(b)
+}
+
+// toVulnerability 将OSV记录转换为漏洞信息
+func (e *osvEntry) toVulnerability() *response.Vulnerability {
+	vuln := &response.Vulnerability{
+		ID:          e.ID,
+		Title:       e.Summary,
+		Description: e.Details,
+		Advisory:    "https://osv.dev/vulnerability/" + e.ID,
+	}
+
+	for _, id := range append([]string{e.ID}, e.Aliases...) {
+		if strings.HasPrefix(id, "CVE-") {
+			vuln.CVE = id
+			break
+		}
+	}
+
+	if vuln.Title == "" {
+		vuln.Title = strings.TrimSpace(strings.SplitN(e.Details, "\n", 2)[0])
+	}
+
+	for _, ref := range e.References {
+		if ref.Type == "ADVISORY" && ref.URL != "" {
+			vuln.Advisory = ref.URL
+			break
+		}
+	}
+
+	for _, severity := range e.Severity {
+		if !strings.HasPrefix(severity.Type, "CVSS_V3") {
+			continue
+		}
+		if score, err := cvss3BaseScore(severity.Score); err == nil {
+			vuln.CvssVector = severity.Score
+			vuln.CvssScore = score
+			break
+		}
+	}
+
+	switch strings.ToUpper(e.DatabaseSpecific.Severity) {
+	case "CRITICAL":
+		vuln.Severity = string(SecuritySeverityCritical)
+	case "HIGH":
+		vuln.Severity = string(SecuritySeverityHigh)
+	case "MODERATE", "MEDIUM":
+		vuln.Severity = string(SecuritySeverityMedium)
+	case "LOW":
+		vuln.Severity = string(SecuritySeverityLow)
+	default:
+		vuln.Severity = string(scoreSeverity(vuln.CvssScore))
+	}
+	return vuln
+}
+
+// severityRank 严重性级别的排序，未知级别视为NONE
+func severityRank(severity string) int {
+	switch SecuritySeverity(strings.ToUpper(severity)) {
+	case SecuritySeverityCritical:
+		return 4
+	case SecuritySeverityHigh:
+		return 3
+	case SecuritySeverityMedium:
+		return 2
+	case SecuritySeverityLow:
+		return 1
+	}
+	return 0
+}
+
+// severityScore 没有CVSS评分时按严重性估算的评分，取CVSS对应区间的下限
+func severityScore(severity string) float64 {
+	switch SecuritySeverity(strings.ToUpper(severity)) {
+	case SecuritySeverityCritical:
+		return 9.0
+	case SecuritySeverityHigh:
+		return 7.0
+	case SecuritySeverityMedium:
+		return 4.0
+	case SecuritySeverityLow:
+		return 0.1
+	}
+	return 0
+}
+
+// scoreSeverity 按CVSS v3的定性评级将评分转换为严重性级别
+func scoreSeverity(score float64) SecuritySeverity {
+	switch {
+	case score >= 9.0:
+		return SecuritySeverityCritical
+	case score >= 7.0:
+		return SecuritySeverityHigh
+	case score >= 4.0:
+		return SecuritySeverityMedium
+	case score > 0:
+		return SecuritySeverityLow
+	}
+	return SecuritySeverityNone
+}
+
+// cvss3Weights CVSS v3基础指标的权重
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3BaseScore 按CVSS v3.x规范从向量计算基础评分
+//
+// 向量形如"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"，时间和环境指标会被忽略。
+func cvss3BaseScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, fmt.Errorf("不支持的CVSS向量: %s", vector)
+	}
+
+	metrics := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		if key, value, ok := strings.Cut(part, ":"); ok {
+			metrics[key] = value
+		}
+	}
+
+	scope := metrics["S"]
+	if scope != "U" && scope != "C" {
+		return 0, fmt.Errorf("CVSS向量缺少有效的S指标: %s", vector)
+	}
+	values := make(map[string]float64, len(cvss3Weights))
+	for key, weights := range cvss3Weights {
+		value, ok := weights[metrics[key]]
+		if !ok {
+			return 0, fmt.Errorf("CVSS向量缺少有效的%s指标: %s", key, vector)
+		}
+		values[key] = value
+	}
+	if scope == "C" {
+		// 影响范围改变时，PR的权重更高
+		switch metrics["PR"] {
+		case "L":
+			values["PR"] = 0.68
+		case "H":
+			values["PR"] = 0.5
+		}
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	var impact float64
+	if scope == "U" {
+		impact = 6.42 * iss
+	} else {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	exploitability := 8.22 * values["AV"] * values["AC"] * values["PR"] * values["UI"]
+	if scope == "U" {
+		return cvssRoundUp(math.Min(impact+exploitability, 10)), nil
+	}
+	return cvssRoundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+}
+
+// cvssRoundUp CVSS v3.1规范定义的向上取整到一位小数，避免浮点误差
+func cvssRoundUp(value float64) float64 {
+	n := int64(math.Round(value * 100000))
+	if n%10000 == 0 {
+		return float64(n) / 100000
+	}
+	return float64(n/10000+1) / 10
+}
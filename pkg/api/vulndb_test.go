@@ -0,0 +1,244 @@
+package api
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// osvFixtures Log4j相关的OSV漏洞记录（节选自GitHub Advisory Database）
+var osvFixtures = map[string]string{
+	"GHSA-jfh8-c2jp-5v3q.json": `{
+  "id": "GHSA-jfh8-c2jp-5v3q",
+  "modified": "2024-01-01T00:00:00Z",
+  "aliases": ["CVE-2021-44228"],
+  "summary": "Remote code injection in Log4j",
+  "details": "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP endpoints.",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [
+      {"introduced": "2.13.0"}, {"fixed": "2.15.0"},
+      {"introduced": "2.0-beta9"}, {"fixed": "2.3.1"},
+      {"introduced": "2.4"}, {"fixed": "2.12.2"}
+    ]}]
+  }],
+  "references": [{"type": "WEB", "url": "https://logging.apache.org/log4j/2.x/security.html"},
+                 {"type": "ADVISORY", "url": "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"}],
+  "database_specific": {"severity": "CRITICAL"}
+}`,
+	"GHSA-7rjr-3q55-vv33.json": `{
+  "id": "GHSA-7rjr-3q55-vv33",
+  "modified": "2024-01-01T00:00:00Z",
+  "aliases": ["CVE-2021-45046"],
+  "summary": "Incomplete fix for Apache Log4j vulnerability",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:C/C:H/I:H/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.16.0"}]}]
+  }],
+  "database_specific": {"severity": "CRITICAL"}
+}`,
+	"GHSA-last-affected.json": `{
+  "id": "GHSA-last-affected",
+  "summary": "Unfixed issue",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.example:lib"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"last_affected": "1.2"}]}],
+    "versions": ["2.0-legacy"]
+  }],
+  "database_specific": {"severity": "MODERATE"}
+}`,
+	"PYSEC-2021-1.json": `{
+  "id": "PYSEC-2021-1",
+  "affected": [{"package": {"ecosystem": "PyPI", "name": "log4j"}}]
+}`,
+}
+
+// newVulnerabilityDBZip 将测试数据写入临时zip文件
+func newVulnerabilityDBZip(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range osvFixtures {
+		entry, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = entry.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return path
+}
+
+// TestVulnerabilityDB 测试OSV数据的加载和版本范围判断
+func TestVulnerabilityDB(t *testing.T) {
+	db, err := LoadVulnerabilityDB(newVulnerabilityDBZip(t))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, db.Len(), "非Maven生态的记录应当被忽略")
+
+	tests := []struct {
+		artifact string
+		version  string
+		expected []string
+	}{
+		{"log4j-core", "2.14.1", []string{"GHSA-jfh8-c2jp-5v3q", "GHSA-7rjr-3q55-vv33"}},
+		{"log4j-core", "2.15.0", []string{"GHSA-7rjr-3q55-vv33"}},
+		{"log4j-core", "2.16.0", nil},
+		{"log4j-core", "2.0-beta9", []string{"GHSA-jfh8-c2jp-5v3q"}},
+		{"log4j-core", "2.0-alpha1", nil},
+		{"log4j-core", "2.3.1", nil},
+		{"log4j-core", "2.3", []string{"GHSA-jfh8-c2jp-5v3q"}},
+		{"log4j-core", "2.12.1", []string{"GHSA-jfh8-c2jp-5v3q"}},
+		{"log4j-core", "2.12.2", nil},
+		{"log4j-api", "2.14.1", nil},
+	}
+	for _, tc := range tests {
+		var ids []string
+		for _, vuln := range db.Vulnerabilities("org.apache.logging.log4j", tc.artifact, tc.version) {
+			ids = append(ids, vuln.ID)
+		}
+		assert.Equal(t, tc.expected, ids, "%s:%s", tc.artifact, tc.version)
+	}
+
+	vulns := db.Vulnerabilities("org.apache.logging.log4j", "log4j-core", "2.14.1")
+	assert.Equal(t, "CVE-2021-44228", vulns[0].CVE)
+	assert.Equal(t, "CRITICAL", vulns[0].Severity)
+	assert.Equal(t, 10.0, vulns[0].CvssScore)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2021-44228", vulns[0].Advisory)
+	assert.Equal(t, 9.0, vulns[1].CvssScore)
+	assert.Equal(t, "https://osv.dev/vulnerability/GHSA-7rjr-3q55-vv33", vulns[1].Advisory)
+
+	// last_affected包含边界版本，versions中列出的版本也受影响
+	assert.Len(t, db.Vulnerabilities("org.example", "lib", "1.2"), 1)
+	assert.Empty(t, db.Vulnerabilities("org.example", "lib", "1.2.1"))
+	lib := db.Vulnerabilities("org.example", "lib", "2.0-legacy")
+	if assert.Len(t, lib, 1) {
+		assert.Equal(t, "MEDIUM", lib[0].Severity)
+	}
+
+	rating := db.Rating("org.example", "lib", "1.0")
+	assert.Equal(t, 1, rating.VulnCount)
+	assert.Equal(t, "MEDIUM", rating.Severity)
+	assert.Equal(t, 4.0, rating.RiskScore)
+	assert.Equal(t, 6.0, rating.Score)
+
+	// 同一ID的较新记录替换旧记录
+	assert.NoError(t, db.AddOSV([]byte(`{"id": "GHSA-last-affected", "modified": "2025-01-01T00:00:00Z",
+		"affected": [{"package": {"ecosystem": "Maven", "name": "org.example:lib"},
+		"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.1"}]}]}]}`)))
+	assert.Equal(t, 3, db.Len())
+	assert.Empty(t, db.Vulnerabilities("org.example", "lib", "1.2"))
+
+	_, err = LoadVulnerabilityDB(t.TempDir())
+	assert.Error(t, err)
+}
+
+// TestCVSS3BaseScore 测试CVSS v3基础评分计算
+func TestCVSS3BaseScore(t *testing.T) {
+	tests := map[string]float64{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H": 10.0,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": 9.8,
+		"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N": 5.9,
+		"CVSS:3.0/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N": 5.5,
+		"CVSS:3.1/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N": 5.4,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N": 0,
+	}
+	for vector, expected := range tests {
+		score, err := cvss3BaseScore(vector)
+		assert.NoError(t, err, vector)
+		assert.Equal(t, expected, score, vector)
+	}
+
+	_, err := cvss3BaseScore("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	assert.Error(t, err)
+	_, err = cvss3BaseScore("CVSS:3.1/AV:N/AC:L")
+	assert.Error(t, err)
+}
+
+// TestSecurityWithVulnerabilityDB 测试基于漏洞数据库的安全查询
+func TestSecurityWithVulnerabilityDB(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewClient().GetSecurityRating(ctx, "org.apache.logging.log4j", "log4j-core", "2.14.1")
+	assert.True(t, errors.Is(err, ErrNoVulnerabilityDB))
+
+	db, err := LoadVulnerabilityDB(newVulnerabilityDBZip(t))
+	assert.NoError(t, err)
+
+	docs := versionDocs("org.apache.logging.log4j", "log4j-core", "2.17.1", "2.16.0", "2.15.0", "2.14.1")
+	client := newTestClient(newTestServer(t, nil, docs...).URL, WithVulnerabilityDB(db))
+
+	rating, err := client.GetSecurityRating(ctx, "org.apache.logging.log4j", "log4j-core", "2.14.1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rating.VulnCount)
+	assert.Equal(t, "CRITICAL", rating.Severity)
+	assert.Equal(t, 10.0, rating.RiskScore)
+	assert.Equal(t, 0.0, rating.Score)
+	assert.Len(t, rating.Advisories, 2)
+
+	details, err := client.GetVulnerabilityDetails(ctx, "org.apache.logging.log4j", "log4j-core", "2.16.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.16.0", details.Version)
+	assert.Empty(t, details.Vulnerabilities)
+
+	impacted, vuln, err := client.CheckCVEImpact(ctx, "cve-2021-44228", "org.apache.logging.log4j", "log4j-core", "2.14.1")
+	assert.NoError(t, err)
+	assert.True(t, impacted)
+	assert.Equal(t, "GHSA-jfh8-c2jp-5v3q", vuln.ID)
+	impacted, _, err = client.CheckCVEImpact(ctx, "GHSA-7rjr-3q55-vv33", "org.apache.logging.log4j", "log4j-core", "2.15.0")
+	assert.NoError(t, err)
+	assert.True(t, impacted)
+	impacted, _, err = client.CheckCVEImpact(ctx, "CVE-2021-44228", "org.apache.logging.log4j", "log4j-core", "2.15.0")
+	assert.NoError(t, err)
+	assert.False(t, impacted)
+
+	safe, err := client.GetSecurityRating(ctx, "org.apache.logging.log4j", "log4j-core", "2.16.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, safe.Score)
+	assert.Equal(t, 0.0, safe.RiskScore)
+	comparison, err := client.CompareVersionSecurity(ctx, "org.apache.logging.log4j", "log4j-core", "2.14.1", "2.16.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.16.0", comparison.SaferVersion)
+
+	recommended, err := client.GetRecommendedSecureVersion(ctx, "org.apache.logging.log4j", "log4j-core", "2.14.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.16.0", recommended)
+
+	// 仓库不可用时使用漏洞数据库中的修复版本
+	unreachable := newTestServer(t, nil)
+	unreachable.Close()
+	offline := newTestClient(unreachable.URL, WithVulnerabilityDB(db))
+	recommended, err = offline.GetRecommendedSecureVersion(ctx, "org.apache.logging.log4j", "log4j-core", "2.12.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.12.2", recommended)
+}
+
+// TestRecommendedSecureVersionSkipsPreRelease 测试推荐的安全版本优先选择正式版本
+func TestRecommendedSecureVersionSkipsPreRelease(t *testing.T) {
+	ctx := context.Background()
+	db := NewVulnerabilityDB()
+	assert.NoError(t, db.AddOSV([]byte(`{"id": "GHSA-pre-release", "modified": "2025-01-01T00:00:00Z",
+		"affected": [{"package": {"ecosystem": "Maven", "name": "org.example:lib"},
+		"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.0-beta1"}]}]}]}`)))
+
+	docs := versionDocs("org.example", "lib", "2.0", "2.0-rc1", "2.0-beta1", "1.0")
+	client := newTestClient(newTestServer(t, nil, docs...).URL, WithVulnerabilityDB(db))
+	recommended, err := client.GetRecommendedSecureVersion(ctx, "org.example", "lib", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0", recommended)
+
+	// 没有正式版本可选时才推荐预发布版本
+	docs = versionDocs("org.example", "lib", "2.0-rc1", "2.0-beta1", "1.0")
+	client = newTestClient(newTestServer(t, nil, docs...).URL, WithVulnerabilityDB(db))
+	recommended, err = client.GetRecommendedSecureVersion(ctx, "org.example", "lib", "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0-beta1", recommended)
+}
@@ -0,0 +1,237 @@
+package license
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Verdict 兼容性判断结论
+type Verdict string
+
+const (
+	VerdictCompatible   Verdict = "compatible"   // 兼容
+	VerdictIncompatible Verdict = "incompatible" // 不兼容
+	VerdictUnknown      Verdict = "unknown"      // 许可证无法识别或矩阵中没有对应规则
+)
+
+// verdictRank 结论的优劣顺序，OR取最优，AND取最差
+var verdictRank = map[Verdict]int{
+	VerdictIncompatible: 0,
+	VerdictUnknown:      1,
+	VerdictCompatible:   2,
+}
+
+// Rule 兼容性矩阵中的一条规则
+type Rule struct {
+	Compatible bool   `json:"compatible"`
+	Reason     string `json:"reason"`
+}
+
+// Matrix 数据驱动的许可证兼容性矩阵
+//
+// 矩阵回答的问题是"以inbound许可证发布的组件，能否用在以outbound许可证对外发布的作品中"，
+// 因此它是有方向的: MIT的组件可以用在GPL-3.0-only的项目中，反之则不行。
+//
+// 判断时先查找Overrides中inbound许可证ID到outbound许可证ID的规则，没有时再按
+// Categories中inbound类别到outbound类别的规则判断。带有WITH例外的许可证不使用Overrides，
+// 直接按例外调整后的类别判断。
+//
+// 矩阵可以用JSON描述，格式与内置矩阵相同:
+//
+//	{
+//	  "categories": {"copyleft": {"proprietary": {"compatible": false, "reason": "..."}}},
+//	  "overrides": {"Apache-2.0": {"GPL-2.0-only": {"compatible": false, "reason": "..."}}}
+//	}
+type Matrix struct {
+	Categories map[Category]map[Category]Rule `json:"categories"`
+	Overrides  map[string]map[string]Rule     `json:"overrides"`
+}
+
+// Result 一次兼容性判断的结果
+type Result struct {
+	Inbound  string  `json:"inbound"`
+	Outbound string  `json:"outbound"`
+	Verdict  Verdict `json:"verdict"`
+	Reason   string  `json:"reason"`
+}
+
+// Compatible 判断结论是否为兼容
+func (r *Result) Compatible() bool {
+	return r.Verdict == VerdictCompatible
+}
+
+//go:embed compatibility.json
+var compatibilityJSON []byte
+
+var (
+	defaultMatrixOnce sync.Once
+	defaultMatrix     *Matrix
+)
+
+// DefaultMatrix 返回内置的兼容性矩阵
+//
+// 内置矩阵覆盖了常见的类别组合，以及Apache-2.0与GPL-2.0、CDDL/EPL与GPL、GPL不同版本之间
+// 等广为人知的特例。它只用于自动化筛查，不能替代法律意见。返回的矩阵为共享实例，不应修改。
+func DefaultMatrix() *Matrix {
+	defaultMatrixOnce.Do(func() {
+		m, err := ParseMatrix(compatibilityJSON)
+		if err != nil {
+			panic("license: 内置兼容性矩阵格式错误: " + err.Error())
+		}
+		defaultMatrix = m
+	})
+	return defaultMatrix
+}
+
+// ParseMatrix 从JSON数据解析兼容性矩阵
+//
+// 规则中的许可证ID会按内置列表规范化，因此"gpl-2.0"与"GPL-2.0-only"等价。
+func ParseMatrix(data []byte) (*Matrix, error) {
+	var m Matrix
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析许可证兼容性矩阵失败: %w", err)
+	}
+
+	overrides := make(map[string]map[string]Rule, len(m.Overrides))
+	for inbound, rules := range m.Overrides {
+		normalized := make(map[string]Rule, len(rules))
+		for outbound, rule := range rules {
+			normalized[canonicalID(outbound)] = rule
+		}
+		overrides[canonicalID(inbound)] = normalized
+	}
+	m.Overrides = overrides
+	return &m, nil
+}
+
+// LoadMatrix 从Reader读取JSON格式的兼容性矩阵
+func LoadMatrix(r io.Reader) (*Matrix, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取许可证兼容性矩阵失败: %w", err)
+	}
+	return ParseMatrix(data)
+}
+
+// Check 判断inbound许可证表达式能否用在以outbound许可证表达式发布的作品中
+//
+// 两侧都可以是复合表达式: OR表示可以任选其一，取最好的结论；AND表示需要同时满足，取最差的结论。
+// 相同的许可证总是兼容的。结果中的Inbound和Outbound是决定结论的那一对单个许可证。
+//
+// 参数:
+//   - inbound: 组件的许可证表达式，如"CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0"
+//   - outbound: 作品对外发布的许可证表达式，如"Apache-2.0"；闭源发布时使用ProprietaryID
+//
+// 返回:
+//   - Result: 判断结果，表达式无法解析时结论为VerdictUnknown
+//
+// 使用示例:
+//
+//	result := license.DefaultMatrix().Check("LGPL-2.1-only", license.ProprietaryID)
+//	fmt.Println(result.Verdict, result.Reason)
+func (m *Matrix) Check(inbound, outbound string) Result {
+	in, err := Parse(inbound)
+	if err != nil {
+		return Result{Inbound: inbound, Outbound: outbound, Verdict: VerdictUnknown, Reason: err.Error()}
+	}
+	out, err := Parse(outbound)
+	if err != nil {
+		return Result{Inbound: inbound, Outbound: outbound, Verdict: VerdictUnknown, Reason: err.Error()}
+	}
+	return m.CheckExpression(in, out)
+}
+
+// CheckExpression 与Check相同，但接受已解析的表达式
+func (m *Matrix) CheckExpression(inbound, outbound Expression) Result {
+	switch out := outbound.(type) {
+	case *Or:
+		return best(m.CheckExpression(inbound, out.Left), m.CheckExpression(inbound, out.Right))
+	case *And:
+		return worst(m.CheckExpression(inbound, out.Left), m.CheckExpression(inbound, out.Right))
+	}
+	switch in := inbound.(type) {
+	case *Or:
+		return best(m.CheckExpression(in.Left, outbound), m.CheckExpression(in.Right, outbound))
+	case *And:
+		return worst(m.CheckExpression(in.Left, outbound), m.CheckExpression(in.Right, outbound))
+	}
+	return m.checkSimple(inbound.(*Simple), outbound.(*Simple))
+}
+
+// checkSimple 判断单个许可证之间的兼容性
+func (m *Matrix) checkSimple(in, out *Simple) Result {
+	result := Result{Inbound: in.String(), Outbound: out.String()}
+
+	if strings.EqualFold(in.ID, out.ID) && in.Exception == out.Exception {
+		result.Verdict = VerdictCompatible
+		result.Reason = "相同的许可证总是兼容的"
+		return result
+	}
+
+	if in.Exception == "" {
+		if rule, ok := m.Overrides[in.ID][out.ID]; ok {
+			return applyRule(result, rule)
+		}
+	}
+
+	inCategory, outCategory := in.Category(), out.Category()
+	if inCategory == CategoryUnknown {
+		result.Verdict = VerdictUnknown
+		result.Reason = fmt.Sprintf("无法识别许可证%s", in)
+		return result
+	}
+	if outCategory == CategoryUnknown {
+		result.Verdict = VerdictUnknown
+		result.Reason = fmt.Sprintf("无法识别许可证%s", out)
+		return result
+	}
+
+	rule, ok := m.Categories[inCategory][outCategory]
+	if !ok {
+		result.Verdict = VerdictUnknown
+		result.Reason = fmt.Sprintf("兼容性矩阵中没有%s到%s的规则", inCategory, outCategory)
+		return result
+	}
+	return applyRule(result, rule)
+}
+
+// applyRule 将矩阵规则转换为判断结果
+func applyRule(result Result, rule Rule) Result {
+	if rule.Compatible {
+		result.Verdict = VerdictCompatible
+	} else {
+		result.Verdict = VerdictIncompatible
+	}
+	result.Reason = rule.Reason
+	return result
+}
+
+// best 返回两个结果中较好的一个，结论相同时返回第一个
+func best(a, b Result) Result {
+	if verdictRank[b.Verdict] > verdictRank[a.Verdict] {
+		return b
+	}
+	return a
+}
+
+// worst 返回两个结果中较差的一个，结论相同时返回第一个
+func worst(a, b Result) Result {
+	if verdictRank[b.Verdict] < verdictRank[a.Verdict] {
+		return b
+	}
+	return a
+}
+
+// canonicalID 将许可证ID规范为内置列表中的写法，未知ID保持原样
+func canonicalID(id string) string {
+	if expr, err := Parse(id); err == nil {
+		if simple, ok := expr.(*Simple); ok && simple.Exception == "" && !simple.OrLater {
+			return simple.ID
+		}
+	}
+	return id
+}
@@ -0,0 +1,359 @@
+package license
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidExpression SPDX许可证表达式格式错误
+var ErrInvalidExpression = errors.New("无效的SPDX许可证表达式")
+
+// Expression SPDX许可证表达式
+//
+// 表达式由Simple、And和Or三种节点组成，对应SPDX规范附录D中的语法:
+//   - Simple: 单个许可证，可以带"+"（或更高版本）和WITH例外，如"GPL-2.0-only WITH Classpath-exception-2.0"
+//   - And: 需要同时遵守的两个许可证，如"LGPL-2.1-only AND MIT"
+//   - Or: 可以任选其一的两个许可证，如"Apache-2.0 OR MIT"
+type Expression interface {
+	// String 返回表达式的规范SPDX写法
+	String() string
+
+	// Known 判断表达式中的许可证和例外是否都在内置许可证列表中
+	Known() bool
+
+	// Category 返回表达式的类别，规则见CategoryOf
+	Category() Category
+
+	// Licenses 按出现顺序返回表达式中的全部单个许可证
+	Licenses() []*Simple
+}
+
+// Simple 单个许可证
+type Simple struct {
+	// ID 许可证标识符，已知许可证使用列表中的规范大小写
+	ID string
+
+	// OrLater 是否带有"+"后缀，表示该版本或更高版本
+	OrLater bool
+
+	// Exception WITH子句中的例外标识符，没有时为空
+	Exception string
+}
+
+// And 需要同时遵守的两个许可证表达式
+type And struct {
+	Left, Right Expression
+}
+
+// Or 可以任选其一的两个许可证表达式
+type Or struct {
+	Left, Right Expression
+}
+
+// String 返回许可证的SPDX写法
+func (s *Simple) String() string {
+	str := s.ID
+	if s.OrLater {
+		str += "+"
+	}
+	if s.Exception != "" {
+		str += " WITH " + s.Exception
+	}
+	return str
+}
+
+// Known 判断许可证及其例外是否在内置许可证列表中
+func (s *Simple) Known() bool {
+	if _, ok := getList().byID[strings.ToLower(s.ID)]; !ok {
+		return false
+	}
+	if s.Exception != "" {
+		if _, ok := LookupException(s.Exception); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Category 返回许可证的类别，例外的类别比许可证本身宽松时使用例外的类别
+func (s *Simple) Category() Category {
+	if strings.EqualFold(s.ID, ProprietaryID) {
+		return CategoryProprietary
+	}
+	l, ok := getList().byID[strings.ToLower(s.ID)]
+	if !ok {
+		return CategoryUnknown
+	}
+	category := l.Category
+	if s.Exception != "" {
+		if e, ok := LookupException(s.Exception); ok && restrictiveness[e.Category] < restrictiveness[category] {
+			category = e.Category
+		}
+	}
+	return category
+}
+
+// Licenses 返回只包含自身的列表
+func (s *Simple) Licenses() []*Simple {
+	return []*Simple{s}
+}
+
+// String 返回表达式的SPDX写法，OR子表达式会加上括号
+func (a *And) String() string {
+	return wrapOr(a.Left) + " AND " + wrapOr(a.Right)
+}
+
+// Known 判断两侧的表达式是否都已知
+func (a *And) Known() bool {
+	return a.Left.Known() && a.Right.Known()
+}
+
+// Category 返回两侧中限制最多的类别
+func (a *And) Category() Category {
+	left, right := a.Left.Category(), a.Right.Category()
+	if restrictiveness[left] >= restrictiveness[right] {
+		return left
+	}
+	return right
+}
+
+// Licenses 返回两侧的全部许可证
+func (a *And) Licenses() []*Simple {
+	return append(a.Left.Licenses(), a.Right.Licenses()...)
+}
+
+// String 返回表达式的SPDX写法
+func (o *Or) String() string {
+	return o.Left.String() + " OR " + o.Right.String()
+}
+
+// Known 判断两侧的表达式是否都已知
+func (o *Or) Known() bool {
+	return o.Left.Known() && o.Right.Known()
+}
+
+// Category 返回两侧中限制最少的类别
+func (o *Or) Category() Category {
+	left, right := o.Left.Category(), o.Right.Category()
+	if restrictiveness[left] <= restrictiveness[right] {
+		return left
+	}
+	return right
+}
+
+// Licenses 返回两侧的全部许可证
+func (o *Or) Licenses() []*Simple {
+	return append(o.Left.Licenses(), o.Right.Licenses()...)
+}
+
+// wrapOr 在AND的操作数是OR表达式时加上括号
+func wrapOr(e Expression) string {
+	if _, ok := e.(*Or); ok {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// idPattern 许可证和例外标识符允许的字符，DocumentRef形式中可以包含冒号
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9.\-:]+$`)
+
+// idStringInvalid SPDX idstring（SPDXRef和LicenseRef的后缀）中不允许出现的字符
+var idStringInvalid = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// SanitizeIDString 将任意文本转换为SPDX idstring
+//
+// 不允许的字符序列替换为"-"，并去掉首尾的"-"；文本中没有可用字符时返回空字符串。
+func SanitizeIDString(s string) string {
+	return strings.Trim(idStringInvalid.ReplaceAllString(s, "-"), "-")
+}
+
+// LicenseRef 为无法识别的许可证名称生成LicenseRef标识符
+//
+// 名称中没有可用字符时（如"许可证"）返回LicenseRef-unknown-<n>，
+// 由调用方提供在所在文档或表达式中唯一的序号n。
+//
+// 示例:
+//
+//	license.LicenseRef("Company License v1", 1) // "LicenseRef-Company-License-v1"
+//	license.LicenseRef("许可证", 2)              // "LicenseRef-unknown-2"
+func LicenseRef(name string, n int) string {
+	if ref := SanitizeIDString(name); ref != "" {
+		return "LicenseRef-" + ref
+	}
+	return fmt.Sprintf("LicenseRef-unknown-%d", n)
+}
+
+// Parse 解析SPDX许可证表达式
+//
+// 运算符优先级从高到低为WITH、AND、OR，可以使用括号改变优先级，运算符接受全大写或全小写。
+// 已知的许可证标识符会被规范为列表中的大小写，已废弃的标识符会被替换，
+// 例如"GPL-2.0+"解析为"GPL-2.0-or-later"，"gpl-2.0"解析为"GPL-2.0-only"。
+// 不在内置列表中的标识符（包括LicenseRef-*）保持原样，可以通过Known判断。
+//
+// 参数:
+//   - expression: SPDX许可证表达式，如"Apache-2.0 OR MIT"
+//
+// 返回:
+//   - Expression: 解析后的表达式
+//   - error: 语法错误时返回包装了ErrInvalidExpression的错误
+//
+// 使用示例:
+//
+//	expr, err := license.Parse("(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, l := range expr.Licenses() {
+//	    fmt.Println(l.ID, l.Exception)
+//	}
+func Parse(expression string) (Expression, error) {
+	p := &parser{tokens: tokenize(expression)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("%w: 表达式不能为空", ErrInvalidExpression)
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: 多余的内容 %q", ErrInvalidExpression, p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// tokenize 将表达式拆分为括号和单词
+func tokenize(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(expression)
+}
+
+// parser 递归下降的表达式解析器
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// operator 判断当前单词是否为指定的运算符
+func (p *parser) operator(op string) bool {
+	token := p.peek()
+	return token == op || token == strings.ToLower(op)
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.operator("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.operator("AND") {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("%w: 表达式意外结束", ErrInvalidExpression)
+	case token == "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("%w: 缺少右括号", ErrInvalidExpression)
+		}
+		p.pos++
+		return expr, nil
+	case token == ")" || p.operator("AND") || p.operator("OR") || p.operator("WITH"):
+		return nil, fmt.Errorf("%w: 意外的 %q", ErrInvalidExpression, token)
+	}
+
+	p.pos++
+	expr, err := newSimple(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.operator("WITH") {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || !idPattern.MatchString(exception) {
+			return nil, fmt.Errorf("%w: WITH后缺少例外标识符", ErrInvalidExpression)
+		}
+		p.pos++
+		simple, ok := expr.(*Simple)
+		if !ok || simple.Exception != "" {
+			return nil, fmt.Errorf("%w: %q不能再附加例外", ErrInvalidExpression, token)
+		}
+		if e, ok := LookupException(exception); ok {
+			exception = e.ID
+		}
+		simple.Exception = exception
+	}
+	return expr, nil
+}
+
+// newSimple 解析单个许可证标识符，已废弃的标识符会被替换为对应的表达式
+func newSimple(token string) (Expression, error) {
+	ll := getList()
+	if replacement, ok := ll.deprecated[strings.ToLower(token)]; ok {
+		return Parse(replacement)
+	}
+
+	id := token
+	orLater := strings.HasSuffix(id, "+")
+	if orLater {
+		id = strings.TrimSuffix(id, "+")
+	}
+	if !idPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: 无效的许可证标识符 %q", ErrInvalidExpression, token)
+	}
+
+	if replacement, ok := ll.deprecated[strings.ToLower(id)]; ok {
+		expr, err := Parse(replacement)
+		if err != nil {
+			return nil, err
+		}
+		if simple, ok := expr.(*Simple); ok {
+			simple.OrLater = simple.OrLater || orLater
+		}
+		return expr, nil
+	}
+	if l, ok := ll.byID[strings.ToLower(id)]; ok {
+		id = l.ID
+	}
+	return &Simple{ID: id, OrLater: orLater}, nil
+}
@@ -0,0 +1,264 @@
+package license
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Category 许可证类别
+type Category string
+
+const (
+	CategoryPermissive      Category = "permissive"       // 宽松许可证，如MIT、Apache-2.0
+	CategoryWeakCopyleft    Category = "weak-copyleft"    // 弱传染性许可证，如LGPL、MPL、EPL
+	CategoryCopyleft        Category = "copyleft"         // 传染性许可证，如GPL
+	CategoryNetworkCopyleft Category = "network-copyleft" // 网络传染性许可证，如AGPL
+	CategoryNonCommercial   Category = "non-commercial"   // 非商业许可证
+	CategoryProprietary     Category = "proprietary"      // 闭源专有许可证，只用作对外发布的许可证
+	CategoryUnknown         Category = "unknown"          // 无法识别的许可证
+)
+
+// ProprietaryID 表示闭源发布的许可证标识符，可作为对外发布的许可证传给Matrix.Check
+const ProprietaryID = "LicenseRef-Proprietary"
+
+// restrictiveness 类别的限制程度，用于在组合表达式中选出最严格或最宽松的类别
+var restrictiveness = map[Category]int{
+	CategoryPermissive:      0,
+	CategoryWeakCopyleft:    1,
+	CategoryCopyleft:        2,
+	CategoryNetworkCopyleft: 3,
+	CategoryNonCommercial:   4,
+	CategoryProprietary:     5,
+	CategoryUnknown:         6,
+}
+
+// License 许可证列表中的一个许可证或许可证例外
+type License struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Category    Category `json:"category"`
+	OSIApproved bool     `json:"osiApproved"`
+	URLs        []string `json:"urls,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// URL 返回许可证在SPDX许可证列表中的页面地址
+func (l *License) URL() string {
+	return "https://spdx.org/licenses/" + l.ID + ".html"
+}
+
+//go:embed licenses.json
+var licensesJSON []byte
+
+// licenseList 内置许可证列表的索引
+type licenseList struct {
+	byID       map[string]*License // 小写ID -> 许可证
+	exceptions map[string]*License // 小写ID -> 许可证例外
+	deprecated map[string]string   // 小写的已废弃ID -> 替代的表达式
+	byName     map[string]string   // 规范化名称 -> 表达式
+	byURL      map[string]string   // 规范化URL -> 表达式
+}
+
+var (
+	listOnce sync.Once
+	list     *licenseList
+)
+
+// getList 返回内置许可证列表，首次调用时解析
+func getList() *licenseList {
+	listOnce.Do(func() {
+		var data struct {
+			Licenses    []*License        `json:"licenses"`
+			Exceptions  []*License        `json:"exceptions"`
+			Deprecated  map[string]string `json:"deprecated"`
+			Expressions map[string]string `json:"expressions"`
+		}
+		if err := json.Unmarshal(licensesJSON, &data); err != nil {
+			panic("license: 内置许可证列表格式错误: " + err.Error())
+		}
+
+		list = &licenseList{
+			byID:       make(map[string]*License),
+			exceptions: make(map[string]*License),
+			deprecated: make(map[string]string),
+			byName:     make(map[string]string),
+			byURL:      make(map[string]string),
+		}
+		for _, l := range data.Licenses {
+			list.byID[strings.ToLower(l.ID)] = l
+			list.addNames(l.ID, l)
+			list.addURLs(l.ID, l.URLs)
+			list.addURLs(l.ID, []string{
+				"https://opensource.org/licenses/" + l.ID,
+				"https://spdx.org/licenses/" + l.ID,
+				"https://spdx.org/licenses/" + l.ID + ".html",
+			})
+		}
+		for _, e := range data.Exceptions {
+			list.exceptions[strings.ToLower(e.ID)] = e
+		}
+		for id, expr := range data.Deprecated {
+			list.deprecated[strings.ToLower(id)] = expr
+			list.byName[normalizeName(id)] = expr
+		}
+		for name, expr := range data.Expressions {
+			list.byName[normalizeName(name)] = expr
+		}
+	})
+	return list
+}
+
+func (ll *licenseList) addNames(expr string, l *License) {
+	for _, name := range append([]string{l.ID, l.Name}, l.Aliases...) {
+		key := normalizeName(name)
+		if _, exists := ll.byName[key]; !exists {
+			ll.byName[key] = expr
+		}
+	}
+}
+
+func (ll *licenseList) addURLs(expr string, urls []string) {
+	for _, u := range urls {
+		key := normalizeURL(u)
+		if _, exists := ll.byURL[key]; !exists {
+			ll.byURL[key] = expr
+		}
+	}
+}
+
+// Lookup 按SPDX标识符查找许可证，忽略大小写，已废弃的标识符返回替代后的许可证
+//
+// 参数:
+//   - id: SPDX许可证标识符，如"Apache-2.0"、"GPL-2.0"
+//
+// 返回:
+//   - *License: 许可证信息
+//   - bool: 标识符不在内置列表中时返回false
+func Lookup(id string) (*License, bool) {
+	ll := getList()
+	key := strings.ToLower(strings.TrimSpace(id))
+	if replacement, ok := ll.deprecated[key]; ok {
+		key = strings.ToLower(replacement)
+	}
+	l, ok := ll.byID[key]
+	return l, ok
+}
+
+// LookupException 按SPDX标识符查找许可证例外，忽略大小写
+func LookupException(id string) (*License, bool) {
+	l, ok := getList().exceptions[strings.ToLower(strings.TrimSpace(id))]
+	return l, ok
+}
+
+// Normalize 将POM中自由书写的许可证名称和URL规范化为SPDX表达式
+//
+// 依次尝试以下方式，返回第一个成功的结果:
+//  1. 名称本身就是只包含已知许可证的SPDX表达式，如"Apache-2.0"、"MIT OR GPL-2.0+"
+//  2. 名称与内置列表中的许可证名称或别名匹配，忽略大小写、标点、"The"前缀、"Version"等措辞差异
+//  3. URL与内置列表中的许可证URL匹配，忽略协议、www前缀、结尾斜杠和.txt/.html等扩展名
+//
+// 大多数情况下结果是单个许可证标识符，但像"CDDL + GPLv2 with classpath exception"这样的
+// 双许可声明会得到"CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0"这样的复合表达式。
+//
+// 参数:
+//   - name: POM中<license><name>的内容
+//   - licenseURL: POM中<license><url>的内容
+//
+// 返回:
+//   - string: 规范化后的SPDX表达式
+//   - bool: 无法识别时返回false
+//
+// 使用示例:
+//
+//	expr, ok := license.Normalize("The Apache Software License, Version 2.0", "")
+//	fmt.Println(expr, ok) // Apache-2.0 true
+func Normalize(name, licenseURL string) (string, bool) {
+	ll := getList()
+
+	name = strings.TrimSpace(name)
+	if name != "" {
+		if expr, err := Parse(name); err == nil && expr.Known() {
+			return expr.String(), true
+		}
+		if expr, ok := ll.byName[normalizeName(name)]; ok {
+			return expr, true
+		}
+	}
+
+	licenseURL = strings.TrimSpace(licenseURL)
+	if licenseURL != "" {
+		if expr, ok := ll.byURL[normalizeURL(licenseURL)]; ok {
+			return expr, true
+		}
+	}
+
+	return "", false
+}
+
+// CategoryOf 返回许可证表达式的类别
+//
+// OR连接的许可证可以任选其一，因此取限制最少的类别；AND连接的许可证需要同时遵守，
+// 因此取限制最多的类别。带有WITH例外的许可证使用例外声明的类别（例如GPL加上
+// Classpath-exception-2.0后按弱传染性许可证处理）。无法解析的表达式返回CategoryUnknown。
+func CategoryOf(expression string) Category {
+	expr, err := Parse(expression)
+	if err != nil {
+		return CategoryUnknown
+	}
+	return expr.Category()
+}
+
+// nameNoise 规范化名称时去掉的措辞
+var nameNoise = map[string]bool{
+	"the":      true,
+	"version":  true,
+	"v":        true,
+	"ver":      true,
+	"license":  true,
+	"licensed": true,
+	"under":    true,
+}
+
+// nameSeparator 名称中的非字母数字字符
+var nameSeparator = regexp.MustCompile(`[^a-z0-9+]+`)
+
+// versionPrefix 紧贴在版本号前面的"v"，如"v2.0"
+var versionPrefix = regexp.MustCompile(`\bv(\d)`)
+
+// normalizeName 将许可证名称规范化为用于匹配的键
+func normalizeName(name string) string {
+	s := strings.ToLower(name)
+	s = strings.ReplaceAll(s, "licence", "license")
+	s = versionPrefix.ReplaceAllString(s, "$1")
+	fields := strings.Fields(nameSeparator.ReplaceAllString(s, " "))
+
+	kept := fields[:0]
+	for _, f := range fields {
+		if !nameNoise[f] {
+			kept = append(kept, f)
+		}
+	}
+	// "2.0"与"2"视为相同的版本
+	for len(kept) > 1 && kept[len(kept)-1] == "0" {
+		kept = kept[:len(kept)-1]
+	}
+	return strings.Join(kept, " ")
+}
+
+// normalizeURL 将许可证URL规范化为用于匹配的键
+func normalizeURL(raw string) string {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if u, err := url.Parse(s); err == nil && u.Host != "" {
+		s = u.Host + u.Path
+	}
+	s = strings.TrimPrefix(s, "www.")
+	s = strings.TrimRight(s, "/")
+	for _, ext := range []string{".txt", ".html", ".htm", ".php", ".md"} {
+		s = strings.TrimSuffix(s, ext)
+	}
+	return s
+}
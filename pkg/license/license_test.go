@@ -0,0 +1,173 @@
+package license
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalize 测试POM许可证名称和URL的规范化
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"The Apache Software License, Version 2.0", "", "Apache-2.0"},
+		{"Apache License, Version 2.0", "", "Apache-2.0"},
+		{"Apache 2", "", "Apache-2.0"},
+		{"apache-2.0", "", "Apache-2.0"},
+		{"The MIT License", "", "MIT"},
+		{"GNU Lesser General Public License, Version 2.1", "", "LGPL-2.1-only"},
+		{"GNU Lesser General Public Licence v3.0", "", "LGPL-3.0-only"},
+		{"GPL-2.0", "", "GPL-2.0-only"},
+		{"GPLv2+", "", "GPL-2.0-or-later"},
+		{"Eclipse Public License - v 1.0", "", "EPL-1.0"},
+		{"Eclipse Distribution License - v 1.0", "", "BSD-3-Clause"},
+		{"CDDL + GPLv2 with classpath exception", "", "CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0"},
+		{"MIT OR Apache-2.0", "", "MIT OR Apache-2.0"},
+		{"", "http://www.apache.org/licenses/LICENSE-2.0.txt", "Apache-2.0"},
+		{"Some Custom Name", "https://opensource.org/licenses/MIT/", "MIT"},
+		{"Company License", "https://spdx.org/licenses/EPL-2.0.html", "EPL-2.0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name+tc.url, func(t *testing.T) {
+			expr, ok := Normalize(tc.name, tc.url)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, expr)
+		})
+	}
+
+	_, ok := Normalize("Proprietary Company License", "https://example.com/license")
+	assert.False(t, ok)
+	_, ok = Normalize("GPL", "")
+	assert.False(t, ok, "不带版本的GPL无法确定具体许可证")
+}
+
+// TestParse 测试SPDX表达式的解析和规范输出
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Apache-2.0", "Apache-2.0"},
+		{"apache-2.0 or mit", "Apache-2.0 OR MIT"},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{"GPL-2.0+", "GPL-2.0-or-later"},
+		{"Apache-2.0+", "Apache-2.0+"},
+		{"(MIT OR Apache-2.0) AND BSD-3-Clause", "(MIT OR Apache-2.0) AND BSD-3-Clause"},
+		{"MIT OR Apache-2.0 AND BSD-3-Clause", "MIT OR Apache-2.0 AND BSD-3-Clause"},
+		{"((MIT))", "MIT"},
+		{"LicenseRef-Custom OR MIT", "LicenseRef-Custom OR MIT"},
+	}
+	for _, tc := range tests {
+		expr, err := Parse(tc.input)
+		if assert.NoError(t, err, tc.input) {
+			assert.Equal(t, tc.expected, expr.String())
+		}
+	}
+
+	// AND的优先级高于OR
+	expr, err := Parse("MIT OR Apache-2.0 AND BSD-3-Clause")
+	assert.NoError(t, err)
+	or, ok := expr.(*Or)
+	if assert.True(t, ok) {
+		_, ok = or.Right.(*And)
+		assert.True(t, ok)
+	}
+
+	expr, err = Parse("CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0")
+	assert.NoError(t, err)
+	licenses := expr.Licenses()
+	assert.Len(t, licenses, 2)
+	assert.Equal(t, "Classpath-exception-2.0", licenses[1].Exception)
+	assert.True(t, expr.Known())
+
+	expr, err = Parse("LicenseRef-Custom")
+	assert.NoError(t, err)
+	assert.False(t, expr.Known())
+
+	for _, invalid := range []string{"", "MIT OR", "(MIT", "MIT)", "MIT Apache-2.0", "AND MIT", "MIT WITH", "MIT/Apache"} {
+		_, err := Parse(invalid)
+		assert.True(t, errors.Is(err, ErrInvalidExpression), "表达式%q应当解析失败", invalid)
+	}
+}
+
+// TestLicenseRef 测试无法识别的许可证名称转换为LicenseRef
+func TestLicenseRef(t *testing.T) {
+	assert.Equal(t, "Company-License-v1.0", SanitizeIDString(" Company License (v1.0) "))
+	assert.Equal(t, "", SanitizeIDString("许可证"))
+	assert.Equal(t, "LicenseRef-Company-License-v1", LicenseRef("Company License v1", 3))
+	assert.Equal(t, "LicenseRef-unknown-3", LicenseRef("许可证", 3))
+
+	expr, err := Parse(LicenseRef("许可证", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "LicenseRef-unknown-1", expr.String())
+}
+
+// TestCategoryOf 测试许可证类别判断
+func TestCategoryOf(t *testing.T) {
+	assert.Equal(t, CategoryPermissive, CategoryOf("MIT"))
+	assert.Equal(t, CategoryWeakCopyleft, CategoryOf("LGPL-2.1"), "LGPL不能被当作GPL")
+	assert.Equal(t, CategoryCopyleft, CategoryOf("GPL-3.0"))
+	assert.Equal(t, CategoryNetworkCopyleft, CategoryOf("AGPL-3.0-only"))
+	assert.Equal(t, CategoryWeakCopyleft, CategoryOf("GPL-2.0-only WITH Classpath-exception-2.0"))
+	assert.Equal(t, CategoryPermissive, CategoryOf("GPL-3.0-only OR MIT"))
+	assert.Equal(t, CategoryCopyleft, CategoryOf("GPL-3.0-only AND MIT"))
+	assert.Equal(t, CategoryUnknown, CategoryOf("LicenseRef-Custom"))
+	assert.Equal(t, CategoryUnknown, CategoryOf("not a license"))
+}
+
+// TestMatrixCheck 测试基于兼容性矩阵的判断
+func TestMatrixCheck(t *testing.T) {
+	m := DefaultMatrix()
+	tests := []struct {
+		inbound  string
+		outbound string
+		expected Verdict
+	}{
+		{"MIT", "Apache-2.0", VerdictCompatible},
+		{"MIT", "GPL-3.0-only", VerdictCompatible},
+		{"Apache-2.0", "GPL-2.0-only", VerdictIncompatible},
+		{"Apache-2.0", "GPL-3.0-or-later", VerdictCompatible},
+		{"GPL-3.0-only", "Apache-2.0", VerdictIncompatible},
+		{"CDDL-1.0", "GPL-3.0-only", VerdictIncompatible},
+		{"GPL-2.0-only", "GPL-3.0-only", VerdictIncompatible},
+		{"GPL-2.0-or-later", "GPL-3.0-only", VerdictCompatible},
+		{"LGPL-2.1-only", ProprietaryID, VerdictCompatible},
+		{"GPL-2.0-only", ProprietaryID, VerdictIncompatible},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", ProprietaryID, VerdictCompatible},
+		{"CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0", "Apache-2.0", VerdictCompatible},
+		{"MIT AND GPL-3.0-only", "Apache-2.0", VerdictIncompatible},
+		{"GPL-3.0-only", "Apache-2.0 OR GPL-3.0-or-later", VerdictCompatible},
+		{"LicenseRef-Custom", "Apache-2.0", VerdictUnknown},
+		{"LicenseRef-Custom", "LicenseRef-Custom", VerdictCompatible},
+	}
+	for _, tc := range tests {
+		result := m.Check(tc.inbound, tc.outbound)
+		assert.Equal(t, tc.expected, result.Verdict, "%s -> %s: %s", tc.inbound, tc.outbound, result.Reason)
+		assert.NotEmpty(t, result.Reason)
+	}
+}
+
+// TestLoadMatrix 测试加载自定义兼容性矩阵
+func TestLoadMatrix(t *testing.T) {
+	m, err := LoadMatrix(strings.NewReader(`{
+		"categories": {"permissive": {"permissive": {"compatible": true, "reason": "ok"}}},
+		"overrides": {"mit": {"apache-2.0": {"compatible": false, "reason": "内部政策禁止"}}}
+	}`))
+	assert.NoError(t, err)
+
+	result := m.Check("MIT", "Apache-2.0")
+	assert.Equal(t, VerdictIncompatible, result.Verdict)
+	assert.Equal(t, "内部政策禁止", result.Reason)
+
+	assert.Equal(t, VerdictCompatible, m.Check("BSD-3-Clause", "Apache-2.0").Verdict)
+	assert.Equal(t, VerdictUnknown, m.Check("GPL-3.0-only", "Apache-2.0").Verdict)
+
+	_, err = LoadMatrix(strings.NewReader("{"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,374 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/license"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/version"
+)
+
+// Source 策略评估所需的数据来源
+//
+// *api.Client实现了该接口。
+type Source interface {
+	GetComponentLicenses(ctx context.Context, groupId, artifactId, version string) ([]response.LicenseInfo, error)
+	GetSecurityRating(ctx context.Context, groupId, artifactId, version string) (*response.SecurityRating, error)
+	ListVersions(ctx context.Context, groupId, artifactId string, limit int) ([]*response.Version, error)
+}
+
+// Violation 制品违反的一条规则
+type Violation struct {
+	RuleID     string   `json:"ruleId"`
+	RuleType   RuleType `json:"ruleType"`
+	Action     Action   `json:"action"`
+	Message    string   `json:"message"`
+	Suggestion string   `json:"suggestion,omitempty"` // 建议升级到的版本
+}
+
+// ArtifactResult 单个制品的评估结果
+//
+// Errors记录评估过程中无法获取的数据（例如许可证或安全评分查询失败、未配置漏洞数据库），
+// 依赖这些数据的规则不会产生违规，但制品会被视为未通过评估，见Report.Passed。
+type ArtifactResult struct {
+	Artifact   response.ArtifactRef `json:"artifact"`
+	Violations []*Violation         `json:"violations,omitempty"`
+	Errors     []string             `json:"errors,omitempty"`
+}
+
+// Denied 判断制品是否违反了任意一条deny规则
+func (r *ArtifactResult) Denied() bool {
+	for _, v := range r.Violations {
+		if v.Action == ActionDeny {
+			return true
+		}
+	}
+	return false
+}
+
+// Incomplete 判断是否有规则因为数据获取失败而没有完成评估
+func (r *ArtifactResult) Incomplete() bool {
+	return len(r.Errors) > 0
+}
+
+// Report 一次策略评估的报告，Results与传入的制品顺序一致
+type Report struct {
+	Policy  string            `json:"policy,omitempty"`
+	Results []*ArtifactResult `json:"results"`
+}
+
+// Passed 判断是否所有制品都通过了评估
+//
+// 制品违反deny规则或有规则没有完成评估（Errors不为空）时都视为未通过，
+// 避免数据来源不可用时策略检查被静默跳过。
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if result.Denied() || result.Incomplete() {
+			return false
+		}
+	}
+	return true
+}
+
+// Engine 策略评估引擎
+type Engine struct {
+	source Source
+	policy *Policy
+	now    func() time.Time
+}
+
+// NewEngine 创建策略评估引擎
+//
+// 参数:
+//   - source: 数据来源，通常为*api.Client
+//   - policy: 已通过Validate校验的策略
+func NewEngine(source Source, policy *Policy) *Engine {
+	return &Engine{source: source, policy: policy, now: time.Now}
+}
+
+// SetNow 设置计算制品发布时长时使用的当前时间，主要用于测试
+func (e *Engine) SetNow(now func() time.Time) *Engine {
+	e.now = now
+	return e
+}
+
+// artifactData 评估单个制品时按需获取并缓存的数据
+type artifactData struct {
+	ref response.ArtifactRef
+
+	licensesLoaded bool
+	licenses       []response.LicenseInfo
+	licensesErr    error
+
+	ratingLoaded bool
+	rating       *response.SecurityRating
+	ratingErr    error
+}
+
+// EvaluatePolicy 对一组制品评估策略
+//
+// 每个制品依次按策略中的规则检查，只有适用范围内的规则才会被评估；许可证、安全评分和版本列表
+// 只在有规则需要时才会查询，同一groupId:artifactId的版本列表在一次评估中只查询一次。
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制请求的超时和取消
+//   - refs: 要评估的制品列表
+//
+// 返回:
+//   - *Report: 每个制品的违规情况
+//   - error: 上下文被取消时返回错误，数据查询失败记录在ArtifactResult.Errors中，并使Report.Passed返回false
+//
+// 使用示例:
+//
+//	p, err := policy.Load("policy.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	report, err := policy.NewEngine(client, p).EvaluatePolicy(ctx, refs)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, result := range report.Results {
+//	    for _, v := range result.Violations {
+//	        fmt.Printf("%s:%s:%s [%s] %s\n", result.Artifact.GroupId, result.Artifact.ArtifactId,
+//	            result.Artifact.Version, v.RuleID, v.Message)
+//	    }
+//	}
+//	if !report.Passed() {
+//	    os.Exit(1)
+//	}
+func (e *Engine) EvaluatePolicy(ctx context.Context, refs []response.ArtifactRef) (*Report, error) {
+	report := &Report{Policy: e.policy.Name, Results: make([]*ArtifactResult, 0, len(refs))}
+	versionCache := make(map[string][]*response.Version)
+	versionErrs := make(map[string]error)
+
+	listVersions := func(groupId, artifactId string) ([]*response.Version, error) {
+		key := groupId + ":" + artifactId
+		if versions, ok := versionCache[key]; ok {
+			return versions, nil
+		}
+		if err, ok := versionErrs[key]; ok {
+			return nil, err
+		}
+		versions, err := e.source.ListVersions(ctx, groupId, artifactId, 0)
+		if err != nil {
+			versionErrs[key] = err
+			return nil, err
+		}
+		versionCache[key] = versions
+		return versions, nil
+	}
+
+	for _, ref := range refs {
+		result := &ArtifactResult{Artifact: ref}
+		data := &artifactData{ref: ref}
+
+		for _, rule := range e.policy.Rules {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if !rule.Matches(ref.GroupId, ref.ArtifactId) {
+				continue
+			}
+
+			var violation *Violation
+			var err error
+			switch rule.Type {
+			case RuleDenyArtifact:
+				violation = &Violation{Message: fmt.Sprintf("制品%s:%s被禁止使用", ref.GroupId, ref.ArtifactId)}
+			case RuleDenyLicenseCategory, RuleDenyLicense:
+				violation, err = e.checkLicenses(ctx, rule, data)
+			case RuleMinVersion:
+				violation, err = e.checkMinVersion(rule, ref, listVersions)
+			case RuleMaxAge:
+				violation, err = e.checkMaxAge(rule, ref, listVersions)
+			case RuleMaxSeverity:
+				violation, err = e.checkSeverity(ctx, rule, data)
+			}
+
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				result.Errors = appendUnique(result.Errors, fmt.Sprintf("规则%s: %v", rule.ID, err))
+				continue
+			}
+			if violation != nil {
+				violation.RuleID = rule.ID
+				violation.RuleType = rule.Type
+				violation.Action = rule.Action
+				if rule.Description != "" {
+					violation.Message = rule.Description + ": " + violation.Message
+				}
+				result.Violations = append(result.Violations, violation)
+			}
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// checkLicenses 检查许可证类规则
+//
+// 制品声明多个许可证时视为可以任选其一，只有全部许可证都被禁止时才算违规。
+func (e *Engine) checkLicenses(ctx context.Context, rule *Rule, data *artifactData) (*Violation, error) {
+	if !data.licensesLoaded {
+		ref := data.ref
+		data.licenses, data.licensesErr = e.source.GetComponentLicenses(ctx, ref.GroupId, ref.ArtifactId, ref.Version)
+		data.licensesLoaded = true
+	}
+	if data.licensesErr != nil {
+		return nil, data.licensesErr
+	}
+
+	if len(data.licenses) == 0 {
+		if rule.Type == RuleDenyLicenseCategory && containsCategory(rule.Categories, license.CategoryUnknown) {
+			return &Violation{Message: "制品没有声明许可证"}, nil
+		}
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(data.licenses))
+	for _, info := range data.licenses {
+		var denied bool
+		if rule.Type == RuleDenyLicenseCategory {
+			denied = containsCategory(rule.Categories, license.CategoryOf(info.Type))
+		} else {
+			denied = rule.licenseDenied(info.Type)
+		}
+		if !denied {
+			return nil, nil
+		}
+		names = append(names, info.Type)
+	}
+
+	if rule.Type == RuleDenyLicenseCategory {
+		return &Violation{Message: fmt.Sprintf("许可证%s属于被禁止的类别", strings.Join(names, ", "))}, nil
+	}
+	return &Violation{Message: fmt.Sprintf("许可证%s被禁止使用", strings.Join(names, ", "))}, nil
+}
+
+// licenseDenied 判断许可证表达式是否无法在不使用被禁止许可证的前提下满足
+func (r *Rule) licenseDenied(expression string) bool {
+	expr, err := license.Parse(expression)
+	if err != nil {
+		return r.deniedLicenses[strings.ToLower(expression)]
+	}
+	return r.expressionDenied(expr)
+}
+
+func (r *Rule) expressionDenied(expr license.Expression) bool {
+	switch e := expr.(type) {
+	case *license.Or:
+		return r.expressionDenied(e.Left) && r.expressionDenied(e.Right)
+	case *license.And:
+		return r.expressionDenied(e.Left) || r.expressionDenied(e.Right)
+	case *license.Simple:
+		return r.deniedLicenses[strings.ToLower(e.String())] || r.deniedLicenses[strings.ToLower(e.ID)]
+	}
+	return false
+}
+
+// checkMinVersion 检查最低版本规则，违规时建议满足要求的最低正式版本
+func (e *Engine) checkMinVersion(rule *Rule, ref response.ArtifactRef, listVersions func(string, string) ([]*response.Version, error)) (*Violation, error) {
+	minVersion := version.Parse(rule.MinVersion)
+	if version.Parse(ref.Version).Compare(minVersion) >= 0 {
+		return nil, nil
+	}
+
+	violation := &Violation{Message: fmt.Sprintf("版本%s低于要求的最低版本%s", ref.Version, rule.MinVersion)}
+	if versions, err := listVersions(ref.GroupId, ref.ArtifactId); err == nil {
+		var suggestion *version.Version
+		for _, v := range versions {
+			candidate := version.Parse(v.Version)
+			if candidate.IsPreRelease() || candidate.Compare(minVersion) < 0 {
+				continue
+			}
+			if suggestion == nil || candidate.Compare(suggestion) < 0 {
+				suggestion = candidate
+			}
+		}
+		if suggestion != nil {
+			violation.Suggestion = suggestion.String()
+		}
+	}
+	return violation, nil
+}
+
+// checkMaxAge 检查发布时长规则，违规时建议最新的正式版本
+func (e *Engine) checkMaxAge(rule *Rule, ref response.ArtifactRef, listVersions func(string, string) ([]*response.Version, error)) (*Violation, error) {
+	versions, err := listVersions(ref.GroupId, ref.ArtifactId)
+	if err != nil {
+		return nil, err
+	}
+
+	var released int64
+	var releases []string
+	for _, v := range versions {
+		if v.Version == ref.Version {
+			released = v.Timestamp
+		}
+		if !version.Parse(v.Version).IsPreRelease() {
+			releases = append(releases, v.Version)
+		}
+	}
+	if released == 0 {
+		return nil, fmt.Errorf("找不到版本%s的发布时间", ref.Version)
+	}
+
+	releasedAt := time.UnixMilli(released)
+	if e.now().Sub(releasedAt) <= rule.maxAge {
+		return nil, nil
+	}
+
+	violation := &Violation{Message: fmt.Sprintf("版本%s发布于%s，超过了允许的%s", ref.Version, releasedAt.Format("2006-01-02"), rule.MaxAge)}
+	if latest := version.Max(releases); latest != "" && latest != ref.Version {
+		violation.Suggestion = latest
+	}
+	return violation, nil
+}
+
+// checkSeverity 检查漏洞严重性规则
+func (e *Engine) checkSeverity(ctx context.Context, rule *Rule, data *artifactData) (*Violation, error) {
+	if !data.ratingLoaded {
+		ref := data.ref
+		data.rating, data.ratingErr = e.source.GetSecurityRating(ctx, ref.GroupId, ref.ArtifactId, ref.Version)
+		data.ratingLoaded = true
+	}
+	if data.ratingErr != nil {
+		return nil, data.ratingErr
+	}
+	if data.rating == nil {
+		return nil, nil
+	}
+
+	severity := strings.ToUpper(data.rating.Severity)
+	if severityRank[severity] <= severityRank[rule.MaxSeverity] {
+		return nil, nil
+	}
+	return &Violation{Message: fmt.Sprintf("存在%d个已知漏洞，最高严重性%s超过了允许的%s", data.rating.VulnCount, severity, rule.MaxSeverity)}, nil
+}
+
+func containsCategory(categories []license.Category, category license.Category) bool {
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,301 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/license"
+)
+
+// ErrInvalidPolicy 策略定义错误
+var ErrInvalidPolicy = errors.New("无效的策略定义")
+
+// RuleType 规则类型
+type RuleType string
+
+const (
+	// RuleDenyLicenseCategory 禁止指定类别的许可证，参数为Categories
+	RuleDenyLicenseCategory RuleType = "deny-license-category"
+	// RuleDenyLicense 禁止指定的许可证，参数为Licenses
+	RuleDenyLicense RuleType = "deny-license"
+	// RuleDenyArtifact 禁止GroupId/ArtifactId匹配的制品，没有额外参数
+	RuleDenyArtifact RuleType = "deny-artifact"
+	// RuleMinVersion 要求匹配的制品不低于指定版本，参数为MinVersion
+	RuleMinVersion RuleType = "min-version"
+	// RuleMaxAge 禁止发布时间早于指定时长的制品版本，参数为MaxAge
+	RuleMaxAge RuleType = "max-age"
+	// RuleMaxSeverity 禁止已知漏洞的最高严重性超过指定级别，参数为MaxSeverity
+	RuleMaxSeverity RuleType = "max-severity"
+)
+
+// Action 违反规则时的处理方式
+type Action string
+
+const (
+	// ActionDeny 违反规则时不通过，默认值
+	ActionDeny Action = "deny"
+	// ActionWarn 违反规则时只给出警告
+	ActionWarn Action = "warn"
+)
+
+// severityRank 漏洞严重性的排序
+var severityRank = map[string]int{
+	"NONE":     0,
+	"LOW":      1,
+	"MEDIUM":   2,
+	"HIGH":     3,
+	"CRITICAL": 4,
+}
+
+// Policy 一组规则组成的策略
+//
+// 策略可以用YAML或JSON描述:
+//
+//	name: ci-gate
+//	rules:
+//	  - id: no-copyleft
+//	    type: deny-license-category
+//	    categories: [copyleft, network-copyleft]
+//	  - id: no-evil
+//	    type: deny-artifact
+//	    groupId: "com.evil.*"
+//	  - id: log4shell
+//	    type: min-version
+//	    groupId: org.apache.logging.log4j
+//	    artifactId: log4j-core
+//	    minVersion: 2.17.1
+//	  - id: stale
+//	    type: max-age
+//	    maxAge: 5y
+//	    action: warn
+//	  - id: severity-cap
+//	    type: max-severity
+//	    maxSeverity: HIGH
+type Policy struct {
+	Name  string  `json:"name,omitempty" yaml:"name,omitempty"`
+	Rules []*Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule 策略中的一条规则
+//
+// GroupId和ArtifactId是规则的适用范围，支持"*"通配符（如"com.evil.*"），为空时适用于全部制品。
+// 对于deny-artifact规则，适用范围本身就是被禁止的制品。
+type Rule struct {
+	ID          string   `json:"id" yaml:"id"`
+	Type        RuleType `json:"type" yaml:"type"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Action      Action   `json:"action,omitempty" yaml:"action,omitempty"`
+	GroupId     string   `json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactId  string   `json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+
+	// Categories deny-license-category规则禁止的许可证类别，可以包含"unknown"以禁止无法识别的许可证
+	Categories []license.Category `json:"categories,omitempty" yaml:"categories,omitempty"`
+
+	// Licenses deny-license规则禁止的许可证，可以使用SPDX标识符或POM中常见的许可证名称
+	Licenses []string `json:"licenses,omitempty" yaml:"licenses,omitempty"`
+
+	// MinVersion min-version规则要求的最低版本，按Maven版本规则比较
+	MinVersion string `json:"minVersion,omitempty" yaml:"minVersion,omitempty"`
+
+	// MaxAge max-age规则允许的最长发布时长，如"90d"、"6mo"、"3y"，也接受Go的时长写法如"720h"
+	MaxAge string `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+
+	// MaxSeverity max-severity规则允许的最高漏洞严重性: NONE、LOW、MEDIUM、HIGH或CRITICAL
+	MaxSeverity string `json:"maxSeverity,omitempty" yaml:"maxSeverity,omitempty"`
+
+	groupPattern    *regexp.Regexp
+	artifactPattern *regexp.Regexp
+	maxAge          time.Duration
+	deniedLicenses  map[string]bool
+}
+
+// Parse 解析YAML或JSON格式的策略定义
+//
+// 以"{"开头的内容按JSON解析，其余按YAML解析。两种格式都不允许出现未知字段，
+// 以免拼写错误的规则被静默忽略。解析后会调用Validate校验规则。
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&p); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(trimmed))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&p); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Load 从文件加载策略定义，文件格式见Parse
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取策略文件失败: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return p, nil
+}
+
+// Validate 校验策略中的规则并预先编译匹配模式
+//
+// 通过代码构造的策略在交给Engine之前应当调用一次该方法；Parse和Load会自动调用。
+func (p *Policy) Validate() error {
+	ids := make(map[string]bool, len(p.Rules))
+	for i, rule := range p.Rules {
+		if rule == nil {
+			return fmt.Errorf("%w: 第%d条规则为空", ErrInvalidPolicy, i+1)
+		}
+		if rule.ID == "" {
+			return fmt.Errorf("%w: 第%d条规则缺少id", ErrInvalidPolicy, i+1)
+		}
+		if ids[rule.ID] {
+			return fmt.Errorf("%w: 规则id %q 重复", ErrInvalidPolicy, rule.ID)
+		}
+		ids[rule.ID] = true
+		if err := rule.compile(); err != nil {
+			return fmt.Errorf("%w: 规则%s: %v", ErrInvalidPolicy, rule.ID, err)
+		}
+	}
+	return nil
+}
+
+// compile 校验规则参数并预先计算匹配所需的数据
+func (r *Rule) compile() error {
+	switch r.Action {
+	case "":
+		r.Action = ActionDeny
+	case ActionDeny, ActionWarn:
+	default:
+		return fmt.Errorf("未知的action %q", r.Action)
+	}
+
+	r.groupPattern = compilePattern(r.GroupId)
+	r.artifactPattern = compilePattern(r.ArtifactId)
+
+	switch r.Type {
+	case RuleDenyLicenseCategory:
+		if len(r.Categories) == 0 {
+			return errors.New("缺少categories")
+		}
+	case RuleDenyLicense:
+		if len(r.Licenses) == 0 {
+			return errors.New("缺少licenses")
+		}
+		r.deniedLicenses = make(map[string]bool, len(r.Licenses))
+		for _, name := range r.Licenses {
+			if expr, ok := license.Normalize(name, ""); ok {
+				name = expr
+			}
+			r.deniedLicenses[strings.ToLower(name)] = true
+		}
+	case RuleDenyArtifact:
+		if r.GroupId == "" && r.ArtifactId == "" {
+			return errors.New("缺少groupId或artifactId")
+		}
+	case RuleMinVersion:
+		if r.MinVersion == "" {
+			return errors.New("缺少minVersion")
+		}
+		if r.GroupId == "" && r.ArtifactId == "" {
+			return errors.New("缺少groupId或artifactId")
+		}
+	case RuleMaxAge:
+		maxAge, err := parseAge(r.MaxAge)
+		if err != nil {
+			return err
+		}
+		r.maxAge = maxAge
+	case RuleMaxSeverity:
+		r.MaxSeverity = strings.ToUpper(strings.TrimSpace(r.MaxSeverity))
+		if _, ok := severityRank[r.MaxSeverity]; !ok {
+			return fmt.Errorf("无效的maxSeverity %q", r.MaxSeverity)
+		}
+	default:
+		return fmt.Errorf("未知的规则类型 %q", r.Type)
+	}
+	return nil
+}
+
+// Matches 判断制品坐标是否在规则的适用范围内
+func (r *Rule) Matches(groupId, artifactId string) bool {
+	if r.groupPattern != nil && !r.groupPattern.MatchString(groupId) {
+		return false
+	}
+	if r.artifactPattern != nil && !r.artifactPattern.MatchString(artifactId) {
+		return false
+	}
+	return true
+}
+
+// compilePattern 将带"*"通配符的模式编译为正则表达式，空模式返回nil
+func compilePattern(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// parseAge 解析时长，支持d(天)、w(周)、mo或M(月，按30天计)、y(年，按365天计)后缀和Go的时长写法
+//
+// Go的时长写法中m表示分钟，为避免把"6m"误当作6分钟或6个月，整数加m的写法会被拒绝，
+// 月份请写作"6mo"，分钟请写作"1h30m"或"90m0s"这类带其他单位的Go时长。
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("缺少maxAge")
+	}
+
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"mo", 30 * 24 * time.Hour},
+		{"M", 30 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+		{"w", 7 * 24 * time.Hour},
+		{"y", 365 * 24 * time.Hour},
+	}
+	for _, u := range units {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, u.suffix)); err == nil && n > 0 {
+			return time.Duration(n) * u.unit, nil
+		}
+		break
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "m")); err == nil && strings.HasSuffix(s, "m") {
+		return 0, fmt.Errorf("maxAge %q有歧义，月份请写作\"%dmo\"", s, n)
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("无效的maxAge %q", s)
+}
@@ -0,0 +1,240 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/response"
+)
+
+// fakeSource 内存中的测试数据来源
+type fakeSource struct {
+	licenses     map[string][]response.LicenseInfo
+	ratings      map[string]*response.SecurityRating
+	versions     map[string][]*response.Version
+	versionCalls int
+}
+
+func (f *fakeSource) GetComponentLicenses(_ context.Context, groupId, artifactId, version string) ([]response.LicenseInfo, error) {
+	licenses, ok := f.licenses[groupId+":"+artifactId+":"+version]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return licenses, nil
+}
+
+func (f *fakeSource) GetSecurityRating(_ context.Context, groupId, artifactId, version string) (*response.SecurityRating, error) {
+	rating, ok := f.ratings[groupId+":"+artifactId+":"+version]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return rating, nil
+}
+
+func (f *fakeSource) ListVersions(_ context.Context, groupId, artifactId string, _ int) ([]*response.Version, error) {
+	f.versionCalls++
+	return f.versions[groupId+":"+artifactId], nil
+}
+
+const testPolicyYAML = `
+name: ci-gate
+rules:
+  - id: no-copyleft
+    type: deny-license-category
+    categories: [copyleft, network-copyleft]
+  - id: no-evil
+    type: deny-artifact
+    groupId: "com.evil.*"
+  - id: log4shell
+    type: min-version
+    groupId: org.apache.logging.log4j
+    artifactId: log4j-core
+    minVersion: 2.17.1
+  - id: stale
+    type: max-age
+    groupId: org.apache.logging.log4j
+    maxAge: 2y
+    action: warn
+  - id: severity-cap
+    type: max-severity
+    maxSeverity: high
+`
+
+func newTestSource() *fakeSource {
+	day := int64(24 * 3600 * 1000)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	return &fakeSource{
+		licenses: map[string][]response.LicenseInfo{
+			"org.apache.logging.log4j:log4j-core:2.14.1": {{Type: "Apache-2.0"}},
+			"org.example:gpl-lib:1.0":                    {{Type: "GPL-3.0-only"}},
+			"org.example:dual:1.0":                       {{Type: "GPL-3.0-only"}, {Type: "MIT"}},
+			"com.evil.tools:backdoor:1.0":                {{Type: "MIT"}},
+		},
+		ratings: map[string]*response.SecurityRating{
+			"org.apache.logging.log4j:log4j-core:2.14.1": {VulnCount: 3, Severity: "CRITICAL"},
+			"org.example:gpl-lib:1.0":                    {Severity: "NONE"},
+			"org.example:dual:1.0":                       {Severity: "HIGH"},
+			"com.evil.tools:backdoor:1.0":                {Severity: "LOW"},
+		},
+		versions: map[string][]*response.Version{
+			"org.apache.logging.log4j:log4j-core": {
+				{Version: "2.14.1", Timestamp: now - 1000*day},
+				{Version: "2.17.0", Timestamp: now - 750*day},
+				{Version: "2.17.1", Timestamp: now - 740*day},
+				{Version: "2.18.0-rc1", Timestamp: now - 600*day},
+				{Version: "2.20.0", Timestamp: now - 300*day},
+			},
+		},
+	}
+}
+
+// TestParsePolicy 测试YAML和JSON策略的解析与校验
+func TestParsePolicy(t *testing.T) {
+	p, err := Parse([]byte(testPolicyYAML))
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-gate", p.Name)
+	assert.Len(t, p.Rules, 5)
+	assert.Equal(t, ActionDeny, p.Rules[0].Action)
+	assert.Equal(t, ActionWarn, p.Rules[3].Action)
+	assert.Equal(t, "HIGH", p.Rules[4].MaxSeverity)
+	assert.True(t, p.Rules[1].Matches("com.evil.tools", "anything"))
+	assert.False(t, p.Rules[1].Matches("com.evilcorp", "anything"))
+
+	p, err = Parse([]byte(`{"rules":[{"id":"gpl","type":"deny-license","licenses":["GPLv3"]}]}`))
+	assert.NoError(t, err)
+	assert.True(t, p.Rules[0].licenseDenied("GPL-3.0-only"))
+	assert.False(t, p.Rules[0].licenseDenied("GPL-3.0-only OR MIT"))
+	assert.True(t, p.Rules[0].licenseDenied("GPL-3.0-only AND MIT"))
+
+	invalid := []string{
+		`rules: [{id: a, type: unknown}]`,
+		`rules: [{type: deny-artifact, groupId: x}]`,
+		`rules: [{id: a, type: deny-artifact, groupId: x}, {id: a, type: deny-artifact, groupId: y}]`,
+		`rules: [{id: a, type: deny-artifact}]`,
+		`rules: [{id: a, type: max-age, maxAge: soon}]`,
+		`rules: [{id: a, type: max-severity, maxSeverity: EXTREME}]`,
+		`rules: [{id: a, type: min-version, minVersion: "1.0"}]`,
+		`rules: [{id: a, type: deny-artifact, groupId: x, typo: true}]`,
+		`{"rules":[{"id":"a","type":"deny-artifact","groupId":"x","typo":true}]}`,
+	}
+	for _, data := range invalid {
+		_, err := Parse([]byte(data))
+		assert.True(t, errors.Is(err, ErrInvalidPolicy), "策略%q应当校验失败", data)
+	}
+}
+
+// TestParseAge 测试maxAge的解析，月份使用mo或M后缀，整数加m因与Go时长中的分钟冲突而被拒绝
+func TestParseAge(t *testing.T) {
+	valid := map[string]time.Duration{
+		"90d":   90 * 24 * time.Hour,
+		"2w":    14 * 24 * time.Hour,
+		"6mo":   180 * 24 * time.Hour,
+		"6M":    180 * 24 * time.Hour,
+		"3y":    3 * 365 * 24 * time.Hour,
+		"720h":  720 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	}
+	for s, expected := range valid {
+		d, err := parseAge(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, d, s)
+	}
+
+	for _, s := range []string{"", "6m", "90m", "0d", "-1mo", "mo", "soon"} {
+		_, err := parseAge(s)
+		assert.Error(t, err, s)
+	}
+	_, err := parseAge("6m")
+	assert.Contains(t, err.Error(), `"6mo"`)
+}
+
+// TestEvaluatePolicy 测试策略评估
+func TestEvaluatePolicy(t *testing.T) {
+	p, err := Parse([]byte(testPolicyYAML))
+	assert.NoError(t, err)
+
+	source := newTestSource()
+	engine := NewEngine(source, p).SetNow(func() time.Time {
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	refs := []response.ArtifactRef{
+		{GroupId: "org.apache.logging.log4j", ArtifactId: "log4j-core", Version: "2.14.1"},
+		{GroupId: "org.example", ArtifactId: "gpl-lib", Version: "1.0"},
+		{GroupId: "org.example", ArtifactId: "dual", Version: "1.0"},
+		{GroupId: "com.evil.tools", ArtifactId: "backdoor", Version: "1.0"},
+		{GroupId: "org.example", ArtifactId: "missing", Version: "1.0"},
+	}
+	report, err := engine.EvaluatePolicy(context.Background(), refs)
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-gate", report.Policy)
+	assert.Len(t, report.Results, len(refs))
+	assert.False(t, report.Passed())
+
+	ruleIDs := func(result *ArtifactResult) []string {
+		var ids []string
+		for _, v := range result.Violations {
+			ids = append(ids, v.RuleID)
+		}
+		return ids
+	}
+
+	log4j := report.Results[0]
+	assert.Equal(t, []string{"log4shell", "stale", "severity-cap"}, ruleIDs(log4j))
+	assert.Equal(t, "2.17.1", log4j.Violations[0].Suggestion)
+	assert.Equal(t, ActionWarn, log4j.Violations[1].Action)
+	assert.Equal(t, "2.20.0", log4j.Violations[1].Suggestion)
+	assert.Equal(t, 1, source.versionCalls, "同一制品的版本列表只查询一次")
+
+	assert.Equal(t, []string{"no-copyleft"}, ruleIDs(report.Results[1]))
+	assert.Empty(t, report.Results[2].Violations, "双许可证中有可选的MIT")
+	assert.False(t, report.Results[2].Denied())
+	assert.Equal(t, []string{"no-evil"}, ruleIDs(report.Results[3]))
+
+	missing := report.Results[4]
+	assert.Empty(t, missing.Violations)
+	assert.Len(t, missing.Errors, 2)
+	assert.False(t, missing.Denied())
+	assert.True(t, missing.Incomplete())
+}
+
+// TestReportPassedWithErrors 测试有规则没有完成评估时报告不通过
+func TestReportPassedWithErrors(t *testing.T) {
+	p, err := Parse([]byte(`
+rules:
+  - id: severity-cap
+    type: max-severity
+    maxSeverity: high
+`))
+	assert.NoError(t, err)
+
+	source := newTestSource()
+	engine := NewEngine(source, p)
+	gpl := response.ArtifactRef{GroupId: "org.example", ArtifactId: "gpl-lib", Version: "1.0"}
+	report, err := engine.EvaluatePolicy(context.Background(), []response.ArtifactRef{gpl})
+	assert.NoError(t, err)
+	assert.True(t, report.Passed())
+
+	// 安全评分查询失败时不能视为通过
+	source.ratings = nil
+	report, err = engine.EvaluatePolicy(context.Background(), []response.ArtifactRef{gpl})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Results[0].Violations)
+	assert.True(t, report.Results[0].Incomplete())
+	assert.False(t, report.Passed())
+}
+
+// TestEvaluatePolicyCanceled 测试上下文取消
+func TestEvaluatePolicyCanceled(t *testing.T) {
+	p, err := Parse([]byte(testPolicyYAML))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewEngine(newTestSource(), p).EvaluatePolicy(ctx, []response.ArtifactRef{{GroupId: "g", ArtifactId: "a", Version: "1"}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,516 @@
+package pom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Maven依赖作用域
+const (
+	ScopeCompile  = "compile"
+	ScopeRuntime  = "runtime"
+	ScopeProvided = "provided"
+	ScopeTest     = "test"
+	ScopeSystem   = "system"
+	ScopeImport   = "import"
+)
+
+// 依赖节点被省略的原因
+const (
+	// OmittedDuplicate 同一制品的相同版本已经在更近的位置出现
+	OmittedDuplicate = "duplicate"
+	// OmittedConflict 同一制品的其他版本在更近的位置出现，按就近原则被淘汰
+	OmittedConflict = "conflict"
+	// OmittedCycle 制品已经出现在自身的祖先路径上，形成循环依赖
+	OmittedCycle = "cycle"
+)
+
+// defaultGraphConcurrency 解析依赖图时并发下载POM的默认数量
+const defaultGraphConcurrency = 4
+
+// GraphOptions 依赖图解析选项
+//
+// 字段说明:
+//   - Scopes: 根制品的直接依赖中需要纳入解析的作用域，为空时默认为compile和runtime
+//   - IncludeOptional: 是否纳入根制品直接声明的可选依赖；传递路径上的可选依赖始终会被剪除
+//   - MaxDepth: 最大解析深度，直接依赖的深度为1，0表示不限制
+//   - Concurrency: 同一层级并发下载POM的数量，0表示使用默认值4
+type GraphOptions struct {
+	Scopes          []string
+	IncludeOptional bool
+	MaxDepth        int
+	Concurrency     int
+}
+
+// DependencyNode 依赖图中的一个节点
+//
+// 每个节点对应依赖树中的一次出现。被就近原则淘汰、重复或形成循环的节点仍然保留在树中
+// 以便展示完整的依赖关系，但OmittedReason不为空且不会展开子节点。
+//
+// 字段说明:
+//   - Scope: 沿依赖路径传播之后的有效作用域
+//   - DeclaredScope: 在父节点POM中声明的作用域
+//   - Depth: 节点深度，根节点为0，直接依赖为1
+//   - OmittedReason: 节点被省略的原因，为空表示节点参与最终的类路径
+//   - OmittedFor: 因冲突被省略时，胜出节点的版本
+//   - Error: 节点的POM无法获取或解析时的错误信息，此时节点不会展开子节点
+type DependencyNode struct {
+	GroupId       string            `json:"groupId"`
+	ArtifactId    string            `json:"artifactId"`
+	Version       string            `json:"version"`
+	Type          string            `json:"type,omitempty"`
+	Classifier    string            `json:"classifier,omitempty"`
+	Scope         string            `json:"scope,omitempty"`
+	DeclaredScope string            `json:"declaredScope,omitempty"`
+	Optional      bool              `json:"optional,omitempty"`
+	Depth         int               `json:"depth"`
+	OmittedReason string            `json:"omittedReason,omitempty"`
+	OmittedFor    string            `json:"omittedFor,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	Children      []*DependencyNode `json:"children,omitempty"`
+
+	Parent *DependencyNode `json:"-"`
+
+	exclusions      []*Exclusion
+	scopeCandidates []string
+}
+
+// DependencyGraph 传递依赖图
+//
+// Root为被解析的制品本身，Cycles记录解析过程中发现的循环依赖路径，
+// 每条路径以groupId:artifactId:version形式从根节点开始列出。
+type DependencyGraph struct {
+	Root   *DependencyNode `json:"root"`
+	Cycles [][]string      `json:"cycles,omitempty"`
+
+	resolver *Resolver
+}
+
+// Resolver 返回构建该依赖图的解析器，其中缓存了解析过程中获取的POM
+//
+// 依赖图不是由ResolveGraph创建（如从JSON反序列化）时返回nil。
+func (g *DependencyGraph) Resolver() *Resolver {
+	return g.resolver
+}
+
+// Coordinate 返回节点的groupId:artifactId:version坐标
+func (n *DependencyNode) Coordinate() string {
+	return gavKey(n.GroupId, n.ArtifactId, n.Version)
+}
+
+// ConflictKey 返回用于版本仲裁的键，同一键下的节点只会有一个胜出
+func (n *DependencyNode) ConflictKey() string {
+	nodeType := n.Type
+	if nodeType == "" {
+		nodeType = "jar"
+	}
+	return fmt.Sprintf("%s:%s:%s:%s", n.GroupId, n.ArtifactId, nodeType, n.Classifier)
+}
+
+// IsResolved 判断节点是否参与最终的类路径
+func (n *DependencyNode) IsResolved() bool {
+	return n.OmittedReason == ""
+}
+
+// Path 返回从根节点到当前节点的坐标路径
+func (n *DependencyNode) Path() []string {
+	path := make([]string, 0, n.Depth+1)
+	for node := n; node != nil; node = node.Parent {
+		path = append(path, node.Coordinate())
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Walk 以深度优先顺序遍历依赖图中的所有节点（包括被省略的节点）
+//
+// 当fn返回false时不再遍历该节点的子节点。
+func (g *DependencyGraph) Walk(fn func(node *DependencyNode) bool) {
+	var walk func(node *DependencyNode)
+	walk = func(node *DependencyNode) {
+		if !fn(node) {
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	if g.Root != nil {
+		walk(g.Root)
+	}
+}
+
+// HasCycles 判断依赖图中是否存在循环依赖
+func (g *DependencyGraph) HasCycles() bool {
+	return len(g.Cycles) > 0
+}
+
+// Classpath 返回经过版本仲裁后的扁平化依赖列表
+//
+// 结果按广度优先顺序排列，不包含根节点和被省略的节点。可以通过scopes参数筛选作用域，
+// 例如Classpath("compile", "runtime")得到运行时类路径；不传参数时返回全部节点。
+//
+// 参数:
+//   - scopes: 需要保留的作用域，为空表示不筛选
+//
+// 返回:
+//   - []*DependencyNode: 参与类路径的依赖节点
+func (g *DependencyGraph) Classpath(scopes ...string) []*DependencyNode {
+	result := make([]*DependencyNode, 0)
+	if g.Root == nil {
+		return result
+	}
+
+	allowed := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		allowed[scope] = true
+	}
+
+	queue := append([]*DependencyNode(nil), g.Root.Children...)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if !node.IsResolved() {
+			continue
+		}
+		if len(allowed) == 0 || allowed[node.Scope] {
+			result = append(result, node)
+		}
+		queue = append(queue, node.Children...)
+	}
+	return result
+}
+
+// ResolveGraph 解析指定制品的传递依赖图
+//
+// 该方法遵循Maven的依赖解析规则:
+//   - 就近原则：同一制品出现多次时，深度最小的声明胜出，深度相同时先声明者胜出
+//   - 作用域传播：compile/runtime/provided/test按照Maven的传播表向下传递，
+//     传递路径上的provided、test和system依赖会被剪除
+//   - 可选依赖：只有根制品直接声明的可选依赖会被保留（需开启IncludeOptional）
+//   - 排除项：路径上任意节点声明的<exclusions>都会作用于其全部后代
+//   - 依赖管理：根制品有效模型中的dependencyManagement会覆盖传递依赖的版本和作用域
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制POM下载的超时和取消
+//   - groupId: 根制品的组ID
+//   - artifactId: 根制品ID
+//   - version: 根制品版本
+//   - opts: 解析选项，传入nil时使用默认值
+//
+// 返回:
+//   - *DependencyGraph: 依赖图，无法获取POM的传递依赖会记录在对应节点的Error字段中
+//   - error: 仅当根制品的POM无法解析时返回错误
+func (r *Resolver) ResolveGraph(ctx context.Context, groupId, artifactId, version string, opts *GraphOptions) (*DependencyGraph, error) {
+	if opts == nil {
+		opts = &GraphOptions{}
+	}
+
+	rootProject, err := r.resolve(ctx, groupId, artifactId, version, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &DependencyNode{
+		GroupId:    rootProject.GroupId,
+		ArtifactId: rootProject.ArtifactId,
+		Version:    rootProject.Version,
+		Type:       rootProject.GetPackaging(),
+	}
+	graph := &DependencyGraph{Root: root, resolver: r}
+
+	scopes := opts.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{ScopeCompile, ScopeRuntime}
+	}
+	allowedScopes := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		allowedScopes[scope] = true
+	}
+
+	managed := make(map[string]*Dependency)
+	if rootProject.DependencyManagement != nil {
+		for _, dep := range rootProject.DependencyManagement.Dependencies {
+			if _, exists := managed[dep.ManagementKey()]; !exists {
+				managed[dep.ManagementKey()] = dep
+			}
+		}
+	}
+
+	level := make([]*DependencyNode, 0)
+	for _, dep := range rootProject.Dependencies {
+		if !allowedScopes[dep.GetScope()] {
+			continue
+		}
+		if dep.IsOptional() && !opts.IncludeOptional {
+			continue
+		}
+		node := newDependencyNode(dep, root, dep.GetScope())
+		root.Children = append(root.Children, node)
+		level = append(level, node)
+	}
+
+	winners := make(map[string]*DependencyNode)
+	for depth := 1; len(level) > 0; depth++ {
+		expand := make([]*DependencyNode, 0, len(level))
+		for _, node := range level {
+			if cycle := findCycle(node); cycle {
+				node.OmittedReason = OmittedCycle
+				graph.Cycles = append(graph.Cycles, node.Path())
+				continue
+			}
+
+			key := node.ConflictKey()
+			if winner, exists := winners[key]; exists {
+				if winner.Version == node.Version {
+					node.OmittedReason = OmittedDuplicate
+				} else {
+					node.OmittedReason = OmittedConflict
+					node.OmittedFor = winner.Version
+				}
+				winner.scopeCandidates = append(winner.scopeCandidates, node.Scope)
+				continue
+			}
+			winners[key] = node
+
+			if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+				continue
+			}
+			expand = append(expand, node)
+		}
+
+		projects := r.prefetch(ctx, expand, opts.Concurrency)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		next := make([]*DependencyNode, 0)
+		for i, node := range expand {
+			project := projects[i]
+			if project.err != nil {
+				node.Error = project.err.Error()
+				continue
+			}
+
+			for _, dep := range project.project.Dependencies {
+				if dep.IsOptional() {
+					continue
+				}
+				if isExcluded(node, dep) {
+					continue
+				}
+
+				dep = dep.Clone()
+				if m, ok := managed[dep.ManagementKey()]; ok {
+					if m.Version != "" {
+						dep.Version = m.Version
+					}
+					if m.Scope != "" {
+						dep.Scope = m.Scope
+					}
+				}
+
+				scope, ok := propagateScope(node.Scope, dep.GetScope())
+				if !ok {
+					continue
+				}
+
+				child := newDependencyNode(dep, node, scope)
+				node.Children = append(node.Children, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	normalizeScopes(root)
+
+	return graph, nil
+}
+
+// projectResult 预取POM的结果
+type projectResult struct {
+	project *Project
+	err     error
+}
+
+// prefetch 并发获取一组节点的有效POM，结果顺序与nodes一致
+func (r *Resolver) prefetch(ctx context.Context, nodes []*DependencyNode, concurrency int) []projectResult {
+	if concurrency <= 0 {
+		concurrency = defaultGraphConcurrency
+	}
+
+	results := make([]projectResult, len(nodes))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node *DependencyNode) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if node.Version == "" {
+				results[i].err = fmt.Errorf("无法确定 %s:%s 的版本", node.GroupId, node.ArtifactId)
+				return
+			}
+			project, err := r.resolve(ctx, node.GroupId, node.ArtifactId, node.Version, nil)
+			results[i] = projectResult{project: project, err: err}
+		}(i, node)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// newDependencyNode 根据依赖声明创建依赖节点，并继承父节点路径上的排除项
+func newDependencyNode(dep *Dependency, parent *DependencyNode, scope string) *DependencyNode {
+	node := &DependencyNode{
+		GroupId:       dep.GroupId,
+		ArtifactId:    dep.ArtifactId,
+		Version:       dep.Version,
+		Type:          dep.GetType(),
+		Classifier:    dep.Classifier,
+		Scope:         scope,
+		DeclaredScope: dep.GetScope(),
+		Optional:      dep.IsOptional(),
+		Depth:         parent.Depth + 1,
+		Parent:        parent,
+	}
+	node.exclusions = append(node.exclusions, parent.exclusions...)
+	node.exclusions = append(node.exclusions, dep.Exclusions...)
+	return node
+}
+
+// isExcluded 判断依赖是否被节点路径上的排除项排除
+func isExcluded(node *DependencyNode, dep *Dependency) bool {
+	for _, exclusion := range node.exclusions {
+		if (exclusion.GroupId == "*" || exclusion.GroupId == dep.GroupId) &&
+			(exclusion.ArtifactId == "*" || exclusion.ArtifactId == dep.ArtifactId) {
+			return true
+		}
+	}
+	return false
+}
+
+// findCycle 判断节点的groupId:artifactId是否已经出现在其祖先路径上
+func findCycle(node *DependencyNode) bool {
+	for ancestor := node.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		if ancestor.GroupId == node.GroupId && ancestor.ArtifactId == node.ArtifactId {
+			return true
+		}
+	}
+	return false
+}
+
+// propagateScope 按照Maven的作用域传播规则计算传递依赖的有效作用域
+//
+// 返回的布尔值为false表示该依赖不会被传递（provided、test和system依赖只对声明者可见）。
+func propagateScope(parentScope, scope string) (string, bool) {
+	switch scope {
+	case ScopeProvided, ScopeTest, ScopeSystem, ScopeImport:
+		return "", false
+	}
+
+	switch parentScope {
+	case ScopeRuntime:
+		return ScopeRuntime, true
+	case ScopeProvided:
+		return ScopeProvided, true
+	case ScopeTest:
+		return ScopeTest, true
+	default:
+		return scope, true
+	}
+}
+
+// scopeRank 作用域的宽度排序，数值越小可见范围越宽
+var scopeRank = map[string]int{
+	ScopeCompile:  0,
+	ScopeRuntime:  1,
+	ScopeProvided: 2,
+	ScopeSystem:   3,
+	ScopeTest:     4,
+}
+
+// widestScope 返回一组作用域中可见范围最宽的一个
+func widestScope(scopes ...string) string {
+	widest := ""
+	for _, scope := range scopes {
+		if scope == "" {
+			continue
+		}
+		if widest == "" || scopeRank[scope] < scopeRank[widest] {
+			widest = scope
+		}
+	}
+	return widest
+}
+
+// normalizeScopes 自顶向下重新计算传递依赖的作用域
+//
+// 当同一制品在多条路径上以不同作用域出现时，Maven会把胜出节点的作用域提升为其中最宽的一个
+// （直接依赖除外，直接依赖始终保留声明的作用域），随后其子孙节点的作用域也需要随之更新。
+func normalizeScopes(root *DependencyNode) {
+	var visit func(node *DependencyNode)
+	visit = func(node *DependencyNode) {
+		for _, child := range node.Children {
+			if child.IsResolved() && child.Depth > 1 {
+				propagated, _ := propagateScope(node.Scope, child.DeclaredScope)
+				child.Scope = widestScope(append([]string{propagated}, child.scopeCandidates...)...)
+			}
+			visit(child)
+		}
+	}
+	visit(root)
+}
+
+// String 以类似`mvn dependency:tree`的格式输出依赖树
+func (g *DependencyGraph) String() string {
+	var builder strings.Builder
+	var write func(node *DependencyNode, prefix string, last bool)
+	write = func(node *DependencyNode, prefix string, last bool) {
+		line := node.Coordinate()
+		if node.Depth > 0 {
+			connector := "+- "
+			if last {
+				connector = "\\- "
+			}
+			line = prefix + connector + line + ":" + node.Scope
+		}
+		switch node.OmittedReason {
+		case OmittedDuplicate:
+			line += " (omitted for duplicate)"
+		case OmittedConflict:
+			line += " (omitted for conflict with " + node.OmittedFor + ")"
+		case OmittedCycle:
+			line += " (omitted for cycle)"
+		}
+		if node.Error != "" {
+			line += " (error: " + node.Error + ")"
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+
+		childPrefix := prefix
+		if node.Depth > 0 {
+			if last {
+				childPrefix += "   "
+			} else {
+				childPrefix += "|  "
+			}
+		}
+		for i, child := range node.Children {
+			write(child, childPrefix, i == len(node.Children)-1)
+		}
+	}
+	if g.Root != nil {
+		write(g.Root, "", true)
+	}
+	return builder.String()
+}
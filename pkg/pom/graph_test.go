@@ -0,0 +1,135 @@
+package pom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// simplePom 构建只包含依赖声明的测试POM
+func simplePom(groupId, artifactId, version, dependencies string) string {
+	return `<project><groupId>` + groupId + `</groupId><artifactId>` + artifactId +
+		`</artifactId><version>` + version + `</version><dependencies>` + dependencies +
+		`</dependencies></project>`
+}
+
+// dep 构建测试用的依赖声明片段
+func dep(groupId, artifactId, version, extra string) string {
+	return `<dependency><groupId>` + groupId + `</groupId><artifactId>` + artifactId +
+		`</artifactId><version>` + version + `</version>` + extra + `</dependency>`
+}
+
+var graphFixtures = mapFetcher{
+	"app:app:1": `<project><groupId>app</groupId><artifactId>app</artifactId><version>1</version>
+<dependencyManagement><dependencies>` + dep("lib", "managed", "9", "") + `</dependencies></dependencyManagement>
+<dependencies>` +
+		dep("lib", "a", "1", "") +
+		dep("lib", "b", "1", "<scope>runtime</scope>") +
+		dep("lib", "c", "2", "") +
+		dep("lib", "t", "1", "<scope>test</scope>") +
+		dep("lib", "opt", "1", "<optional>true</optional>") +
+		`</dependencies></project>`,
+	"lib:a:1": simplePom("lib", "a", "1",
+		dep("lib", "c", "1", "")+
+			dep("lib", "d", "1", "<exclusions><exclusion><groupId>lib</groupId><artifactId>e</artifactId></exclusion></exclusions>")+
+			dep("lib", "p", "1", "<scope>provided</scope>")+
+			dep("lib", "o", "1", "<optional>true</optional>")+
+			dep("lib", "managed", "1", "")),
+	"lib:b:1":       simplePom("lib", "b", "1", dep("lib", "f", "1", "")),
+	"lib:c:1":       simplePom("lib", "c", "1", ""),
+	"lib:c:2":       simplePom("lib", "c", "2", ""),
+	"lib:d:1":       simplePom("lib", "d", "1", dep("lib", "e", "1", "")+dep("lib", "a", "1", "")),
+	"lib:e:1":       simplePom("lib", "e", "1", ""),
+	"lib:f:1":       simplePom("lib", "f", "1", ""),
+	"lib:t:1":       simplePom("lib", "t", "1", ""),
+	"lib:managed:9": simplePom("lib", "managed", "9", ""),
+}
+
+// TestResolveGraph 测试就近原则、作用域传播、可选依赖和排除项
+func TestResolveGraph(t *testing.T) {
+	resolver := NewResolver(graphFixtures)
+
+	graph, err := resolver.ResolveGraph(context.Background(), "app", "app", "1", nil)
+	assert.NoError(t, err)
+
+	resolved := make(map[string]*DependencyNode)
+	for _, node := range graph.Classpath() {
+		resolved[node.ArtifactId] = node
+	}
+
+	// 默认不包含test作用域和可选的直接依赖
+	assert.NotContains(t, resolved, "t")
+	assert.NotContains(t, resolved, "opt")
+
+	// 就近原则：直接声明的c:2胜出
+	assert.Equal(t, "2", resolved["c"].Version)
+
+	// 排除项和可选、provided传递依赖被剪除
+	assert.NotContains(t, resolved, "e")
+	assert.NotContains(t, resolved, "o")
+	assert.NotContains(t, resolved, "p")
+
+	// 作用域传播
+	assert.Equal(t, "compile", resolved["d"].Scope)
+	assert.Equal(t, "runtime", resolved["f"].Scope)
+
+	// 根制品的依赖管理覆盖传递依赖的版本
+	assert.Equal(t, "9", resolved["managed"].Version)
+
+	// a -> d -> a 形成循环
+	assert.True(t, graph.HasCycles())
+
+	// 被淘汰的节点保留在树中
+	var conflict *DependencyNode
+	graph.Walk(func(node *DependencyNode) bool {
+		if node.ArtifactId == "c" && node.OmittedReason == OmittedConflict {
+			conflict = node
+		}
+		return true
+	})
+	assert.NotNil(t, conflict)
+	assert.Equal(t, "2", conflict.OmittedFor)
+	assert.Equal(t, []string{"app:app:1", "lib:a:1", "lib:c:1"}, conflict.Path())
+
+	assert.Len(t, graph.Classpath("runtime"), 2)
+}
+
+// TestResolveGraphOptions 测试作用域和可选依赖选项
+func TestResolveGraphOptions(t *testing.T) {
+	resolver := NewResolver(graphFixtures)
+
+	graph, err := resolver.ResolveGraph(context.Background(), "app", "app", "1", &GraphOptions{
+		Scopes:          []string{ScopeCompile, ScopeRuntime, ScopeTest},
+		IncludeOptional: true,
+		MaxDepth:        1,
+	})
+	assert.NoError(t, err)
+
+	classpath := graph.Classpath()
+	assert.Len(t, classpath, 5)
+	for _, node := range classpath {
+		assert.Equal(t, 1, node.Depth)
+	}
+}
+
+// TestPropagateScope 测试作用域传播表
+func TestPropagateScope(t *testing.T) {
+	cases := []struct {
+		parent, scope, expected string
+		ok                      bool
+	}{
+		{"compile", "compile", "compile", true},
+		{"compile", "runtime", "runtime", true},
+		{"runtime", "compile", "runtime", true},
+		{"provided", "compile", "provided", true},
+		{"test", "runtime", "test", true},
+		{"compile", "test", "", false},
+		{"compile", "provided", "", false},
+	}
+	for _, c := range cases {
+		scope, ok := propagateScope(c.parent, c.scope)
+		assert.Equal(t, c.ok, ok)
+		assert.Equal(t, c.expected, scope)
+	}
+}
@@ -0,0 +1,141 @@
+package pom
+
+import (
+	"strings"
+)
+
+// maxInterpolationDepth 属性嵌套替换的最大深度，防止属性互相引用导致死循环
+const maxInterpolationDepth = 16
+
+// interpolate 使用模型属性替换项目中的${...}占位符
+//
+// 支持的占位符包括<properties>中定义的属性，以及project.groupId、project.artifactId、
+// project.version、project.parent.*等内置属性（同时兼容已废弃的pom.*和无前缀写法）。
+// 无法解析的占位符保持原样，便于调用者发现缺失的属性。
+func interpolate(project *Project) {
+	values := builtinProperties(project)
+	for k, v := range project.Properties {
+		values[k] = v
+	}
+
+	expand := func(s string) string {
+		return expandProperties(s, values)
+	}
+
+	project.GroupId = expand(project.GroupId)
+	project.Version = expand(project.Version)
+	project.Name = expand(project.Name)
+	project.Description = expand(project.Description)
+	project.URL = expand(project.URL)
+
+	for k, v := range project.Properties {
+		project.Properties[k] = expand(v)
+	}
+
+	interpolateDependencies(project.Dependencies, expand)
+	if project.DependencyManagement != nil {
+		interpolateDependencies(project.DependencyManagement.Dependencies, expand)
+	}
+
+	for _, license := range project.Licenses {
+		license.Name = expand(license.Name)
+		license.URL = expand(license.URL)
+	}
+	if project.SCM != nil {
+		project.SCM.URL = expand(project.SCM.URL)
+		project.SCM.Connection = expand(project.SCM.Connection)
+		project.SCM.DeveloperConnection = expand(project.SCM.DeveloperConnection)
+		project.SCM.Tag = expand(project.SCM.Tag)
+	}
+	if project.IssueManagement != nil {
+		project.IssueManagement.URL = expand(project.IssueManagement.URL)
+	}
+}
+
+// interpolateDependencies 替换依赖声明中的占位符
+func interpolateDependencies(dependencies []*Dependency, expand func(string) string) {
+	for _, dep := range dependencies {
+		dep.GroupId = expand(dep.GroupId)
+		dep.ArtifactId = expand(dep.ArtifactId)
+		dep.Version = expand(dep.Version)
+		dep.Type = expand(dep.Type)
+		dep.Classifier = expand(dep.Classifier)
+		dep.Scope = expand(dep.Scope)
+		dep.Optional = expand(dep.Optional)
+		for _, exclusion := range dep.Exclusions {
+			exclusion.GroupId = expand(exclusion.GroupId)
+			exclusion.ArtifactId = expand(exclusion.ArtifactId)
+		}
+	}
+}
+
+// builtinProperties 构建Maven内置的project.*属性
+func builtinProperties(project *Project) map[string]string {
+	values := make(map[string]string)
+
+	coordinates := map[string]string{
+		"groupId":     project.GetGroupId(),
+		"artifactId":  project.ArtifactId,
+		"version":     project.GetVersion(),
+		"packaging":   project.GetPackaging(),
+		"name":        project.Name,
+		"description": project.Description,
+		"url":         project.URL,
+	}
+	for k, v := range coordinates {
+		values["project."+k] = v
+		values["pom."+k] = v
+	}
+	values["groupId"] = coordinates["groupId"]
+	values["artifactId"] = coordinates["artifactId"]
+	values["version"] = coordinates["version"]
+
+	if project.Parent != nil {
+		for _, prefix := range []string{"project.parent.", "parent."} {
+			values[prefix+"groupId"] = project.Parent.GroupId
+			values[prefix+"artifactId"] = project.Parent.ArtifactId
+			values[prefix+"version"] = project.Parent.Version
+		}
+	}
+
+	return values
+}
+
+// expandProperties 递归替换字符串中的${...}占位符
+func expandProperties(s string, values map[string]string) string {
+	for depth := 0; depth < maxInterpolationDepth && strings.Contains(s, "${"); depth++ {
+		var builder strings.Builder
+		changed := false
+		rest := s
+
+		for {
+			start := strings.Index(rest, "${")
+			if start < 0 {
+				builder.WriteString(rest)
+				break
+			}
+			end := strings.Index(rest[start:], "}")
+			if end < 0 {
+				builder.WriteString(rest)
+				break
+			}
+			end += start
+
+			name := rest[start+2 : end]
+			builder.WriteString(rest[:start])
+			if value, ok := values[name]; ok {
+				builder.WriteString(value)
+				changed = true
+			} else {
+				builder.WriteString(rest[start : end+1])
+			}
+			rest = rest[end+1:]
+		}
+
+		s = builder.String()
+		if !changed {
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,319 @@
+package pom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Project Maven POM文件的项目模型
+//
+// Project对应POM文件的根元素<project>，只保留了SDK在依赖分析、许可证审计和元数据展示中
+// 需要用到的字段。通过Parse得到的Project是"原始模型"，其中的${...}占位符尚未替换，
+// 父POM中的配置也尚未合并；需要完整的"有效模型"时请使用Resolver.Resolve。
+//
+// 使用示例:
+//
+//	project, err := pom.Parse(pomData)
+//	if err != nil {
+//	    log.Fatalf("解析POM失败: %v", err)
+//	}
+//	fmt.Printf("%s:%s:%s\n", project.GetGroupId(), project.ArtifactId, project.GetVersion())
+type Project struct {
+	XMLName xml.Name `xml:"project"`
+
+	ModelVersion string `xml:"modelVersion"`
+
+	// 父POM引用
+	Parent *Parent `xml:"parent"`
+
+	// 坐标信息
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Packaging  string `xml:"packaging"`
+
+	// 项目描述信息
+	Name        string `xml:"name"`
+	Description string `xml:"description"`
+	URL         string `xml:"url"`
+
+	// 属性定义，用于${...}插值
+	Properties Properties `xml:"properties"`
+
+	// 依赖管理与依赖声明
+	DependencyManagement *DependencyManagement `xml:"dependencyManagement"`
+	Dependencies         []*Dependency         `xml:"dependencies>dependency"`
+
+	// 许可证、开发者、源码和问题跟踪信息
+	Licenses        []*License       `xml:"licenses>license"`
+	Developers      []*Developer     `xml:"developers>developer"`
+	SCM             *SCM             `xml:"scm"`
+	IssueManagement *IssueManagement `xml:"issueManagement"`
+	Organization    *Organization    `xml:"organization"`
+
+	// 多模块项目的子模块列表
+	Modules []string `xml:"modules>module"`
+}
+
+// Parent 父POM引用
+type Parent struct {
+	GroupId      string `xml:"groupId"`
+	ArtifactId   string `xml:"artifactId"`
+	Version      string `xml:"version"`
+	RelativePath string `xml:"relativePath"`
+}
+
+// DependencyManagement 依赖管理配置
+type DependencyManagement struct {
+	Dependencies []*Dependency `xml:"dependencies>dependency"`
+}
+
+// Dependency 依赖声明
+//
+// 除了坐标之外还包含作用域、类型、分类器、可选标志和排除列表。
+// 当Scope为空时，Maven按照"compile"处理；Type为空时按照"jar"处理。
+type Dependency struct {
+	GroupId    string       `xml:"groupId"`
+	ArtifactId string       `xml:"artifactId"`
+	Version    string       `xml:"version"`
+	Type       string       `xml:"type"`
+	Classifier string       `xml:"classifier"`
+	Scope      string       `xml:"scope"`
+	Optional   string       `xml:"optional"`
+	Exclusions []*Exclusion `xml:"exclusions>exclusion"`
+}
+
+// Exclusion 依赖排除项，GroupId或ArtifactId可以为通配符"*"
+type Exclusion struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+}
+
+// License 许可证声明
+type License struct {
+	Name         string `xml:"name"`
+	URL          string `xml:"url"`
+	Distribution string `xml:"distribution"`
+	Comments     string `xml:"comments"`
+}
+
+// Developer 开发者信息
+type Developer struct {
+	ID              string `xml:"id"`
+	Name            string `xml:"name"`
+	Email           string `xml:"email"`
+	URL             string `xml:"url"`
+	Organization    string `xml:"organization"`
+	OrganizationURL string `xml:"organizationUrl"`
+}
+
+// SCM 源码管理信息
+type SCM struct {
+	URL                 string `xml:"url"`
+	Connection          string `xml:"connection"`
+	DeveloperConnection string `xml:"developerConnection"`
+	Tag                 string `xml:"tag"`
+}
+
+// IssueManagement 问题跟踪系统信息
+type IssueManagement struct {
+	System string `xml:"system"`
+	URL    string `xml:"url"`
+}
+
+// Organization 组织信息
+type Organization struct {
+	Name string `xml:"name"`
+	URL  string `xml:"url"`
+}
+
+// Properties POM中<properties>元素定义的属性集合
+//
+// POM的属性以任意元素名作为键，因此无法直接映射为结构体字段，这里通过自定义的
+// UnmarshalXML将每个子元素解析为一个键值对。
+type Properties map[string]string
+
+// UnmarshalXML 实现xml.Unmarshaler接口，将<properties>的子元素解析为键值对
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if *p == nil {
+		*p = make(Properties)
+	}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Parse 解析POM文件内容
+//
+// 该函数将POM的XML内容解析为Project结构体，并去除各字段首尾的空白字符。
+// 解析结果是原始模型：父POM未合并，属性占位符未替换。
+//
+// 参数:
+//   - data: POM文件的原始XML内容
+//
+// 返回:
+//   - *Project: 解析得到的项目模型
+//   - error: 如果内容不是合法的POM XML，返回解析错误
+//
+// 使用示例:
+//
+//	data, _ := os.ReadFile("pom.xml")
+//	project, err := pom.Parse(data)
+//	if err != nil {
+//	    log.Fatalf("解析POM失败: %v", err)
+//	}
+//	for _, dep := range project.Dependencies {
+//	    fmt.Printf("%s:%s:%s\n", dep.GroupId, dep.ArtifactId, dep.Version)
+//	}
+func Parse(data []byte) (*Project, error) {
+	var project Project
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// 部分历史POM声明了非UTF-8编码，这里按原样读取字节，避免解析直接失败
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&project); err != nil {
+		return nil, fmt.Errorf("解析POM失败: %w", err)
+	}
+	project.trimSpace()
+	return &project, nil
+}
+
+// GetGroupId 获取项目的groupId，未声明时继承父POM的groupId
+func (p *Project) GetGroupId() string {
+	if p.GroupId == "" && p.Parent != nil {
+		return p.Parent.GroupId
+	}
+	return p.GroupId
+}
+
+// GetVersion 获取项目的版本，未声明时继承父POM的版本
+func (p *Project) GetVersion() string {
+	if p.Version == "" && p.Parent != nil {
+		return p.Parent.Version
+	}
+	return p.Version
+}
+
+// GetPackaging 获取项目的打包类型，未声明时默认为"jar"
+func (p *Project) GetPackaging() string {
+	if p.Packaging == "" {
+		return "jar"
+	}
+	return p.Packaging
+}
+
+// ManagementKey 返回依赖在dependencyManagement中的匹配键
+//
+// Maven使用groupId:artifactId:type:classifier作为依赖管理的匹配键，
+// type为空时按"jar"处理。
+func (d *Dependency) ManagementKey() string {
+	return fmt.Sprintf("%s:%s:%s:%s", d.GroupId, d.ArtifactId, d.GetType(), d.Classifier)
+}
+
+// GetType 获取依赖类型，未声明时默认为"jar"
+func (d *Dependency) GetType() string {
+	if d.Type == "" {
+		return "jar"
+	}
+	return d.Type
+}
+
+// GetScope 获取依赖作用域，未声明时默认为"compile"
+func (d *Dependency) GetScope() string {
+	if d.Scope == "" {
+		return "compile"
+	}
+	return d.Scope
+}
+
+// IsOptional 判断依赖是否被声明为可选
+func (d *Dependency) IsOptional() bool {
+	return strings.EqualFold(strings.TrimSpace(d.Optional), "true")
+}
+
+// IsExcluded 判断给定坐标是否被该依赖的排除列表排除
+func (d *Dependency) IsExcluded(groupId, artifactId string) bool {
+	for _, exclusion := range d.Exclusions {
+		if (exclusion.GroupId == "*" || exclusion.GroupId == groupId) &&
+			(exclusion.ArtifactId == "*" || exclusion.ArtifactId == artifactId) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone 返回依赖声明的深拷贝
+func (d *Dependency) Clone() *Dependency {
+	clone := *d
+	if d.Exclusions != nil {
+		clone.Exclusions = make([]*Exclusion, len(d.Exclusions))
+		for i, exclusion := range d.Exclusions {
+			e := *exclusion
+			clone.Exclusions[i] = &e
+		}
+	}
+	return &clone
+}
+
+// trimSpace 去除项目模型中常用字符串字段的首尾空白
+func (p *Project) trimSpace() {
+	p.GroupId = strings.TrimSpace(p.GroupId)
+	p.ArtifactId = strings.TrimSpace(p.ArtifactId)
+	p.Version = strings.TrimSpace(p.Version)
+	p.Packaging = strings.TrimSpace(p.Packaging)
+	p.Name = strings.TrimSpace(p.Name)
+	p.Description = strings.TrimSpace(p.Description)
+	p.URL = strings.TrimSpace(p.URL)
+
+	if p.Parent != nil {
+		p.Parent.GroupId = strings.TrimSpace(p.Parent.GroupId)
+		p.Parent.ArtifactId = strings.TrimSpace(p.Parent.ArtifactId)
+		p.Parent.Version = strings.TrimSpace(p.Parent.Version)
+	}
+
+	trimDependencies(p.Dependencies)
+	if p.DependencyManagement != nil {
+		trimDependencies(p.DependencyManagement.Dependencies)
+	}
+
+	for _, license := range p.Licenses {
+		license.Name = strings.TrimSpace(license.Name)
+		license.URL = strings.TrimSpace(license.URL)
+	}
+}
+
+// trimDependencies 去除依赖声明中各字段的首尾空白
+func trimDependencies(dependencies []*Dependency) {
+	for _, dep := range dependencies {
+		dep.GroupId = strings.TrimSpace(dep.GroupId)
+		dep.ArtifactId = strings.TrimSpace(dep.ArtifactId)
+		dep.Version = strings.TrimSpace(dep.Version)
+		dep.Type = strings.TrimSpace(dep.Type)
+		dep.Classifier = strings.TrimSpace(dep.Classifier)
+		dep.Scope = strings.TrimSpace(dep.Scope)
+		dep.Optional = strings.TrimSpace(dep.Optional)
+		for _, exclusion := range dep.Exclusions {
+			exclusion.GroupId = strings.TrimSpace(exclusion.GroupId)
+			exclusion.ArtifactId = strings.TrimSpace(exclusion.ArtifactId)
+		}
+	}
+}
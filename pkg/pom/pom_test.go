@@ -0,0 +1,99 @@
+package pom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const samplePom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>example-parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>example-core</artifactId>
+  <name>Example Core</name>
+  <description>
+    Core library
+  </description>
+  <properties>
+    <slf4j.version>1.7.36</slf4j.version>
+    <project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>${slf4j.version}</version>
+      <exclusions>
+        <exclusion>
+          <groupId>*</groupId>
+          <artifactId>*</artifactId>
+        </exclusion>
+      </exclusions>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+      <optional>true</optional>
+    </dependency>
+  </dependencies>
+  <licenses>
+    <license>
+      <name>Apache License, Version 2.0</name>
+      <url>https://www.apache.org/licenses/LICENSE-2.0.txt</url>
+    </license>
+  </licenses>
+</project>`
+
+// TestParse 测试POM原始模型解析
+func TestParse(t *testing.T) {
+	project, err := Parse([]byte(samplePom))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "org.example", project.GetGroupId())
+	assert.Equal(t, "example-core", project.ArtifactId)
+	assert.Equal(t, "1.0.0", project.GetVersion())
+	assert.Equal(t, "jar", project.GetPackaging())
+	assert.Equal(t, "Core library", project.Description)
+
+	assert.Equal(t, "1.7.36", project.Properties["slf4j.version"])
+	assert.Equal(t, "UTF-8", project.Properties["project.build.sourceEncoding"])
+
+	assert.Len(t, project.Dependencies, 2)
+	slf4j := project.Dependencies[0]
+	assert.Equal(t, "${slf4j.version}", slf4j.Version)
+	assert.Equal(t, "compile", slf4j.GetScope())
+	assert.True(t, slf4j.IsExcluded("any.group", "any-artifact"))
+
+	junit := project.Dependencies[1]
+	assert.Equal(t, "test", junit.GetScope())
+	assert.True(t, junit.IsOptional())
+	assert.False(t, junit.IsExcluded("org.hamcrest", "hamcrest-core"))
+
+	assert.Len(t, project.Licenses, 1)
+	assert.Equal(t, "Apache License, Version 2.0", project.Licenses[0].Name)
+}
+
+// TestParseInvalid 测试非法内容的解析错误
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse([]byte("not a pom"))
+	assert.Error(t, err)
+}
+
+// TestExpandProperties 测试属性占位符的递归替换
+func TestExpandProperties(t *testing.T) {
+	values := map[string]string{
+		"a":    "${b}-x",
+		"b":    "1.0",
+		"loop": "${loop}",
+	}
+	assert.Equal(t, "1.0-x", expandProperties("${a}", values))
+	assert.Equal(t, "v1.0/${missing}", expandProperties("v${b}/${missing}", values))
+	assert.Equal(t, "${loop}", expandProperties("${loop}", values))
+}
@@ -0,0 +1,452 @@
+package pom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxResolveDepth 父POM链与BOM导入的最大嵌套深度，防止异常POM导致无限递归
+const maxResolveDepth = 32
+
+// ErrCyclicReference 父POM链或BOM导入中存在循环引用
+var ErrCyclicReference = errors.New("POM存在循环引用")
+
+// Fetcher POM文件获取接口
+//
+// Resolver通过该接口按坐标获取POM文件内容。api.Client的DownloadPom方法满足该接口，
+// 因此可以直接把客户端传给NewResolver；测试或离线场景中也可以提供基于本地文件的实现。
+type Fetcher interface {
+	DownloadPom(ctx context.Context, groupId, artifactId, version string) ([]byte, error)
+}
+
+// FetcherFunc 将普通函数适配为Fetcher接口
+type FetcherFunc func(ctx context.Context, groupId, artifactId, version string) ([]byte, error)
+
+// DownloadPom 实现Fetcher接口
+func (f FetcherFunc) DownloadPom(ctx context.Context, groupId, artifactId, version string) ([]byte, error) {
+	return f(ctx, groupId, artifactId, version)
+}
+
+// Resolver POM有效模型解析器
+//
+// Resolver按照Maven构建有效模型(effective model)的流程处理POM:
+//  1. 沿<parent>链逐级获取父POM并合并继承的配置
+//  2. 使用合并后的属性替换${...}占位符
+//  3. 导入dependencyManagement中scope为import的BOM
+//  4. 用依赖管理补全依赖声明中缺失的版本、作用域和排除项
+//
+// 解析过程中获取到的原始POM和有效模型都会被缓存，同一个Resolver可以被多个goroutine
+// 并发使用，重复解析同一坐标时不会再次发起下载。
+//
+// 使用示例:
+//
+//	client := api.NewClient()
+//	resolver := pom.NewResolver(client)
+//
+//	project, err := resolver.Resolve(ctx, "org.springframework", "spring-core", "5.3.25")
+//	if err != nil {
+//	    log.Fatalf("解析POM失败: %v", err)
+//	}
+//	for _, dep := range project.Dependencies {
+//	    fmt.Printf("%s:%s:%s (%s)\n", dep.GroupId, dep.ArtifactId, dep.Version, dep.GetScope())
+//	}
+type Resolver struct {
+	fetcher Fetcher
+
+	mu        sync.Mutex
+	raw       map[string]*Project
+	effective map[string]*Project
+}
+
+// NewResolver 创建一个新的POM解析器
+//
+// 参数:
+//   - fetcher: 用于按坐标获取POM文件的实现，通常为*api.Client
+//
+// 返回:
+//   - *Resolver: 解析器实例
+func NewResolver(fetcher Fetcher) *Resolver {
+	return &Resolver{
+		fetcher:   fetcher,
+		raw:       make(map[string]*Project),
+		effective: make(map[string]*Project),
+	}
+}
+
+// Resolve 获取并解析指定坐标的有效POM模型
+//
+// 参数:
+//   - ctx: 上下文对象，用于控制下载的超时和取消
+//   - groupId: 制品的组ID
+//   - artifactId: 制品ID
+//   - version: 制品版本
+//
+// 返回:
+//   - *Project: 合并父POM、替换属性并应用依赖管理之后的有效模型，调用者可以自由修改
+//   - error: 当POM或其父POM、导入的BOM无法获取或解析时返回错误
+func (r *Resolver) Resolve(ctx context.Context, groupId, artifactId, version string) (*Project, error) {
+	project, err := r.resolve(ctx, groupId, artifactId, version, nil)
+	if err != nil {
+		return nil, err
+	}
+	return project.Clone(), nil
+}
+
+// ResolveProject 为已解析的原始模型构建有效模型
+//
+// 与Resolve不同，该方法不下载项目自身的POM，适用于处理本地pom.xml的场景。
+// 父POM和BOM仍会通过Fetcher获取。
+//
+// 参数:
+//   - ctx: 上下文对象
+//   - project: 通过Parse得到的原始模型，不会被修改
+//
+// 返回:
+//   - *Project: 有效模型
+//   - error: 父POM或BOM获取失败时返回错误
+func (r *Resolver) ResolveProject(ctx context.Context, project *Project) (*Project, error) {
+	return r.build(ctx, project, nil)
+}
+
+// resolve 带循环检测地解析有效模型，chain记录当前正在解析的坐标链
+func (r *Resolver) resolve(ctx context.Context, groupId, artifactId, version string, chain []string) (*Project, error) {
+	key := gavKey(groupId, artifactId, version)
+
+	r.mu.Lock()
+	cached, ok := r.effective[key]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	raw, err := r.fetch(ctx, groupId, artifactId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	effective, err := r.build(ctx, raw, append(chain, key))
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.effective[key] = effective
+	r.mu.Unlock()
+
+	return effective, nil
+}
+
+// build 由原始模型构建有效模型
+func (r *Resolver) build(ctx context.Context, raw *Project, chain []string) (*Project, error) {
+	if len(chain) > maxResolveDepth {
+		return nil, fmt.Errorf("POM嵌套层级超过%d: %s", maxResolveDepth, strings.Join(chain, " -> "))
+	}
+
+	project, err := r.inherit(ctx, raw, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	interpolate(project)
+
+	if err := r.importBoms(ctx, project, chain); err != nil {
+		return nil, err
+	}
+
+	applyManagement(project)
+
+	return project, nil
+}
+
+// inherit 沿父POM链合并继承配置，返回未经插值的合并模型
+func (r *Resolver) inherit(ctx context.Context, raw *Project, chain []string) (*Project, error) {
+	project := raw.Clone()
+	parentRef := raw.Parent
+	visited := append([]string(nil), chain...)
+
+	for depth := 0; parentRef != nil; depth++ {
+		if depth > maxResolveDepth {
+			return nil, fmt.Errorf("父POM层级超过%d", maxResolveDepth)
+		}
+
+		key := gavKey(parentRef.GroupId, parentRef.ArtifactId, parentRef.Version)
+		for _, k := range visited {
+			if k == key {
+				return nil, fmt.Errorf("%w: %s -> %s", ErrCyclicReference, strings.Join(visited, " -> "), key)
+			}
+		}
+		visited = append(visited, key)
+
+		parent, err := r.fetch(ctx, parentRef.GroupId, parentRef.ArtifactId, parentRef.Version)
+		if err != nil {
+			return nil, fmt.Errorf("获取父POM %s 失败: %w", key, err)
+		}
+
+		mergeParent(project, parent)
+		parentRef = parent.Parent
+	}
+
+	return project, nil
+}
+
+// importBoms 导入dependencyManagement中scope为import、type为pom的BOM
+//
+// 按照Maven的规则，导入的条目不会覆盖当前模型中已声明的依赖管理，多个BOM之间
+// 按声明顺序先到先得。导入完成后，import条目本身会从依赖管理中移除。
+func (r *Resolver) importBoms(ctx context.Context, project *Project, chain []string) error {
+	if project.DependencyManagement == nil {
+		return nil
+	}
+
+	managed := make([]*Dependency, 0, len(project.DependencyManagement.Dependencies))
+	imports := make([]*Dependency, 0)
+	seen := make(map[string]bool)
+	for _, dep := range project.DependencyManagement.Dependencies {
+		if dep.Scope == "import" && dep.GetType() == "pom" {
+			imports = append(imports, dep)
+			continue
+		}
+		seen[dep.ManagementKey()] = true
+		managed = append(managed, dep)
+	}
+
+	for _, imp := range imports {
+		key := gavKey(imp.GroupId, imp.ArtifactId, imp.Version)
+		for _, k := range chain {
+			if k == key {
+				return fmt.Errorf("%w: %s -> %s", ErrCyclicReference, strings.Join(chain, " -> "), key)
+			}
+		}
+
+		bom, err := r.resolve(ctx, imp.GroupId, imp.ArtifactId, imp.Version, chain)
+		if err != nil {
+			return fmt.Errorf("导入BOM %s 失败: %w", key, err)
+		}
+		if bom.DependencyManagement == nil {
+			continue
+		}
+
+		for _, dep := range bom.DependencyManagement.Dependencies {
+			if seen[dep.ManagementKey()] {
+				continue
+			}
+			seen[dep.ManagementKey()] = true
+			managed = append(managed, dep.Clone())
+		}
+	}
+
+	project.DependencyManagement.Dependencies = managed
+	return nil
+}
+
+// fetch 获取并解析原始POM，结果会被缓存
+func (r *Resolver) fetch(ctx context.Context, groupId, artifactId, version string) (*Project, error) {
+	key := gavKey(groupId, artifactId, version)
+
+	r.mu.Lock()
+	cached, ok := r.raw[key]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	if groupId == "" || artifactId == "" || version == "" {
+		return nil, fmt.Errorf("POM坐标不完整: %s", key)
+	}
+
+	data, err := r.fetcher.DownloadPom(ctx, groupId, artifactId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	r.mu.Lock()
+	r.raw[key] = project
+	r.mu.Unlock()
+
+	return project, nil
+}
+
+// mergeParent 将父POM中可继承的配置合并到子模型，子模型中已有的配置优先
+func mergeParent(child, parent *Project) {
+	if child.GroupId == "" {
+		child.GroupId = parent.GetGroupId()
+	}
+	if child.Version == "" {
+		child.Version = parent.GetVersion()
+	}
+	if child.Description == "" {
+		child.Description = parent.Description
+	}
+	if child.URL == "" {
+		child.URL = parent.URL
+	}
+
+	merged := make(Properties, len(parent.Properties)+len(child.Properties))
+	for k, v := range parent.Properties {
+		merged[k] = v
+	}
+	for k, v := range child.Properties {
+		merged[k] = v
+	}
+	child.Properties = merged
+
+	if parent.DependencyManagement != nil {
+		if child.DependencyManagement == nil {
+			child.DependencyManagement = &DependencyManagement{}
+		}
+		child.DependencyManagement.Dependencies = mergeDependencies(
+			child.DependencyManagement.Dependencies, parent.DependencyManagement.Dependencies)
+	}
+	child.Dependencies = mergeDependencies(child.Dependencies, parent.Dependencies)
+
+	if len(child.Licenses) == 0 {
+		for _, license := range parent.Licenses {
+			l := *license
+			child.Licenses = append(child.Licenses, &l)
+		}
+	}
+	if len(child.Developers) == 0 {
+		for _, developer := range parent.Developers {
+			d := *developer
+			child.Developers = append(child.Developers, &d)
+		}
+	}
+	if child.SCM == nil && parent.SCM != nil {
+		scm := *parent.SCM
+		child.SCM = &scm
+	}
+	if child.IssueManagement == nil && parent.IssueManagement != nil {
+		issues := *parent.IssueManagement
+		child.IssueManagement = &issues
+	}
+	if child.Organization == nil && parent.Organization != nil {
+		org := *parent.Organization
+		child.Organization = &org
+	}
+}
+
+// mergeDependencies 合并依赖列表，own中已声明的依赖优先，inherited中重复的条目被忽略
+func mergeDependencies(own, inherited []*Dependency) []*Dependency {
+	seen := make(map[string]bool, len(own))
+	for _, dep := range own {
+		seen[dep.ManagementKey()] = true
+	}
+	for _, dep := range inherited {
+		if seen[dep.ManagementKey()] {
+			continue
+		}
+		seen[dep.ManagementKey()] = true
+		own = append(own, dep.Clone())
+	}
+	return own
+}
+
+// applyManagement 使用依赖管理补全依赖声明中缺失的版本、作用域、可选标志和排除项
+func applyManagement(project *Project) {
+	if project.DependencyManagement == nil {
+		return
+	}
+
+	managed := make(map[string]*Dependency, len(project.DependencyManagement.Dependencies))
+	for _, dep := range project.DependencyManagement.Dependencies {
+		if _, exists := managed[dep.ManagementKey()]; !exists {
+			managed[dep.ManagementKey()] = dep
+		}
+	}
+
+	for _, dep := range project.Dependencies {
+		m, ok := managed[dep.ManagementKey()]
+		if !ok {
+			continue
+		}
+		if dep.Version == "" {
+			dep.Version = m.Version
+		}
+		if dep.Scope == "" {
+			dep.Scope = m.Scope
+		}
+		if dep.Optional == "" {
+			dep.Optional = m.Optional
+		}
+		if len(dep.Exclusions) == 0 && len(m.Exclusions) > 0 {
+			dep.Exclusions = m.Clone().Exclusions
+		}
+	}
+}
+
+// Clone 返回项目模型的深拷贝
+func (p *Project) Clone() *Project {
+	clone := *p
+
+	if p.Parent != nil {
+		parent := *p.Parent
+		clone.Parent = &parent
+	}
+	if p.Properties != nil {
+		clone.Properties = make(Properties, len(p.Properties))
+		for k, v := range p.Properties {
+			clone.Properties[k] = v
+		}
+	}
+	if p.DependencyManagement != nil {
+		clone.DependencyManagement = &DependencyManagement{
+			Dependencies: cloneDependencies(p.DependencyManagement.Dependencies),
+		}
+	}
+	clone.Dependencies = cloneDependencies(p.Dependencies)
+
+	if p.Licenses != nil {
+		clone.Licenses = make([]*License, len(p.Licenses))
+		for i, license := range p.Licenses {
+			l := *license
+			clone.Licenses[i] = &l
+		}
+	}
+	if p.Developers != nil {
+		clone.Developers = make([]*Developer, len(p.Developers))
+		for i, developer := range p.Developers {
+			d := *developer
+			clone.Developers[i] = &d
+		}
+	}
+	if p.SCM != nil {
+		scm := *p.SCM
+		clone.SCM = &scm
+	}
+	if p.IssueManagement != nil {
+		issues := *p.IssueManagement
+		clone.IssueManagement = &issues
+	}
+	if p.Organization != nil {
+		org := *p.Organization
+		clone.Organization = &org
+	}
+	clone.Modules = append([]string(nil), p.Modules...)
+
+	return &clone
+}
+
+// cloneDependencies 深拷贝依赖列表
+func cloneDependencies(dependencies []*Dependency) []*Dependency {
+	if dependencies == nil {
+		return nil
+	}
+	clones := make([]*Dependency, len(dependencies))
+	for i, dep := range dependencies {
+		clones[i] = dep.Clone()
+	}
+	return clones
+}
+
+// gavKey 构建groupId:artifactId:version形式的坐标键
+func gavKey(groupId, artifactId, version string) string {
+	return groupId + ":" + artifactId + ":" + version
+}
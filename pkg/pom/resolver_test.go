@@ -0,0 +1,178 @@
+package pom
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapFetcher 基于内存映射的Fetcher实现，用于离线测试
+type mapFetcher map[string]string
+
+func (m mapFetcher) DownloadPom(ctx context.Context, groupId, artifactId, version string) ([]byte, error) {
+	if data, ok := m[gavKey(groupId, artifactId, version)]; ok {
+		return []byte(data), nil
+	}
+	return nil, errors.New("not found")
+}
+
+var resolverFixtures = mapFetcher{
+	"org.example:example-parent:1.0.0": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>example-parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <url>https://example.org</url>
+  <properties>
+    <slf4j.version>1.7.30</slf4j.version>
+    <guava.version>31.1-jre</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>org.example</groupId>
+        <artifactId>example-bom</artifactId>
+        <version>2.0.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+  <licenses>
+    <license><name>MIT</name></license>
+  </licenses>
+</project>`,
+	"org.example:example-bom:2.0.0": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>example-bom</artifactId>
+  <version>2.0.0</version>
+  <packaging>pom</packaging>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${project.version}.1</version>
+        <scope>runtime</scope>
+      </dependency>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>0.0.1</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+	"org.example:example-core:1.0.0": `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>example-parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>example-core</artifactId>
+  <properties>
+    <slf4j.version>1.7.36</slf4j.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>${slf4j.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>example-api</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+  </dependencies>
+</project>`,
+	"org.example:cycle-a:1": `<project>
+  <parent><groupId>org.example</groupId><artifactId>cycle-b</artifactId><version>1</version></parent>
+  <artifactId>cycle-a</artifactId>
+</project>`,
+	"org.example:cycle-b:1": `<project>
+  <parent><groupId>org.example</groupId><artifactId>cycle-a</artifactId><version>1</version></parent>
+  <artifactId>cycle-b</artifactId>
+</project>`,
+}
+
+// TestResolverResolve 测试父POM继承、属性插值和BOM导入
+func TestResolverResolve(t *testing.T) {
+	resolver := NewResolver(resolverFixtures)
+
+	project, err := resolver.Resolve(context.Background(), "org.example", "example-core", "1.0.0")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "org.example", project.GroupId)
+	assert.Equal(t, "1.0.0", project.Version)
+	assert.Equal(t, "https://example.org", project.URL)
+	assert.Equal(t, "1.7.36", project.Properties["slf4j.version"])
+	assert.Len(t, project.Licenses, 1)
+
+	versions := make(map[string]string)
+	scopes := make(map[string]string)
+	for _, dep := range project.Dependencies {
+		versions[dep.ArtifactId] = dep.Version
+		scopes[dep.ArtifactId] = dep.GetScope()
+	}
+
+	// 子POM覆盖父POM中的属性
+	assert.Equal(t, "1.7.36", versions["slf4j-api"])
+	// 父POM中的依赖管理优先于BOM
+	assert.Equal(t, "31.1-jre", versions["guava"])
+	// BOM中的条目在BOM自身的上下文中插值
+	assert.Equal(t, "2.0.0.1", versions["jackson-databind"])
+	assert.Equal(t, "runtime", scopes["jackson-databind"])
+	// 内置属性
+	assert.Equal(t, "1.0.0", versions["example-api"])
+	// 继承父POM中的依赖
+	assert.Equal(t, "test", scopes["junit"])
+
+	for _, dep := range project.DependencyManagement.Dependencies {
+		assert.NotEqual(t, "import", dep.Scope)
+	}
+}
+
+// TestResolverCycle 测试父POM循环引用检测
+func TestResolverCycle(t *testing.T) {
+	resolver := NewResolver(resolverFixtures)
+
+	_, err := resolver.Resolve(context.Background(), "org.example", "cycle-a", "1")
+	assert.ErrorIs(t, err, ErrCyclicReference)
+}
+
+// TestResolverMissingParent 测试父POM缺失时返回错误
+func TestResolverMissingParent(t *testing.T) {
+	resolver := NewResolver(mapFetcher{
+		"org.example:orphan:1": `<project>
+  <parent><groupId>org.example</groupId><artifactId>missing</artifactId><version>1</version></parent>
+  <artifactId>orphan</artifactId>
+</project>`,
+	})
+
+	_, err := resolver.Resolve(context.Background(), "org.example", "orphan", "1")
+	assert.Error(t, err)
+}
@@ -53,6 +53,24 @@ func (x *AdvancedSearchOptions) SetClassifier(classifier string) *AdvancedSearch
 	return x
 }
 
+// Expr 将高级搜索选项编译为查询表达式，没有任何条件时返回nil
+//
+// 与Query相同，值中的*和?作为通配符，其他特殊字符会被转义。
+func (x *AdvancedSearchOptions) Expr() Expr {
+	return And(
+		fieldCondition(FieldGroupId, x.GroupId),
+		fieldCondition(FieldArtifactId, x.ArtifactId),
+		fieldCondition(FieldVersion, x.Version),
+		fieldCondition(FieldPackaging, x.Packaging),
+		fieldCondition(FieldClassifier, x.Classifier),
+	)
+}
+
+// ToQuery 将高级搜索选项转换为查询参数
+func (x *AdvancedSearchOptions) ToQuery() *Query {
+	return NewQuery().SetExpr(x.Expr())
+}
+
 // MakeDependencyQuery 创建依赖查询字符串
 func MakeDependencyQuery(groupId, artifactId string) string {
 	if groupId != "" && artifactId != "" {
@@ -34,8 +34,11 @@ type Query struct {
 
 	Classifier string
 
-	// 自定义查询语句
+	// 自定义查询语句，设置后其他条件都不生效
 	CustomQuery string
+
+	// 查询表达式，与上面的各个字段条件是且的关系
+	Expression Expr
 }
 
 func NewQuery() *Query {
@@ -99,49 +102,59 @@ func (x *Query) SetCustomQuery(query string) *Query {
 	return x
 }
 
-func (x *Query) ToRequestParamValue() string {
-	conditions := make([]string, 0)
-
-	// 如果设置了自定义查询，直接使用自定义查询
-	if x.CustomQuery != "" {
-		return url.QueryEscape(x.CustomQuery)
-	}
-
-	if x.GroupId != "" {
-		conditions = append(conditions, "g:"+x.GroupId)
-	}
-
-	if x.ArtifactId != "" {
-		conditions = append(conditions, "a:"+x.ArtifactId)
-	}
-
-	if x.Version != "" {
-		conditions = append(conditions, "v:"+x.Version)
-	}
+// SetExpr 设置查询表达式
+//
+// 使用示例:
+//
+//	query := request.NewQuery().
+//	    SetGroupId("org.apache.commons").
+//	    SetExpr(request.TimestampRange(since, time.Time{}))
+func (x *Query) SetExpr(expr Expr) *Query {
+	x.Expression = expr
+	return x
+}
 
-	if x.Tags != "" {
-		conditions = append(conditions, "tags:"+x.Tags)
+// Expr 将各个字段条件和Expression编译为查询表达式，没有任何条件时返回nil
+//
+// 字段值中的*和?作为通配符，其他特殊字符会被转义；CustomQuery不参与编译。
+func (x *Query) Expr() Expr {
+	conditions := []Expr{
+		fieldCondition(FieldGroupId, x.GroupId),
+		fieldCondition(FieldArtifactId, x.ArtifactId),
+		fieldCondition(FieldVersion, x.Version),
+		fieldCondition(FieldTags, x.Tags),
+		fieldCondition(FieldSha1, x.Sha1),
+		fieldCondition(FieldClassName, x.ClassName),
+		fieldCondition(FieldFullyQualifiedClassName, x.FullyQualifiedClassName),
+		fieldCondition(FieldPackaging, x.Packaging),
+		fieldCondition(FieldClassifier, x.Classifier),
+		x.Expression,
 	}
+	return And(conditions...)
+}
 
-	if x.Sha1 != "" {
-		conditions = append(conditions, "1:"+x.Sha1)
+// String 返回未经URL编码的查询语句
+func (x *Query) String() string {
+	if x.CustomQuery != "" {
+		return x.CustomQuery
 	}
-
-	if x.ClassName != "" {
-		conditions = append(conditions, "c:"+x.ClassName)
+	if expr := x.Expr(); expr != nil {
+		return expr.String()
 	}
+	return ""
+}
 
-	if x.FullyQualifiedClassName != "" {
-		conditions = append(conditions, "fc:"+x.FullyQualifiedClassName)
-	}
+func (x *Query) ToRequestParamValue() string {
+	return url.QueryEscape(x.String())
+}
 
-	if x.Packaging != "" {
-		conditions = append(conditions, "p:"+x.Packaging)
+// fieldCondition 字段值为空时返回nil，包含*或?时作为通配符
+func fieldCondition(field, value string) Expr {
+	if value == "" {
+		return nil
 	}
-
-	if x.Classifier != "" {
-		conditions = append(conditions, "l:"+x.Classifier)
+	if strings.ContainsAny(value, "*?") {
+		return Wildcard(field, value)
 	}
-
-	return url.QueryEscape(strings.Join(conditions, " AND "))
+	return Field(field, value)
 }
@@ -0,0 +1,380 @@
+package request
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 常用的搜索字段
+const (
+	FieldGroupId                 = "g"
+	FieldArtifactId              = "a"
+	FieldVersion                 = "v"
+	FieldPackaging               = "p"
+	FieldClassifier              = "l"
+	FieldTags                    = "tags"
+	FieldSha1                    = "1"
+	FieldClassName               = "c"
+	FieldFullyQualifiedClassName = "fc"
+	FieldTimestamp               = "timestamp"
+	FieldId                      = "id"
+)
+
+// Expr 查询表达式
+//
+// 表达式通过And、Or、Not、Field、Phrase、Wildcard、Range等函数组合而成，
+// String返回转义后的Solr查询语法，ParseQuery可以将其解析回表达式。
+type Expr interface {
+	// String 返回Solr查询语法
+	String() string
+
+	render(nested bool) string
+}
+
+// AndExpr 所有子表达式都要匹配
+type AndExpr struct {
+	Exprs []Expr
+}
+
+// OrExpr 任一子表达式匹配即可
+type OrExpr struct {
+	Exprs []Expr
+}
+
+// NotExpr 排除匹配子表达式的文档
+type NotExpr struct {
+	Expr Expr
+}
+
+// TermExpr 字段等于某个值，Field为空时搜索默认字段
+type TermExpr struct {
+	Field string
+	Value string
+}
+
+// PhraseExpr 字段包含某个短语
+type PhraseExpr struct {
+	Field string
+	Text  string
+}
+
+// WildcardExpr 字段匹配通配符模式，*匹配任意多个字符，?匹配单个字符
+type WildcardExpr struct {
+	Field   string
+	Pattern string
+}
+
+// RangeExpr 字段在某个范围内，From或To为空表示不限
+type RangeExpr struct {
+	Field       string
+	From        string
+	To          string
+	IncludeFrom bool
+	IncludeTo   bool
+}
+
+// MatchAllExpr 匹配所有文档
+type MatchAllExpr struct{}
+
+// And 组合多个必须同时满足的条件
+//
+// nil会被忽略，嵌套的And会被展开；没有条件时返回nil，只有一个条件时直接返回该条件。
+//
+// 使用示例:
+//
+//	expr := request.And(
+//	    request.Field(request.FieldGroupId, "org.apache.commons"),
+//	    request.Wildcard(request.FieldArtifactId, "commons-*"),
+//	)
+//	fmt.Println(expr) // g:org.apache.commons AND a:commons-*
+func And(exprs ...Expr) Expr {
+	var flat []Expr
+	for _, expr := range exprs {
+		switch e := expr.(type) {
+		case nil:
+		case *AndExpr:
+			flat = append(flat, e.Exprs...)
+		case *MatchAllExpr:
+		default:
+			flat = append(flat, e)
+		}
+	}
+	switch len(flat) {
+	case 0:
+		for _, expr := range exprs {
+			if _, ok := expr.(*MatchAllExpr); ok {
+				return expr
+			}
+		}
+		return nil
+	case 1:
+		return flat[0]
+	}
+	return &AndExpr{Exprs: flat}
+}
+
+// Or 组合多个满足其一即可的条件
+//
+// nil会被忽略，嵌套的Or会被展开；没有条件时返回nil，只有一个条件时直接返回该条件。
+func Or(exprs ...Expr) Expr {
+	var flat []Expr
+	for _, expr := range exprs {
+		switch e := expr.(type) {
+		case nil:
+		case *OrExpr:
+			flat = append(flat, e.Exprs...)
+		case *MatchAllExpr:
+			return e
+		default:
+			flat = append(flat, e)
+		}
+	}
+	switch len(flat) {
+	case 0:
+		return nil
+	case 1:
+		return flat[0]
+	}
+	return &OrExpr{Exprs: flat}
+}
+
+// Not 排除满足条件的文档，expr为nil时返回nil
+func Not(expr Expr) Expr {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *NotExpr:
+		return e.Expr
+	}
+	return &NotExpr{Expr: expr}
+}
+
+// Field 字段等于给定的值，多个值之间是或的关系
+//
+// 值会被完整转义，其中的*和?不作为通配符，需要通配符时请使用Wildcard；没有值时返回nil。
+//
+// 使用示例:
+//
+//	request.Field(request.FieldGroupId, "org.slf4j")          // g:org.slf4j
+//	request.Field(request.FieldPackaging, "jar", "bundle")    // p:(jar OR bundle)
+func Field(field string, values ...string) Expr {
+	exprs := make([]Expr, 0, len(values))
+	for _, value := range values {
+		exprs = append(exprs, &TermExpr{Field: field, Value: value})
+	}
+	return Or(exprs...)
+}
+
+// Text 在默认字段中搜索给定的值
+func Text(value string) Expr {
+	return &TermExpr{Value: value}
+}
+
+// Phrase 字段包含给定的短语
+func Phrase(field, text string) Expr {
+	return &PhraseExpr{Field: field, Text: text}
+}
+
+// Wildcard 字段匹配通配符模式，*和?以外的特殊字符会被转义
+func Wildcard(field, pattern string) Expr {
+	return &WildcardExpr{Field: field, Pattern: pattern}
+}
+
+// Range 字段在闭区间[from, to]内，from或to为空表示不限
+func Range(field, from, to string) Expr {
+	return &RangeExpr{Field: field, From: from, To: to, IncludeFrom: true, IncludeTo: true}
+}
+
+// TimestampRange 发布时间在闭区间[from, to]内，from或to为零值表示不限
+//
+// 使用示例:
+//
+//	// 2023年以来发布的版本
+//	expr := request.TimestampRange(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+func TimestampRange(from, to time.Time) Expr {
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return Range(FieldTimestamp, format(from), format(to))
+}
+
+// MatchAll 匹配所有文档
+func MatchAll() Expr {
+	return &MatchAllExpr{}
+}
+
+func (e *AndExpr) String() string { return e.render(false) }
+
+func (e *AndExpr) render(nested bool) string {
+	parts := make([]string, 0, len(e.Exprs)+1)
+	negative := true
+	for _, expr := range e.Exprs {
+		if _, ok := expr.(*NotExpr); !ok {
+			negative = false
+		}
+		parts = append(parts, renderOperand(expr))
+	}
+	// Solr无法处理嵌套的纯否定子查询，需要补充匹配所有文档的条件
+	if nested && negative {
+		parts = append([]string{"*:*"}, parts...)
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func (e *OrExpr) String() string { return e.render(false) }
+
+func (e *OrExpr) render(nested bool) string {
+	if field, ok := e.commonField(); ok {
+		values := make([]string, 0, len(e.Exprs))
+		for _, expr := range e.Exprs {
+			values = append(values, renderValue(expr))
+		}
+		return field + ":(" + strings.Join(values, " OR ") + ")"
+	}
+
+	parts := make([]string, 0, len(e.Exprs))
+	for _, expr := range e.Exprs {
+		if not, ok := expr.(*NotExpr); ok {
+			parts = append(parts, "(*:* AND "+not.render(true)+")")
+			continue
+		}
+		parts = append(parts, renderOperand(expr))
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// commonField 所有子表达式都是同一个字段上的值时返回该字段，此时可以渲染为field:(a OR b)
+func (e *OrExpr) commonField() (string, bool) {
+	field := ""
+	for i, expr := range e.Exprs {
+		var f string
+		switch v := expr.(type) {
+		case *TermExpr:
+			f = v.Field
+		case *PhraseExpr:
+			f = v.Field
+		case *WildcardExpr:
+			f = v.Field
+		default:
+			return "", false
+		}
+		if f == "" || i > 0 && f != field {
+			return "", false
+		}
+		field = f
+	}
+	return field, true
+}
+
+func (e *NotExpr) String() string { return e.render(false) }
+
+func (e *NotExpr) render(bool) string {
+	if _, ok := e.Expr.(*NotExpr); ok {
+		return "NOT (*:* AND " + e.Expr.render(true) + ")"
+	}
+	return "NOT " + renderOperand(e.Expr)
+}
+
+func (e *TermExpr) String() string { return e.render(false) }
+
+func (e *TermExpr) render(bool) string { return withField(e.Field, renderValue(e)) }
+
+func (e *PhraseExpr) String() string { return e.render(false) }
+
+func (e *PhraseExpr) render(bool) string { return withField(e.Field, renderValue(e)) }
+
+func (e *WildcardExpr) String() string { return e.render(false) }
+
+func (e *WildcardExpr) render(bool) string { return withField(e.Field, renderValue(e)) }
+
+func (e *RangeExpr) String() string { return e.render(false) }
+
+func (e *RangeExpr) render(bool) string {
+	left, right := "{", "}"
+	if e.IncludeFrom {
+		left = "["
+	}
+	if e.IncludeTo {
+		right = "]"
+	}
+	bound := func(value string) string {
+		if value == "" {
+			return "*"
+		}
+		return escapeValue(value, false)
+	}
+	return withField(e.Field, left+bound(e.From)+" TO "+bound(e.To)+right)
+}
+
+func (e *MatchAllExpr) String() string { return e.render(false) }
+
+func (e *MatchAllExpr) render(bool) string { return "*:*" }
+
+// renderOperand 渲染作为And、Or、Not操作数的表达式，复合表达式加上括号
+func renderOperand(expr Expr) string {
+	switch e := expr.(type) {
+	case *OrExpr:
+		if _, ok := e.commonField(); ok {
+			return e.render(true)
+		}
+		return "(" + e.render(true) + ")"
+	case *AndExpr:
+		return "(" + e.render(true) + ")"
+	}
+	return expr.render(true)
+}
+
+// renderValue 渲染不带字段名的值
+func renderValue(expr Expr) string {
+	switch v := expr.(type) {
+	case *TermExpr:
+		return escapeValue(v.Value, false)
+	case *PhraseExpr:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v.Text) + `"`
+	case *WildcardExpr:
+		return escapeValue(v.Pattern, true)
+	}
+	return expr.render(true)
+}
+
+// withField 在值前加上字段名
+func withField(field, value string) string {
+	if field == "" {
+		return value
+	}
+	return field + ":" + value
+}
+
+// Escape 转义Solr查询语法中的特殊字符，返回的值可以安全地拼接到查询语句中
+func Escape(value string) string {
+	return escapeValue(value, false)
+}
+
+// escapeValue 转义单个值，wildcard为true时保留*和?
+//
+// 空值渲染为空短语，AND、OR、NOT等关键字转义首字母，-和+只在开头时转义（在词中间不是运算符）。
+func escapeValue(value string, wildcard bool) string {
+	switch value {
+	case "":
+		return `""`
+	case "AND", "OR", "NOT", "TO":
+		return `\` + value
+	}
+
+	var b strings.Builder
+	for i, r := range value {
+		switch {
+		case wildcard && (r == '*' || r == '?'):
+		case strings.ContainsRune(`\:()[]{}"^~*?!/&|`, r), r == ' ', r == '\t', r == '\n', r == '\r', r == '　':
+			b.WriteByte('\\')
+		case (r == '-' || r == '+') && i == 0:
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
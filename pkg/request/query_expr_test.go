@@ -0,0 +1,128 @@
+package request
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExprString 测试查询表达式的渲染
+func TestExprString(t *testing.T) {
+	cases := []struct {
+		expr     Expr
+		expected string
+	}{
+		{Field(FieldGroupId, "org.apache.commons"), "g:org.apache.commons"},
+		{Field(FieldArtifactId, "commons-lang3"), "a:commons-lang3"},
+		{Field(FieldVersion, "-1 beta:2"), `v:\-1\ beta\:2`},
+		{Field(FieldPackaging, "jar", "bundle"), "p:(jar OR bundle)"},
+		{Field(FieldTags, "AND"), `tags:\AND`},
+		{Field(FieldTags, ""), `tags:""`},
+		{Field(FieldClassName, "a*b?"), `c:a\*b\?`},
+		{Wildcard(FieldArtifactId, "commons-*"), "a:commons-*"},
+		{Phrase("text", `say "hi"`), `text:"say \"hi\""`},
+		{Text("guava"), "guava"},
+		{Range(FieldVersion, "1.0", ""), "v:[1.0 TO *]"},
+		{&RangeExpr{Field: FieldVersion, From: "1.0", To: "2.0", IncludeFrom: true}, "v:[1.0 TO 2.0}"},
+		{
+			TimestampRange(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}),
+			"timestamp:[1672531200000 TO *]",
+		},
+		{
+			And(Field(FieldGroupId, "org.slf4j"), Or(Field(FieldArtifactId, "slf4j-api"), Wildcard(FieldArtifactId, "slf4j-simple*"))),
+			"g:org.slf4j AND a:(slf4j-api OR slf4j-simple*)",
+		},
+		{
+			And(Field(FieldGroupId, "g"), Or(Field(FieldArtifactId, "a"), Field(FieldVersion, "1"))),
+			"g:g AND (a:a OR v:1)",
+		},
+		{
+			And(Field("cve", "CVE-1", "CVE-2"), Not(And(Field(FieldGroupId, "g"), Field(FieldArtifactId, "a")))),
+			"cve:(CVE-1 OR CVE-2) AND NOT (g:g AND a:a)",
+		},
+		{Or(Field(FieldGroupId, "a"), Not(Field(FieldGroupId, "b"))), "g:a OR (*:* AND NOT g:b)"},
+		{Or(Field(FieldGroupId, "a"), And(Not(Field(FieldGroupId, "b")), Not(Field(FieldGroupId, "c")))), "g:a OR (*:* AND NOT g:b AND NOT g:c)"},
+		{Not(Not(Field(FieldGroupId, "a"))), "g:a"},
+		{And(MatchAll(), Field(FieldGroupId, "a")), "g:a"},
+		{Or(MatchAll(), Field(FieldGroupId, "a")), "*:*"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.expr.String())
+	}
+
+	assert.Nil(t, And())
+	assert.Nil(t, Or(nil, Field(FieldGroupId)))
+	assert.Nil(t, Not(nil))
+}
+
+// TestParseQuery 测试查询语句的解析
+func TestParseQuery(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected Expr
+	}{
+		{"g:org.apache.commons", Field(FieldGroupId, "org.apache.commons")},
+		{`v:\-1\ beta\:2`, Field(FieldVersion, "-1 beta:2")},
+		{"g:a AND a:b", And(Field(FieldGroupId, "a"), Field(FieldArtifactId, "b"))},
+		{"g:a && a:b", And(Field(FieldGroupId, "a"), Field(FieldArtifactId, "b"))},
+		{"g:a a:b", Or(Field(FieldGroupId, "a"), Field(FieldArtifactId, "b"))},
+		{"+g:a a:b", Field(FieldGroupId, "a")},
+		{"g:a -a:b", And(Field(FieldGroupId, "a"), Not(Field(FieldArtifactId, "b")))},
+		{"g:a AND NOT a:b", And(Field(FieldGroupId, "a"), Not(Field(FieldArtifactId, "b")))},
+		{"!a:b", Not(Field(FieldArtifactId, "b"))},
+		{"p:(jar OR bundle)", Field(FieldPackaging, "jar", "bundle")},
+		{`text:"hello world"`, Phrase("text", "hello world")},
+		{"a:commons-*", Wildcard(FieldArtifactId, "commons-*")},
+		{"timestamp:{1 TO *]", &RangeExpr{Field: FieldTimestamp, From: "1", IncludeTo: true}},
+		{"*:*", MatchAll()},
+		{"(*:* AND NOT g:b)", Not(Field(FieldGroupId, "b"))},
+		{"ANDROID", Text("ANDROID")},
+		{"  ", nil},
+	}
+	for _, c := range cases {
+		expr, err := ParseQuery(c.query)
+		assert.NoError(t, err, c.query)
+		assert.Equal(t, c.expected, expr, c.query)
+	}
+
+	for _, query := range []string{"AND g:a", "g:(a", "g:a)", `g:"a`, "g:[1 2]", "g:a^2", "g:", `g:a\`, ":a"} {
+		_, err := ParseQuery(query)
+		assert.Error(t, err, query)
+	}
+}
+
+// TestParseQueryRoundTrip 测试渲染后再解析得到相同的表达式
+func TestParseQueryRoundTrip(t *testing.T) {
+	exprs := []Expr{
+		And(Field(FieldGroupId, "org.slf4j"), Or(Field(FieldArtifactId, "slf4j-api"), Wildcard(FieldArtifactId, "slf4j-*"))),
+		And(Field("cve", "CVE-1", "CVE-2"), Not(And(Field(FieldGroupId, "g"), Field(FieldArtifactId, "a")))),
+		Or(Field(FieldGroupId, "a"), Not(Field(FieldGroupId, "b")), Phrase("text", `a "b" \c`)),
+		And(Field(FieldVersion, "AND", "x y", "-z", "a+b"), TimestampRange(time.UnixMilli(1), time.UnixMilli(2))),
+		Or(And(Not(Field(FieldGroupId, "b")), Not(Field(FieldGroupId, "c"))), Text("a:b")),
+	}
+	for _, expr := range exprs {
+		parsed, err := ParseQuery(expr.String())
+		assert.NoError(t, err, expr.String())
+		assert.Equal(t, expr, parsed, expr.String())
+	}
+}
+
+// TestQueryExpr 测试Query和AdvancedSearchOptions编译为查询表达式
+func TestQueryExpr(t *testing.T) {
+	query := NewQuery().SetGroupId("org.apache.commons").SetArtifactId("commons lang")
+	assert.Equal(t, `g:org.apache.commons AND a:commons\ lang`, query.String())
+	assert.Equal(t, "g%3Aorg.apache.commons+AND+a%3Acommons%5C+lang", query.ToRequestParamValue())
+
+	query = NewQuery().SetFullyQualifiedClassName("org.slf4j.*").SetExpr(TimestampRange(time.UnixMilli(5), time.Time{}))
+	assert.Equal(t, "fc:org.slf4j.* AND timestamp:[5 TO *]", query.String())
+
+	query.SetCustomQuery("g:raw")
+	assert.Equal(t, "g:raw", query.String())
+
+	assert.Equal(t, "", NewQuery().ToRequestParamValue())
+
+	options := NewAdvancedSearchOptions().SetGroupId("org.example").SetVersion("1.0").SetClassifier("sources")
+	assert.Equal(t, "g:org.example AND v:1.0 AND l:sources", options.ToQuery().String())
+	assert.Nil(t, NewAdvancedSearchOptions().Expr())
+}
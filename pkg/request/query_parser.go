@@ -0,0 +1,355 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseQuery 将Solr查询语法解析为查询表达式
+//
+// 支持AND、OR、NOT（以及&&、||、!、+、-）、括号分组、字段分组（如g:(a OR b)）、
+// 短语、通配符和范围查询；与Solr一致，没有运算符连接的条件之间是或的关系。
+// 不支持权重（^）和模糊查询（~）。查询语句为空时返回nil。
+//
+// 参数:
+//   - query: Solr格式的查询语句
+//
+// 返回:
+//   - Expr: 查询表达式
+//   - error: 语法错误
+//
+// 使用示例:
+//
+//	expr, err := request.ParseQuery(`g:org.apache.commons AND (a:commons-lang3 OR a:commons-text)`)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	expr = request.And(expr, request.Field(request.FieldPackaging, "jar"))
+func ParseQuery(query string) (Expr, error) {
+	p := &queryParser{input: []rune(query)}
+	expr, err := p.parseGroup("")
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf("多余的%q", string(p.peek()))
+	}
+	return expr, nil
+}
+
+// clauseOccur 子句在组中的出现方式
+type clauseOccur int
+
+const (
+	occurShould clauseOccur = iota
+	occurMust
+	occurMustNot
+)
+
+// queryClause 组中的一个子句
+type queryClause struct {
+	occur clauseOccur
+	expr  Expr
+}
+
+// queryParser Solr查询语法解析器
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+// parseGroup 解析一组子句，直到输入结束或遇到右括号
+//
+// 运算符的处理与Lucene一致：AND将前后两个子句都变为必须，OR将前后两个子句都变为可选，
+// 有必须的子句时可选子句不影响匹配结果。
+func (p *queryParser) parseGroup(field string) (Expr, error) {
+	var clauses []*queryClause
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() == ')' {
+			break
+		}
+
+		conj := ""
+		switch {
+		case p.consumeKeyword("AND"), p.consume("&&"):
+			conj = "AND"
+		case p.consumeKeyword("OR"), p.consume("||"):
+			conj = "OR"
+		}
+		if conj != "" && len(clauses) == 0 {
+			return nil, p.errorf("%s前缺少条件", conj)
+		}
+
+		p.skipSpace()
+		occur := occurShould
+		switch {
+		case p.consumeKeyword("NOT"), p.consume("!"), p.consume("-"):
+			occur = occurMustNot
+		case p.consume("+"), conj == "AND":
+			occur = occurMust
+		}
+
+		expr, err := p.parseClause(field)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(clauses) > 0 {
+			prev := clauses[len(clauses)-1]
+			if prev.occur != occurMustNot {
+				switch conj {
+				case "AND":
+					prev.occur = occurMust
+				case "OR":
+					prev.occur = occurShould
+				}
+			}
+		}
+		clauses = append(clauses, &queryClause{occur: occur, expr: expr})
+	}
+
+	var musts, shoulds, nots []Expr
+	for _, clause := range clauses {
+		switch clause.occur {
+		case occurMust:
+			musts = append(musts, clause.expr)
+		case occurShould:
+			shoulds = append(shoulds, clause.expr)
+		case occurMustNot:
+			nots = append(nots, Not(clause.expr))
+		}
+	}
+	if len(musts) == 0 {
+		musts = append(musts, Or(shoulds...))
+	}
+	return And(append(musts, nots...)...), nil
+}
+
+// parseClause 解析单个子句：括号分组、字段:值或不带字段的值
+func (p *queryParser) parseClause(field string) (Expr, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.errorf("缺少条件")
+	}
+
+	switch p.peek() {
+	case '(', '"', '[', '{':
+		return p.parseValue(field)
+	}
+
+	start := p.pos
+	token, wildcard, err := p.readTerm()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos == start {
+		return nil, p.errorf("意外的%q", string(p.peek()))
+	}
+	if !p.eof() && p.peek() == ':' {
+		name := string(p.input[start:p.pos])
+		if strings.ContainsRune(name, '\\') {
+			return nil, p.errorf("无效的字段名%q", name)
+		}
+		p.pos++
+		if name == "*" && p.consume("*") {
+			return MatchAll(), p.checkModifier()
+		}
+		return p.parseValue(name)
+	}
+	return p.termExpr(field, token, wildcard)
+}
+
+// parseValue 解析字段的值：字段分组、短语、范围或普通值
+func (p *queryParser) parseValue(field string) (Expr, error) {
+	if p.eof() {
+		return nil, p.errorf("缺少值")
+	}
+
+	switch p.peek() {
+	case '(':
+		p.pos++
+		expr, err := p.parseGroup(field)
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("缺少右括号")
+		}
+		if expr == nil {
+			return nil, p.errorf("括号中缺少条件")
+		}
+		return expr, p.checkModifier()
+	case '"':
+		text, err := p.readPhrase()
+		if err != nil {
+			return nil, err
+		}
+		return &PhraseExpr{Field: field, Text: text}, p.checkModifier()
+	case '[', '{':
+		return p.parseRange(field)
+	}
+
+	token, wildcard, err := p.readTerm()
+	if err != nil {
+		return nil, err
+	}
+	return p.termExpr(field, token, wildcard)
+}
+
+// termExpr 根据是否包含通配符创建普通值或通配符表达式
+func (p *queryParser) termExpr(field, token string, wildcard bool) (Expr, error) {
+	if token == "" {
+		return nil, p.errorf("缺少值")
+	}
+	if err := p.checkModifier(); err != nil {
+		return nil, err
+	}
+	if wildcard {
+		return &WildcardExpr{Field: field, Pattern: token}, nil
+	}
+	return &TermExpr{Field: field, Value: token}, nil
+}
+
+// parseRange 解析[from TO to]形式的范围，*表示不限
+func (p *queryParser) parseRange(field string) (Expr, error) {
+	expr := &RangeExpr{Field: field, IncludeFrom: p.peek() == '['}
+	p.pos++
+
+	bound := func() (string, error) {
+		p.skipSpace()
+		if !p.eof() && p.peek() == '"' {
+			return p.readPhrase()
+		}
+		token, wildcard, err := p.readTerm()
+		if err != nil {
+			return "", err
+		}
+		if token == "*" && wildcard {
+			return "", nil
+		}
+		if token == "" {
+			return "", p.errorf("范围缺少边界")
+		}
+		return token, nil
+	}
+
+	var err error
+	if expr.From, err = bound(); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consumeKeyword("TO") {
+		return nil, p.errorf("范围缺少TO")
+	}
+	if expr.To, err = bound(); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	switch {
+	case p.consume("]"):
+		expr.IncludeTo = true
+	case p.consume("}"):
+	default:
+		return nil, p.errorf("范围缺少结束括号")
+	}
+	return expr, p.checkModifier()
+}
+
+// readTerm 读取一个值并去除转义，同时返回其中是否有未转义的通配符
+func (p *queryParser) readTerm() (string, bool, error) {
+	var b strings.Builder
+	wildcard := false
+	for !p.eof() {
+		r := p.peek()
+		if unicode.IsSpace(r) || strings.ContainsRune(`()[]{}"^~:`, r) {
+			break
+		}
+		p.pos++
+		switch r {
+		case '\\':
+			if p.eof() {
+				return "", false, p.errorf("转义符后缺少字符")
+			}
+			r = p.peek()
+			p.pos++
+		case '*', '?':
+			wildcard = true
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), wildcard, nil
+}
+
+// readPhrase 读取双引号包围的短语并去除转义
+func (p *queryParser) readPhrase() (string, error) {
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		switch r {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("转义符后缺少字符")
+			}
+			r = p.peek()
+			p.pos++
+		}
+		b.WriteRune(r)
+	}
+	return "", p.errorf("短语缺少结束引号")
+}
+
+// checkModifier 值后面不能有权重或模糊查询
+func (p *queryParser) checkModifier() error {
+	if !p.eof() && (p.peek() == '^' || p.peek() == '~') {
+		return p.errorf("不支持%q", string(p.peek()))
+	}
+	return nil
+}
+
+// consumeKeyword 当前位置是独立的关键字时跳过它
+func (p *queryParser) consumeKeyword(keyword string) bool {
+	end := p.pos + len(keyword)
+	if end > len(p.input) || string(p.input[p.pos:end]) != keyword {
+		return false
+	}
+	if end < len(p.input) && !unicode.IsSpace(p.input[end]) && p.input[end] != '(' && p.input[end] != '"' {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// consume 当前位置是给定的字符串时跳过它
+func (p *queryParser) consume(s string) bool {
+	end := p.pos + len([]rune(s))
+	if end > len(p.input) || string(p.input[p.pos:end]) != s {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *queryParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *queryParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *queryParser) peek() rune {
+	return p.input[p.pos]
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("查询语法错误（位置%d）: %s", p.pos, fmt.Sprintf(format, args...))
+}
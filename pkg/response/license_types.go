@@ -52,3 +52,23 @@ type LicenseReport struct {
 	LicenseDistribution map[string]int     `json:"licenseDistribution"`
 	Recommendations     []string           `json:"recommendations"`
 }
+
+// ComponentLicenseCompliance 单个组件相对于对外发布许可证的兼容性
+type ComponentLicenseCompliance struct {
+	GroupId    string `json:"groupId"`
+	ArtifactId string `json:"artifactId"`
+	Version    string `json:"version"`
+	License    string `json:"license,omitempty"` // 组件许可证的SPDX表达式
+	Verdict    string `json:"verdict"`           // compatible、incompatible或unknown
+	Reason     string `json:"reason"`
+}
+
+// OutboundLicenseReport 一组组件相对于对外发布许可证的兼容性报告
+type OutboundLicenseReport struct {
+	OutboundLicense   string                       `json:"outboundLicense"`
+	TotalComponents   int                          `json:"totalComponents"`
+	CompatibleCount   int                          `json:"compatibleCount"`
+	IncompatibleCount int                          `json:"incompatibleCount"`
+	UnknownCount      int                          `json:"unknownCount"`
+	Components        []ComponentLicenseCompliance `json:"components"`
+}
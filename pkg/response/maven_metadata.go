@@ -0,0 +1,113 @@
+package response
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MavenMetadataTimeFormat maven-metadata.xml中lastUpdated字段的时间格式（UTC）
+const MavenMetadataTimeFormat = "20060102150405"
+
+// MavenMetadata 仓库中maven-metadata.xml的内容
+//
+// 同一个结构同时用于三种元数据:
+//   - 制品级元数据(groupId/artifactId/maven-metadata.xml): 包含全部已发布版本以及latest、release
+//   - 快照版本元数据(groupId/artifactId/1.0-SNAPSHOT/maven-metadata.xml): 包含最新快照的时间戳和构建号
+//   - 组级元数据(groupId/maven-metadata.xml): 包含Maven插件前缀映射
+type MavenMetadata struct {
+	XMLName    xml.Name           `xml:"metadata" json:"-"`
+	GroupId    string             `xml:"groupId" json:"groupId,omitempty"`
+	ArtifactId string             `xml:"artifactId" json:"artifactId,omitempty"`
+	Version    string             `xml:"version" json:"version,omitempty"`
+	Versioning *MavenVersioning   `xml:"versioning" json:"versioning,omitempty"`
+	Plugins    []*MavenPluginInfo `xml:"plugins>plugin" json:"plugins,omitempty"`
+}
+
+// MavenVersioning 元数据中的版本信息
+type MavenVersioning struct {
+	// Latest 最近部署的版本（可能是快照版本）
+	Latest string `xml:"latest" json:"latest,omitempty"`
+
+	// Release 最近部署的正式版本
+	Release string `xml:"release" json:"release,omitempty"`
+
+	// Versions 全部已部署的版本，按部署顺序排列
+	Versions []string `xml:"versions>version" json:"versions,omitempty"`
+
+	// LastUpdated 元数据最后更新时间，格式为yyyyMMddHHmmss（UTC）
+	LastUpdated string `xml:"lastUpdated" json:"lastUpdated,omitempty"`
+
+	// Snapshot 快照版本元数据中最新快照的时间戳和构建号
+	Snapshot *MavenSnapshot `xml:"snapshot" json:"snapshot,omitempty"`
+
+	// SnapshotVersions 快照版本元数据中每个文件对应的带时间戳版本
+	SnapshotVersions []*MavenSnapshotVersion `xml:"snapshotVersions>snapshotVersion" json:"snapshotVersions,omitempty"`
+}
+
+// MavenSnapshot 最新快照的标识
+type MavenSnapshot struct {
+	Timestamp   string `xml:"timestamp" json:"timestamp,omitempty"`
+	BuildNumber int    `xml:"buildNumber" json:"buildNumber,omitempty"`
+	LocalCopy   bool   `xml:"localCopy" json:"localCopy,omitempty"`
+}
+
+// MavenSnapshotVersion 快照版本中单个文件的带时间戳版本
+//
+// 例如Value为"1.0-20230115.103000-3"时，对应的JAR文件名为"demo-1.0-20230115.103000-3.jar"。
+type MavenSnapshotVersion struct {
+	Classifier string `xml:"classifier" json:"classifier,omitempty"`
+	Extension  string `xml:"extension" json:"extension"`
+	Value      string `xml:"value" json:"value"`
+	Updated    string `xml:"updated" json:"updated,omitempty"`
+}
+
+// MavenPluginInfo 组级元数据中的Maven插件信息
+type MavenPluginInfo struct {
+	Name       string `xml:"name" json:"name,omitempty"`
+	Prefix     string `xml:"prefix" json:"prefix"`
+	ArtifactId string `xml:"artifactId" json:"artifactId"`
+}
+
+// LastUpdatedTime 将lastUpdated解析为时间，元数据中没有该字段时返回零值
+func (m *MavenMetadata) LastUpdatedTime() time.Time {
+	if m.Versioning == nil || m.Versioning.LastUpdated == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(MavenMetadataTimeFormat, m.Versioning.LastUpdated)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// SnapshotVersion 返回快照版本中指定文件对应的带时间戳版本
+//
+// 存在snapshotVersions时只使用其中的精确记录；旧格式的元数据只有snapshot节点，
+// 此时根据时间戳和构建号拼接版本。找不到对应记录时返回空字符串。
+//
+// 参数:
+//   - extension: 文件扩展名，如"jar"、"pom"
+//   - classifier: 分类器，没有分类器时传空字符串
+func (m *MavenMetadata) SnapshotVersion(extension, classifier string) string {
+	if m.Versioning == nil {
+		return ""
+	}
+	for _, sv := range m.Versioning.SnapshotVersions {
+		if sv.Extension == extension && sv.Classifier == classifier {
+			return sv.Value
+		}
+	}
+
+	if len(m.Versioning.SnapshotVersions) > 0 {
+		return ""
+	}
+
+	snapshot := m.Versioning.Snapshot
+	if snapshot == nil || snapshot.Timestamp == "" || !strings.HasSuffix(m.Version, "-SNAPSHOT") {
+		return ""
+	}
+	base := strings.TrimSuffix(m.Version, "-SNAPSHOT")
+	return base + "-" + snapshot.Timestamp + "-" + strconv.Itoa(snapshot.BuildNumber)
+}
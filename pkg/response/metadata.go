@@ -26,15 +26,33 @@ type ArtifactMetadata struct {
 
 	// 项目信息
 	ProjectInfo *ProjectInfo `json:"projectInfo,omitempty"`
+
+	// 父POM坐标
+	Parent *ArtifactRef `json:"parent,omitempty"`
+
+	// 依赖管理（已合并父POM并导入BOM）
+	ManagedDependencies []*Dependency `json:"managedDependencies,omitempty"`
+
+	// POM属性（已合并父POM）
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 // Dependency 依赖信息
 type Dependency struct {
+	GroupId    string       `json:"groupId"`
+	ArtifactId string       `json:"artifactId"`
+	Version    string       `json:"version"`
+	Scope      string       `json:"scope,omitempty"`
+	Optional   bool         `json:"optional,omitempty"`
+	Type       string       `json:"type,omitempty"`
+	Classifier string       `json:"classifier,omitempty"`
+	Exclusions []*Exclusion `json:"exclusions,omitempty"`
+}
+
+// Exclusion 依赖排除项，GroupId或ArtifactId可以为通配符"*"
+type Exclusion struct {
 	GroupId    string `json:"groupId"`
 	ArtifactId string `json:"artifactId"`
-	Version    string `json:"version"`
-	Scope      string `json:"scope,omitempty"`
-	Optional   bool   `json:"optional,omitempty"`
 }
 
 // MetadataSecurityRating 安全评分信息（元数据中的版本）
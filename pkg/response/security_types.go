@@ -5,6 +5,7 @@ type SecurityRating struct {
 	VulnCount  int      `json:"vulnerabilityCount"` // 漏洞数量
 	Severity   string   `json:"maxSeverity"`        // 最高严重性级别: CRITICAL, HIGH, MEDIUM, LOW, NONE
 	Score      float64  `json:"score"`              // 安全评分 (0-10, 越高越安全)
+	RiskScore  float64  `json:"riskScore"`          // 风险评分，即漏洞中最高的CVSS评分 (0-10, 越高风险越大)
 	Advisories []string `json:"advisories"`         // 安全建议链接列表
 }
 
@@ -32,4 +32,10 @@ type VersionComparison struct {
 	Version2    string `json:"version2"`
 	V1Timestamp string `json:"v1Timestamp"`
 	V2Timestamp string `json:"v2Timestamp"`
+
+	// Result 按照Maven版本比较规则的结果，Version1较低时为-1，相等时为0，较高时为1
+	Result int `json:"result"`
+
+	// NewerVersion 两个版本中较高的版本，相等时为Version1
+	NewerVersion string `json:"newerVersion"`
 }
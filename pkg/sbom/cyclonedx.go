@@ -0,0 +1,186 @@
+package sbom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+	"time"
+)
+
+// CycloneDX规范版本与命名空间
+const (
+	cycloneDXSpecVersion = "1.5"
+	cycloneDXNamespace   = "http://cyclonedx.org/schema/bom/1.5"
+)
+
+// cdxBOM CycloneDX文档，同一结构用于JSON和XML编码
+type cdxBOM struct {
+	XMLName      xml.Name         `xml:"bom" json:"-"`
+	XMLNS        string           `xml:"xmlns,attr" json:"-"`
+	BOMFormat    string           `xml:"-" json:"bomFormat"`
+	SpecVersion  string           `xml:"-" json:"specVersion"`
+	SerialNumber string           `xml:"serialNumber,attr" json:"serialNumber"`
+	Version      int              `xml:"version,attr" json:"version"`
+	Metadata     *cdxMetadata     `xml:"metadata" json:"metadata"`
+	Components   []*cdxComponent  `xml:"components>component" json:"components"`
+	Dependencies []*cdxDependency `xml:"dependencies>dependency" json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `xml:"timestamp" json:"timestamp"`
+	Tools     *cdxTools     `xml:"tools" json:"tools"`
+	Component *cdxComponent `xml:"component,omitempty" json:"component,omitempty"`
+}
+
+type cdxTools struct {
+	Components []*cdxComponent `xml:"components>component" json:"components"`
+}
+
+type cdxComponent struct {
+	Type        string           `xml:"type,attr" json:"type"`
+	BOMRef      string           `xml:"bom-ref,attr,omitempty" json:"bom-ref,omitempty"`
+	Group       string           `xml:"group,omitempty" json:"group,omitempty"`
+	Name        string           `xml:"name" json:"name"`
+	Version     string           `xml:"version,omitempty" json:"version,omitempty"`
+	Description string           `xml:"description,omitempty" json:"description,omitempty"`
+	Scope       string           `xml:"scope,omitempty" json:"scope,omitempty"`
+	Hashes      []*cdxHash       `xml:"hashes>hash,omitempty" json:"hashes,omitempty"`
+	Licenses    []*cdxLicenseRef `xml:"licenses>license,omitempty" json:"licenses,omitempty"`
+	PURL        string           `xml:"purl,omitempty" json:"purl,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `xml:"alg,attr" json:"alg"`
+	Content string `xml:",chardata" json:"content"`
+}
+
+// cdxLicenseRef JSON中许可证包装在{"license": {...}}中，XML中直接是<license>元素
+type cdxLicenseRef struct {
+	License *cdxLicense `xml:"-" json:"license"`
+	ID      string      `xml:"id,omitempty" json:"-"`
+	Name    string      `xml:"name,omitempty" json:"-"`
+	URL     string      `xml:"url,omitempty" json:"-"`
+}
+
+type cdxLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cdxDependency struct {
+	Ref       string           `xml:"ref,attr" json:"ref"`
+	DependsOn []string         `xml:"-" json:"dependsOn,omitempty"`
+	Children  []*cdxDependency `xml:"dependency,omitempty" json:"-"`
+}
+
+// encodeCycloneDXJSON 将文档编码为CycloneDX 1.5 JSON
+func encodeCycloneDXJSON(bom *BOM) ([]byte, error) {
+	return json.MarshalIndent(buildCycloneDX(bom), "", "  ")
+}
+
+// encodeCycloneDXXML 将文档编码为CycloneDX 1.5 XML
+func encodeCycloneDXXML(bom *BOM) ([]byte, error) {
+	doc := buildCycloneDX(bom)
+	doc.XMLNS = cycloneDXNamespace
+	for _, dep := range doc.Dependencies {
+		for _, ref := range dep.DependsOn {
+			dep.Children = append(dep.Children, &cdxDependency{Ref: ref})
+		}
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// buildCycloneDX 将通用文档模型转换为CycloneDX结构
+func buildCycloneDX(bom *BOM) *cdxBOM {
+	doc := &cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: bom.SerialNumber,
+		Version:      1,
+		Metadata: &cdxMetadata{
+			Timestamp: bom.Timestamp.UTC().Format(time.RFC3339),
+			Tools: &cdxTools{
+				Components: []*cdxComponent{{Type: "application", Name: ToolName}},
+			},
+		},
+		Components:   make([]*cdxComponent, 0, len(bom.Components)),
+		Dependencies: make([]*cdxDependency, 0, len(bom.Components)+1),
+	}
+
+	if bom.Root != nil {
+		doc.Metadata.Component = convertCycloneDXComponent(bom.Root)
+		doc.Metadata.Component.Scope = ""
+		doc.Dependencies = append(doc.Dependencies, convertCycloneDXDependency(bom.Root))
+	}
+	for _, component := range bom.Components {
+		doc.Components = append(doc.Components, convertCycloneDXComponent(component))
+		doc.Dependencies = append(doc.Dependencies, convertCycloneDXDependency(component))
+	}
+	return doc
+}
+
+// convertCycloneDXComponent 转换单个组件
+func convertCycloneDXComponent(c *Component) *cdxComponent {
+	component := &cdxComponent{
+		Type:        "library",
+		BOMRef:      c.Ref(),
+		Group:       c.GroupId,
+		Name:        c.ArtifactId,
+		Version:     c.Version,
+		Description: c.Description,
+		Scope:       cycloneDXScope(c.Scope),
+		PURL:        c.PURL(),
+	}
+
+	algorithms := make([]string, 0, len(c.Hashes))
+	for alg := range c.Hashes {
+		algorithms = append(algorithms, alg)
+	}
+	sort.Strings(algorithms)
+	for _, alg := range algorithms {
+		component.Hashes = append(component.Hashes, &cdxHash{Alg: alg, Content: c.Hashes[alg]})
+	}
+
+	for _, license := range c.Licenses {
+		ref := &cdxLicenseRef{ID: license.ID}
+		if license.ID == "" {
+			ref.Name = license.Name
+			if ref.Name == "" {
+				ref.Name = license.URL
+			}
+			ref.URL = license.URL
+		}
+		ref.License = &cdxLicense{ID: ref.ID, Name: ref.Name, URL: ref.URL}
+		component.Licenses = append(component.Licenses, ref)
+	}
+	return component
+}
+
+// convertCycloneDXDependency 转换组件的依赖关系
+func convertCycloneDXDependency(c *Component) *cdxDependency {
+	dependsOn := append([]string(nil), c.DependsOn...)
+	sort.Strings(dependsOn)
+	return &cdxDependency{Ref: c.Ref(), DependsOn: dependsOn}
+}
+
+// cycloneDXScope 将Maven作用域映射为CycloneDX作用域
+//
+// compile和runtime依赖在运行时必需；provided和system由运行环境提供，视为可选；
+// test依赖不会随制品分发，标记为排除。
+func cycloneDXScope(scope string) string {
+	switch scope {
+	case "", "compile", "runtime":
+		return "required"
+	case "provided", "system":
+		return "optional"
+	case "test":
+		return "excluded"
+	}
+	return ""
+}
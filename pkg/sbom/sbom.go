@@ -0,0 +1,176 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Format SBOM输出格式
+type Format string
+
+const (
+	// FormatCycloneDXJSON CycloneDX 1.5 JSON格式
+	FormatCycloneDXJSON Format = "cyclonedx-json"
+	// FormatCycloneDXXML CycloneDX 1.5 XML格式
+	FormatCycloneDXXML Format = "cyclonedx-xml"
+	// FormatSPDXJSON SPDX 2.3 JSON格式
+	FormatSPDXJSON Format = "spdx-json"
+)
+
+// ErrUnsupportedFormat 不支持的SBOM输出格式
+var ErrUnsupportedFormat = errors.New("unsupported SBOM format")
+
+// 组件哈希算法名称，与CycloneDX中的算法名称保持一致
+const (
+	HashSHA1   = "SHA-1"
+	HashSHA256 = "SHA-256"
+)
+
+// ToolName 写入SBOM文档的生成工具名称
+const ToolName = "sonatype-central-sdk"
+
+// License 组件声明的许可证
+//
+// ID为SPDX许可证标识符（如"Apache-2.0"），无法识别时为空，此时使用Name和URL描述许可证。
+type License struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Component SBOM中的一个Maven组件
+//
+// 字段说明:
+//   - Type: 组件主文件的类型（扩展名），如jar、war、pom，为空时视为jar
+//   - Scope: Maven依赖作用域，为空时视为compile
+//   - Hashes: 组件主文件的哈希值，键为HashSHA1、HashSHA256等算法名称
+//   - DownloadURL: 组件主文件在仓库中的下载地址
+//   - DependsOn: 直接依赖的组件，值为对应组件的Ref()
+//   - Error: 收集组件信息过程中出现的错误，不影响文档生成
+type Component struct {
+	GroupId     string            `json:"groupId"`
+	ArtifactId  string            `json:"artifactId"`
+	Version     string            `json:"version"`
+	Type        string            `json:"type,omitempty"`
+	Classifier  string            `json:"classifier,omitempty"`
+	Scope       string            `json:"scope,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Licenses    []License         `json:"licenses,omitempty"`
+	Hashes      map[string]string `json:"hashes,omitempty"`
+	DownloadURL string            `json:"downloadUrl,omitempty"`
+	DependsOn   []string          `json:"dependsOn,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// BOM 与输出格式无关的SBOM文档模型
+//
+// Root为被描述的主组件（例如依赖树的根制品），只提供一组制品时可以为空，
+// 此时Components中的每个组件都是文档直接描述的对象。
+type BOM struct {
+	SerialNumber string       `json:"serialNumber"`
+	Timestamp    time.Time    `json:"timestamp"`
+	Root         *Component   `json:"root,omitempty"`
+	Components   []*Component `json:"components"`
+}
+
+// New 创建一个带有随机序列号和当前时间戳的空文档
+func New() *BOM {
+	return &BOM{
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Timestamp:    time.Now().UTC(),
+	}
+}
+
+// ParseFormat 将格式名称解析为Format，忽略大小写
+//
+// 除了完整名称外还接受"cyclonedx"（等同于cyclonedx-json）和"spdx"（等同于spdx-json）。
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "cyclonedx", string(FormatCycloneDXJSON):
+		return FormatCycloneDXJSON, nil
+	case string(FormatCycloneDXXML):
+		return FormatCycloneDXXML, nil
+	case "spdx", string(FormatSPDXJSON):
+		return FormatSPDXJSON, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+}
+
+// Encode 将文档编码为指定格式
+//
+// 参数:
+//   - bom: 要编码的文档
+//   - format: 输出格式
+//
+// 返回:
+//   - []byte: 编码后的文档内容
+//   - error: 格式不受支持或编码失败时返回错误
+func Encode(bom *BOM, format Format) ([]byte, error) {
+	switch format {
+	case FormatCycloneDXJSON:
+		return encodeCycloneDXJSON(bom)
+	case FormatCycloneDXXML:
+		return encodeCycloneDXXML(bom)
+	case FormatSPDXJSON:
+		return encodeSPDXJSON(bom)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// Coordinate 返回组件的groupId:artifactId:version坐标
+func (c *Component) Coordinate() string {
+	return c.GroupId + ":" + c.ArtifactId + ":" + c.Version
+}
+
+// Ref 返回组件在文档内的唯一引用，即组件的purl
+func (c *Component) Ref() string {
+	return c.PURL()
+}
+
+// PURL 返回组件的Maven Package URL
+//
+// 格式为pkg:maven/<groupId>/<artifactId>@<version>，非jar打包类型和分类器以限定符形式附加，
+// 例如pkg:maven/org.example/demo@1.0?classifier=sources&type=war。
+func (c *Component) PURL() string {
+	var b strings.Builder
+	b.WriteString("pkg:maven/")
+	b.WriteString(escapePURLSegment(c.GroupId))
+	b.WriteString("/")
+	b.WriteString(escapePURLSegment(c.ArtifactId))
+	if c.Version != "" {
+		b.WriteString("@")
+		b.WriteString(escapePURLSegment(c.Version))
+	}
+
+	// 限定符按键名字典序排列
+	var qualifiers []string
+	if c.Classifier != "" {
+		qualifiers = append(qualifiers, "classifier="+escapePURLSegment(c.Classifier))
+	}
+	if c.Type != "" && c.Type != "jar" {
+		qualifiers = append(qualifiers, "type="+escapePURLSegment(c.Type))
+	}
+	if len(qualifiers) > 0 {
+		b.WriteString("?")
+		b.WriteString(strings.Join(qualifiers, "&"))
+	}
+	return b.String()
+}
+
+// escapePURLSegment 按purl规范对路径片段进行百分号编码
+func escapePURLSegment(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "+", "%2B")
+}
+
+// newUUID 生成随机的RFC 4122第4版UUID
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,186 @@
+package sbom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestBOM 创建一个包含根组件和两个依赖的测试文档
+func newTestBOM() *BOM {
+	bom := New()
+	lang := &Component{
+		GroupId:    "org.apache.commons",
+		ArtifactId: "commons-lang3",
+		Version:    "3.12.0",
+		Licenses:   []License{{ID: "Apache-2.0"}},
+		Hashes:     map[string]string{HashSHA1: "c6842c86792ff03b9f1d1fe2aab8dc23aa6c6f0e", HashSHA256: "d919d904486c037f8d193412da0c92e22a9fa24230b9d67a57855c5c31c7e94e"},
+	}
+	web := &Component{
+		GroupId:    "org.example",
+		ArtifactId: "web",
+		Version:    "2.0",
+		Type:       "war",
+		Scope:      "provided",
+		Licenses:   []License{{Name: "Example License", URL: "https://example.org/license"}, {ID: "MIT"}},
+	}
+	bom.Root = &Component{GroupId: "org.example", ArtifactId: "app", Version: "1.0", DependsOn: []string{lang.Ref(), web.Ref()}}
+	bom.Components = []*Component{lang, web}
+	return bom
+}
+
+// TestPURL 测试Maven Package URL的生成
+func TestPURL(t *testing.T) {
+	assert.Equal(t, "pkg:maven/org.example/demo@1.0", (&Component{GroupId: "org.example", ArtifactId: "demo", Version: "1.0", Type: "jar"}).PURL())
+	assert.Equal(t, "pkg:maven/org.example/demo@1.0?classifier=sources&type=zip",
+		(&Component{GroupId: "org.example", ArtifactId: "demo", Version: "1.0", Type: "zip", Classifier: "sources"}).PURL())
+	assert.Equal(t, "pkg:maven/org.example/demo@1.0%2Bbuild.1", (&Component{GroupId: "org.example", ArtifactId: "demo", Version: "1.0+build.1"}).PURL())
+
+	format, err := ParseFormat("SPDX")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatSPDXJSON, format)
+	_, err = ParseFormat("swid")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+// TestEncodeCycloneDX 测试CycloneDX JSON和XML编码
+func TestEncodeCycloneDX(t *testing.T) {
+	bom := newTestBOM()
+
+	data, err := Encode(bom, FormatCycloneDXJSON)
+	assert.NoError(t, err)
+	var doc struct {
+		SerialNumber string `json:"serialNumber"`
+		Metadata     struct {
+			Component struct {
+				Name string `json:"name"`
+			} `json:"component"`
+		} `json:"metadata"`
+		Components []struct {
+			Scope    string `json:"scope"`
+			PURL     string `json:"purl"`
+			Hashes   []struct{ Alg, Content string }
+			Licenses []struct {
+				License struct{ ID, Name, URL string } `json:"license"`
+			} `json:"licenses"`
+		} `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, bom.SerialNumber, doc.SerialNumber)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, doc.SerialNumber)
+	assert.Equal(t, "app", doc.Metadata.Component.Name)
+	assert.Equal(t, "required", doc.Components[0].Scope)
+	assert.Equal(t, "optional", doc.Components[1].Scope)
+	assert.Equal(t, "SHA-1", doc.Components[0].Hashes[0].Alg)
+	assert.Equal(t, "Apache-2.0", doc.Components[0].Licenses[0].License.ID)
+	assert.Equal(t, "Example License", doc.Components[1].Licenses[0].License.Name)
+	assert.Equal(t, "pkg:maven/org.example/web@2.0?type=war", doc.Components[1].PURL)
+	assert.Len(t, doc.Dependencies, 3)
+	assert.Len(t, doc.Dependencies[0].DependsOn, 2)
+
+	data, err = Encode(bom, FormatCycloneDXXML)
+	assert.NoError(t, err)
+	var xmlDoc struct {
+		XMLName    xml.Name `xml:"http://cyclonedx.org/schema/bom/1.5 bom"`
+		Version    int      `xml:"version,attr"`
+		Components []struct {
+			Hashes   []string `xml:"hashes>hash"`
+			Licenses []struct {
+				ID   string `xml:"id"`
+				Name string `xml:"name"`
+			} `xml:"licenses>license"`
+		} `xml:"components>component"`
+		Dependencies []struct {
+			Ref      string `xml:"ref,attr"`
+			Children []struct {
+				Ref string `xml:"ref,attr"`
+			} `xml:"dependency"`
+		} `xml:"dependencies>dependency"`
+	}
+	assert.NoError(t, xml.Unmarshal(data, &xmlDoc))
+	assert.Equal(t, 1, xmlDoc.Version)
+	assert.Len(t, xmlDoc.Components, 2)
+	assert.Len(t, xmlDoc.Components[0].Hashes, 2)
+	assert.Equal(t, "MIT", xmlDoc.Components[1].Licenses[1].ID)
+	assert.Len(t, xmlDoc.Dependencies[0].Children, 2)
+}
+
+// TestEncodeSPDX 测试SPDX JSON编码
+func TestEncodeSPDX(t *testing.T) {
+	data, err := Encode(newTestBOM(), FormatSPDXJSON)
+	assert.NoError(t, err)
+
+	var doc struct {
+		SPDXVersion       string `json:"spdxVersion"`
+		DocumentNamespace string `json:"documentNamespace"`
+		Packages          []struct {
+			SPDXID           string `json:"SPDXID"`
+			LicenseDeclared  string `json:"licenseDeclared"`
+			DownloadLocation string `json:"downloadLocation"`
+			Checksums        []struct {
+				Algorithm string `json:"algorithm"`
+			} `json:"checksums"`
+			ExternalRefs []struct {
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+		Relationships []struct {
+			SPDXElementId    string `json:"spdxElementId"`
+			RelationshipType string `json:"relationshipType"`
+		} `json:"relationships"`
+		ExtractedLicenses []struct {
+			LicenseId string   `json:"licenseId"`
+			SeeAlsos  []string `json:"seeAlsos"`
+		} `json:"hasExtractedLicensingInfos"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Contains(t, doc.DocumentNamespace, "org.example-app-1.0")
+	assert.Len(t, doc.Packages, 3)
+	assert.Equal(t, "SPDXRef-Package-org.example-app-1.0", doc.Packages[0].SPDXID)
+	assert.Equal(t, "NOASSERTION", doc.Packages[0].DownloadLocation)
+	assert.Equal(t, "Apache-2.0", doc.Packages[1].LicenseDeclared)
+	assert.Equal(t, "SHA1", doc.Packages[1].Checksums[0].Algorithm)
+	assert.Equal(t, "(LicenseRef-Example-License OR MIT)", doc.Packages[2].LicenseDeclared)
+	assert.Equal(t, "pkg:maven/org.example/web@2.0?type=war", doc.Packages[2].ExternalRefs[0].ReferenceLocator)
+
+	assert.Len(t, doc.Relationships, 3)
+	assert.Equal(t, "DESCRIBES", doc.Relationships[0].RelationshipType)
+	assert.Equal(t, "DEPENDS_ON", doc.Relationships[1].RelationshipType)
+	assert.Equal(t, "LicenseRef-Example-License", doc.ExtractedLicenses[0].LicenseId)
+	assert.Equal(t, []string{"https://example.org/license"}, doc.ExtractedLicenses[0].SeeAlsos)
+}
+
+// TestEncodeSPDXNonASCIILicenseName 测试名称中没有可用字符的许可证生成合法的LicenseRef
+func TestEncodeSPDXNonASCIILicenseName(t *testing.T) {
+	bom := New()
+	bom.Components = []*Component{
+		{GroupId: "org.example", ArtifactId: "a", Version: "1.0", Licenses: []License{{Name: "许可证"}, {Name: "协议"}}},
+		{GroupId: "org.example", ArtifactId: "b", Version: "1.0", Licenses: []License{{Name: "许可证"}}},
+	}
+
+	data, err := Encode(bom, FormatSPDXJSON)
+	assert.NoError(t, err)
+	var doc struct {
+		Packages []struct {
+			LicenseDeclared string `json:"licenseDeclared"`
+		} `json:"packages"`
+		ExtractedLicenses []struct {
+			LicenseId     string `json:"licenseId"`
+			ExtractedText string `json:"extractedText"`
+		} `json:"hasExtractedLicensingInfos"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "(LicenseRef-unknown-1 OR LicenseRef-unknown-2)", doc.Packages[0].LicenseDeclared)
+	assert.Equal(t, "LicenseRef-unknown-1", doc.Packages[1].LicenseDeclared)
+	assert.Len(t, doc.ExtractedLicenses, 2)
+	assert.Equal(t, "LicenseRef-unknown-1", doc.ExtractedLicenses[0].LicenseId)
+	assert.Equal(t, "许可证", doc.ExtractedLicenses[0].ExtractedText)
+	assert.Equal(t, "协议", doc.ExtractedLicenses[1].ExtractedText)
+}
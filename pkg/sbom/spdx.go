@@ -0,0 +1,237 @@
+package sbom
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/license"
+)
+
+// SPDX规范中的固定取值
+const (
+	spdxVersion         = "SPDX-2.3"
+	spdxDataLicense     = "CC0-1.0"
+	spdxDocumentId      = "SPDXRef-DOCUMENT"
+	spdxNoAssertion     = "NOASSERTION"
+	spdxNamespacePrefix = "https://spdx.org/spdxdocs/"
+)
+
+// spdxHashAlgorithms 组件哈希算法名称到SPDX校验和算法名称的映射
+var spdxHashAlgorithms = map[string]string{
+	HashSHA1:   "SHA1",
+	HashSHA256: "SHA256",
+	"SHA-512":  "SHA512",
+	"MD5":      "MD5",
+}
+
+type spdxDocument struct {
+	SPDXVersion       string                  `json:"spdxVersion"`
+	DataLicense       string                  `json:"dataLicense"`
+	SPDXID            string                  `json:"SPDXID"`
+	Name              string                  `json:"name"`
+	DocumentNamespace string                  `json:"documentNamespace"`
+	CreationInfo      *spdxCreationInfo       `json:"creationInfo"`
+	Packages          []*spdxPackage          `json:"packages"`
+	Relationships     []*spdxRelationship     `json:"relationships"`
+	ExtractedLicenses []*spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string             `json:"name"`
+	SPDXID           string             `json:"SPDXID"`
+	VersionInfo      string             `json:"versionInfo,omitempty"`
+	Supplier         string             `json:"supplier,omitempty"`
+	DownloadLocation string             `json:"downloadLocation"`
+	FilesAnalyzed    bool               `json:"filesAnalyzed"`
+	Checksums        []*spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string             `json:"licenseConcluded"`
+	LicenseDeclared  string             `json:"licenseDeclared"`
+	CopyrightText    string             `json:"copyrightText"`
+	Description      string             `json:"description,omitempty"`
+	ExternalRefs     []*spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type spdxExtractedLicense struct {
+	LicenseId     string   `json:"licenseId"`
+	ExtractedText string   `json:"extractedText"`
+	Name          string   `json:"name,omitempty"`
+	SeeAlsos      []string `json:"seeAlsos,omitempty"`
+}
+
+// encodeSPDXJSON 将文档编码为SPDX 2.3 JSON
+func encodeSPDXJSON(bom *BOM) ([]byte, error) {
+	doc := &spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       spdxDataLicense,
+		SPDXID:            spdxDocumentId,
+		Name:              spdxDocumentName(bom),
+		DocumentNamespace: spdxNamespacePrefix + spdxDocumentName(bom) + "-" + strings.TrimPrefix(bom.SerialNumber, "urn:uuid:"),
+		CreationInfo: &spdxCreationInfo{
+			Created:  bom.Timestamp.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + ToolName},
+		},
+		Packages:      make([]*spdxPackage, 0, len(bom.Components)+1),
+		Relationships: make([]*spdxRelationship, 0),
+	}
+
+	// 组件引用到SPDX元素ID的映射，用于转换依赖关系
+	ids := make(map[string]string, len(bom.Components)+1)
+	extracted := make(map[string]*spdxExtractedLicense)
+
+	all := bom.Components
+	if bom.Root != nil {
+		all = append([]*Component{bom.Root}, bom.Components...)
+	}
+	for _, component := range all {
+		pkg := convertSPDXPackage(component, extracted)
+		ids[component.Ref()] = pkg.SPDXID
+		doc.Packages = append(doc.Packages, pkg)
+	}
+
+	// 有根组件时文档只描述根组件，否则描述每个组件
+	if bom.Root != nil {
+		doc.Relationships = append(doc.Relationships, &spdxRelationship{
+			SPDXElementId: spdxDocumentId, RelationshipType: "DESCRIBES", RelatedSPDXElement: ids[bom.Root.Ref()],
+		})
+	} else {
+		for _, component := range bom.Components {
+			doc.Relationships = append(doc.Relationships, &spdxRelationship{
+				SPDXElementId: spdxDocumentId, RelationshipType: "DESCRIBES", RelatedSPDXElement: ids[component.Ref()],
+			})
+		}
+	}
+	for _, component := range all {
+		dependsOn := append([]string(nil), component.DependsOn...)
+		sort.Strings(dependsOn)
+		for _, ref := range dependsOn {
+			target, ok := ids[ref]
+			if !ok {
+				continue
+			}
+			doc.Relationships = append(doc.Relationships, &spdxRelationship{
+				SPDXElementId: ids[component.Ref()], RelationshipType: "DEPENDS_ON", RelatedSPDXElement: target,
+			})
+		}
+	}
+
+	licenseIds := make([]string, 0, len(extracted))
+	for id := range extracted {
+		licenseIds = append(licenseIds, id)
+	}
+	sort.Strings(licenseIds)
+	for _, id := range licenseIds {
+		doc.ExtractedLicenses = append(doc.ExtractedLicenses, extracted[id])
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// convertSPDXPackage 转换单个组件，无法识别的许可证登记到extracted中
+func convertSPDXPackage(c *Component, extracted map[string]*spdxExtractedLicense) *spdxPackage {
+	pkg := &spdxPackage{
+		Name:             c.GroupId + ":" + c.ArtifactId,
+		SPDXID:           "SPDXRef-Package-" + license.SanitizeIDString(c.Coordinate()+"-"+c.Classifier+"-"+c.Type),
+		VersionInfo:      c.Version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxNoAssertion,
+		CopyrightText:    spdxNoAssertion,
+		Description:      c.Description,
+		ExternalRefs: []*spdxExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  c.PURL(),
+		}},
+	}
+	if c.DownloadURL != "" {
+		pkg.DownloadLocation = c.DownloadURL
+	}
+
+	algorithms := make([]string, 0, len(c.Hashes))
+	for alg := range c.Hashes {
+		if _, ok := spdxHashAlgorithms[alg]; ok {
+			algorithms = append(algorithms, alg)
+		}
+	}
+	sort.Strings(algorithms)
+	for _, alg := range algorithms {
+		pkg.Checksums = append(pkg.Checksums, &spdxChecksum{Algorithm: spdxHashAlgorithms[alg], ChecksumValue: c.Hashes[alg]})
+	}
+
+	// 多个许可证在Maven中表示可以任选其一，对应SPDX的OR表达式
+	terms := make([]string, 0, len(c.Licenses))
+	for _, l := range c.Licenses {
+		if l.ID != "" {
+			terms = append(terms, l.ID)
+			continue
+		}
+		name := l.Name
+		if name == "" {
+			name = l.URL
+		}
+		if name == "" {
+			continue
+		}
+		id := spdxLicenseRef(name, extracted)
+		if _, ok := extracted[id]; !ok {
+			info := &spdxExtractedLicense{LicenseId: id, ExtractedText: name, Name: name}
+			if l.URL != "" {
+				info.SeeAlsos = []string{l.URL}
+			}
+			extracted[id] = info
+		}
+		terms = append(terms, id)
+	}
+	if len(terms) == 1 {
+		pkg.LicenseDeclared = terms[0]
+	} else if len(terms) > 1 {
+		pkg.LicenseDeclared = "(" + strings.Join(terms, " OR ") + ")"
+	}
+	return pkg
+}
+
+// spdxLicenseRef 返回许可证名称在文档中的LicenseRef，同名的许可证共用一个标识符；
+// 名称中没有可用字符时按文档中出现的顺序编号为LicenseRef-unknown-<n>
+func spdxLicenseRef(name string, extracted map[string]*spdxExtractedLicense) string {
+	if license.SanitizeIDString(name) != "" {
+		return license.LicenseRef(name, 0)
+	}
+	for n := 1; ; n++ {
+		id := license.LicenseRef(name, n)
+		if info, ok := extracted[id]; !ok || info.Name == name {
+			return id
+		}
+	}
+}
+
+// spdxDocumentName 根据根组件生成文档名称
+func spdxDocumentName(bom *BOM) string {
+	if bom.Root != nil {
+		return license.SanitizeIDString(bom.Root.Coordinate())
+	}
+	return ToolName + "-sbom"
+}
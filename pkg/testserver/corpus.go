@@ -0,0 +1,327 @@
+package testserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/pom"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/version"
+)
+
+// checksumExtensions 仓库中的校验和签名文件扩展名，这些文件不作为制品建立索引
+var checksumExtensions = []string{".sha1", ".md5", ".sha256", ".sha512", ".asc"}
+
+// Document 语料库中的一个制品版本，对应搜索API中core=gav的一条文档
+type Document struct {
+	// GroupId 组ID
+	GroupId string `json:"g"`
+
+	// ArtifactId 制品ID
+	ArtifactId string `json:"a"`
+
+	// Version 版本号
+	Version string `json:"v"`
+
+	// Packaging 打包类型，为空时为jar
+	Packaging string `json:"p,omitempty"`
+
+	// Timestamp 发布时间（毫秒）
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// Extensions 该版本包含的文件后缀，如".jar"、"-sources.jar"、".pom"
+	Extensions []string `json:"ec,omitempty"`
+
+	// Tags 标签
+	Tags []string `json:"tags,omitempty"`
+
+	// Classes 包含的全限定类名，可以通过fc、c字段搜索
+	Classes []string `json:"fc,omitempty"`
+
+	// Sha1s 该版本中各个文件的SHA-1，可以通过1字段搜索
+	Sha1s []string `json:"sha1,omitempty"`
+}
+
+// ID 返回文档ID，格式为groupId:artifactId:version
+func (d *Document) ID() string {
+	return d.GroupId + ":" + d.ArtifactId + ":" + d.Version
+}
+
+// merge 将other中的非空字段合并到文档中，列表字段取并集
+func (d *Document) merge(other *Document) {
+	if other.Packaging != "" {
+		d.Packaging = other.Packaging
+	}
+	if other.Timestamp != 0 {
+		d.Timestamp = other.Timestamp
+	}
+	d.Extensions = appendUnique(d.Extensions, other.Extensions...)
+	d.Tags = appendUnique(d.Tags, other.Tags...)
+	d.Classes = appendUnique(d.Classes, other.Classes...)
+	d.Sha1s = appendUnique(d.Sha1s, other.Sha1s...)
+}
+
+// Corpus 测试服务器使用的语料库，包含仓库文件和搜索文档
+//
+// 搜索文档可以直接添加，也可以根据添加的仓库文件自动生成：文件路径决定坐标，
+// 文件后缀、SHA-1、JAR中的类名和POM中的打包类型会被写入对应版本的文档。
+// Corpus是并发安全的，可以在服务器运行时继续添加内容。
+type Corpus struct {
+	mu    sync.RWMutex
+	docs  map[string]*Document
+	files map[string][]byte
+
+	// noChecksums 为true时不为仓库文件生成校验文件
+	noChecksums bool
+}
+
+// NewCorpus 创建空的语料库
+func NewCorpus() *Corpus {
+	return &Corpus{
+		docs:  make(map[string]*Document),
+		files: make(map[string][]byte),
+	}
+}
+
+// LoadCorpus 从目录加载语料库
+//
+// 目录结构如下，两部分都是可选的：
+//
+//	dir/
+//	├── maven2/          Maven仓库布局的文件，如maven2/org/example/demo/1.0/demo-1.0.jar
+//	└── search.json      搜索文档数组，用于补充时间戳、标签等无法从文件得到的字段
+//
+// 参数:
+//   - dir: 语料库目录
+//
+// 返回:
+//   - *Corpus: 加载的语料库
+//   - error: 读取或解析失败时返回错误
+//
+// 使用示例:
+//
+//	corpus, err := testserver.LoadCorpus("testdata/corpus")
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	server := testserver.New(corpus)
+//	defer server.Close()
+func LoadCorpus(dir string) (*Corpus, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("读取语料库目录失败: %w", err)
+	}
+
+	corpus := NewCorpus()
+	repoDir := filepath.Join(dir, "maven2")
+	if _, err := os.Stat(repoDir); err == nil {
+		err = filepath.WalkDir(repoDir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(repoDir, p)
+			if err != nil {
+				return err
+			}
+			corpus.AddFile(filepath.ToSlash(rel), data)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("读取仓库文件失败: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "search.json"))
+	switch {
+	case err == nil:
+		var docs []*Document
+		if err := json.Unmarshal(data, &docs); err != nil {
+			return nil, fmt.Errorf("解析search.json失败: %w", err)
+		}
+		for _, doc := range docs {
+			corpus.AddDocument(doc)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("读取search.json失败: %w", err)
+	}
+
+	return corpus, nil
+}
+
+// AddDocument 添加搜索文档，同一坐标的文档已存在时合并
+func (c *Corpus) AddDocument(doc *Document) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.document(doc.GroupId, doc.ArtifactId, doc.Version).merge(doc)
+}
+
+// AddFile 添加仓库文件并更新对应版本的搜索文档
+//
+// repoPath是文件在仓库中的相对路径，如"org/example/demo/1.0/demo-1.0.jar"。
+// 不符合Maven仓库布局的文件（如maven-metadata.xml）和校验文件只提供下载，不建立索引。
+func (c *Corpus) AddFile(repoPath string, data []byte) {
+	repoPath = strings.TrimPrefix(path.Clean("/"+repoPath), "/")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files[repoPath] = data
+
+	groupId, artifactId, ver, suffix, ok := parseRepoPath(repoPath)
+	if !ok {
+		return
+	}
+	for _, ext := range checksumExtensions {
+		if strings.HasSuffix(suffix, ext) {
+			return
+		}
+	}
+
+	doc := c.document(groupId, artifactId, ver)
+	sum := sha1.Sum(data)
+	doc.Sha1s = appendUnique(doc.Sha1s, hex.EncodeToString(sum[:]))
+	doc.Extensions = appendUnique(doc.Extensions, suffix)
+
+	switch {
+	case suffix == ".pom":
+		if project, err := pom.Parse(data); err == nil && project.Packaging != "" {
+			doc.Packaging = project.Packaging
+		}
+	case !strings.HasPrefix(suffix, "-") && (suffix == ".jar" || suffix == ".war" || suffix == ".aar"):
+		doc.Classes = appendUnique(doc.Classes, jarClasses(data)...)
+		if doc.Packaging == "" {
+			doc.Packaging = strings.TrimPrefix(suffix, ".")
+		}
+	}
+}
+
+// Documents 返回语料库中的所有文档，按坐标排序
+func (c *Corpus) Documents() []*Document {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	docs := make([]*Document, 0, len(c.docs))
+	for _, doc := range c.docs {
+		copied := *doc
+		docs = append(docs, &copied)
+	}
+	sortDocuments(docs)
+	return docs
+}
+
+// File 返回仓库文件的内容
+func (c *Corpus) File(repoPath string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	data, ok := c.files[strings.TrimPrefix(repoPath, "/")]
+	return data, ok
+}
+
+// SetGenerateChecksums 设置仓库文件没有对应的校验文件时，服务器是否自动生成，默认生成
+//
+// 关闭后只返回通过AddFile添加的校验文件，可以用来模拟缺少校验文件或校验文件错误的仓库。
+func (c *Corpus) SetGenerateChecksums(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.noChecksums = !enabled
+}
+
+// generatesChecksums 判断是否自动生成校验文件
+func (c *Corpus) generatesChecksums() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.noChecksums
+}
+
+// document 返回指定坐标的文档，不存在时创建，调用方需要持有写锁
+func (c *Corpus) document(groupId, artifactId, ver string) *Document {
+	id := groupId + ":" + artifactId + ":" + ver
+	doc, ok := c.docs[id]
+	if !ok {
+		doc = &Document{GroupId: groupId, ArtifactId: artifactId, Version: ver}
+		c.docs[id] = doc
+	}
+	return doc
+}
+
+// parseRepoPath 解析group/path/artifact/version/artifact-version[-classifier].ext形式的仓库路径
+func parseRepoPath(repoPath string) (groupId, artifactId, ver, suffix string, ok bool) {
+	parts := strings.Split(repoPath, "/")
+	if len(parts) < 4 {
+		return "", "", "", "", false
+	}
+	n := len(parts)
+	artifactId, ver = parts[n-3], parts[n-2]
+	prefix := artifactId + "-" + ver
+	if !strings.HasPrefix(parts[n-1], prefix) {
+		return "", "", "", "", false
+	}
+	suffix = strings.TrimPrefix(parts[n-1], prefix)
+	if !strings.HasPrefix(suffix, ".") && !strings.HasPrefix(suffix, "-") {
+		return "", "", "", "", false
+	}
+	return strings.Join(parts[:n-3], "."), artifactId, ver, suffix, true
+}
+
+// jarClasses 返回JAR中的顶层类名，内部类、module-info和package-info不包括在内
+func jarClasses(data []byte) []string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil
+	}
+	var classes []string
+	for _, f := range reader.File {
+		name := f.Name
+		if !strings.HasSuffix(name, ".class") || strings.HasPrefix(name, "META-INF/") || strings.Contains(name, "$") {
+			continue
+		}
+		base := path.Base(name)
+		if base == "module-info.class" || base == "package-info.class" {
+			continue
+		}
+		classes = append(classes, strings.ReplaceAll(strings.TrimSuffix(name, ".class"), "/", "."))
+	}
+	return classes
+}
+
+// sortDocuments 按groupId、artifactId升序，版本降序排序
+func sortDocuments(docs []*Document) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		if docs[i].GroupId != docs[j].GroupId {
+			return docs[i].GroupId < docs[j].GroupId
+		}
+		if docs[i].ArtifactId != docs[j].ArtifactId {
+			return docs[i].ArtifactId < docs[j].ArtifactId
+		}
+		return version.Compare(docs[i].Version, docs[j].Version) > 0
+	})
+}
+
+// appendUnique 追加list中不存在的值
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		exists := false
+		for _, existing := range list {
+			if existing == value {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,315 @@
+package testserver
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/version"
+)
+
+// textFields 分词匹配的字段，其他字段按整个值匹配（不区分大小写）
+var textFields = map[string]bool{
+	"":                                   true,
+	"text":                               true,
+	request.FieldTags:                    true,
+	request.FieldClassName:               true,
+	request.FieldFullyQualifiedClassName: true,
+}
+
+// fieldValues 返回文档中某个字段的所有值，字段不存在时返回nil
+func fieldValues(doc *Document, field string) []string {
+	switch field {
+	case request.FieldGroupId:
+		return []string{doc.GroupId}
+	case request.FieldArtifactId:
+		return []string{doc.ArtifactId}
+	case request.FieldVersion, "latestVersion":
+		return []string{doc.Version}
+	case request.FieldPackaging:
+		return []string{doc.packaging()}
+	case request.FieldId:
+		return []string{doc.ID(), doc.GroupId + ":" + doc.ArtifactId}
+	case request.FieldTimestamp:
+		return []string{strconv.FormatInt(doc.Timestamp, 10)}
+	case request.FieldTags:
+		return doc.Tags
+	case request.FieldSha1:
+		return doc.Sha1s
+	case request.FieldFullyQualifiedClassName:
+		return doc.Classes
+	case request.FieldClassName:
+		names := make([]string, 0, len(doc.Classes))
+		for _, class := range doc.Classes {
+			names = append(names, class[strings.LastIndex(class, ".")+1:])
+		}
+		return names
+	case request.FieldClassifier:
+		var classifiers []string
+		for _, ext := range doc.Extensions {
+			if strings.HasPrefix(ext, "-") {
+				classifiers = append(classifiers, strings.TrimPrefix(ext[:strings.LastIndex(ext, ".")], "-"))
+			}
+		}
+		return classifiers
+	case "ec":
+		return doc.Extensions
+	case "", "text":
+		return append([]string{doc.GroupId, doc.ArtifactId, doc.ID()}, doc.Tags...)
+	}
+	return nil
+}
+
+// packaging 返回打包类型，未设置时为jar
+func (d *Document) packaging() string {
+	if d.Packaging == "" {
+		return "jar"
+	}
+	return d.Packaging
+}
+
+// matches 判断文档是否匹配查询表达式，nil匹配所有文档
+func matches(expr request.Expr, doc *Document) bool {
+	switch e := expr.(type) {
+	case nil, *request.MatchAllExpr:
+		return true
+	case *request.AndExpr:
+		for _, sub := range e.Exprs {
+			if !matches(sub, doc) {
+				return false
+			}
+		}
+		return true
+	case *request.OrExpr:
+		for _, sub := range e.Exprs {
+			if matches(sub, doc) {
+				return true
+			}
+		}
+		return false
+	case *request.NotExpr:
+		return !matches(e.Expr, doc)
+	case *request.RangeExpr:
+		for _, value := range fieldValues(doc, e.Field) {
+			if inRange(e, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	field, ok := valueField(expr)
+	if !ok {
+		return false
+	}
+	for _, value := range fieldValues(doc, field) {
+		if _, _, ok := matchValue(expr, field, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// valueField 返回值表达式（普通值、短语、通配符）的字段
+func valueField(expr request.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *request.TermExpr:
+		return e.Field, true
+	case *request.PhraseExpr:
+		return e.Field, true
+	case *request.WildcardExpr:
+		return e.Field, true
+	}
+	return "", false
+}
+
+// matchValue 判断字段值是否匹配值表达式，匹配时返回值中匹配部分的起止位置
+//
+// 分词字段中，查询词的分词结果是值的分词结果中连续的一段即为匹配，
+// 例如fc:org.apache.commons.io匹配org.apache.commons.io.FileUtils。
+func matchValue(expr request.Expr, field, value string) (int, int, bool) {
+	var query string
+	switch e := expr.(type) {
+	case *request.TermExpr:
+		query = e.Value
+	case *request.PhraseExpr:
+		query = e.Text
+	case *request.WildcardExpr:
+		if wildcardPattern(e.Pattern).MatchString(value) {
+			return 0, len(value), true
+		}
+		if textFields[field] {
+			for _, token := range tokenize(value) {
+				if wildcardPattern(e.Pattern).MatchString(value[token[0]:token[1]]) {
+					return token[0], token[1], true
+				}
+			}
+		}
+		return 0, 0, false
+	default:
+		return 0, 0, false
+	}
+
+	if strings.EqualFold(query, value) {
+		return 0, len(value), true
+	}
+	if !textFields[field] {
+		return 0, 0, false
+	}
+
+	valueTokens, queryTokens := tokenize(value), tokenize(query)
+	if len(queryTokens) == 0 {
+		return 0, 0, false
+	}
+	for i := 0; i+len(queryTokens) <= len(valueTokens); i++ {
+		matched := true
+		for j, token := range queryTokens {
+			v := valueTokens[i+j]
+			if !strings.EqualFold(value[v[0]:v[1]], query[token[0]:token[1]]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return valueTokens[i][0], valueTokens[i+len(queryTokens)-1][1], true
+		}
+	}
+	return 0, 0, false
+}
+
+// tokenize 按字母和数字以外的字符分词，返回每个词的起止位置
+func tokenize(s string) [][2]int {
+	var tokens [][2]int
+	start := -1
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			tokens = append(tokens, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, [2]int{start, len(s)})
+	}
+	return tokens
+}
+
+// wildcardPattern 将通配符模式转换为不区分大小写的正则表达式
+func wildcardPattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// inRange 判断值是否在范围内，两端都是整数时按数值比较，版本字段按版本比较，其他按字符串比较
+func inRange(r *request.RangeExpr, value string) bool {
+	compare := func(a, b string) int {
+		x, errX := strconv.ParseInt(a, 10, 64)
+		y, errY := strconv.ParseInt(b, 10, 64)
+		switch {
+		case errX == nil && errY == nil:
+			return compareInt(x, y)
+		case r.Field == request.FieldVersion:
+			return version.Compare(a, b)
+		}
+		return strings.Compare(a, b)
+	}
+
+	if r.From != "" {
+		c := compare(value, r.From)
+		if c < 0 || c == 0 && !r.IncludeFrom {
+			return false
+		}
+	}
+	if r.To != "" {
+		c := compare(value, r.To)
+		if c > 0 || c == 0 && !r.IncludeTo {
+			return false
+		}
+	}
+	return true
+}
+
+// compareInt 比较两个整数
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// usesVersionFields 查询是否引用了只存在于版本文档中的字段，此时即使没有指定core=gav也返回版本文档
+func usesVersionFields(expr request.Expr) bool {
+	switch e := expr.(type) {
+	case *request.AndExpr:
+		for _, sub := range e.Exprs {
+			if usesVersionFields(sub) {
+				return true
+			}
+		}
+	case *request.OrExpr:
+		for _, sub := range e.Exprs {
+			if usesVersionFields(sub) {
+				return true
+			}
+		}
+	case *request.NotExpr:
+		return usesVersionFields(e.Expr)
+	case *request.RangeExpr:
+		return isVersionField(e.Field)
+	default:
+		field, ok := valueField(expr)
+		return ok && isVersionField(field)
+	}
+	return false
+}
+
+// isVersionField 是否是只存在于版本文档中的字段
+func isVersionField(field string) bool {
+	switch field {
+	case request.FieldVersion, request.FieldSha1, request.FieldClassName, request.FieldFullyQualifiedClassName, request.FieldClassifier:
+		return true
+	}
+	return false
+}
+
+// highlightTerms 收集查询中针对某个字段的值表达式，用于生成高亮片段
+func highlightTerms(expr request.Expr, field string) []request.Expr {
+	switch e := expr.(type) {
+	case *request.AndExpr:
+		var terms []request.Expr
+		for _, sub := range e.Exprs {
+			terms = append(terms, highlightTerms(sub, field)...)
+		}
+		return terms
+	case *request.OrExpr:
+		var terms []request.Expr
+		for _, sub := range e.Exprs {
+			terms = append(terms, highlightTerms(sub, field)...)
+		}
+		return terms
+	}
+	if f, ok := valueField(expr); ok && f == field {
+		return []request.Expr{expr}
+	}
+	return nil
+}
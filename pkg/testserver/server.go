@@ -0,0 +1,647 @@
+// Package testserver 提供进程内的Maven Central模拟服务器，用于编写不依赖网络的确定性测试
+//
+// 服务器模拟搜索API（/solrsearch/select，支持q、rows、start、core、sort、fl、facet、hl参数）
+// 和仓库布局（/maven2/...，自动生成校验文件和制品级maven-metadata.xml），
+// 数据来自一个可以从目录加载的语料库。
+//
+// 使用示例:
+//
+//	server, err := testserver.NewFromDir("testdata/corpus")
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer server.Close()
+//
+//	client := api.NewClient(
+//	    api.WithBaseURL(server.URL),
+//	    api.WithRepoBaseURL(server.RepoURL()),
+//	)
+//	versions, err := client.ListVersions(ctx, "org.example", "demo", 0)
+package testserver
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scagogogo/sonatype-central-sdk/pkg/request"
+	"github.com/scagogogo/sonatype-central-sdk/pkg/version"
+)
+
+const (
+	// searchPath 搜索API的路径
+	searchPath = "/solrsearch/select"
+
+	// repoPrefix 仓库文件的路径前缀
+	repoPrefix = "/maven2/"
+
+	// defaultRows 未指定rows参数时返回的文档数
+	defaultRows = 20
+
+	// defaultFacetLimit 未指定facet.limit参数时每个聚合字段返回的值数
+	defaultFacetLimit = 100
+)
+
+// Server 运行在本地端口上的模拟服务器
+type Server struct {
+	*httptest.Server
+
+	handler *handler
+}
+
+// New 使用语料库启动模拟服务器
+//
+// 参数:
+//   - corpus: 语料库，服务器运行期间添加的内容会立即生效
+//
+// 返回:
+//   - *Server: 已启动的服务器，使用完毕后需要调用Close
+func New(corpus *Corpus) *Server {
+	h := &handler{corpus: corpus}
+	return &Server{Server: httptest.NewServer(h), handler: h}
+}
+
+// NewFromDir 从目录加载语料库并启动模拟服务器，目录结构见LoadCorpus
+func NewFromDir(dir string) (*Server, error) {
+	corpus, err := LoadCorpus(dir)
+	if err != nil {
+		return nil, err
+	}
+	return New(corpus), nil
+}
+
+// RepoURL 返回仓库的基础URL，用于api.WithRepoBaseURL
+func (s *Server) RepoURL() string {
+	return s.URL + strings.TrimSuffix(repoPrefix, "/")
+}
+
+// Corpus 返回服务器使用的语料库
+func (s *Server) Corpus() *Corpus {
+	return s.handler.corpus
+}
+
+// Requests 返回服务器收到的所有请求的URL，按接收顺序排列
+func (s *Server) Requests() []*url.URL {
+	return s.handler.requestLog()
+}
+
+// Transport 返回直接在进程内调用服务器处理函数的http.RoundTripper
+//
+// 所有请求不论主机名都交给模拟服务器处理，因此客户端可以保留默认的搜索和仓库地址。
+//
+// 使用示例:
+//
+//	client := api.NewClient(api.WithTransport(server.Transport()))
+func (s *Server) Transport() http.RoundTripper {
+	return &handlerTransport{handler: s.handler}
+}
+
+// NewHandler 返回模拟服务器的http.Handler，可以挂载到自定义的服务器上
+func NewHandler(corpus *Corpus) http.Handler {
+	return &handler{corpus: corpus}
+}
+
+// NewTransport 返回不监听端口、直接在进程内处理请求的http.RoundTripper
+func NewTransport(corpus *Corpus) http.RoundTripper {
+	return &handlerTransport{handler: &handler{corpus: corpus}}
+}
+
+// handlerTransport 在进程内调用http.Handler的RoundTripper
+type handlerTransport struct {
+	handler http.Handler
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// handler 模拟服务器的请求处理
+type handler struct {
+	corpus *Corpus
+
+	mu       sync.Mutex
+	requests []*url.URL
+}
+
+// ServeHTTP 实现http.Handler接口
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	copied := *r.URL
+	h.requests = append(h.requests, &copied)
+	h.mu.Unlock()
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case r.URL.Path == searchPath:
+		h.search(w, r)
+	case strings.HasPrefix(r.URL.Path, repoPrefix):
+		h.repository(w, strings.TrimPrefix(r.URL.Path, repoPrefix))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// requestLog 返回请求记录的副本
+func (h *handler) requestLog() []*url.URL {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*url.URL(nil), h.requests...)
+}
+
+// searchHit 一条搜索结果，制品文档中doc为最新版本，versionCount为版本数
+type searchHit struct {
+	doc          *Document
+	versionCount int
+	artifact     bool
+}
+
+// search 处理搜索API请求
+func (h *handler) search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	q := params.Get("q")
+	if q == "" {
+		writeSearchError(w, "缺少q参数")
+		return
+	}
+	expr, err := request.ParseQuery(q)
+	if err != nil {
+		writeSearchError(w, err.Error())
+		return
+	}
+	rows, err := intParam(params, "rows", defaultRows)
+	if err != nil {
+		writeSearchError(w, err.Error())
+		return
+	}
+	start, err := intParam(params, "start", 0)
+	if err != nil {
+		writeSearchError(w, err.Error())
+		return
+	}
+
+	all := h.corpus.Documents()
+	var matched []*Document
+	for _, doc := range all {
+		if matches(expr, doc) {
+			matched = append(matched, doc)
+		}
+	}
+
+	var hits []*searchHit
+	if params.Get("core") == "gav" || usesVersionFields(expr) {
+		for _, doc := range matched {
+			hits = append(hits, &searchHit{doc: doc})
+		}
+	} else {
+		hits = artifactHits(matched, all)
+	}
+	if err := sortHits(hits, params.Get("sort")); err != nil {
+		writeSearchError(w, err.Error())
+		return
+	}
+
+	page := hits[min(start, len(hits)):min(start+rows, len(hits))]
+	fields := fieldList(params.Get("fl"))
+	docs := make([]map[string]interface{}, 0, len(page))
+	for _, hit := range page {
+		docs = append(docs, hit.render(fields))
+	}
+
+	body := map[string]interface{}{
+		"responseHeader": map[string]interface{}{
+			"status": 0,
+			"QTime":  0,
+			"params": map[string]string{
+				"q":     q,
+				"core":  params.Get("core"),
+				"fl":    params.Get("fl"),
+				"start": strconv.Itoa(start),
+				"sort":  params.Get("sort"),
+				"rows":  strconv.Itoa(rows),
+				"wt":    "json",
+			},
+		},
+		"response": map[string]interface{}{
+			"numFound": len(hits),
+			"start":    start,
+			"docs":     docs,
+		},
+	}
+	if params.Get("facet") == "true" {
+		facetLimit, err := intParam(params, "facet.limit", defaultFacetLimit)
+		if err != nil {
+			writeSearchError(w, err.Error())
+			return
+		}
+		body["facet_counts"] = map[string]interface{}{
+			"facet_fields": facetCounts(hits, params["facet.field"], facetLimit),
+		}
+	}
+	if params.Get("hl") == "true" {
+		snippets, err := intParam(params, "hl.snippets", 1)
+		if err != nil {
+			writeSearchError(w, err.Error())
+			return
+		}
+		body["highlighting"] = highlighting(expr, page, fieldList(params.Get("hl.fl")), snippets)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// artifactHits 将匹配的版本按制品聚合，版本数和最新版本根据语料库中该制品的所有版本计算
+func artifactHits(matched, all []*Document) []*searchHit {
+	versions := make(map[string][]*Document)
+	for _, doc := range all {
+		key := doc.GroupId + ":" + doc.ArtifactId
+		versions[key] = append(versions[key], doc)
+	}
+
+	var hits []*searchHit
+	seen := make(map[string]bool)
+	for _, doc := range matched {
+		key := doc.GroupId + ":" + doc.ArtifactId
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		all := versions[key]
+		names := make([]string, 0, len(all))
+		for _, v := range all {
+			if !version.Parse(v.Version).IsSnapshot() {
+				names = append(names, v.Version)
+			}
+		}
+		if len(names) == 0 {
+			for _, v := range all {
+				names = append(names, v.Version)
+			}
+		}
+		latestVersion := version.Max(names)
+
+		var latest Document
+		var tags []string
+		for _, v := range all {
+			if v.Version == latestVersion {
+				latest = *v
+			}
+			tags = appendUnique(tags, v.Tags...)
+		}
+		latest.Tags = tags
+		hits = append(hits, &searchHit{doc: &latest, versionCount: len(all), artifact: true})
+	}
+	return hits
+}
+
+// render 将搜索结果转换为JSON文档，fields不为空时只保留其中的字段
+func (hit *searchHit) render(fields []string) map[string]interface{} {
+	doc := hit.doc
+	var rendered map[string]interface{}
+	if hit.artifact {
+		rendered = map[string]interface{}{
+			"id":            doc.GroupId + ":" + doc.ArtifactId,
+			"g":             doc.GroupId,
+			"a":             doc.ArtifactId,
+			"latestVersion": doc.Version,
+			"repositoryId":  "central",
+			"p":             doc.packaging(),
+			"timestamp":     doc.Timestamp,
+			"versionCount":  hit.versionCount,
+			"text":          append([]string{doc.GroupId, doc.ArtifactId}, doc.Tags...),
+			"ec":            nonNil(doc.Extensions),
+		}
+	} else {
+		rendered = map[string]interface{}{
+			"id":        doc.ID(),
+			"g":         doc.GroupId,
+			"a":         doc.ArtifactId,
+			"v":         doc.Version,
+			"p":         doc.packaging(),
+			"timestamp": doc.Timestamp,
+			"ec":        nonNil(doc.Extensions),
+		}
+	}
+	if len(doc.Tags) > 0 {
+		rendered["tags"] = doc.Tags
+	}
+
+	if len(fields) == 0 {
+		return rendered
+	}
+	filtered := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := rendered[field]; ok {
+			filtered[field] = value
+		}
+	}
+	return filtered
+}
+
+// sortHits 按sort参数排序，参数格式为"字段 asc|desc"，多个条件用逗号分隔
+func sortHits(hits []*searchHit, spec string) error {
+	type sortClause struct {
+		field      string
+		descending bool
+	}
+	var clauses []sortClause
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Fields(part)
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) != 2 || fields[1] != "asc" && fields[1] != "desc":
+			return fmt.Errorf("无效的sort参数: %q", spec)
+		}
+		clauses = append(clauses, sortClause{field: fields[0], descending: fields[1] == "desc"})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		for _, clause := range clauses {
+			c := compareHits(hits[i], hits[j], clause.field)
+			if c == 0 {
+				continue
+			}
+			if clause.descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
+
+// compareHits 按字段比较两条搜索结果
+func compareHits(a, b *searchHit, field string) int {
+	switch field {
+	case request.FieldTimestamp:
+		return compareInt(a.doc.Timestamp, b.doc.Timestamp)
+	case "versionCount":
+		return compareInt(int64(a.versionCount), int64(b.versionCount))
+	case request.FieldVersion, "latestVersion":
+		return version.Compare(a.doc.Version, b.doc.Version)
+	}
+	return strings.Compare(firstValue(a.doc, field), firstValue(b.doc, field))
+}
+
+// firstValue 返回字段的第一个值
+func firstValue(doc *Document, field string) string {
+	if values := fieldValues(doc, field); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// facetCounts 统计聚合字段的取值，按数量降序、取值升序排列，格式与Solr一致为[值, 数量, 值, 数量...]
+func facetCounts(hits []*searchHit, fields []string, limit int) map[string][]interface{} {
+	result := make(map[string][]interface{}, len(fields))
+	for _, field := range fields {
+		counts := make(map[string]int)
+		for _, hit := range hits {
+			seen := make(map[string]bool)
+			for _, value := range fieldValues(hit.doc, field) {
+				if !seen[value] {
+					seen[value] = true
+					counts[value]++
+				}
+			}
+		}
+
+		values := make([]string, 0, len(counts))
+		for value := range counts {
+			values = append(values, value)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if counts[values[i]] != counts[values[j]] {
+				return counts[values[i]] > counts[values[j]]
+			}
+			return values[i] < values[j]
+		})
+		if limit >= 0 && len(values) > limit {
+			values = values[:limit]
+		}
+
+		flat := make([]interface{}, 0, len(values)*2)
+		for _, value := range values {
+			flat = append(flat, value, counts[value])
+		}
+		result[field] = flat
+	}
+	return result
+}
+
+// highlighting 生成高亮片段，匹配部分用<em>标签包围
+//
+// 与Central一致，fch和ch分别表示全限定类名和简单类名的高亮字段。
+func highlighting(expr request.Expr, hits []*searchHit, fields []string, snippets int) map[string]map[string][]string {
+	result := make(map[string]map[string][]string, len(hits))
+	for _, hit := range hits {
+		id := hit.doc.ID()
+		if hit.artifact {
+			id = hit.doc.GroupId + ":" + hit.doc.ArtifactId
+		}
+		entry := make(map[string][]string)
+		for _, hlField := range fields {
+			field := hlField
+			switch hlField {
+			case "fch":
+				field = request.FieldFullyQualifiedClassName
+			case "ch":
+				field = request.FieldClassName
+			}
+
+			var fragments []string
+			for _, value := range fieldValues(hit.doc, field) {
+				if len(fragments) >= snippets {
+					break
+				}
+				for _, term := range highlightTerms(expr, field) {
+					if start, end, ok := matchValue(term, field, value); ok {
+						fragments = append(fragments, value[:start]+"<em>"+value[start:end]+"</em>"+value[end:])
+						break
+					}
+				}
+			}
+			if len(fragments) > 0 {
+				entry[hlField] = fragments
+			}
+		}
+		result[id] = entry
+	}
+	return result
+}
+
+// repository 处理仓库文件请求
+func (h *handler) repository(w http.ResponseWriter, repoPath string) {
+	data, ok := h.repositoryFile(repoPath)
+	if !ok {
+		newHash := map[string]func() hash.Hash{
+			".sha1":   sha1.New,
+			".md5":    md5.New,
+			".sha256": sha256.New,
+			".sha512": sha512.New,
+		}[path.Ext(repoPath)]
+		if newHash == nil || !h.corpus.generatesChecksums() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		base, ok := h.repositoryFile(strings.TrimSuffix(repoPath, path.Ext(repoPath)))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		sum := newHash()
+		sum.Write(base)
+		data = []byte(hex.EncodeToString(sum.Sum(nil)))
+	}
+
+	switch path.Ext(repoPath) {
+	case ".pom", ".xml":
+		w.Header().Set("Content-Type", "text/xml")
+	case ".sha1", ".md5", ".sha256", ".sha512", ".asc":
+		w.Header().Set("Content-Type", "text/plain")
+	default:
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	_, _ = w.Write(data)
+}
+
+// repositoryFile 返回仓库文件，制品级的maven-metadata.xml不存在时根据语料库生成
+func (h *handler) repositoryFile(repoPath string) ([]byte, bool) {
+	if data, ok := h.corpus.File(repoPath); ok {
+		return data, true
+	}
+	if path.Base(repoPath) != "maven-metadata.xml" {
+		return nil, false
+	}
+
+	dir := path.Dir(repoPath)
+	groupId := strings.ReplaceAll(path.Dir(dir), "/", ".")
+	artifactId := path.Base(dir)
+	var versions []string
+	var lastUpdated int64
+	for _, doc := range h.corpus.Documents() {
+		if doc.GroupId == groupId && doc.ArtifactId == artifactId {
+			versions = append(versions, doc.Version)
+			if doc.Timestamp > lastUpdated {
+				lastUpdated = doc.Timestamp
+			}
+		}
+	}
+	if len(versions) == 0 {
+		return nil, false
+	}
+	return mavenMetadata(groupId, artifactId, versions, lastUpdated), true
+}
+
+// mavenMetadata 生成制品级的maven-metadata.xml
+func mavenMetadata(groupId, artifactId string, versions []string, lastUpdated int64) []byte {
+	version.Sort(versions)
+	var releases []string
+	for _, v := range versions {
+		if !version.Parse(v).IsSnapshot() {
+			releases = append(releases, v)
+		}
+	}
+
+	type versioning struct {
+		Latest      string   `xml:"latest"`
+		Release     string   `xml:"release,omitempty"`
+		Versions    []string `xml:"versions>version"`
+		LastUpdated string   `xml:"lastUpdated"`
+	}
+	metadata := struct {
+		XMLName    xml.Name   `xml:"metadata"`
+		GroupId    string     `xml:"groupId"`
+		ArtifactId string     `xml:"artifactId"`
+		Versioning versioning `xml:"versioning"`
+	}{
+		GroupId:    groupId,
+		ArtifactId: artifactId,
+		Versioning: versioning{
+			Latest:      versions[len(versions)-1],
+			Versions:    versions,
+			LastUpdated: time.UnixMilli(lastUpdated).UTC().Format("20060102150405"),
+		},
+	}
+	if len(releases) > 0 {
+		metadata.Versioning.Release = releases[len(releases)-1]
+	}
+
+	data, _ := xml.MarshalIndent(metadata, "", "  ")
+	return append([]byte(xml.Header), data...)
+}
+
+// writeSearchError 返回400错误
+func writeSearchError(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"responseHeader": map[string]interface{}{"status": http.StatusBadRequest},
+		"error":          map[string]interface{}{"msg": msg, "code": http.StatusBadRequest},
+	})
+}
+
+// intParam 读取非负整数参数
+func intParam(params url.Values, name string, defaultValue int) (int, error) {
+	value := params.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 && name != "facet.limit" {
+		return 0, fmt.Errorf("无效的%s参数: %q", name, value)
+	}
+	return n, nil
+}
+
+// fieldList 解析逗号或空格分隔的字段列表
+func fieldList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// nonNil 将nil切片转换为空切片，使JSON中输出[]而不是null
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
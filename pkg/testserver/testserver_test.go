@@ -0,0 +1,211 @@
+package testserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// searchResult 搜索API的响应
+type searchResult struct {
+	Response struct {
+		NumFound int                      `json:"numFound"`
+		Start    int                      `json:"start"`
+		Docs     []map[string]interface{} `json:"docs"`
+	} `json:"response"`
+	FacetCounts struct {
+		FacetFields map[string][]interface{} `json:"facet_fields"`
+	} `json:"facet_counts"`
+	Highlighting map[string]map[string][]string `json:"highlighting"`
+}
+
+// newTestJar 创建包含指定类的JAR
+func newTestJar(t *testing.T, classes ...string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range append(classes, "META-INF/MANIFEST.MF") {
+		_, err := w.Create(name)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// newTestServer 加载testdata中的语料库并添加一个JAR
+func newTestServer(t *testing.T) (*Server, string) {
+	server, err := NewFromDir("testdata/corpus")
+	assert.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	jar := newTestJar(t, "org/example/demo/Demo.class", "org/example/demo/Demo$Inner.class", "org/example/demo/util/Strings.class", "module-info.class")
+	server.Corpus().AddFile("org/example/demo/1.1/demo-1.1.jar", jar)
+	server.Corpus().AddFile("org/example/demo/1.1/demo-1.1-sources.jar", []byte("sources"))
+	sum := sha1.Sum(jar)
+	return server, hex.EncodeToString(sum[:])
+}
+
+// search 执行搜索请求
+func search(t *testing.T, server *Server, params url.Values) (*searchResult, int) {
+	resp, err := http.Get(server.URL + "/solrsearch/select?" + params.Encode())
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result searchResult
+	if resp.StatusCode == http.StatusOK {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	}
+	return &result, resp.StatusCode
+}
+
+// TestLoadCorpus 测试从目录加载语料库
+func TestLoadCorpus(t *testing.T) {
+	corpus, err := LoadCorpus("testdata/corpus")
+	assert.NoError(t, err)
+
+	docs := corpus.Documents()
+	assert.Len(t, docs, 5)
+	assert.Equal(t, "com.acme:widgets:3.2.1", docs[0].ID())
+	assert.Equal(t, "org.example:demo:2.0-SNAPSHOT", docs[1].ID(), "同一制品的版本按版本号降序")
+
+	byID := make(map[string]*Document)
+	for _, doc := range docs {
+		byID[doc.ID()] = doc
+	}
+	assert.Equal(t, "pom", byID["org.example:demo-bom:1.1"].Packaging)
+	assert.Equal(t, int64(1700000000000), byID["org.example:demo:1.1"].Timestamp)
+	assert.ElementsMatch(t, []string{".pom", ".jar"}, byID["org.example:demo:1.1"].Extensions)
+	assert.Len(t, byID["org.example:demo:1.0"].Sha1s, 1)
+
+	_, err = LoadCorpus("testdata/missing")
+	assert.Error(t, err)
+}
+
+// TestSearch 测试搜索API
+func TestSearch(t *testing.T) {
+	server, jarSha1 := newTestServer(t)
+
+	// 默认返回制品文档
+	result, status := search(t, server, url.Values{"q": {"g:org.example"}})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, result.Response.NumFound)
+	demo := result.Response.Docs[0]
+	assert.Equal(t, "org.example:demo", demo["id"])
+	assert.Equal(t, "1.1", demo["latestVersion"], "最新版本不包括快照")
+	assert.Equal(t, float64(3), demo["versionCount"])
+
+	// core=gav返回版本文档，支持排序和分页
+	result, _ = search(t, server, url.Values{
+		"q": {"g:org.example AND a:demo"}, "core": {"gav"}, "sort": {"timestamp asc"}, "rows": {"2"}, "start": {"1"},
+	})
+	assert.Equal(t, 3, result.Response.NumFound)
+	assert.Len(t, result.Response.Docs, 2)
+	assert.Equal(t, "1.1", result.Response.Docs[0]["v"])
+	assert.Equal(t, "2.0-SNAPSHOT", result.Response.Docs[1]["v"])
+
+	// SHA-1和类名字段总是返回版本文档
+	result, _ = search(t, server, url.Values{"q": {"1:" + jarSha1}})
+	assert.Equal(t, 1, result.Response.NumFound)
+	assert.Equal(t, "org.example:demo:1.1", result.Response.Docs[0]["id"])
+
+	result, _ = search(t, server, url.Values{"q": {"c:Strings"}})
+	assert.Equal(t, 1, result.Response.NumFound)
+
+	result, _ = search(t, server, url.Values{"q": {"fc:org.example.demo"}, "hl": {"true"}, "hl.fl": {"fch"}, "hl.snippets": {"3"}})
+	assert.Equal(t, 1, result.Response.NumFound)
+	assert.Equal(t, []string{
+		"<em>org.example.demo</em>.Demo",
+		"<em>org.example.demo</em>.util.Strings",
+	}, result.Highlighting["org.example:demo:1.1"]["fch"])
+
+	// 通配符、范围、否定和标签
+	result, _ = search(t, server, url.Values{"q": {"a:demo* AND v:[1.0 TO 1.1} AND NOT p:pom"}, "core": {"gav"}})
+	assert.Equal(t, 1, result.Response.NumFound)
+	assert.Equal(t, "1.0", result.Response.Docs[0]["v"])
+
+	result, _ = search(t, server, url.Values{"q": {"tags:ui"}, "fl": {"id,g"}})
+	assert.Equal(t, []map[string]interface{}{{"id": "com.acme:widgets", "g": "com.acme"}}, result.Response.Docs)
+
+	// 聚合
+	result, _ = search(t, server, url.Values{"q": {"*:*"}, "core": {"gav"}, "rows": {"0"}, "facet": {"true"}, "facet.field": {"g", "p"}})
+	assert.Equal(t, 5, result.Response.NumFound)
+	assert.Empty(t, result.Response.Docs)
+	assert.Equal(t, []interface{}{"org.example", float64(4), "com.acme", float64(1)}, result.FacetCounts.FacetFields["g"])
+	assert.Equal(t, []interface{}{"jar", float64(4), "pom", float64(1)}, result.FacetCounts.FacetFields["p"])
+
+	// 参数错误
+	for _, params := range []url.Values{{}, {"q": {"g:(a"}}, {"q": {"g:a"}, "rows": {"-1"}}, {"q": {"g:a"}, "sort": {"g up"}}} {
+		_, status = search(t, server, params)
+		assert.Equal(t, http.StatusBadRequest, status, params.Encode())
+	}
+
+	assert.Len(t, server.Requests(), 12)
+}
+
+// TestRepository 测试仓库文件
+func TestRepository(t *testing.T) {
+	server, jarSha1 := newTestServer(t)
+
+	get := func(p string) (int, string) {
+		resp, err := http.Get(server.RepoURL() + "/" + p)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := get("org/example/demo/1.1/demo-1.1.pom")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "<version>1.1</version>")
+
+	status, body = get("org/example/demo/1.1/demo-1.1.jar.sha1")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, jarSha1, body)
+
+	status, body = get("org/example/demo/maven-metadata.xml")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "<latest>2.0-SNAPSHOT</latest>")
+	assert.Contains(t, body, "<release>1.1</release>")
+	assert.Contains(t, body, "<lastUpdated>20240309160000</lastUpdated>")
+
+	status, _ = get("org/example/demo/1.1/demo-1.1-javadoc.jar")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = get("org/example/missing/maven-metadata.xml")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// 关闭后只返回添加过的校验文件
+	server.Corpus().AddFile("org/example/demo/1.1/demo-1.1.pom.md5", []byte("ffffffffffffffffffffffffffffffff"))
+	server.Corpus().SetGenerateChecksums(false)
+	status, _ = get("org/example/demo/1.1/demo-1.1.jar.sha1")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, body = get("org/example/demo/1.1/demo-1.1.pom.md5")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ffffffffffffffffffffffffffffffff", body)
+}
+
+// TestTransport 测试进程内的传输层
+func TestTransport(t *testing.T) {
+	corpus, err := LoadCorpus("testdata/corpus")
+	assert.NoError(t, err)
+
+	client := &http.Client{Transport: NewTransport(corpus)}
+	resp, err := client.Get("https://search.maven.org/solrsearch/select?q=a:widgets&core=gav")
+	assert.NoError(t, err)
+	var result searchResult
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	resp.Body.Close()
+	assert.Equal(t, 1, result.Response.NumFound)
+
+	resp, err = client.Get("https://repo1.maven.org/maven2/org/example/demo-bom/1.1/demo-bom-1.1.pom")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,262 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidRange 版本范围格式错误
+var ErrInvalidRange = errors.New("无效的版本范围")
+
+// Restriction 版本范围中的一个区间
+//
+// Lower或Upper为nil表示该方向没有边界。
+type Restriction struct {
+	Lower          *Version
+	LowerInclusive bool
+	Upper          *Version
+	UpperInclusive bool
+}
+
+// Contains 判断版本是否落在区间内
+func (r *Restriction) Contains(v *Version) bool {
+	if r.Lower != nil {
+		c := r.Lower.Compare(v)
+		if c > 0 || (c == 0 && !r.LowerInclusive) {
+			return false
+		}
+	}
+	if r.Upper != nil {
+		c := r.Upper.Compare(v)
+		if c < 0 || (c == 0 && !r.UpperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回区间的Maven表示形式
+func (r *Restriction) String() string {
+	var builder strings.Builder
+	if r.LowerInclusive {
+		builder.WriteString("[")
+	} else {
+		builder.WriteString("(")
+	}
+	if r.Lower != nil && r.Upper != nil && r.LowerInclusive && r.UpperInclusive && r.Lower.Equal(r.Upper) {
+		builder.WriteString(r.Lower.String())
+		builder.WriteString("]")
+		return builder.String()
+	}
+	if r.Lower != nil {
+		builder.WriteString(r.Lower.String())
+	}
+	builder.WriteString(",")
+	if r.Upper != nil {
+		builder.WriteString(r.Upper.String())
+	}
+	if r.UpperInclusive {
+		builder.WriteString("]")
+	} else {
+		builder.WriteString(")")
+	}
+	return builder.String()
+}
+
+// Range Maven版本范围
+//
+// 支持Maven依赖声明中的全部范围写法:
+//   - "1.0": 软性要求，推荐使用1.0，但任何版本都满足
+//   - "[1.0]": 精确匹配1.0
+//   - "[1.2,2.0)": 1.2 <= x < 2.0
+//   - "(,1.0]": x <= 1.0
+//   - "[1.5,)": x >= 1.5
+//   - "(,1.5],[2.0,)": x <= 1.5 或 x >= 2.0
+//
+// 使用示例:
+//
+//	r, err := version.ParseRange("[1.2,2.0)")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(r.Matches("1.10"))                            // true
+//	fmt.Println(r.Highest([]string{"1.1", "1.9", "2.0"}))     // 1.9
+type Range struct {
+	// Recommended 软性要求中的推荐版本，范围写法时为nil
+	Recommended *Version
+
+	// Restrictions 组成范围的区间，任意一个区间包含版本即视为匹配
+	Restrictions []*Restriction
+}
+
+// ParseRange 解析Maven版本范围
+//
+// 参数:
+//   - spec: 版本范围字符串
+//
+// 返回:
+//   - *Range: 解析后的版本范围
+//   - error: 范围格式错误、区间重叠或边界顺序颠倒时返回包装了ErrInvalidRange的错误
+func ParseRange(spec string) (*Range, error) {
+	process := strings.TrimSpace(spec)
+	if process == "" {
+		return nil, fmt.Errorf("%w: 范围不能为空", ErrInvalidRange)
+	}
+
+	r := &Range{}
+	var upperBound *Version
+
+	for strings.HasPrefix(process, "[") || strings.HasPrefix(process, "(") {
+		index1 := strings.Index(process, ")")
+		index2 := strings.Index(process, "]")
+
+		index := index2
+		if index2 < 0 || (index1 >= 0 && index1 < index2) {
+			index = index1
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s 未闭合", ErrInvalidRange, spec)
+		}
+
+		restriction, err := parseRestriction(process[:index+1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, spec)
+		}
+		if upperBound != nil {
+			if restriction.Lower == nil || restriction.Lower.Compare(upperBound) < 0 {
+				return nil, fmt.Errorf("%w: %s 中的区间重叠", ErrInvalidRange, spec)
+			}
+		}
+		r.Restrictions = append(r.Restrictions, restriction)
+		upperBound = restriction.Upper
+
+		process = strings.TrimSpace(process[index+1:])
+		if strings.HasPrefix(process, ",") {
+			process = strings.TrimSpace(process[1:])
+		}
+	}
+
+	if process != "" {
+		if len(r.Restrictions) > 0 {
+			return nil, fmt.Errorf("%w: %s 中包含多个区间时只能使用完整的区间写法", ErrInvalidRange, spec)
+		}
+		r.Recommended = Parse(process)
+		r.Restrictions = append(r.Restrictions, &Restriction{})
+	}
+
+	return r, nil
+}
+
+// parseRestriction 解析单个区间，如"[1.0,2.0)"
+func parseRestriction(spec string) (*Restriction, error) {
+	restriction := &Restriction{
+		LowerInclusive: strings.HasPrefix(spec, "["),
+		UpperInclusive: strings.HasSuffix(spec, "]"),
+	}
+
+	process := strings.TrimSpace(spec[1 : len(spec)-1])
+	index := strings.Index(process, ",")
+
+	if index < 0 {
+		if !restriction.LowerInclusive || !restriction.UpperInclusive {
+			return nil, fmt.Errorf("%w: 单个版本必须使用[]包围", ErrInvalidRange)
+		}
+		if process == "" {
+			return nil, fmt.Errorf("%w: 区间不能为空", ErrInvalidRange)
+		}
+		v := Parse(process)
+		restriction.Lower = v
+		restriction.Upper = v
+		return restriction, nil
+	}
+
+	lower := strings.TrimSpace(process[:index])
+	upper := strings.TrimSpace(process[index+1:])
+	if lower != "" && lower == upper {
+		return nil, fmt.Errorf("%w: 区间的上下边界不能相同", ErrInvalidRange)
+	}
+
+	if lower != "" {
+		restriction.Lower = Parse(lower)
+	}
+	if upper != "" {
+		restriction.Upper = Parse(upper)
+	}
+	if restriction.Lower != nil && restriction.Upper != nil && restriction.Upper.Compare(restriction.Lower) < 0 {
+		return nil, fmt.Errorf("%w: 区间的上边界小于下边界", ErrInvalidRange)
+	}
+
+	return restriction, nil
+}
+
+// IsRange 判断版本声明是否为范围写法（而不是软性要求的单个版本）
+func IsRange(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	return strings.HasPrefix(spec, "[") || strings.HasPrefix(spec, "(")
+}
+
+// HasRestrictions 判断范围是否对版本有实际约束
+//
+// 软性要求（如"1.0"）对版本没有约束，返回false。
+func (r *Range) HasRestrictions() bool {
+	return r.Recommended == nil
+}
+
+// Contains 判断已解析的版本是否满足范围
+func (r *Range) Contains(v *Version) bool {
+	for _, restriction := range r.Restrictions {
+		if restriction.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches 判断版本字符串是否满足范围
+func (r *Range) Matches(v string) bool {
+	return r.Contains(Parse(v))
+}
+
+// Filter 返回满足范围的版本，保持原有顺序
+func (r *Range) Filter(versions []string) []string {
+	result := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if r.Matches(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Highest 返回满足范围的最高版本
+//
+// 对于软性要求，如果候选列表中包含推荐版本则直接返回推荐版本。
+//
+// 参数:
+//   - versions: 候选版本列表，无需预先排序
+//
+// 返回:
+//   - string: 满足范围的最高版本，没有满足的版本时返回空字符串
+func (r *Range) Highest(versions []string) string {
+	if r.Recommended != nil {
+		for _, v := range versions {
+			if Parse(v).Equal(r.Recommended) {
+				return v
+			}
+		}
+	}
+	return Max(r.Filter(versions))
+}
+
+// String 返回范围的Maven表示形式
+func (r *Range) String() string {
+	if r.Recommended != nil {
+		return r.Recommended.String()
+	}
+	parts := make([]string, len(r.Restrictions))
+	for i, restriction := range r.Restrictions {
+		parts[i] = restriction.String()
+	}
+	return strings.Join(parts, ",")
+}
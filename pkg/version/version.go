@@ -0,0 +1,412 @@
+package version
+
+import (
+	"sort"
+	"strings"
+)
+
+// 版本限定符的排序，与Maven ComparableVersion保持一致
+//
+// 未知限定符排在所有已知限定符之后，并按字典序相互比较；
+// 空字符串表示正式发布版本，因此"1.0-rc1" < "1.0" < "1.0-sp1"。
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// qualifierAliases 限定符别名
+var qualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// releaseQualifierIndex 正式发布版本（空限定符）在qualifiers中的位置
+const releaseQualifierIndex = "5"
+
+// Version 按照Maven ComparableVersion语义解析的版本号
+//
+// Maven的版本号由数字段和限定符段组成，使用"."和"-"分隔，数字与字母之间的切换
+// 也会隐式地产生分隔。比较规则的要点:
+//   - 数字段按数值比较，因此"1.10" > "1.9"
+//   - 末尾的0和空段会被忽略，因此"1.0" == "1" == "1.0.0"
+//   - 已知限定符的顺序为 alpha < beta < milestone < rc = cr < snapshot < (release) = ga = final < sp
+//   - "a1"、"b2"、"m3"分别是alpha、beta、milestone的缩写
+//   - 未知限定符排在已知限定符之后，并按字典序比较
+//
+// 使用示例:
+//
+//	v1 := version.Parse("1.0-RC1")
+//	v2 := version.Parse("1.0")
+//	fmt.Println(v1.Compare(v2)) // -1
+type Version struct {
+	original string
+	items    *listItem
+}
+
+// Parse 解析版本字符串
+//
+// 任何字符串都可以被解析为Maven版本，因此该函数不会返回错误。
+//
+// 参数:
+//   - v: 版本字符串，如"1.2.3"、"2.0-SNAPSHOT"、"31.1-jre"
+//
+// 返回:
+//   - *Version: 解析后的版本对象
+func Parse(v string) *Version {
+	return &Version{original: v, items: parseItems(v)}
+}
+
+// String 返回原始版本字符串
+func (v *Version) String() string {
+	return v.original
+}
+
+// Canonical 返回规范化后的版本字符串，相等的版本具有相同的规范形式
+func (v *Version) Canonical() string {
+	return v.items.String()
+}
+
+// Compare 比较两个版本
+//
+// 返回:
+//   - int: v小于other时返回-1，相等时返回0，大于时返回1
+func (v *Version) Compare(other *Version) int {
+	return sign(v.items.compareTo(other.items))
+}
+
+// Equal 判断两个版本在Maven语义下是否相等
+func (v *Version) Equal(other *Version) bool {
+	return v.Compare(other) == 0
+}
+
+// IsSnapshot 判断版本是否为快照版本
+func (v *Version) IsSnapshot() bool {
+	return strings.HasSuffix(strings.ToUpper(v.original), "-SNAPSHOT")
+}
+
+// IsPreRelease 判断版本是否为预发布版本（alpha、beta、milestone、rc或snapshot）
+func (v *Version) IsPreRelease() bool {
+	return v.items.hasPreReleaseQualifier()
+}
+
+// Compare 按照Maven语义比较两个版本字符串
+//
+// 返回:
+//   - int: a小于b时返回-1，相等时返回0，大于时返回1
+//
+// 使用示例:
+//
+//	version.Compare("1.10", "1.9")        // 1
+//	version.Compare("1.0-RC1", "1.0")     // -1
+//	version.Compare("1.0.0", "1")         // 0
+func Compare(a, b string) int {
+	return Parse(a).Compare(Parse(b))
+}
+
+// Sort 按照Maven语义对版本字符串升序排序（原地排序）
+func Sort(versions []string) {
+	parsed := make([]*Version, len(versions))
+	for i, v := range versions {
+		parsed[i] = Parse(v)
+	}
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return parsed[i].Compare(parsed[j]) < 0
+	})
+	for i, v := range parsed {
+		versions[i] = v.original
+	}
+}
+
+// Max 返回一组版本中最高的版本，列表为空时返回空字符串
+func Max(versions []string) string {
+	var max *Version
+	for _, v := range versions {
+		parsed := Parse(v)
+		if max == nil || parsed.Compare(max) > 0 {
+			max = parsed
+		}
+	}
+	if max == nil {
+		return ""
+	}
+	return max.original
+}
+
+// item ComparableVersion中的版本段
+type item interface {
+	compareTo(other item) int
+	isNull() bool
+	String() string
+}
+
+// intItem 数字版本段，以去除前导零的十进制字符串保存以支持任意大小的数字
+type intItem string
+
+func (i intItem) isNull() bool {
+	return i == "0"
+}
+
+func (i intItem) String() string {
+	return string(i)
+}
+
+func (i intItem) compareTo(other item) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case intItem:
+		if len(i) != len(o) {
+			return len(i) - len(o)
+		}
+		return strings.Compare(string(i), string(o))
+	case stringItem:
+		// 1.1 > 1-sp
+		return 1
+	case *listItem:
+		// 1.1 > 1-1
+		return 1
+	}
+	return 0
+}
+
+// stringItem 限定符版本段
+type stringItem string
+
+// newStringItem 创建限定符段，followedByDigit表示限定符后紧跟数字（如"a1"）
+func newStringItem(value string, followedByDigit bool) stringItem {
+	if followedByDigit && len(value) == 1 {
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := qualifierAliases[value]; ok {
+		value = alias
+	}
+	return stringItem(value)
+}
+
+func (s stringItem) isNull() bool {
+	return comparableQualifier(string(s)) == releaseQualifierIndex
+}
+
+func (s stringItem) String() string {
+	return string(s)
+}
+
+func (s stringItem) compareTo(other item) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-ga == 1, 1-sp > 1
+		return strings.Compare(comparableQualifier(string(s)), releaseQualifierIndex)
+	case intItem:
+		return -1
+	case stringItem:
+		return strings.Compare(comparableQualifier(string(s)), comparableQualifier(string(o)))
+	case *listItem:
+		return -1
+	}
+	return 0
+}
+
+// comparableQualifier 将限定符转换为可按字典序比较的形式
+func comparableQualifier(qualifier string) string {
+	for i, q := range qualifiers {
+		if q == qualifier {
+			return string(rune('0' + i))
+		}
+	}
+	return string(rune('0'+len(qualifiers))) + "-" + qualifier
+}
+
+// listItem 由分隔符"-"或数字/字母切换产生的子列表
+type listItem struct {
+	items []item
+}
+
+func (l *listItem) add(i item) {
+	l.items = append(l.items, i)
+}
+
+func (l *listItem) isNull() bool {
+	return len(l.items) == 0
+}
+
+// normalize 去除列表末尾的空段（0、空限定符和空列表）
+func (l *listItem) normalize() {
+	for i := len(l.items) - 1; i >= 0; i-- {
+		last := l.items[i]
+		if last.isNull() {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+		} else if _, ok := last.(*listItem); !ok {
+			break
+		}
+	}
+}
+
+func (l *listItem) String() string {
+	var builder strings.Builder
+	for i, it := range l.items {
+		if i > 0 {
+			if _, ok := it.(*listItem); ok {
+				builder.WriteString("-")
+			} else {
+				builder.WriteString(".")
+			}
+		}
+		builder.WriteString(it.String())
+	}
+	return builder.String()
+}
+
+func (l *listItem) compareTo(other item) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l.items) == 0 {
+			return 0
+		}
+		return l.items[0].compareTo(nil)
+	case intItem:
+		// 1-1 < 1.0.x
+		return -1
+	case stringItem:
+		// 1-1 > 1-sp
+		return 1
+	case *listItem:
+		for i := 0; i < len(l.items) || i < len(o.items); i++ {
+			var left, right item
+			if i < len(l.items) {
+				left = l.items[i]
+			}
+			if i < len(o.items) {
+				right = o.items[i]
+			}
+
+			var result int
+			switch {
+			case left == nil && right == nil:
+				result = 0
+			case left == nil:
+				result = -1 * right.compareTo(nil)
+			default:
+				result = left.compareTo(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+// hasPreReleaseQualifier 判断列表中是否包含排在正式发布之前的限定符
+func (l *listItem) hasPreReleaseQualifier() bool {
+	for _, it := range l.items {
+		switch v := it.(type) {
+		case stringItem:
+			if v.compareTo(nil) < 0 {
+				return true
+			}
+		case *listItem:
+			if v.hasPreReleaseQualifier() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseItems 按照ComparableVersion的规则将版本字符串解析为版本段列表
+func parseItems(version string) *listItem {
+	version = strings.ToLower(version)
+
+	root := &listItem{}
+	list := root
+	stack := []*listItem{root}
+
+	isDigit := false
+	startIndex := 0
+
+	pushList := func() {
+		sub := &listItem{}
+		list.add(sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+
+		switch {
+		case c == '.':
+			if i == startIndex {
+				list.add(intItem("0"))
+			} else {
+				list.add(parseItem(isDigit, version[startIndex:i]))
+			}
+			startIndex = i + 1
+		case c == '-':
+			if i == startIndex {
+				list.add(intItem("0"))
+			} else {
+				list.add(parseItem(isDigit, version[startIndex:i]))
+			}
+			startIndex = i + 1
+			pushList()
+		case c >= '0' && c <= '9':
+			if !isDigit && i > startIndex {
+				list.add(newStringItem(version[startIndex:i], true))
+				startIndex = i
+				pushList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > startIndex {
+				list.add(parseItem(true, version[startIndex:i]))
+				startIndex = i
+				pushList()
+			}
+			isDigit = false
+		}
+	}
+
+	if len(version) > startIndex {
+		list.add(parseItem(isDigit, version[startIndex:]))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root
+}
+
+// parseItem 将一个版本段解析为数字段或限定符段
+func parseItem(isDigit bool, buf string) item {
+	if isDigit {
+		trimmed := strings.TrimLeft(buf, "0")
+		if trimmed == "" {
+			trimmed = "0"
+		}
+		return intItem(trimmed)
+	}
+	return newStringItem(buf, false)
+}
+
+// sign 将比较结果规范化为-1、0或1
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompare 测试Maven版本比较规则
+func TestCompare(t *testing.T) {
+	// 每组中前一个版本严格小于后一个版本
+	ordered := []string{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123",
+		"1-m2", "1-m11", "1-rc", "1-cr2", "1-rc123", "1-SNAPSHOT", "1",
+		"1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+		"1-1", "1-2", "1-123", "2", "2.0.1", "2.0.1-klm", "2.0.1-lmn",
+		"2.0.1-xyz", "2.0.1-123", "2.0.1.1", "2.0.2", "2.10", "11.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		assert.Equal(t, -1, Compare(ordered[i], ordered[i+1]), "%s < %s", ordered[i], ordered[i+1])
+		assert.Equal(t, 1, Compare(ordered[i+1], ordered[i]), "%s > %s", ordered[i+1], ordered[i])
+	}
+
+	equal := [][]string{
+		{"1", "1.0", "1.0.0"},
+		{"1-ga", "1-GA", "1-final", "1-release", "1.0"},
+		{"1a1", "1-a1", "1-alpha-1"},
+		{"1cr", "1rc"},
+		{"1.0.0-2", "1-2"},
+	}
+	for _, group := range equal {
+		for i := 0; i < len(group)-1; i++ {
+			assert.Equal(t, 0, Compare(group[i], group[i+1]), "%s == %s", group[i], group[i+1])
+		}
+	}
+
+	assert.Equal(t, 1, Compare("1.10", "1.9"))
+	assert.Equal(t, -1, Compare("1.0-RC1", "1.0"))
+	assert.Equal(t, 1, Compare("100000000000000000000", "99999999999999999999"))
+}
+
+// TestSortAndMax 测试排序和取最大值
+func TestSortAndMax(t *testing.T) {
+	versions := []string{"1.10", "1.9", "1.0-RC1", "1.0", "2.0-SNAPSHOT"}
+	Sort(versions)
+	assert.Equal(t, []string{"1.0-RC1", "1.0", "1.9", "1.10", "2.0-SNAPSHOT"}, versions)
+	assert.Equal(t, "2.0-SNAPSHOT", Max(versions))
+	assert.Equal(t, "", Max(nil))
+}
+
+// TestVersionFlags 测试快照和预发布判断
+func TestVersionFlags(t *testing.T) {
+	assert.True(t, Parse("1.0-SNAPSHOT").IsSnapshot())
+	assert.True(t, Parse("1.0-SNAPSHOT").IsPreRelease())
+	assert.True(t, Parse("2.0.0-M1").IsPreRelease())
+	assert.True(t, Parse("5.0.0-beta-2").IsPreRelease())
+	assert.False(t, Parse("31.1-jre").IsPreRelease())
+	assert.False(t, Parse("1.0.Final").IsPreRelease())
+	assert.Equal(t, "1", Parse("1.0.0").Canonical())
+}
+
+// TestParseRange 测试版本范围解析与匹配
+func TestParseRange(t *testing.T) {
+	r, err := ParseRange("[1.2,2.0)")
+	assert.NoError(t, err)
+	assert.True(t, r.Matches("1.2"))
+	assert.True(t, r.Matches("1.10"))
+	assert.False(t, r.Matches("2.0"))
+	assert.False(t, r.Matches("1.1"))
+	assert.True(t, r.HasRestrictions())
+	assert.Equal(t, "[1.2,2.0)", r.String())
+
+	r, err = ParseRange("(,1.5],[2.0,)")
+	assert.NoError(t, err)
+	assert.True(t, r.Matches("1.0"))
+	assert.True(t, r.Matches("1.5"))
+	assert.False(t, r.Matches("1.6"))
+	assert.True(t, r.Matches("3.0"))
+	assert.Equal(t, "1.5", r.Highest([]string{"1.5", "1.6", "1.9"}))
+	assert.Equal(t, "10.0", r.Highest([]string{"1.0", "2.0", "10.0", "9.0"}))
+
+	r, err = ParseRange("[1.0]")
+	assert.NoError(t, err)
+	assert.True(t, r.Matches("1"))
+	assert.False(t, r.Matches("1.0.1"))
+
+	r, err = ParseRange("1.0")
+	assert.NoError(t, err)
+	assert.False(t, r.HasRestrictions())
+	assert.True(t, r.Matches("5.0"))
+	assert.Equal(t, "1.0", r.Highest([]string{"1.0", "2.0"}))
+	assert.Equal(t, "2.0", r.Highest([]string{"2.0", "1.5"}))
+}
+
+// TestParseRangeInvalid 测试非法版本范围
+func TestParseRangeInvalid(t *testing.T) {
+	invalid := []string{"", "[1.0,2.0", "(1.0)", "[2.0,1.0]", "[1.0,1.0]", "[1.0,2.0),[1.5,3.0)", "[1.0,2.0),3.0"}
+	for _, spec := range invalid {
+		_, err := ParseRange(spec)
+		assert.ErrorIs(t, err, ErrInvalidRange, spec)
+	}
+	assert.True(t, IsRange("[1.0,)"))
+	assert.False(t, IsRange("1.0"))
+}